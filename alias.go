@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	errAliasNotFound = errors.New("Alias tidak ditemukan")
+	errAliasIsItem   = errors.New("Alias sama dengan nama item menu")
+)
+
+// Struct untuk Alias item menu, mis. "nasgor" untuk Nasi Goreng
+// Disimpan terpisah dari menu agar tidak hilang saat menu dirilis ulang dari kantor pusat
+type MenuAlias struct {
+	Alias   string `json:"alias"`             // Penulisan yang diketik kasir, disimpan dalam huruf kecil
+	Item    string `json:"item"`              // Nama item menu yang dituju
+	Learned bool   `json:"learned,omitempty"` // Dipelajari dari koreksi salah ketik yang dikonfirmasi kasir
+}
+
+// Fungsi untuk menyeragamkan alias: huruf kecil dengan satu spasi antar kata
+func normalizeAlias(alias string) string {
+	return strings.Join(strings.Fields(strings.ToLower(alias)), " ")
+}
+
+// Mengambil salinan kamus alias
+func (s *Store) MenuAliases() []MenuAlias {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]MenuAlias(nil), s.data.MenuAliases...)
+}
+
+// Menyimpan alias item menu; alias yang sudah ada diarahkan ke item baru
+// Alias yang diatur manual tidak ditimpa oleh alias yang dipelajari
+func (s *Store) SetMenuAlias(a MenuAlias) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a.Alias = normalizeAlias(a.Alias)
+	if a.Alias == "" {
+		return errAliasNotFound
+	}
+	if i := slices.IndexFunc(s.data.MenuAliases, func(m MenuAlias) bool { return m.Alias == a.Alias }); i >= 0 {
+		if a.Learned && !s.data.MenuAliases[i].Learned {
+			return nil
+		}
+		s.data.MenuAliases[i] = a
+	} else {
+		s.data.MenuAliases = append(s.data.MenuAliases, a)
+	}
+	return s.save()
+}
+
+// Menghapus alias
+func (s *Store) RemoveMenuAlias(alias string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	alias = normalizeAlias(alias)
+	i := slices.IndexFunc(s.data.MenuAliases, func(m MenuAlias) bool { return m.Alias == alias })
+	if i < 0 {
+		return errAliasNotFound
+	}
+	s.data.MenuAliases = slices.Delete(s.data.MenuAliases, i, i+1)
+	return s.save()
+}
+
+// Menempelkan kamus alias ke item menu; pemanggil harus memegang s.mu
+// Alias untuk item yang sudah tidak ada di menu diabaikan
+// Slice alias disalin agar tidak menulis ke array milik menu tersimpan
+func (s *Store) applyAliases(r *Restaurant) {
+	for _, a := range s.data.MenuAliases {
+		if item := r.findMenuItem(a.Item); item != nil && !slices.Contains(item.Aliases, a.Alias) {
+			item.Aliases = append(slices.Clip(item.Aliases), a.Alias)
+		}
+	}
+}
+
+// Mencari item menu berdasarkan alias
+func (r *Restaurant) itemByAlias(alias string) *MenuItem {
+	alias = normalizeAlias(alias)
+	r.aliasMu.RLock()
+	defer r.aliasMu.RUnlock()
+	for i := range r.Menu {
+		if slices.Contains(r.Menu[i].Aliases, alias) {
+			return &r.Menu[i]
+		}
+	}
+	return nil
+}
+
+// Fungsi untuk memeriksa dan menyimpan alias baru yang menunjuk ke item menu
+func addMenuAlias(store *Store, restaurant *Restaurant, alias, item string, learned bool) error {
+	target := restaurant.findMenuItem(item)
+	if target == nil {
+		return fmt.Errorf("%w: %s", errItemNotFound, item)
+	}
+	if restaurant.findMenuItem(normalizeAlias(alias)) != nil {
+		return errAliasIsItem
+	}
+	return store.SetMenuAlias(MenuAlias{Alias: alias, Item: target.Name, Learned: learned})
+}
+
+// Fungsi untuk mempelajari alias dari koreksi salah ketik yang dikonfirmasi kasir
+// Kegagalan hanya ditampilkan karena pesanan tetap bisa dilanjutkan
+func learnAlias(restaurant *Restaurant, typed string, item *MenuItem) {
+	alias := normalizeAlias(typed)
+	if restaurant.LearnAlias == nil || alias == "" {
+		return
+	}
+	if err := restaurant.LearnAlias(alias, item.Name); err != nil {
+		fmt.Println(tr("Alias tidak dapat disimpan:"), err)
+		return
+	}
+	// Berlaku langsung untuk sisa sesi kasir ini; menu yang sama juga dibaca goroutine intake
+	restaurant.aliasMu.Lock()
+	defer restaurant.aliasMu.Unlock()
+	if target := restaurant.findMenuItem(item.Name); target != nil && !slices.Contains(target.Aliases, alias) {
+		target.Aliases = append(slices.Clip(target.Aliases), alias)
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "alias"
+func runAliasCommand(store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: alias list|add <alias> <item>|remove <alias>")
+	}
+	switch args[0] {
+	case "list":
+	case "add":
+		if len(args) != 3 {
+			return fmt.Errorf("Gunakan: alias add <alias> <item>, mis. alias add nasgor \"Nasi Goreng\"")
+		}
+		if err := addMenuAlias(store, store.Menu(), args[1], args[2], false); err != nil {
+			return err
+		}
+	case "remove":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: alias remove <alias>")
+		}
+		if err := store.RemoveMenuAlias(args[1]); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Sub-perintah alias tidak dikenal: %s", args[0])
+	}
+	aliases := store.MenuAliases()
+	if len(aliases) == 0 {
+		fmt.Println("Belum ada alias.")
+	}
+	for _, a := range aliases {
+		source := ""
+		if a.Learned {
+			source = "(dipelajari)"
+		}
+		fmt.Printf("%-20s -> %-20s %s\n", a.Alias, a.Item, source)
+	}
+	return nil
+}
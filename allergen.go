@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Menampilkan deskripsi dan alergen di daftar menu, diatur dengan flag -detail
+var menuDetail bool
+
+// Mengatur deskripsi dan alergen item menu
+func (r *Restaurant) SetDetails(name, description string, allergens ...string) {
+	if item := r.findMenuItem(name); item != nil {
+		item.Description = description
+		item.Allergens = allergens
+	}
+}
+
+// Mendapatkan alergen item; paket memuat alergen seluruh isinya
+func (r *Restaurant) ItemAllergens(item MenuItem) []string {
+	allergens := slices.Clone(item.Allergens)
+	for _, c := range item.Components {
+		if component := r.findMenuItem(c.Item); component != nil {
+			for _, a := range component.Allergens {
+				if !slices.ContainsFunc(allergens, func(b string) bool { return strings.EqualFold(a, b) }) {
+					allergens = append(allergens, a)
+				}
+			}
+		}
+	}
+	return allergens
+}
+
+// Fungsi untuk menampilkan deskripsi dan alergen di bawah item menu
+func printMenuDetail(r *Restaurant, item MenuItem) {
+	if item.Description != "" {
+		fmt.Println("   " + item.Description)
+	}
+	if allergens := r.ItemAllergens(item); len(allergens) > 0 {
+		fmt.Println(tr("   Alergen:"), strings.Join(allergens, ", "))
+	}
+}
+
+// Fungsi untuk mencari alergen item yang cocok dengan alergi pelanggan (tidak peka huruf besar/kecil)
+func matchAllergies(allergens, allergies []string) []string {
+	var matched []string
+	for _, a := range allergens {
+		if slices.ContainsFunc(allergies, func(b string) bool { return strings.EqualFold(a, b) }) {
+			matched = append(matched, a)
+		}
+	}
+	return matched
+}
+
+// Fungsi untuk menanyakan alergi pelanggan di awal input pesanan
+func promptAllergies(order *Order) error {
+	answer, err := readLineErr(tr("Alergi pelanggan, pisahkan dengan koma (Enter jika tidak ada):"))
+	if err != nil {
+		return err
+	}
+	for _, a := range strings.Split(answer, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			order.Allergies = append(order.Allergies, a)
+		}
+	}
+	return nil
+}
+
+// Fungsi untuk memperingatkan kasir jika item mengandung alergen yang disebut pelanggan
+// Mengembalikan false jika kasir memilih tidak menambahkan item
+func confirmAllergens(restaurant *Restaurant, order Order, item MenuItem) bool {
+	matched := matchAllergies(restaurant.ItemAllergens(item), order.Allergies)
+	if len(matched) == 0 {
+		return true
+	}
+	fmt.Print(tr("PERINGATAN: %s mengandung %s yang menjadi alergi pelanggan\n", item.Name, strings.Join(matched, ", ")))
+	return strings.EqualFold(readLine(tr("Tetap tambahkan? (y/n)")), "y")
+}
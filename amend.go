@@ -0,0 +1,256 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Fungsi untuk mencetak tiket dapur berisi perubahan saja, mis. "TAMBAHAN meja 4" atau "BATAL"
+// Dapur tidak menerima ulang seluruh pesanan, hanya baris yang ditambah atau dibatalkan
+func writeAmendTicket(w io.Writer, order Order, kind string, lines []OrderLine) {
+	where := tr("bawa pulang")
+	if order.Table != "" {
+		where = tr("meja %s", order.Table)
+	}
+	fmt.Fprintln(w, tr("---------- TIKET DAPUR ----------"))
+	fmt.Fprintf(w, "%s %s\n", kind, where)
+	// Tab meja yang belum dibayar belum mendapat nomor antrean
+	if order.QueueNumber > 0 {
+		fmt.Fprint(w, tr("Antrean %03d   %s\n", order.QueueNumber, order.ID))
+	} else {
+		fmt.Fprintln(w, order.ID)
+	}
+	writeTicketLines(w, lines)
+	fmt.Fprintln(w, "---------------------------------")
+}
+
+// Fungsi untuk mendapatkan baris pesanan yang dibatalkan dari refund per item
+func voidedLines(order Order, refund Refund) []OrderLine {
+	var lines []OrderLine
+	for _, rl := range refund.Lines {
+		if rl.Line < len(order.Lines) {
+			line := order.Lines[rl.Line]
+			line.Qty = rl.Qty
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Mencatat revisi item pesanan yang sudah dikirim ke dapur dan mengembalikan nomor revisinya
+func (s *Store) ReviseOrder(id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(id)
+	if order == nil {
+		return 0, errOrderNotFound
+	}
+	if order.Status != OrderPending {
+		return 0, errOrderNotPending
+	}
+	if err := s.record(EventOrderRevised, eventOrderRef{OrderID: id}); err != nil {
+		return 0, err
+	}
+	order.Revision++
+	return order.Revision, s.save()
+}
+
+// Fungsi untuk mengurangi baris pesanan dengan jumlah yang sudah di-refund per item
+func remainingLines(order Order, refunds []Refund) []OrderLine {
+	lines := slices.Clone(order.Lines)
+	for _, r := range refunds {
+		if r.OrderID != order.ID {
+			continue
+		}
+		for _, rl := range r.Lines {
+			if rl.Line < len(lines) {
+				lines[rl.Line].Qty -= rl.Qty
+			}
+		}
+	}
+	return slices.DeleteFunc(lines, func(l OrderLine) bool { return l.Qty <= 0 })
+}
+
+// Fungsi untuk menyusun isi pesanan setelah revisi: baris asal yang tidak dibatalkan ditambah baris pesanan tambahannya
+// Harga, pajak, dan pembulatan dihitung ulang dari baris tersebut
+func revisedOrder(store *Store, pricing Pricing, order Order) Order {
+	refunds := store.Refunds()
+	revised := order
+	revised.Lines = remainingLines(order, refunds)
+	for _, id := range store.AmendmentsOf(order.ID) {
+		if added, err := store.Order(id); err == nil && added.Status != OrderCancelled {
+			revised.Lines = append(revised.Lines, remainingLines(added, refunds)...)
+		}
+	}
+	revised.Subtotal, revised.Tax, revised.Rounding, revised.Total = 0, 0, 0, 0
+	pricing.Apply(&revised)
+	return revised
+}
+
+// Fungsi untuk mencetak tiket dapur "REVISI" berisi seluruh isi pesanan terbaru beserta perubahannya
+// Dapur mengganti tiket lama dengan tiket ini; baris yang berubah ditandai di bawahnya
+func writeRevisionTicket(w io.Writer, revised Order, added, voided []OrderLine) {
+	writeAmendTicket(w, revised, tr("REVISI %d", revised.Revision), revised.Lines)
+	for _, l := range added {
+		fmt.Fprintf(w, "  + %dx %s\n", l.Qty, l.Label())
+	}
+	for _, l := range voided {
+		fmt.Fprint(w, tr("  - %dx %s (batal)\n", l.Qty, l.Label()))
+	}
+	if len(added)+len(voided) > 0 {
+		fmt.Fprintln(w, "---------------------------------")
+	}
+}
+
+// Fungsi untuk menandai pesanan asal beserta pesanan tambahannya siap setelah dapur menyelesaikan tiket REVISI
+func markRevisionReady(store *Store) func(Order) {
+	ready := markReady(store)
+	return func(order Order) {
+		ready(order)
+		for _, id := range store.AmendmentsOf(order.ID) {
+			ready(Order{ID: id})
+		}
+	}
+}
+
+// Fungsi untuk mencatat revisi pesanan, mencetak tiket REVISI dan total yang dihitung ulang, lalu mengirim revisinya ke dapur
+// Dapur menerima isi pesanan terbaru, termasuk revisi yang hanya membatalkan item
+func reviseOrder(cfg *Config, store *Store, order Order, added, voided []OrderLine) error {
+	revision, err := store.ReviseOrder(order.ID)
+	if err != nil {
+		return err
+	}
+	revised := revisedOrder(store, cfg.Pricing.Strategy(), order)
+	revised.Revision = revision
+	writeRevisionTicket(os.Stdout, revised, added, voided)
+	writePriceBreakdown(os.Stdout, []Order{revised}, 0)
+	fmt.Print(tr("Total revisi  : Rp%.2f\n", revised.Total))
+
+	kitchen := startKitchen(cfg.Kitchen, markRevisionReady(store))
+	if err := kitchen.Submit(revised); err != nil {
+		fmt.Println("Revisi pesanan", revised.ID, "tidak dikirim ke dapur:", err)
+	}
+	kitchen.Drain()
+	return nil
+}
+
+// Fungsi untuk menjalankan "order amend <id>"
+// Item yang dibatalkan di-refund ke metode pembayaran asal; item tambahan menjadi pesanan baru
+// yang terhubung ke pesanan asal dan dibayar terpisah, lalu dapur menerima tiket REVISI berisi pesanan terbaru
+func runOrderAmend(cfg *Config, store *Store, id string, args []string) error {
+	fs := flag.NewFlagSet("order amend", flag.ContinueOnError)
+	var add, void itemQtyFlag
+	fs.Var(&add, "add", "item tambahan, format \"Nama Item:jumlah\" (boleh berulang), varian memakai pilihan pertama")
+	fs.Var(&void, "void", "item yang dibatalkan, format \"Nama Item:jumlah\" (boleh berulang)")
+	reason := fs.String("reason", "", "alasan pembatalan item")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(add.m) == 0 && len(void.m) == 0 {
+		return fmt.Errorf("Gunakan: order amend <id> [-add \"Nama Item:jumlah\"] [-void \"Nama Item:jumlah\"] [-reason alasan]")
+	}
+	order, err := store.Order(id)
+	if err != nil {
+		return err
+	}
+	if order.Status != OrderPending {
+		return errOrderNotPending
+	}
+
+	var voided []OrderLine
+	if len(void.m) > 0 {
+		admin, err := requireAdmin(cfg, store)
+		if err != nil {
+			return err
+		}
+		providers, err := newPaymentProviders(cfg, store)
+		if err != nil {
+			return err
+		}
+		before := auditOrderState(store, id)
+		refund, err := refundOrder(providers, store, id, void.m, 0, *reason)
+		if err != nil {
+			return err
+		}
+		printRefund(refund)
+		voided = voidedLines(order, refund)
+		if err := store.AuditChange(admin, "order.void", id, before, auditOrderState(store, id), fmt.Sprintf("%s Rp%.2f %s", refund.ID, refund.Amount, *reason)); err != nil {
+			return err
+		}
+	}
+	if len(add.m) == 0 {
+		return reviseOrder(cfg, store, order, nil, voided)
+	}
+
+	backend := newLocalBackend(cfg, store)
+	restaurant, err := backend.Menu()
+	if err != nil {
+		return err
+	}
+	req := OrderRequest{CustomerID: order.CustomerID, Table: order.Table}
+	names := make([]string, 0, len(add.m))
+	for name := range add.m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		req.Items = append(req.Items, OrderRequestItem{Name: name, Qty: add.m[name]})
+	}
+	added, err := buildOrder(restaurant, req)
+	if err != nil {
+		return err
+	}
+	// Tambahan tidak mendapat kode pengambilan sendiri; kode pesanan asal ikut menunjukkan status tambahannya
+	added.ParentID = order.ID
+
+	cashier, err := login(backend, "Masuk sebagai kasir.")
+	if err != nil {
+		return err
+	}
+	orders := []Order{added}
+	payment, err := payOrders(cfg, backend, cashier, orders)
+	if err != nil {
+		// Item yang sudah dibatalkan tetap dikabarkan ke dapur walaupun tambahan tidak jadi dibayar
+		if len(voided) > 0 {
+			if rerr := reviseOrder(cfg, store, order, nil, voided); rerr != nil {
+				return errors.Join(err, fmt.Errorf("Revisi pembatalan item gagal dicatat: %w", rerr))
+			}
+		}
+		return err
+	}
+	receipt, err := store.Receipt(payment.ID)
+	if err != nil {
+		return err
+	}
+	emitReceipt(cfg.Printer, receipt, tr("STRUK PEMBAYARAN"))
+	// Tiket revisi memakai nomor antrean pesanan asal agar dapur menyajikan tambahannya bersama
+	return reviseOrder(cfg, store, order, orders[0].Lines, voided)
+}
+
+// Mengambil ID pesanan tambahan yang terhubung ke pesanan asal
+func (s *Store) AmendmentsOf(id string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for _, o := range s.data.Orders {
+		if o.ParentID == id {
+			ids = append(ids, o.ID)
+		}
+	}
+	return ids
+}
+
+// Fungsi untuk menampilkan pesanan tambahan di detail pesanan
+func writeAmendments(w io.Writer, store *Store, o Order) {
+	if o.ParentID != "" {
+		fmt.Fprintln(w, "Tambahan  : untuk", o.ParentID)
+	}
+	if ids := store.AmendmentsOf(o.ID); len(ids) > 0 {
+		fmt.Fprintln(w, "Tambahan  :", strings.Join(ids, ", "))
+	}
+}
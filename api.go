@@ -0,0 +1,665 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Scope yang dapat diberikan ke API key
+const (
+	scopeCustomersRead    = "customers:read"    // Membaca data pelanggan
+	scopeCustomersWrite   = "customers:write"   // Mengubah tingkat keanggotaan pelanggan
+	scopeTransactionsRead = "transactions:read" // Membaca riwayat transaksi pelanggan
+	scopeTerminal         = "terminal"          // Terminal kasir yang berjalan dalam mode thin client
+	scopeFleetRead        = "fleet:read"        // Melihat kondisi seluruh terminal
+	scopeBranch           = "branch"            // Server cabang yang menerima rilis menu
+	scopeGateway          = "gateway"           // Gateway pembayaran yang mengirim notifikasi sengketa
+	scopeCalendarRead     = "calendar:read"     // Berlangganan feed kalender reservasi
+	scopeWifi             = "wifi"              // Portal hotspot yang melaporkan voucher Wi-Fi dipakai
+)
+
+// Struct untuk Server API
+// Menyediakan endpoint HTTP untuk sistem eksternal seperti CRM/loyalty
+type Server struct {
+	cfg     *Config
+	store   *Store
+	local   *localBackend // Backend lokal yang dilayani ke terminal
+	kitchen *kitchen      // Dapur yang memproses pesanan dari terminal
+
+	draining atomic.Bool // Server sedang berhenti, checkout baru ditolak
+
+	checkoutMu sync.Mutex // Checkout terminal diproses satu per satu untuk pemeriksaan Idempotency-Key
+
+	outlets map[string]*Server // Server outlet yang dipilih dengan header X-Outlet, kosong jika hanya satu outlet
+
+	broker *brokerQueue // Penerbitan kejadian pesanan ke message broker, nil jika tidak diatur
+}
+
+// Fungsi untuk membuat server API baru
+func newServer(cfg *Config, store *Store) *Server {
+	return &Server{cfg: cfg, store: store, local: newLocalBackend(cfg, store), kitchen: startKitchen(cfg.Kitchen, markReady(store))}
+}
+
+// Mendaftarkan seluruh endpoint API
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/customers", s.requireScope(scopeCustomersRead, s.handleListCustomers))
+	mux.Handle("GET /api/v1/customers/{id}", s.requireScope(scopeCustomersRead, s.handleGetCustomer))
+	mux.Handle("PUT /api/v1/customers/{id}/tier", s.requireScope(scopeCustomersWrite, s.handleUpdateTier))
+	mux.Handle("GET /api/v1/transactions", s.requireScope(scopeTransactionsRead, s.handleListTransactions))
+
+	// Endpoint untuk terminal kasir (thin client)
+	mux.HandleFunc("GET /api/v1/health", s.handleHealth)
+	mux.HandleFunc("GET /display", s.handleQueueDisplay)
+	mux.HandleFunc("GET /status", s.handleStatusPage)
+	mux.HandleFunc("GET /orders/status/{code}", s.handleOrderStatus)
+	mux.HandleFunc("GET /calendar/reservations.ics", s.handleReservationCalendar)
+	mux.Handle("GET /api/v1/menu", s.requireScope(scopeTerminal, s.handleMenu))
+	mux.Handle("GET /api/v1/terminal/customers", s.requireScope(scopeTerminal, s.handleTerminalCustomer))
+	mux.Handle("POST /api/v1/terminal/customers", s.requireScope(scopeTerminal, s.handleTerminalAddCustomer))
+	mux.Handle("GET /api/v1/terminal/drawer", s.requireScope(scopeTerminal, s.handleTerminalDrawer))
+	mux.Handle("POST /api/v1/terminal/checkout", s.requireScope(scopeTerminal, s.handleTerminalCheckout))
+	mux.Handle("POST /api/v1/terminal/drafts", s.requireScope(scopeTerminal, s.handleTerminalDrafts))
+	mux.Handle("GET /api/v1/terminal/tabs/{table}", s.requireScope(scopeTerminal, s.handleTerminalTableTab))
+	mux.Handle("POST /api/v1/terminal/held/{label}/resume", s.requireScope(scopeTerminal, s.handleTerminalResumeHeld))
+	mux.Handle("GET /api/v1/terminal/reservations/deposit", s.requireScope(scopeTerminal, s.handleTerminalDeposit))
+	mux.Handle("GET /api/v1/terminal/reservations/upcoming", s.requireScope(scopeTerminal, s.handleTerminalUpcomingReservations))
+	mux.Handle("POST /api/v1/terminal/aliases", s.requireScope(scopeTerminal, s.handleTerminalLearnAlias))
+	mux.Handle("GET /api/v1/terminal/gift-vouchers/{code}", s.requireScope(scopeTerminal, s.handleTerminalGiftVoucher))
+	mux.Handle("GET /api/v1/terminal/stock-alerts", s.requireScope(scopeTerminal, s.handleTerminalStockAlerts))
+	mux.Handle("POST /api/v1/terminal/wifi-vouchers", s.requireScope(scopeTerminal, s.handleTerminalWifiVoucher))
+	mux.Handle("POST /api/v1/terminal/receipts/{id}/email", s.requireScope(scopeTerminal, s.handleTerminalEmailReceipt))
+	mux.Handle("POST /api/v1/terminal/login", s.requireScope(scopeTerminal, s.handleTerminalLogin))
+	mux.Handle("POST /api/v1/terminal/pin", s.requireScope(scopeTerminal, s.handleTerminalChangePIN))
+	mux.Handle("POST /api/v1/terminal/heartbeat", s.requireScope(scopeTerminal, s.handleHeartbeat))
+	mux.Handle("POST /api/v1/terminal/sequence", s.requireScope(scopeTerminal, s.handleTerminalSequence))
+	mux.Handle("GET /api/v1/fleet", s.requireScope(scopeFleetRead, s.handleFleet))
+
+	// Endpoint untuk server cabang
+	mux.Handle("GET /api/v1/branch/menu-releases", s.requireScope(scopeBranch, s.handleBranchReleases))
+	mux.Handle("POST /api/v1/branch/menu-releases/{id}/ack", s.requireScope(scopeBranch, s.handleBranchReleaseAck))
+
+	// Endpoint untuk gateway pembayaran
+	mux.Handle("POST /api/v1/gateway/disputes", s.requireScope(scopeGateway, s.handleGatewayDispute))
+
+	// Endpoint untuk portal hotspot Wi-Fi tamu
+	mux.Handle("POST /api/v1/wifi/redeem", s.requireScope(scopeWifi, s.handleWifiRedeem))
+	return mux
+}
+
+// Menjalankan server HTTP sampai terjadi error atau diminta berhenti
+// Berhenti lewat sinyal (Ctrl-C/SIGTERM) atau perintah "shutdown" di konsol server
+func (s *Server) ListenAndServe() error {
+	fmt.Println("Server API berjalan di", s.cfg.Server.Addr)
+	go monitorFleet(s.cfg, s.store)
+	if s.cfg.HeadOffice.URL != "" {
+		go runBranchMenuSync(s.cfg, s.store)
+	}
+	if s.cfg.Email.Host != "" {
+		go runEmailRetry(s.cfg, s.store)
+	}
+	go runReservationExpiry(s.cfg, s.store)
+	go runPauseExpiry(s.cfg, s.store)
+	if len(s.cfg.Channels) > 0 {
+		go runChannelPolling(s)
+	}
+	if s.cfg.Telegram.BotToken != "" {
+		go runTelegramBot(s)
+	}
+	go runNightlyProjections(s.store)
+	if s.cfg.Offsite.Provider != "" {
+		go runOffsiteExport(s.cfg.Offsite, s.store)
+	}
+	if s.cfg.Close.Time != "" {
+		go runDailyClose(s.cfg, s.store)
+	}
+	if len(s.cfg.Webhooks.Endpoints) > 0 {
+		s.store.EnableWebhooks(s.cfg.Webhooks.Endpoints)
+		go runWebhookDelivery(s.cfg.Webhooks, s.store)
+	}
+	if s.cfg.Broker.Enabled() {
+		broker, err := startBroker(s.cfg.Broker)
+		if err != nil {
+			return err
+		}
+		s.store.Subscribe("broker", broker.enqueue, brokerEvents...)
+		s.broker = broker
+		fmt.Printf("Kejadian pesanan diterbitkan ke %s %s\n", s.cfg.Broker.Type, s.cfg.Broker.URL)
+	}
+
+	handler := s.routes()
+	if len(s.outlets) > 0 {
+		handler = s.outletRouter(handler)
+		for _, o := range s.outlets {
+			go runNightlyProjections(o.store)
+			if o.cfg.Close.Time != "" {
+				go runDailyClose(o.cfg, o.store)
+			}
+		}
+	}
+	srv := &http.Server{Addr: s.cfg.Server.Addr, Handler: handler}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+
+	stop := stopSignal()
+	defer signal.Stop(stop)
+	select {
+	case err := <-serveErr:
+		return err
+	case <-stop:
+	case <-consoleShutdown():
+	}
+	return s.shutdown(srv)
+}
+
+// Fungsi untuk menunggu perintah "shutdown" dari konsol server
+// Channel tidak pernah ditutup jika stdin tidak tersedia, sehingga server hanya berhenti lewat sinyal
+func consoleShutdown() <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		for input.Scan() {
+			if strings.EqualFold(strings.TrimSpace(input.Text()), "shutdown") {
+				close(ch)
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Menghentikan server secara bertahap
+// Checkout baru ditolak, request yang sedang berjalan ditunggu, lalu dapur menyelesaikan isi antreannya
+func (s *Server) shutdown(srv *http.Server) error {
+	fmt.Println("Server berhenti: pesanan baru tidak diterima, menunggu request yang sedang berjalan...")
+	s.draining.Store(true)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := srv.Shutdown(ctx)
+	fmt.Println("Menunggu dapur menyelesaikan pesanan...")
+	s.kitchen.Drain()
+	for _, o := range s.outlets {
+		o.kitchen.Drain()
+	}
+	drainBroker(s.broker)
+	fmt.Println("Server berhenti.")
+	return err
+}
+
+// Middleware untuk memeriksa API key dan scope-nya
+// Key dibaca dari header "Authorization: Bearer <key>" atau "X-API-Key"
+func (s *Server) requireScope(scope string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, ok := s.authenticate(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "API key tidak valid")
+			return
+		}
+		if !key.HasScope(scope) {
+			writeError(w, http.StatusForbidden, "API key tidak memiliki scope "+scope)
+			return
+		}
+		next(w, r)
+	})
+}
+
+// Mencari API key yang cocok dengan request
+func (s *Server) authenticate(r *http.Request) (APIKey, bool) {
+	return findAPIKey(s.cfg.Server.APIKeys, r)
+}
+
+// Fungsi untuk mencari API key dari header X-API-Key atau Authorization: Bearer
+func findAPIKey(keys []APIKey, r *http.Request) (APIKey, bool) {
+	presented := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		presented = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if presented == "" {
+		return APIKey{}, false
+	}
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(key.Key), []byte(presented)) == 1 {
+			return key, true
+		}
+	}
+	return APIKey{}, false
+}
+
+// Struct untuk data pelanggan yang dikirim ke sistem eksternal
+type customerView struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone"`
+	Email     string    `json:"email"`
+	Tier      string    `json:"tier"`
+	Marketing bool      `json:"marketing_consent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Struct untuk event pembelian yang dikirim ke sistem eksternal
+type purchaseEvent struct {
+	OrderID    string              `json:"order_id"`
+	CustomerID string              `json:"customer_id"`
+	Status     OrderStatus         `json:"status"`
+	Items      []purchaseEventItem `json:"items"`
+	Total      Money               `json:"total"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// Struct untuk baris item pada event pembelian
+type purchaseEventItem struct {
+	Name      string   `json:"name"`
+	Modifiers []string `json:"modifiers,omitempty"`
+	Qty       int      `json:"qty"`
+	Price     Money    `json:"price"`
+}
+
+// Mengubah pelanggan menjadi format untuk sistem eksternal
+func newCustomerView(c Customer) customerView {
+	return customerView{
+		ID:        c.ID,
+		Name:      c.Name,
+		Phone:     c.Phone,
+		Email:     c.Email,
+		Tier:      c.Tier,
+		Marketing: c.Consent.Marketing,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// Mencari pelanggan yang menyetujui pembagian data
+// Pelanggan tanpa persetujuan diperlakukan seolah-olah tidak ada
+func (s *Server) sharedCustomer(id string) (Customer, error) {
+	c, err := s.store.Customer(id)
+	if err != nil || !c.Consent.DataSharing {
+		return Customer{}, errCustomerNotFound
+	}
+	return c, nil
+}
+
+// GET /api/v1/customers?tier=gold
+func (s *Server) handleListCustomers(w http.ResponseWriter, r *http.Request) {
+	tier := r.URL.Query().Get("tier")
+	views := []customerView{}
+	for _, c := range s.store.Customers() {
+		if !c.Consent.DataSharing {
+			continue
+		}
+		if tier != "" && !strings.EqualFold(c.Tier, tier) {
+			continue
+		}
+		views = append(views, newCustomerView(c))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// GET /api/v1/customers/{id}
+func (s *Server) handleGetCustomer(w http.ResponseWriter, r *http.Request) {
+	c, err := s.sharedCustomer(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newCustomerView(c))
+}
+
+// PUT /api/v1/customers/{id}/tier dengan body {"tier": "gold"}
+func (s *Server) handleUpdateTier(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Tier string `json:"tier"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || strings.TrimSpace(body.Tier) == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi field tier")
+		return
+	}
+	if _, err := s.sharedCustomer(r.PathValue("id")); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	c, err := s.store.UpdateCustomerTier(r.PathValue("id"), strings.TrimSpace(body.Tier))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newCustomerView(c))
+}
+
+// GET /api/v1/transactions?since=2024-01-01T00:00:00Z&customer_id=CUS-...
+// Hanya transaksi milik pelanggan yang menyetujui pembagian data yang dikirim
+func (s *Server) handleListTransactions(w http.ResponseWriter, r *http.Request) {
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Format since harus RFC3339")
+			return
+		}
+		since = t
+	}
+	customerID := r.URL.Query().Get("customer_id")
+
+	shared := map[string]bool{}
+	for _, c := range s.store.Customers() {
+		shared[c.ID] = c.Consent.DataSharing
+	}
+
+	events := []purchaseEvent{}
+	for _, order := range s.store.Orders() {
+		if order.CustomerID == "" || !shared[order.CustomerID] {
+			continue
+		}
+		if customerID != "" && order.CustomerID != customerID {
+			continue
+		}
+		if order.CreatedAt.Before(since) {
+			continue
+		}
+		event := purchaseEvent{
+			OrderID:    order.ID,
+			CustomerID: order.CustomerID,
+			Status:     order.Status,
+			Total:      order.Total,
+			CreatedAt:  order.CreatedAt,
+		}
+		for _, line := range order.Lines {
+			item := purchaseEventItem{Name: line.Item.Name, Qty: line.Qty, Price: line.UnitPrice()}
+			for _, m := range line.Modifiers {
+				item.Modifiers = append(item.Modifiers, m.Name)
+			}
+			event.Items = append(event.Items, item)
+		}
+		events = append(events, event)
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+// Struct untuk body checkout dari terminal
+type checkoutRequest struct {
+	Orders  []Order `json:"orders"`
+	Payment Payment `json:"payment"`
+}
+
+// Struct untuk respons checkout, berisi nomor antrean dan perkiraan waktu siap yang diberikan server untuk setiap pesanan
+type checkoutResponse struct {
+	Status       string               `json:"status"`
+	QueueNumbers map[string]int       `json:"queue_numbers,omitempty"`
+	ReadyAt      map[string]time.Time `json:"estimated_ready_at,omitempty"`
+	PickupCodes  map[string]string    `json:"pickup_codes,omitempty"`
+}
+
+// GET /api/v1/health
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// GET /api/v1/menu
+func (s *Server) handleMenu(w http.ResponseWriter, r *http.Request) {
+	restaurant, err := s.local.Menu()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, restaurant.Menu)
+}
+
+// GET /api/v1/terminal/customers?phone=0812...
+func (s *Server) handleTerminalCustomer(w http.ResponseWriter, r *http.Request) {
+	c, err := s.local.CustomerByPhone(r.URL.Query().Get("phone"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// POST /api/v1/terminal/customers
+func (s *Server) handleTerminalAddCustomer(w http.ResponseWriter, r *http.Request) {
+	var c Customer
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil || c.Phone == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi data pelanggan dengan nomor HP")
+		return
+	}
+	created, err := s.local.AddCustomer(c)
+	if errors.Is(err, errCustomerExists) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// GET /api/v1/terminal/drawer
+func (s *Server) handleTerminalDrawer(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.local.DrawerDenominations())
+}
+
+// POST /api/v1/terminal/receipts/{id}/email
+// Struk hanya dimasukkan ke antrean; pengiriman ke server SMTP tidak menahan respons
+func (s *Server) handleTerminalEmailReceipt(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		To string `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.To == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi to")
+		return
+	}
+	err := s.local.EmailReceipt(r.PathValue("id"), body.To)
+	switch {
+	case errors.Is(err, errReceiptNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, errEmailDisabled):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "queued"})
+	}
+}
+
+// POST /api/v1/terminal/checkout
+// Total dihitung ulang dari baris pesanan dengan aturan pajak dan pembulatan server; pengiriman ulang pembayaran yang sama diabaikan
+func (s *Server) handleTerminalCheckout(w http.ResponseWriter, r *http.Request) {
+	var req checkoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Orders) == 0 || req.Payment.ID == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi pesanan dan pembayaran")
+		return
+	}
+	if s.draining.Load() {
+		writeError(w, http.StatusServiceUnavailable, "Server sedang berhenti, simpan pesanan sebagai draf")
+		return
+	}
+	// Checkout diproses satu per satu agar request ulang yang datang bersamaan tidak sama-sama lolos pemeriksaan
+	s.checkoutMu.Lock()
+	defer s.checkoutMu.Unlock()
+	req.Payment.IdempotencyKey = r.Header.Get("Idempotency-Key")
+	original, done, err := s.store.IdempotentPayment(req.Payment.IdempotencyKey, req.Payment.ID)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if done {
+		s.replayCheckout(w, original)
+		return
+	}
+	for _, order := range req.Orders {
+		if paid, err := s.store.Order(order.ID); err == nil && paid.PaymentID != "" {
+			// ID sama dengan pembayaran berbeda berarti nomor struk terulang, bukan pengiriman ulang
+			alertSequenceOverlap(s.cfg, s.store, order.ID, []string{fmt.Sprintf("Nomor pesanan %s sudah dipakai pembayaran %s", order.ID, paid.PaymentID)})
+			writeError(w, http.StatusConflict, fmt.Sprintf("%s: %s (%s)", errOrderPaid, order.ID, paid.PaymentID))
+			return
+		}
+	}
+
+	restaurant, err := s.local.Menu()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, order := range req.Orders {
+		if err := validateOrderType(order); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s: %v", order.ID, err))
+			return
+		}
+		// Ongkos kirim dari terminal diperiksa dengan batas harga server, sama seperti impor batch
+		if err := checkDeliveryFee(restaurant, order); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s: %v", order.ID, err))
+			return
+		}
+		if err := s.cfg.OrderLimits.Check(order); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s: %v", order.ID, err))
+			return
+		}
+	}
+
+	var total Money
+	pricing := s.cfg.Pricing.Strategy()
+	for i := range req.Orders {
+		pricing.Apply(&req.Orders[i])
+		total += req.Orders[i].Total
+	}
+	if err := checkReservationDeposit(s.store, req.Payment); err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err := checkGiftVoucher(s.store, req.Payment); err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	// Pembulatan tahap pembayaran berlaku untuk sisa tagihan setelah deposit dan voucher, sama seperti kasir
+	total, req.Payment.Rounding = pricing.RoundPayment(total - req.Payment.Deposit - req.Payment.GiftVoucher)
+	if total != req.Payment.Amount {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Total pembayaran Rp%.2f tidak sesuai dengan total pesanan Rp%.2f", req.Payment.Amount, total))
+		return
+	}
+	if err := s.local.Checkout(req.Orders, req.Payment); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := checkoutResponse{Status: "ok", QueueNumbers: map[string]int{}, ReadyAt: map[string]time.Time{}, PickupCodes: map[string]string{}}
+	for _, order := range req.Orders {
+		resp.QueueNumbers[order.ID] = order.QueueNumber
+		resp.ReadyAt[order.ID] = order.EstimatedReadyAt
+		resp.PickupCodes[order.ID] = order.PickupCode
+		if err := s.kitchen.Submit(order); err != nil {
+			fmt.Println("Pesanan", order.ID, "tidak dikirim ke dapur:", err)
+		}
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// Fungsi untuk mengirim ulang hasil checkout yang sudah tersimpan untuk request yang diulang
+// Pesanan tidak disimpan atau dikirim ke dapur lagi
+func (s *Server) replayCheckout(w http.ResponseWriter, payment Payment) {
+	resp := checkoutResponse{Status: "ok", QueueNumbers: map[string]int{}, ReadyAt: map[string]time.Time{}, PickupCodes: map[string]string{}}
+	for _, id := range payment.OrderIDs {
+		if order, err := s.store.Order(id); err == nil {
+			resp.QueueNumbers[id] = order.QueueNumber
+			resp.ReadyAt[id] = order.EstimatedReadyAt
+			resp.PickupCodes[id] = order.PickupCode
+		}
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// GET /api/v1/terminal/tabs/{table}
+// Terminal menambahkan ronde baru ke tab meja yang sama dengan kasir lain
+func (s *Server) handleTerminalTableTab(w http.ResponseWriter, r *http.Request) {
+	tab, err := s.local.TableTab(r.PathValue("table"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, tab)
+}
+
+// POST /api/v1/terminal/held/{label}/resume
+// Pesanan yang ditahan di satu terminal boleh dilanjutkan di terminal lain
+func (s *Server) handleTerminalResumeHeld(w http.ResponseWriter, r *http.Request) {
+	order, err := s.local.ResumeHeldOrder(r.PathValue("label"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, order)
+}
+
+// GET /api/v1/terminal/reservations/deposit?table=
+func (s *Server) handleTerminalDeposit(w http.ResponseWriter, r *http.Request) {
+	res, err := s.local.ReservationDeposit(r.URL.Query().Get("table"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// GET /api/v1/terminal/reservations/upcoming
+// Ditampilkan terminal saat kasir dibuka
+func (s *Server) handleTerminalUpcomingReservations(w http.ResponseWriter, r *http.Request) {
+	upcoming, _ := s.local.UpcomingReservations()
+	writeJSON(w, http.StatusOK, upcoming)
+}
+
+// GET /api/v1/terminal/stock-alerts
+// Peringatan stok menipis dihitung dari persediaan server setelah terminal menyimpan pembayaran
+func (s *Server) handleTerminalStockAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts, _ := s.local.StockAlerts()
+	writeJSON(w, http.StatusOK, alerts)
+}
+
+// POST /api/v1/terminal/aliases
+// Alias yang dipelajari terminal dibagikan ke semua terminal lewat menu berikutnya
+func (s *Server) handleTerminalLearnAlias(w http.ResponseWriter, r *http.Request) {
+	var a MenuAlias
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		writeError(w, http.StatusBadRequest, "Body harus berisi alias JSON")
+		return
+	}
+	if err := s.local.LearnAlias(a.Alias, a.Item); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// POST /api/v1/terminal/drafts
+// Terminal menyimpan keranjang yang belum dibayar saat berhenti
+func (s *Server) handleTerminalDrafts(w http.ResponseWriter, r *http.Request) {
+	var orders []Order
+	if err := json.NewDecoder(r.Body).Decode(&orders); err != nil || len(orders) == 0 {
+		writeError(w, http.StatusBadRequest, "Body harus berisi daftar pesanan")
+		return
+	}
+	if err := s.local.SaveDrafts(orders); errors.Is(err, errHoldLabelTaken) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]int{"saved": len(orders)})
+}
+
+// Fungsi untuk menulis respons JSON
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Fungsi untuk menulis respons error dalam format JSON
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Versi susunan file data; dinaikkan jika field storeData berubah tanpa kompatibel ke belakang
+const storeSchemaVersion = 1
+
+// Penanda dan versi format arsip cadangan "backup create"
+const (
+	archiveFormat  = "tugaskedua-backup"
+	archiveVersion = 1
+)
+
+// Nama file di dalam arsip cadangan
+const (
+	archiveManifest = "manifest.json"
+	archiveData     = "data.json"
+	archiveJournal  = "journal.jsonl"
+	archiveConfig   = "config.json"
+)
+
+var errNotBackupArchive = errors.New("File bukan arsip cadangan tugaskedua")
+
+// Struct untuk isi manifest.json di arsip cadangan
+type archiveManifestFile struct {
+	Format    string    `json:"format"`     // Selalu "tugaskedua-backup"
+	Version   int       `json:"version"`    // Versi format arsip
+	Schema    int       `json:"schema"`     // Versi susunan file data saat dicadangkan
+	CreatedAt time.Time `json:"created_at"` // Waktu cadangan dibuat
+	Menu      int       `json:"menu"`       // Jumlah item menu tersimpan, untuk ringkasan sebelum dipulihkan
+	Orders    int       `json:"orders"`     // Jumlah pesanan
+	Customers int       `json:"customers"`  // Jumlah pelanggan
+	Journal   bool      `json:"journal"`    // Arsip berisi jurnal kejadian
+	Config    bool      `json:"config"`     // Arsip berisi file konfigurasi
+}
+
+// Memeriksa apakah arsip bisa dipulihkan oleh versi program ini
+func (m archiveManifestFile) check() error {
+	if m.Format != archiveFormat {
+		return errNotBackupArchive
+	}
+	if m.Version > archiveVersion {
+		return fmt.Errorf("Arsip cadangan versi %d dibuat oleh program yang lebih baru (didukung sampai versi %d)", m.Version, archiveVersion)
+	}
+	if m.Schema < 1 || m.Schema > storeSchemaVersion {
+		return fmt.Errorf("Versi data di arsip (%d) tidak didukung, versi data program ini %d", m.Schema, storeSchemaVersion)
+	}
+	return nil
+}
+
+// Struct untuk isi arsip cadangan yang sudah dibaca
+type backupArchive struct {
+	Manifest archiveManifestFile
+	Data     []byte
+	Journal  []byte // Kosong jika arsip tidak berisi jurnal
+	Config   []byte // Kosong jika arsip tidak berisi konfigurasi
+}
+
+// Fungsi untuk menyusun arsip cadangan berisi data (menu, pesanan, pelanggan), jurnal, dan konfigurasi
+func writeBackupArchive(w io.Writer, cfg *Config, store *Store) (archiveManifestFile, error) {
+	data, err := store.Backup()
+	if err != nil {
+		return archiveManifestFile{}, err
+	}
+	journal, err := os.ReadFile(journalPath(store.path))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return archiveManifestFile{}, fmt.Errorf("Gagal membaca jurnal: %w", err)
+	}
+	config, err := os.ReadFile(cfg.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return archiveManifestFile{}, fmt.Errorf("Gagal membaca konfigurasi: %w", err)
+	}
+	restaurant := store.Menu()
+	manifest := archiveManifestFile{
+		Format: archiveFormat, Version: archiveVersion, Schema: storeSchemaVersion, CreatedAt: time.Now(),
+		Menu: len(restaurant.Menu), Orders: len(store.Orders()), Customers: len(store.Customers()),
+		Journal: len(journal) > 0, Config: len(config) > 0,
+	}
+	head, _ := json.MarshalIndent(manifest, "", "  ")
+
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		body []byte
+	}{{archiveManifest, head}, {archiveData, data}, {archiveJournal, journal}, {archiveConfig, config}}
+	for _, f := range files {
+		if len(f.body) == 0 {
+			continue
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: manifest.CreatedAt})
+		if err != nil {
+			return manifest, err
+		}
+		if _, err := fw.Write(f.body); err != nil {
+			return manifest, err
+		}
+	}
+	return manifest, zw.Close()
+}
+
+// Fungsi untuk membaca arsip cadangan dan memeriksa versinya
+// File data di arsip harus bisa dibaca sebagai file data agar pemulihan tidak meninggalkan data rusak
+func readBackupArchive(raw []byte) (backupArchive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return backupArchive{}, errNotBackupArchive
+	}
+	var a backupArchive
+	var head []byte
+	for _, f := range zr.File {
+		var dst *[]byte
+		switch f.Name {
+		case archiveManifest:
+			dst = &head
+		case archiveData:
+			dst = &a.Data
+		case archiveJournal:
+			dst = &a.Journal
+		case archiveConfig:
+			dst = &a.Config
+		default:
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return a, err
+		}
+		*dst, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return a, fmt.Errorf("Gagal membaca %s dari arsip: %w", f.Name, err)
+		}
+	}
+	if head == nil || json.Unmarshal(head, &a.Manifest) != nil {
+		return a, errNotBackupArchive
+	}
+	if err := a.Manifest.check(); err != nil {
+		return a, err
+	}
+	if a.Data == nil {
+		return a, fmt.Errorf("Arsip cadangan tidak berisi %s", archiveData)
+	}
+	var data storeData
+	if err := json.Unmarshal(a.Data, &data); err != nil {
+		return a, fmt.Errorf("File data di arsip tidak valid: %w", err)
+	}
+	if len(a.Config) > 0 {
+		var cfg Config
+		if err := json.Unmarshal(a.Config, &cfg); err != nil {
+			return a, fmt.Errorf("Konfigurasi di arsip tidak valid: %w", err)
+		}
+	}
+	return a, nil
+}
+
+// Mengganti seluruh isi file data dan jurnal dengan isi arsip cadangan
+func (s *Store) Restore(a backupArchive) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var data storeData
+	if err := json.Unmarshal(a.Data, &data); err != nil {
+		return fmt.Errorf("File data di arsip tidak valid: %w", err)
+	}
+	// Jurnal ditulis lebih dulu agar nomor urut kejadian di file data selalu ada di jurnal
+	if s.path != "" {
+		journal := journalPath(s.path)
+		if len(a.Journal) > 0 {
+			if err := os.WriteFile(journal, a.Journal, 0o600); err != nil {
+				return fmt.Errorf("Gagal memulihkan jurnal: %w", err)
+			}
+		} else if err := os.Remove(journal); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("Gagal menghapus jurnal lama: %w", err)
+		}
+	}
+	s.data = data
+	return s.save()
+}
+
+// Fungsi untuk menjalankan "backup create <file>" dan "backup restore <file>"
+func runBackupArchive(cfg *Config, store *Store, args []string) error {
+	switch args[0] {
+	case "create":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: backup create <file.zip>")
+		}
+		var buf bytes.Buffer
+		manifest, err := writeBackupArchive(&buf, cfg, store)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(args[1], buf.Bytes(), 0o600); err != nil {
+			return fmt.Errorf("Gagal menulis arsip cadangan: %w", err)
+		}
+		fmt.Printf("Cadangan %s dibuat: %d item menu, %d pesanan, %d pelanggan\n", args[1], manifest.Menu, manifest.Orders, manifest.Customers)
+		return nil
+	case "restore":
+		fs := flag.NewFlagSet("backup restore", flag.ContinueOnError)
+		withConfig := fs.Bool("with-config", false, "pulihkan juga file konfigurasi dari arsip")
+		force := fs.Bool("force", false, "pulihkan tanpa konfirmasi walaupun file data sudah berisi pesanan")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("Gunakan: backup restore [-with-config] [-force] <file.zip>")
+		}
+		raw, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		archive, err := readBackupArchive(raw)
+		if err != nil {
+			return err
+		}
+		m := archive.Manifest
+		fmt.Printf("Cadangan %s: %d item menu, %d pesanan, %d pelanggan\n", m.CreatedAt.Local().Format("2006-01-02 15:04"), m.Menu, m.Orders, m.Customers)
+		if *withConfig && len(archive.Config) == 0 {
+			return fmt.Errorf("Arsip cadangan tidak berisi konfigurasi")
+		}
+		admin, err := requireAdmin(cfg, store)
+		if err != nil {
+			return err
+		}
+		if n := len(store.Orders()); n > 0 && !*force &&
+			!strings.EqualFold(readLine(fmt.Sprintf("File data %s berisi %d pesanan dan akan diganti. Lanjutkan? (y/n)", store.path, n)), "y") {
+			return fmt.Errorf("Pemulihan dibatalkan")
+		}
+		if err := store.Restore(archive); err != nil {
+			return err
+		}
+		if *withConfig {
+			if err := os.WriteFile(cfg.path, archive.Config, 0o600); err != nil {
+				return fmt.Errorf("Data dipulihkan, tetapi konfigurasi gagal ditulis: %w", err)
+			}
+			fmt.Println("Konfigurasi dipulihkan ke", filepath.Clean(cfg.path))
+		}
+		fmt.Println("Data dipulihkan ke", store.path)
+		return store.Audit(admin, "backup.restore", filepath.Base(fs.Arg(0)), m.CreatedAt.Format(time.RFC3339))
+	}
+	return fmt.Errorf("Gunakan: backup create|restore|push|list")
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Fungsi untuk menghitung hash catatan audit; Hash diabaikan, PrevHash ikut dihitung sehingga catatan saling terantai
+func (e AuditEntry) computeHash() string {
+	e.Hash = ""
+	raw, _ := json.Marshal(e)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// Fungsi untuk mengubah nilai sebelum/sesudah menjadi teks catatan audit
+// Teks disimpan apa adanya, nilai lain disimpan sebagai JSON
+func auditValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+// Menambahkan catatan audit beserta nilai sebelum dan sesudah tindakan
+func (s *Store) AuditChange(actor Staff, action, target string, before, after any, detail string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appendAudit(AuditEntry{
+		At:      time.Now(),
+		StaffID: actor.ID,
+		Name:    actor.Name,
+		Action:  action,
+		Target:  target,
+		Detail:  detail,
+		Before:  auditValue(before),
+		After:   auditValue(after),
+	})
+	return s.save()
+}
+
+// Menambahkan catatan audit ke rantai hash dengan nomor urut berikutnya; pemanggil harus memegang s.mu
+func (s *Store) appendAudit(e AuditEntry) {
+	e.Seq = int64(len(s.data.Audit)) + 1
+	if n := len(s.data.Audit); n > 0 {
+		e.PrevHash = s.data.Audit[n-1].Hash
+	}
+	e.Hash = e.computeHash()
+	s.data.Audit = append(s.data.Audit, e)
+}
+
+// Fungsi untuk memeriksa rantai hash catatan audit
+// Catatan lama sebelum rantai hash dipakai dilewati; mengembalikan jumlah catatan yang diperiksa dan yang dilewati
+func verifyAuditLog(entries []AuditEntry) (checked, legacy int, err error) {
+	prev := ""
+	for i, e := range entries {
+		if e.Hash == "" {
+			if checked > 0 {
+				return checked, legacy, fmt.Errorf("Catatan audit ke-%d tidak memiliki hash, kemungkinan disisipkan atau diubah", i+1)
+			}
+			legacy++
+			continue
+		}
+		if e.Seq != int64(i)+1 {
+			return checked, legacy, fmt.Errorf("Catatan audit ke-%d bernomor #%d, ada catatan yang dihapus atau disisipkan", i+1, e.Seq)
+		}
+		if e.PrevHash != prev {
+			return checked, legacy, fmt.Errorf("Rantai audit terputus di catatan #%d", e.Seq)
+		}
+		if e.computeHash() != e.Hash {
+			return checked, legacy, fmt.Errorf("Catatan audit #%d telah diubah", e.Seq)
+		}
+		prev = e.Hash
+		checked++
+	}
+	return checked, legacy, nil
+}
+
+// Fungsi untuk mencatat perubahan harga menu per item
+// Item baru dicatat tanpa harga lama dan item yang dihapus tanpa harga baru
+func auditMenuPrices(store *Store, actor Staff, source string, before, after []MenuItem) error {
+	prices := func(menu []MenuItem) map[string]Money {
+		m := map[string]Money{}
+		for _, item := range menu {
+			m[item.Name] = item.Price
+		}
+		return m
+	}
+	old, updated := prices(before), prices(after)
+	var names []string
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range updated {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	format := func(m map[string]Money, name string) string {
+		if price, ok := m[name]; ok {
+			return fmt.Sprintf("Rp%.2f", price)
+		}
+		return ""
+	}
+	for _, name := range names {
+		b, a := format(old, name), format(updated, name)
+		if b == a {
+			continue
+		}
+		if err := store.AuditChange(actor, "menu.price", name, b, a, source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Struct untuk keadaan pesanan yang dicatat sebelum dan sesudah pembatalan atau refund
+type orderAuditState struct {
+	Status   OrderStatus `json:"status"`
+	Total    Money       `json:"total"`
+	Refunded Money       `json:"refunded"`
+}
+
+// Fungsi untuk mengambil keadaan pesanan untuk catatan audit
+func auditOrderState(store *Store, id string) orderAuditState {
+	order, err := store.Order(id)
+	if err != nil {
+		return orderAuditState{}
+	}
+	state := orderAuditState{Status: order.Status, Total: order.Total}
+	for _, r := range store.Refunds() {
+		if r.OrderID == id {
+			state.Refunded += r.Amount
+		}
+	}
+	return state
+}
+
+// Fungsi untuk menjalankan sub-perintah "audit"; memerlukan PIN admin
+// "audit" tanpa sub-perintah sama dengan "audit list"
+func runAuditCommand(cfg *Config, store *Store, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+	switch action {
+	case "list":
+		fs := flag.NewFlagSet("audit list", flag.ContinueOnError)
+		dateFlag := fs.String("date", "", "tampilkan hanya tanggal tertentu (YYYY-MM-DD)")
+		kind := fs.String("action", "", "tampilkan hanya tindakan dengan awalan ini, mis. menu atau order.refund")
+		actor := fs.String("actor", "", "tampilkan hanya tindakan staf ini")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		var date time.Time
+		if *dateFlag != "" {
+			var err error
+			if date, err = time.ParseInLocation("2006-01-02", *dateFlag, time.Local); err != nil {
+				return fmt.Errorf("Format tanggal harus YYYY-MM-DD")
+			}
+		}
+		if _, err := requireAdmin(cfg, store); err != nil {
+			return err
+		}
+		for _, e := range store.AuditLog() {
+			if !date.IsZero() && !sameDay(e.At.Local(), date) {
+				continue
+			}
+			if !strings.HasPrefix(e.Action, *kind) || (*actor != "" && !strings.EqualFold(e.Name, *actor) && e.StaffID != *actor) {
+				continue
+			}
+			name := e.Name
+			if name == "" {
+				name = "-"
+			}
+			fmt.Printf("%s  %-15s %-16s %s %s\n", e.At.Local().Format("2006-01-02 15:04:05"), name, e.Action, e.Target, e.Detail)
+			if e.Before != "" || e.After != "" {
+				fmt.Printf("    sebelum: %s\n    sesudah: %s\n", orDash(e.Before), orDash(e.After))
+			}
+		}
+	case "verify":
+		if _, err := requireAdmin(cfg, store); err != nil {
+			return err
+		}
+		entries := store.AuditLog()
+		checked, legacy, err := verifyAuditLog(entries)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d catatan audit utuh", checked)
+		if legacy > 0 {
+			fmt.Printf(", %d catatan lama tanpa hash dilewati", legacy)
+		}
+		fmt.Println()
+		if checked > 0 {
+			// Hash terakhir dicatat di luar aplikasi agar penghapusan catatan terbaru juga bisa dibuktikan
+			fmt.Println("Hash terakhir:", entries[len(entries)-1].Hash)
+		}
+	default:
+		return fmt.Errorf("Sub-perintah audit tidak dikenal: %s (list atau verify)", action)
+	}
+	return nil
+}
+
+// Fungsi untuk menampilkan "-" untuk nilai kosong
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,513 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Peran staf
+type Role string
+
+const (
+	RoleAdmin   Role = "admin"   // Boleh mengubah menu, refund, dan mengelola staf
+	RoleCashier Role = "cashier" // Hanya menerima pesanan dan pembayaran
+)
+
+// Struct untuk Staf yang boleh menggunakan aplikasi
+// PIN tidak disimpan, hanya hash dengan salt acak
+type Staff struct {
+	ID        string    `json:"id"`                 // ID unik staf
+	Name      string    `json:"name"`               // Nama staf, dipakai saat masuk
+	Role      Role      `json:"role"`               // Peran staf
+	PINSalt   string    `json:"pin_salt,omitempty"` // Salt untuk hash PIN
+	PINHash   string    `json:"pin_hash,omitempty"` // Hash SHA-256 dari salt dan PIN
+	CreatedAt time.Time `json:"created_at"`         // Waktu staf didaftarkan
+
+	PINChangedAt   time.Time `json:"pin_changed_at,omitzero"`   // Waktu PIN terakhir diganti
+	MustChangePIN  bool      `json:"must_change_pin,omitempty"` // PIN harus diganti saat masuk berikutnya
+	FailedAttempts int       `json:"failed_attempts,omitempty"` // Jumlah PIN salah berturut-turut
+	LockedUntil    time.Time `json:"locked_until,omitzero"`     // Staf tidak bisa masuk sampai waktu ini
+}
+
+// Struct untuk catatan audit
+// Mencatat siapa melakukan apa terhadap data apa
+// Setiap catatan memuat hash catatan sebelumnya sehingga catatan yang diubah atau dihapus terdeteksi oleh "audit verify"
+type AuditEntry struct {
+	Seq     int64     `json:"seq,omitempty"`    // Nomor urut catatan, kosong pada data lama
+	At      time.Time `json:"at"`               // Waktu tindakan
+	StaffID string    `json:"staff_id"`         // ID staf, kosong jika belum ada staf terdaftar
+	Name    string    `json:"name"`             // Nama staf saat tindakan dilakukan
+	Action  string    `json:"action"`           // Jenis tindakan, mis. "order.refund"
+	Target  string    `json:"target,omitempty"` // ID data yang terkena tindakan
+	Detail  string    `json:"detail,omitempty"` // Keterangan tambahan
+	Before  string    `json:"before,omitempty"` // Nilai sebelum tindakan, mis. harga lama
+	After   string    `json:"after,omitempty"`  // Nilai sesudah tindakan
+
+	PrevHash string `json:"prev_hash,omitempty"` // Hash catatan sebelumnya
+	Hash     string `json:"hash,omitempty"`      // Hash SHA-256 catatan ini beserta PrevHash
+}
+
+// Interface untuk memeriksa dan mengganti PIN staf
+// Diimplementasikan backend lokal dan klien server pusat
+type staffAuthenticator interface {
+	Login(name, pin string) (Staff, error)     // Memeriksa PIN staf; nama kosong untuk cek apakah PIN diperlukan
+	ChangePIN(id, oldPIN, newPIN string) error // Mengganti PIN staf sendiri
+}
+
+var (
+	errPINRequired  = errors.New("PIN diperlukan")
+	errInvalidPIN   = errors.New("Nama staf atau PIN salah")
+	errNotAdmin     = errors.New("Tindakan ini memerlukan PIN admin")
+	errPINReused    = errors.New("PIN baru tidak boleh sama dengan PIN lama")
+	errStaffExists  = errors.New("Nama staf sudah terdaftar")
+	errStaffMissing = errors.New("Staf tidak ditemukan")
+	errStaffLocked  = errors.New("Staf terkunci karena terlalu banyak PIN salah")
+	errLastAdmin    = errors.New("Admin terakhir tidak bisa dihapus")
+)
+
+// Fungsi untuk menghitung hash PIN dengan salt
+func hashPIN(salt, pin string) string {
+	sum := sha256.Sum256([]byte(salt + pin))
+	return hex.EncodeToString(sum[:])
+}
+
+// Memeriksa apakah PIN cocok dengan PIN staf
+func (st Staff) CheckPIN(pin string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashPIN(st.PINSalt, pin)), []byte(st.PINHash)) == 1
+}
+
+// Mengganti PIN staf dengan salt baru
+func (st *Staff) setPIN(pin string) {
+	salt := make([]byte, 8)
+	rand.Read(salt)
+	st.PINSalt = hex.EncodeToString(salt)
+	st.PINHash = hashPIN(st.PINSalt, pin)
+	st.PINChangedAt = time.Now()
+}
+
+// Memeriksa apakah PIN staf sudah melewati masa berlaku
+func (st Staff) PINExpired(policy SecurityConfig) bool {
+	if policy.PINRotationDays <= 0 {
+		return false
+	}
+	changed := st.PINChangedAt
+	if changed.IsZero() {
+		changed = st.CreatedAt
+	}
+	return time.Since(changed) > time.Duration(policy.PINRotationDays)*24*time.Hour
+}
+
+// Memvalidasi format PIN sesuai kebijakan
+func (policy SecurityConfig) ValidatePIN(pin string) error {
+	valid := len(pin) >= policy.PINMinLength && len(pin) <= policy.PINMaxLength
+	for _, r := range pin {
+		valid = valid && unicode.IsDigit(r)
+	}
+	if !valid {
+		return fmt.Errorf("PIN harus %d sampai %d digit angka", policy.PINMinLength, policy.PINMaxLength)
+	}
+	return nil
+}
+
+// Mendaftarkan staf baru dengan PIN
+func (s *Store) AddStaff(name string, role Role, pin string, policy SecurityConfig) (Staff, error) {
+	if err := policy.ValidatePIN(pin); err != nil {
+		return Staff{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findStaff(name) != nil {
+		return Staff{}, errStaffExists
+	}
+	st := Staff{ID: newID("STF"), Name: name, Role: role, CreatedAt: time.Now()}
+	st.setPIN(pin)
+	s.data.Staff = append(s.data.Staff, st)
+	return st, s.save()
+}
+
+// Menghapus staf
+func (s *Store) RemoveStaff(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	admins := 0
+	for _, st := range s.data.Staff {
+		if st.Role == RoleAdmin {
+			admins++
+		}
+	}
+	for i, st := range s.data.Staff {
+		if st.ID == id {
+			if st.Role == RoleAdmin && admins == 1 {
+				return errLastAdmin
+			}
+			s.data.Staff = append(s.data.Staff[:i], s.data.Staff[i+1:]...)
+			return s.save()
+		}
+	}
+	return errStaffMissing
+}
+
+// Mengambil salinan seluruh staf
+func (s *Store) Staff() []Staff {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Staff(nil), s.data.Staff...)
+}
+
+// Mencari pointer staf berdasarkan ID atau nama; pemanggil harus memegang s.mu
+func (s *Store) findStaff(key string) *Staff {
+	for i := range s.data.Staff {
+		if s.data.Staff[i].ID == key || strings.EqualFold(s.data.Staff[i].Name, strings.TrimSpace(key)) {
+			return &s.data.Staff[i]
+		}
+	}
+	return nil
+}
+
+// Memeriksa nama dan PIN staf
+// Jika belum ada staf terdaftar, akses diberikan tanpa PIN agar aplikasi tetap bisa dipakai saat pertama kali
+// PIN salah berturut-turut sebanyak batas kebijakan mengunci staf selama waktu lockout
+func (s *Store) Authenticate(name, pin string, policy SecurityConfig) (Staff, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.data.Staff) == 0 {
+		return Staff{}, nil
+	}
+	if name == "" {
+		return Staff{}, errPINRequired
+	}
+	st := s.findStaff(name)
+	if st == nil {
+		return Staff{}, errInvalidPIN
+	}
+	if err := s.checkStaffPIN(st, pin, policy); err != nil {
+		return Staff{}, err
+	}
+	result := *st
+	result.MustChangePIN = st.MustChangePIN || st.PINExpired(policy)
+	return result, nil
+}
+
+// Memeriksa PIN staf dengan batas percobaan; pemanggil harus memegang s.mu
+// PIN salah berturut-turut sebanyak MaxFailedAttempts mengunci staf dan dicatat di audit sebagai "staff.locked"
+func (s *Store) checkStaffPIN(st *Staff, pin string, policy SecurityConfig) error {
+	if time.Now().Before(st.LockedUntil) {
+		return fmt.Errorf("%w sampai %s", errStaffLocked, st.LockedUntil.Local().Format("15:04"))
+	}
+	if !st.CheckPIN(pin) {
+		st.FailedAttempts++
+		err := errInvalidPIN
+		if policy.MaxFailedAttempts > 0 && st.FailedAttempts >= policy.MaxFailedAttempts {
+			st.FailedAttempts = 0
+			st.LockedUntil = time.Now().Add(time.Duration(policy.LockoutMinutes) * time.Minute)
+			s.appendAudit(AuditEntry{At: time.Now(), Action: "staff.locked", Target: st.ID, Detail: st.Name})
+			err = fmt.Errorf("%w sampai %s", errStaffLocked, st.LockedUntil.Local().Format("15:04"))
+		}
+		if saveErr := s.save(); saveErr != nil {
+			return saveErr
+		}
+		return err
+	}
+	if st.FailedAttempts > 0 {
+		st.FailedAttempts = 0
+		return s.save()
+	}
+	return nil
+}
+
+// Mengganti PIN staf setelah memeriksa PIN lama, dengan batas percobaan yang sama seperti saat masuk
+func (s *Store) ChangePIN(id, oldPIN, newPIN string, policy SecurityConfig) error {
+	if err := policy.ValidatePIN(newPIN); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.findStaff(id)
+	if st == nil {
+		return errStaffMissing
+	}
+	if err := s.checkStaffPIN(st, oldPIN, policy); err != nil {
+		return err
+	}
+	if oldPIN == newPIN {
+		return errPINReused
+	}
+	st.setPIN(newPIN)
+	st.MustChangePIN = false
+	return s.save()
+}
+
+// Mengatur ulang PIN staf oleh admin
+// PIN sementara harus diganti staf saat masuk berikutnya, dan kunci staf dibuka
+func (s *Store) ResetPIN(id, tempPIN string, policy SecurityConfig) error {
+	if err := policy.ValidatePIN(tempPIN); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.findStaff(id)
+	if st == nil {
+		return errStaffMissing
+	}
+	st.setPIN(tempPIN)
+	st.MustChangePIN = true
+	st.FailedAttempts, st.LockedUntil = 0, time.Time{}
+	return s.save()
+}
+
+// Membuka kunci staf tanpa mengganti PIN
+func (s *Store) UnlockStaff(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.findStaff(id)
+	if st == nil {
+		return errStaffMissing
+	}
+	st.FailedAttempts, st.LockedUntil = 0, time.Time{}
+	return s.save()
+}
+
+// Menambahkan catatan audit
+func (s *Store) Audit(actor Staff, action, target, detail string) error {
+	return s.AuditChange(actor, action, target, nil, nil, detail)
+}
+
+// Mengambil salinan seluruh catatan audit
+func (s *Store) AuditLog() []AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]AuditEntry(nil), s.data.Audit...)
+}
+
+// Fungsi untuk membaca PIN tanpa menampilkannya di layar
+func readPIN(prompt string) string {
+	if isTerminal(os.Stdin) {
+		if _, err := stty("-echo"); err == nil {
+			defer func() {
+				stty("echo")
+				fmt.Println()
+			}()
+		}
+	}
+	activeRecorder.Secret()
+	return readLine(prompt)
+}
+
+// Fungsi untuk meminta nama dan PIN staf sampai benar
+// Staf kosong dikembalikan jika belum ada staf terdaftar
+// Jika PIN harus diganti (direset admin atau kedaluwarsa), staf diminta membuat PIN baru
+func login(auth staffAuthenticator, prompt string) (Staff, error) {
+	staff, err := auth.Login("", "")
+	if !errors.Is(err, errPINRequired) {
+		return staff, err
+	}
+	fmt.Println(prompt)
+	for attempt := 0; attempt < 3; attempt++ {
+		activeRecorder.Secret()
+		name := readLine("Nama staf:")
+		pin := readPIN("PIN:")
+		staff, err = auth.Login(name, pin)
+		if errors.Is(err, errInvalidPIN) {
+			fmt.Println("Nama staf atau PIN salah. Coba lagi.")
+			continue
+		}
+		if err != nil {
+			return Staff{}, err
+		}
+		if staff.MustChangePIN {
+			if err := promptNewPIN(auth, staff, pin); err != nil {
+				return Staff{}, err
+			}
+		}
+		return staff, nil
+	}
+	return Staff{}, errInvalidPIN
+}
+
+// Fungsi untuk meminta staf mengganti PIN
+func promptNewPIN(auth staffAuthenticator, staff Staff, oldPIN string) error {
+	fmt.Println("PIN harus diganti sebelum melanjutkan.")
+	for attempt := 0; attempt < 3; attempt++ {
+		pin := readPIN("PIN baru:")
+		if readPIN("Ulangi PIN baru:") != pin {
+			fmt.Println("PIN tidak sama. Coba lagi.")
+			continue
+		}
+		err := auth.ChangePIN(staff.ID, oldPIN, pin)
+		if err == nil {
+			fmt.Println("PIN berhasil diganti.")
+			return nil
+		}
+		fmt.Println(err)
+	}
+	return errors.New("PIN belum diganti")
+}
+
+// Fungsi untuk membuka akses admin dengan PIN
+// Digunakan sebelum tindakan yang memerlukan admin seperti refund dan perubahan menu
+func requireAdmin(cfg *Config, store *Store) (Staff, error) {
+	staff, err := login(newLocalBackend(cfg, store), "Masukkan PIN admin.")
+	if err != nil {
+		return Staff{}, err
+	}
+	if staff.ID != "" && staff.Role != RoleAdmin {
+		return Staff{}, errNotAdmin
+	}
+	return staff, nil
+}
+
+// Fungsi untuk menjalankan sub-perintah "staff"
+func runStaffCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: staff add|list|remove <id>|unlock <id>|reset-pin <id>|change-pin")
+	}
+	policy := cfg.Security
+	switch args[0] {
+	case "add":
+		fs := flag.NewFlagSet("staff add", flag.ContinueOnError)
+		name := fs.String("name", "", "nama staf")
+		role := fs.String("role", string(RoleCashier), "peran staf: admin atau cashier")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *name == "" {
+			return fmt.Errorf("-name wajib diisi")
+		}
+		if Role(*role) != RoleAdmin && Role(*role) != RoleCashier {
+			return fmt.Errorf("-role harus admin atau cashier")
+		}
+		if len(store.Staff()) == 0 && Role(*role) != RoleAdmin {
+			return fmt.Errorf("Staf pertama harus admin")
+		}
+		admin, err := requireAdmin(cfg, store)
+		if err != nil {
+			return err
+		}
+		pin := readPIN("PIN baru untuk " + *name + ":")
+		if readPIN("Ulangi PIN:") != pin {
+			return fmt.Errorf("PIN tidak sama")
+		}
+		st, err := store.AddStaff(*name, Role(*role), pin, policy)
+		if err != nil {
+			return err
+		}
+		if admin.ID == "" {
+			admin = st // Admin pertama mendaftarkan dirinya sendiri
+		}
+		fmt.Println("Staf terdaftar dengan ID", st.ID)
+		return store.Audit(admin, "staff.add", st.ID, fmt.Sprintf("%s (%s)", st.Name, st.Role))
+	case "list":
+		for _, st := range store.Staff() {
+			status := ""
+			switch {
+			case time.Now().Before(st.LockedUntil):
+				status = "terkunci sampai " + st.LockedUntil.Local().Format("15:04")
+			case st.MustChangePIN || st.PINExpired(policy):
+				status = "harus ganti PIN"
+			}
+			fmt.Printf("%s  %-20s %-8s %s\n", st.ID, st.Name, st.Role, status)
+		}
+	case "remove", "unlock", "reset-pin":
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: staff %s <id>", args[0])
+		}
+		id := args[1]
+		admin, err := requireAdmin(cfg, store)
+		if err != nil {
+			return err
+		}
+		switch args[0] {
+		case "remove":
+			err = store.RemoveStaff(id)
+		case "unlock":
+			err = store.UnlockStaff(id)
+		case "reset-pin":
+			pin := readPIN("PIN sementara:")
+			if readPIN("Ulangi PIN sementara:") != pin {
+				return fmt.Errorf("PIN tidak sama")
+			}
+			err = store.ResetPIN(id, pin, policy)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println("Staf", id, "berhasil diperbarui:", args[0])
+		return store.Audit(admin, "staff."+args[0], id, "")
+	case "change-pin":
+		name := readLine("Nama staf:")
+		oldPIN := readPIN("PIN lama:")
+		staff, err := store.Authenticate(name, oldPIN, policy)
+		if err != nil {
+			return err
+		}
+		if err := promptNewPIN(newLocalBackend(cfg, store), staff, oldPIN); err != nil {
+			return err
+		}
+		return store.Audit(staff, "staff.change-pin", staff.ID, "")
+	default:
+		return fmt.Errorf("Sub-perintah staff tidak dikenal: %s", args[0])
+	}
+	return nil
+}
+
+// POST /api/v1/terminal/login
+// Terminal mengirim nama dan PIN kasir; respons berisi staf tanpa data PIN
+func (s *Server) handleTerminalLogin(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+		PIN  string `json:"pin"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Body harus berisi field name dan pin")
+		return
+	}
+	staff, err := s.store.Authenticate(body.Name, body.PIN, s.cfg.Security)
+	switch {
+	case errors.Is(err, errPINRequired), errors.Is(err, errInvalidPIN):
+		writeError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, errStaffLocked):
+		writeError(w, http.StatusLocked, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, Staff{ID: staff.ID, Name: staff.Name, Role: staff.Role, MustChangePIN: staff.MustChangePIN})
+	}
+}
+
+// POST /api/v1/terminal/pin
+// Kasir mengganti PIN dari terminal
+func (s *Server) handleTerminalChangePIN(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		StaffID string `json:"staff_id"`
+		OldPIN  string `json:"old_pin"`
+		NewPIN  string `json:"new_pin"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.StaffID == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi staff_id, old_pin, dan new_pin")
+		return
+	}
+	err := s.store.ChangePIN(body.StaffID, body.OldPIN, body.NewPIN, s.cfg.Security)
+	switch {
+	case errors.Is(err, errInvalidPIN):
+		writeError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, errStaffLocked):
+		writeError(w, http.StatusLocked, err.Error())
+	case errors.Is(err, errStaffMissing):
+		writeError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
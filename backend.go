@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Interface untuk sumber data alur kasir
+// Diimplementasikan oleh penyimpanan lokal dan oleh klien server pusat (mode terminal)
+type CashierBackend interface {
+	Menu() (*Restaurant, error)                           // Mengambil menu yang berlaku
+	CustomerByPhone(phone string) (Customer, error)       // Mencari pelanggan member
+	AddCustomer(c Customer) (Customer, error)             // Mendaftarkan pelanggan baru
+	DrawerDenominations() []int                           // Pecahan yang tersedia di laci kasir
+	Checkout(orders []Order, payment Payment) error       // Menyimpan pesanan beserta pembayarannya
+	EmailReceipt(paymentID, to string) error              // Memasukkan struk ke antrean email
+	SaveDrafts(orders []Order) error                      // Menyimpan pesanan yang belum dibayar sebagai draf
+	TableTab(table string) (Order, error)                 // Tab meja yang masih terbuka
+	ResumeHeldOrder(label string) (Order, error)          // Mengambil pesanan yang ditahan kasir lalu melepasnya dari daftar draf
+	ReservationDeposit(table string) (Reservation, error) // Deposit reservasi yang belum dipakai untuk meja yang tamunya sudah datang
+	WifiVoucher(paymentID string) (string, error)         // Kode Wi-Fi tamu untuk struk pembayaran
+	UpcomingReservations() ([]Reservation, error)         // Reservasi hari ini yang tamunya belum datang
+	LearnAlias(alias, item string) error                  // Menyimpan alias dari koreksi salah ketik yang dikonfirmasi kasir
+	GiftVoucher(code string) (GiftVoucher, error)         // Voucher hadiah beserta sisa saldonya
+	StockAlerts() ([]StockAlert, error)                   // Bahan dan item menu yang stoknya menipis
+	staffAuthenticator                                    // Masuk dan ganti PIN kasir
+}
+
+// Struct untuk backend lokal
+// Menyimpan data langsung ke file data di komputer kasir
+type localBackend struct {
+	cfg   *Config
+	store *Store
+}
+
+// Fungsi untuk membuat backend lokal
+func newLocalBackend(cfg *Config, store *Store) *localBackend {
+	return &localBackend{cfg: cfg, store: store}
+}
+
+func (b *localBackend) Menu() (*Restaurant, error) {
+	restaurant := b.store.Menu()
+	restaurant.Limits = b.cfg.OrderLimits
+	restaurant.PriceGuards, restaurant.DeliveryFee = b.cfg.PriceGuards, b.cfg.Cashier.DeliveryFee
+	return restaurant, nil
+}
+
+func (b *localBackend) CustomerByPhone(phone string) (Customer, error) {
+	return b.store.CustomerByPhone(phone)
+}
+
+func (b *localBackend) AddCustomer(c Customer) (Customer, error) {
+	return b.store.AddCustomer(c)
+}
+
+func (b *localBackend) DrawerDenominations() []int {
+	return b.store.DrawerDenominations(b.cfg)
+}
+
+func (b *localBackend) Login(name, pin string) (Staff, error) {
+	return b.store.Authenticate(name, pin, b.cfg.Security)
+}
+
+func (b *localBackend) ChangePIN(id, oldPIN, newPIN string) error {
+	return b.store.ChangePIN(id, oldPIN, newPIN, b.cfg.Security)
+}
+
+func (b *localBackend) SaveDrafts(orders []Order) error {
+	return b.store.SaveDrafts(orders)
+}
+
+func (b *localBackend) TableTab(table string) (Order, error) {
+	return b.store.TableTab(table)
+}
+
+func (b *localBackend) ResumeHeldOrder(label string) (Order, error) {
+	return b.store.ResumeHeldOrder(label)
+}
+
+func (b *localBackend) ReservationDeposit(table string) (Reservation, error) {
+	return b.store.DepositForTable(table)
+}
+
+func (b *localBackend) UpcomingReservations() ([]Reservation, error) {
+	return b.store.UpcomingReservations(time.Now()), nil
+}
+
+func (b *localBackend) LearnAlias(alias, item string) error {
+	return addMenuAlias(b.store, b.store.Menu(), alias, item, true)
+}
+
+func (b *localBackend) GiftVoucher(code string) (GiftVoucher, error) {
+	return b.store.GiftVoucher(code)
+}
+
+func (b *localBackend) StockAlerts() ([]StockAlert, error) {
+	return b.store.StockAlerts(b.cfg.Inventory.LowPortions), nil
+}
+
+func (b *localBackend) WifiVoucher(paymentID string) (string, error) {
+	return issueWifiCode(b.cfg.Wifi, b.store, paymentID)
+}
+
+func (b *localBackend) Checkout(orders []Order, payment Payment) error {
+	return checkout(b.cfg, b.store, orders, payment)
+}
+
+// Struk dimasukkan ke antrean lalu dikirim di goroutine lain agar kasir tidak menunggu server SMTP
+// Email yang gagal tetap di antrean untuk dicoba lagi oleh "serve" atau "email retry"
+func (b *localBackend) EmailReceipt(paymentID, to string) error {
+	if _, err := queueReceiptEmail(b.cfg, b.store, paymentID, to); err != nil {
+		return err
+	}
+	go deliverQueuedEmails(b.cfg, b.store)
+	return nil
+}
+
+// Fungsi untuk menyimpan pesanan beserta pembayarannya ke penyimpanan
+// Digunakan oleh backend lokal dan oleh server saat menerima checkout dari terminal
+// Nomor antrean dan perkiraan waktu siap diberikan di sini dan ditulis ke slice orders milik pemanggil
+// Checkout yang gagal di tengah jalan boleh diulang: pesanan dan pembayaran yang sudah tersimpan dipakai apa adanya
+func checkout(cfg *Config, store *Store, orders []Order, payment Payment) error {
+	for i := range orders {
+		if stored, err := store.Order(orders[i].ID); err == nil {
+			orders[i] = stored
+		}
+	}
+	// Saldo voucher sudah terpotong jika pembayarannya tersimpan pada percobaan sebelumnya
+	if _, paid, _ := store.IdempotentPayment("", payment.ID); !paid {
+		if err := checkGiftVoucher(store, payment); err != nil {
+			return err
+		}
+	}
+	if err := store.AssignQueueNumbers(orders); err != nil {
+		return err
+	}
+	store.EstimateReadyTimes(orders, cfg.Kitchen, time.Now())
+	for _, order := range orders {
+		if err := store.SaveOrder(order); err != nil {
+			return err
+		}
+	}
+	if err := store.SavePayment(payment); err != nil {
+		return err
+	}
+	// Pesanan terbuka yang dibayar tidak lagi menjadi draf
+	if err := store.RemoveDrafts(payment.OrderIDs); err != nil {
+		return err
+	}
+	cashier := Staff{ID: payment.CashierID}
+	for _, st := range store.Staff() {
+		if st.ID == payment.CashierID {
+			cashier = st
+		}
+	}
+	// Pelanggaran aturan pesanan yang di-override kasir dicatat per pesanan
+	for _, order := range orders {
+		if len(order.Overrides) == 0 {
+			continue
+		}
+		if err := store.Audit(cashier, "order.override", order.ID, strings.Join(order.Overrides, "; ")); err != nil {
+			return err
+		}
+	}
+	return store.Audit(cashier, "payment", payment.ID, fmt.Sprintf("Rp%.2f untuk %s", payment.Amount, strings.Join(payment.OrderIDs, ", ")))
+}
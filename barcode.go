@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Panjang minimal kode barcode/PLU agar tidak tertukar dengan nomor urut menu
+const minItemCodeLength = 4
+
+// Fungsi untuk memeriksa kode barcode/PLU item: hanya angka dan cukup panjang
+// Scanner USB mengetik kode seperti keyboard, sehingga kode berupa huruf tidak dapat dibedakan dari nama item
+func validateItemCode(code string) error {
+	if len(code) < minItemCodeLength {
+		return fmt.Errorf("kode %q terlalu pendek, minimal %d angka", code, minItemCodeLength)
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("kode %q hanya boleh berisi angka", code)
+		}
+	}
+	return nil
+}
+
+// Mencari item menu dengan kode barcode/PLU yang sama persis
+// Item yang habis tetap dikembalikan bersama errItemSoldOut agar bisa dicarikan pengganti
+func (r *Restaurant) itemByCode(code string) (*MenuItem, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < minItemCodeLength {
+		return nil, errItemNotFound
+	}
+	for _, item := range r.Menu {
+		if item.Code != "" && item.Code == code {
+			if item.SoldOut {
+				return &item, errItemSoldOut
+			}
+			return &item, nil
+		}
+	}
+	return nil, errItemNotFound
+}
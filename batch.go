@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct untuk Permintaan pesanan non-interaktif
+// Dibaca dari file JSON/CSV atau dari body API
+type OrderRequest struct {
+	CustomerID string             `json:"customer_id,omitempty"`  // ID pelanggan member, boleh kosong
+	Table      string             `json:"table,omitempty"`        // Nomor meja, boleh kosong
+	Type       OrderType          `json:"type,omitempty"`         // dine_in, takeaway, atau delivery; boleh kosong
+	Address    string             `json:"address,omitempty"`      // Alamat pengantaran untuk delivery
+	Fee        Money              `json:"delivery_fee,omitempty"` // Ongkos kirim untuk delivery
+	Items      []OrderRequestItem `json:"items"`                  // Daftar item yang dipesan
+}
+
+// Struct untuk satu item pada permintaan pesanan
+type OrderRequestItem struct {
+	Name      string   `json:"name"`                // Nama item menu
+	Qty       int      `json:"qty"`                 // Jumlah yang dipesan
+	Modifiers []string `json:"modifiers,omitempty"` // Nama varian/tambahan, mis. ["Pedas", "Extra Telur"]
+}
+
+// Fungsi untuk menyusun dan menghitung harga pesanan dari permintaan tanpa prompt
+// Seluruh kesalahan validasi dikumpulkan agar bisa diperbaiki sekaligus
+func buildOrder(restaurant *Restaurant, req OrderRequest) (Order, error) {
+	order := Order{ID: orderIDs.NewID("ORD"), Status: OrderPending, CustomerID: req.CustomerID, Table: req.Table, CreatedAt: time.Now()}
+	order.Type, order.DeliveryAddress, order.DeliveryFee = req.Type, req.Address, req.Fee
+	var errs []error
+	if err := validateOrderType(order); err != nil {
+		errs = append(errs, err)
+	} else if err := checkDeliveryFee(restaurant, order); err != nil {
+		errs = append(errs, err)
+	}
+	if len(req.Items) == 0 {
+		errs = append(errs, errors.New("Pesanan tidak berisi item"))
+	}
+	for i, reqItem := range req.Items {
+		menuItem, err := validateOrderItem(restaurant, strings.ToLower(strings.TrimSpace(reqItem.Name)))
+		if errors.Is(err, errItemSoldOut) {
+			msg := fmt.Sprintf("Item %d: %s sedang habis", i+1, menuItem.Name)
+			if suggestions := suggestSubstitutes(restaurant, *menuItem); len(suggestions) > 0 {
+				msg += ", pengganti: " + suggestions[0].Name
+			}
+			errs = append(errs, errors.New(msg))
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Item %d: %q tidak ada di menu", i+1, reqItem.Name))
+			continue
+		}
+		if reqItem.Qty <= 0 {
+			errs = append(errs, fmt.Errorf("Item %d: jumlah %s harus lebih dari 0", i+1, menuItem.Name))
+			continue
+		}
+		modifiers, err := resolveModifiers(*menuItem, reqItem.Modifiers)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Item %d: %w", i+1, err))
+			continue
+		}
+		line := OrderLine{Item: *menuItem, Qty: reqItem.Qty, Modifiers: modifiers}
+		order.Lines = append(order.Lines, line)
+		order.Total += line.Subtotal()
+	}
+	if len(errs) == 0 {
+		if err := restaurant.Limits.Check(order); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return Order{}, errors.Join(errs...)
+	}
+	return order, nil
+}
+
+// Fungsi untuk mencocokkan nama modifier dengan varian dan tambahan item
+// Grup varian yang tidak disebut memakai opsi pertama
+func resolveModifiers(item MenuItem, names []string) ([]Modifier, error) {
+	used := make([]bool, len(names))
+	match := func(name string) int {
+		for i, n := range names {
+			if !used[i] && strings.EqualFold(strings.TrimSpace(n), name) {
+				used[i] = true
+				return i
+			}
+		}
+		return -1
+	}
+
+	var modifiers []Modifier
+	for _, group := range item.Variants {
+		if len(group.Options) == 0 {
+			continue
+		}
+		chosen := -1
+		for j, opt := range group.Options {
+			if match(opt.Name) >= 0 {
+				if chosen >= 0 {
+					return nil, fmt.Errorf("%s hanya boleh satu pilihan %s", item.Name, group.Name)
+				}
+				chosen = j
+			}
+		}
+		if chosen < 0 {
+			chosen = 0
+		}
+		opt := group.Options[chosen]
+		modifiers = append(modifiers, Modifier{Group: group.Name, Name: opt.Name, Price: opt.PriceDelta})
+	}
+	for _, addOn := range item.AddOns {
+		if match(addOn.Name) >= 0 {
+			modifiers = append(modifiers, Modifier{Group: addOnGroup, Name: addOn.Name, Price: addOn.Price})
+		}
+	}
+	for i, n := range names {
+		if !used[i] {
+			return nil, fmt.Errorf("pilihan %q tidak tersedia untuk %s", n, item.Name)
+		}
+	}
+	return modifiers, nil
+}
+
+// Fungsi untuk membaca permintaan pesanan dari JSON atau CSV
+// Format CSV: judul kolom "name,qty,modifiers", modifier dipisahkan dengan "|"
+func parseOrderRequest(raw []byte, format string) (OrderRequest, error) {
+	var req OrderRequest
+	if format == "" {
+		format = "csv"
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+			format = "json"
+		}
+	}
+	switch format {
+	case "json":
+		// Terima objek lengkap atau langsung daftar item
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+			err := json.Unmarshal(raw, &req.Items)
+			return req, err
+		}
+		err := json.Unmarshal(raw, &req)
+		return req, err
+	case "csv":
+		r := csv.NewReader(bytes.NewReader(raw))
+		r.FieldsPerRecord = -1
+		rows, err := r.ReadAll()
+		if err != nil {
+			return req, err
+		}
+		for n, row := range rows {
+			if n == 0 && len(row) > 0 && strings.EqualFold(strings.TrimSpace(row[0]), "name") {
+				continue
+			}
+			if len(row) < 2 {
+				return req, fmt.Errorf("Baris %d: harus berisi nama dan jumlah", n+1)
+			}
+			qty, err := strconv.Atoi(strings.TrimSpace(row[1]))
+			if err != nil {
+				return req, fmt.Errorf("Baris %d: jumlah tidak valid", n+1)
+			}
+			item := OrderRequestItem{Name: strings.TrimSpace(row[0]), Qty: qty}
+			if len(row) > 2 && strings.TrimSpace(row[2]) != "" {
+				item.Modifiers = strings.Split(row[2], "|")
+			}
+			req.Items = append(req.Items, item)
+		}
+		return req, nil
+	default:
+		return req, fmt.Errorf("Format %q tidak didukung, gunakan json atau csv", format)
+	}
+}
+
+// Fungsi untuk menjalankan "order take" tanpa prompt
+// Pesanan dibaca dari file (--from) atau stdin, lalu total dan struk ter-encode ditampilkan
+func runBatchOrder(backend CashierBackend, pricing Pricing, signingKey string, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("order take", flag.ContinueOnError)
+	from := fs.String("from", "-", "file pesanan JSON/CSV, \"-\" untuk stdin")
+	format := fs.String("format", "", "format input: json atau csv (default: deteksi otomatis)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var raw []byte
+	var err error
+	if *from == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		raw, err = os.ReadFile(*from)
+		if *format == "" {
+			switch strings.ToLower(filepath.Ext(*from)) {
+			case ".json":
+				*format = "json"
+			case ".csv":
+				*format = "csv"
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	req, err := parseOrderRequest(raw, *format)
+	if err != nil {
+		return fmt.Errorf("Gagal membaca pesanan: %w", err)
+	}
+
+	restaurant, err := backend.Menu()
+	if err != nil {
+		return err
+	}
+	order, err := buildOrder(restaurant, req)
+	if err != nil {
+		return err
+	}
+	pricing.Apply(&order)
+	fmt.Fprintln(out, "Pesanan:")
+	for _, line := range order.Lines {
+		fmt.Fprintf(out, "- %s x%d: Rp%.2f\n", line.Label(), line.Qty, line.Subtotal())
+		writePriceRuleNote(out, line)
+	}
+	total, rounding := pricing.RoundPayment(order.Total)
+	writePriceBreakdown(out, []Order{order}, rounding)
+	fmt.Fprintf(out, "Total Pesanan: Rp%.2f\n", total)
+	fmt.Fprintln(out, "Pesanan (encoded base64):", encodeSignedOrder(order, signingKey))
+	return nil
+}
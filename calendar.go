@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Struct untuk Konfigurasi kalender reservasi
+// Feed ICS dilayani server di /calendar/reservations.ics?key=<API key dengan scope calendar:read>.
+// Google Calendar dan kalender ponsel dapat berlangganan feed tersebut ("Tambah dari URL"),
+// atau reservasi didorong langsung ke koleksi CalDAV dengan "reservation push"
+type CalendarConfig struct {
+	DurationMinutes int    `json:"duration_minutes"` // Lama acara reservasi di kalender
+	CalDAVURL       string `json:"caldav_url"`       // Alamat koleksi CalDAV, mis. "https://apidata.googleusercontent.com/caldav/v2/<id>/events"
+	Username        string `json:"username"`         // Pengguna CalDAV untuk Basic auth, kosong jika memakai token
+	Password        string `json:"password"`         // Kata sandi aplikasi CalDAV, atau token OAuth jika username kosong
+}
+
+// Fungsi untuk meng-escape teks sesuai RFC 5545
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// Fungsi untuk menulis satu baris ICS, dilipat setiap 75 byte sesuai RFC 5545
+func writeICSLine(w io.Writer, line string) {
+	for len(line) > 75 {
+		cut := 75
+		// Jangan memotong di tengah karakter UTF-8
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		fmt.Fprintf(w, "%s\r\n", line[:cut])
+		line = " " + line[cut:]
+	}
+	fmt.Fprintf(w, "%s\r\n", line)
+}
+
+// Fungsi untuk menulis reservasi sebagai VEVENT
+// Reservasi yang dibatalkan atau tidak datang tetap ditulis dengan STATUS:CANCELLED agar kalender menghapusnya
+func writeReservationEvent(w io.Writer, r Reservation, duration time.Duration, now time.Time) {
+	const stamp = "20060102T150405Z"
+	status := "CONFIRMED"
+	if r.Status == ReservationCancelled || r.Status == ReservationNoShow {
+		status = "CANCELLED"
+	}
+	summary := fmt.Sprintf("Reservasi %s (%d orang)", r.Name, r.Guests)
+	if r.Table != "" {
+		summary += " meja " + r.Table
+	}
+	var desc []string
+	if r.Phone != "" {
+		desc = append(desc, "HP: "+r.Phone)
+	}
+	if r.Deposit > 0 {
+		paid := "belum dibayar"
+		if !r.DepositPaidAt.IsZero() {
+			paid = "lunas"
+		}
+		desc = append(desc, fmt.Sprintf("Deposit Rp%.0f %s", r.Deposit, paid))
+	}
+	desc = append(desc, "Status: "+string(r.Status), "ID: "+r.ID)
+
+	writeICSLine(w, "BEGIN:VEVENT")
+	writeICSLine(w, "UID:"+r.ID+"@tugaskedua")
+	writeICSLine(w, "DTSTAMP:"+now.UTC().Format(stamp))
+	writeICSLine(w, "DTSTART:"+r.At.UTC().Format(stamp))
+	end := r.At.Add(duration)
+	if r.Minutes > 0 {
+		end = r.End()
+	}
+	writeICSLine(w, "DTEND:"+end.UTC().Format(stamp))
+	writeICSLine(w, "SUMMARY:"+icsEscape(summary))
+	writeICSLine(w, "DESCRIPTION:"+icsEscape(strings.Join(desc, "\n")))
+	writeICSLine(w, "STATUS:"+status)
+	writeICSLine(w, "END:VEVENT")
+}
+
+// Fungsi untuk menulis kalender ICS berisi reservasi yang dimulai sejak waktu tertentu
+func writeReservationCalendar(w io.Writer, reservations []Reservation, cfg CalendarConfig, from time.Time) {
+	duration := time.Duration(cfg.DurationMinutes) * time.Minute
+	now := time.Now()
+	writeICSLine(w, "BEGIN:VCALENDAR")
+	writeICSLine(w, "VERSION:2.0")
+	writeICSLine(w, "PRODID:-//tugaskedua//Reservasi//ID")
+	writeICSLine(w, "CALSCALE:GREGORIAN")
+	writeICSLine(w, "X-WR-CALNAME:Reservasi")
+	for _, r := range reservations {
+		if r.At.Before(from) {
+			continue
+		}
+		writeReservationEvent(w, r, duration, now)
+	}
+	writeICSLine(w, "END:VCALENDAR")
+}
+
+// Fungsi untuk mendorong setiap reservasi ke koleksi CalDAV sebagai satu file .ics
+// PUT ke alamat yang sama menimpa acara lama, sehingga perubahan status ikut tersinkron
+func pushReservationsCalDAV(cfg CalendarConfig, reservations []Reservation, from time.Time) (pushed int, err error) {
+	if cfg.CalDAVURL == "" {
+		return 0, fmt.Errorf("calendar.caldav_url belum diatur di konfigurasi")
+	}
+	client := &http.Client{Timeout: 15 * time.Second}
+	duration := time.Duration(cfg.DurationMinutes) * time.Minute
+	for _, r := range reservations {
+		if r.At.Before(from) {
+			continue
+		}
+		var body bytes.Buffer
+		writeICSLine(&body, "BEGIN:VCALENDAR")
+		writeICSLine(&body, "VERSION:2.0")
+		writeICSLine(&body, "PRODID:-//tugaskedua//Reservasi//ID")
+		writeReservationEvent(&body, r, duration, time.Now())
+		writeICSLine(&body, "END:VCALENDAR")
+
+		req, err := http.NewRequest(http.MethodPut, strings.TrimSuffix(cfg.CalDAVURL, "/")+"/"+r.ID+".ics", &body)
+		if err != nil {
+			return pushed, err
+		}
+		req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+		if cfg.Username != "" {
+			req.SetBasicAuth(cfg.Username, cfg.Password)
+		} else if cfg.Password != "" {
+			req.Header.Set("Authorization", "Bearer "+cfg.Password)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return pushed, fmt.Errorf("Server kalender tidak dapat dihubungi: %w", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return pushed, fmt.Errorf("Server kalender menolak reservasi %s: %s", r.ID, resp.Status)
+		}
+		pushed++
+	}
+	return pushed, nil
+}
+
+// GET /calendar/reservations.ics?key=
+// Aplikasi kalender tidak dapat mengirim header, sehingga API key dibaca dari query
+func (s *Server) handleReservationCalendar(w http.ResponseWriter, r *http.Request) {
+	presented := r.URL.Query().Get("key")
+	allowed := false
+	for _, key := range s.cfg.Server.APIKeys {
+		if presented != "" && subtle.ConstantTimeCompare([]byte(key.Key), []byte(presented)) == 1 && key.HasScope(scopeCalendarRead) {
+			allowed = true
+		}
+	}
+	if !allowed {
+		writeError(w, http.StatusUnauthorized, "API key tidak valid atau tidak memiliki scope "+scopeCalendarRead)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	// Reservasi seminggu terakhir ikut dikirim agar pembatalan yang baru terjadi tetap terlihat di kalender
+	writeReservationCalendar(w, s.store.Reservations(), s.cfg.Calendar, time.Now().AddDate(0, 0, -7))
+}
+
+// Fungsi untuk menjalankan "reservation ics" dan "reservation push"
+func runReservationCalendar(cfg *Config, store *Store, name string, args []string) error {
+	fs := flag.NewFlagSet("reservation "+name, flag.ContinueOnError)
+	fromFlag := fs.String("from", time.Now().Format("2006-01-02"), "reservasi mulai tanggal YYYY-MM-DD")
+	out := fs.String("out", "", "file tujuan ICS, kosong untuk layar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, err := time.ParseInLocation("2006-01-02", *fromFlag, time.Local)
+	if err != nil {
+		return fmt.Errorf("Format tanggal harus YYYY-MM-DD")
+	}
+	if name == "push" {
+		pushed, err := pushReservationsCalDAV(cfg.Calendar, store.Reservations(), from)
+		fmt.Printf("%d reservasi dikirim ke kalender\n", pushed)
+		return err
+	}
+	if *out == "" {
+		writeReservationCalendar(os.Stdout, store.Reservations(), cfg.Calendar, from)
+		return nil
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	writeReservationCalendar(f, store.Reservations(), cfg.Calendar, from)
+	fmt.Println("Kalender reservasi ditulis ke", *out)
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Pecahan rupiah default yang tersedia di laci kasir
+var defaultDenominations = []int{100000, 50000, 20000, 10000, 5000, 2000, 1000, 500}
+
+// Struct untuk satu pecahan pada rincian kembalian
+type ChangePiece struct {
+	Denomination int // Nilai pecahan, mis. 5000
+	Count        int // Jumlah lembar/keping
+}
+
+// Fungsi untuk memecah kembalian menjadi pecahan yang tersedia di laci
+// Mengembalikan rincian dengan jumlah lembar paling sedikit dan sisa yang tidak bisa dipecah
+func breakdownChange(change Money, denominations []int) ([]ChangePiece, int) {
+	amount := int((change + Rp/2) / Rp) // Pecahan dalam rupiah, sen dibulatkan
+	denoms := normalizeDenominations(denominations)
+	if amount <= 0 || len(denoms) == 0 {
+		return nil, amount
+	}
+
+	// Hitung dalam satuan FPB pecahan agar tabel tetap kecil
+	unit := denoms[0]
+	for _, d := range denoms[1:] {
+		unit = gcd(unit, d)
+	}
+	payable := amount - amount%unit
+	target := payable / unit
+
+	// Pemrograman dinamis: best[i] = jumlah lembar minimum untuk i satuan
+	const unreachable = -1
+	best := make([]int, target+1)
+	last := make([]int, target+1)
+	for i := 1; i <= target; i++ {
+		best[i] = unreachable
+		for _, d := range denoms {
+			u := d / unit
+			if u <= i && best[i-u] != unreachable && (best[i] == unreachable || best[i-u]+1 < best[i]) {
+				best[i] = best[i-u] + 1
+				last[i] = d
+			}
+		}
+	}
+
+	// Jika tidak bisa dipecah tepat, ambil nilai terbesar yang bisa dipecah
+	for target > 0 && best[target] == unreachable {
+		target--
+	}
+	counts := map[int]int{}
+	for i := target; i > 0; i -= last[i] / unit {
+		counts[last[i]]++
+	}
+
+	var pieces []ChangePiece
+	for _, d := range denoms {
+		if counts[d] > 0 {
+			pieces = append(pieces, ChangePiece{Denomination: d, Count: counts[d]})
+		}
+	}
+	return pieces, amount - target*unit
+}
+
+// Fungsi untuk FPB dua bilangan
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Fungsi untuk merapikan daftar pecahan: buang nilai tidak valid dan duplikat, urutkan menurun
+func normalizeDenominations(denominations []int) []int {
+	seen := map[int]bool{}
+	var denoms []int
+	for _, d := range denominations {
+		if d > 0 && !seen[d] {
+			seen[d] = true
+			denoms = append(denoms, d)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(denoms)))
+	return denoms
+}
+
+// Fungsi untuk membaca daftar pecahan dari teks, mis. "50000,20000,5000"
+func parseDenominations(text string) ([]int, error) {
+	var denoms []int
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		d, err := strconv.Atoi(part)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("Pecahan tidak valid: %s", part)
+		}
+		denoms = append(denoms, d)
+	}
+	if len(denoms) == 0 {
+		return nil, fmt.Errorf("Daftar pecahan kosong")
+	}
+	return normalizeDenominations(denoms), nil
+}
+
+// Fungsi untuk menampilkan saran rincian kembalian
+func printChangeBreakdown(change Money, denominations []int) {
+	pieces, rest := breakdownChange(change, denominations)
+	if len(pieces) == 0 && rest == 0 {
+		return
+	}
+	fmt.Println(tr("Saran pecahan kembalian:"))
+	for _, p := range pieces {
+		fmt.Printf("  Rp%d x %d\n", p.Denomination, p.Count)
+	}
+	if rest > 0 {
+		fmt.Print(tr("  Sisa Rp%d tidak bisa dipecah dengan pecahan yang tersedia\n", rest))
+	}
+}
+
+// Mengambil pecahan yang sedang tersedia di laci kasir
+// Pengaturan dari perintah "drawer set" didahulukan, lalu konfigurasi, lalu default
+func (s *Store) DrawerDenominations(cfg *Config) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.data.DrawerDenominations) > 0 {
+		return append([]int(nil), s.data.DrawerDenominations...)
+	}
+	return append([]int(nil), cfg.CashDrawer.Denominations...)
+}
+
+// Menyimpan pecahan yang sedang tersedia di laci kasir
+// Daftar kosong mengembalikan pengaturan ke konfigurasi
+func (s *Store) SetDrawerDenominations(denominations []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.DrawerDenominations = denominations
+	return s.save()
+}
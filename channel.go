@@ -0,0 +1,245 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// Dibayar lewat platform pemesanan online, dana diselesaikan oleh mitra setelah dipotong komisi
+const MethodChannel PaymentMethod = "channel"
+
+var errChannelType = errors.New("Jenis kanal tidak dikenal (pilih simulator)")
+
+// Interface untuk platform pemesanan online, mis. GoFood atau GrabFood
+type Channel interface {
+	Name() string                  // Nama kanal, dicatat di Order.Source
+	Poll() ([]ChannelOrder, error) // Mengambil pesanan baru yang masuk di platform
+	Accept(ref string) error       // Memberi tahu platform bahwa pesanan diterima dan sedang disiapkan
+}
+
+// Struct untuk Konfigurasi satu kanal pemesanan online
+type ChannelConfig struct {
+	Name              string  `json:"name"`               // Nama kanal, mis. "gofood"
+	Type              string  `json:"type"`               // Adapter yang dipakai: simulator
+	CommissionPercent float64 `json:"commission_percent"` // Komisi platform dari total pesanan, mis. 20
+	MarkupPercent     float64 `json:"markup_percent"`     // Kenaikan harga menu di platform untuk menutup komisi, mis. 15
+	PollSeconds       int     `json:"poll_seconds"`       // Interval mengambil pesanan baru dalam mode server
+	SimulatorOrders   int     `json:"simulator_orders"`   // Paling banyak pesanan simulasi per pengambilan
+}
+
+// Struct untuk pesanan dari platform sebelum diubah menjadi Order
+type ChannelOrder struct {
+	Ref      string             `json:"ref"`      // ID pesanan di platform
+	Customer string             `json:"customer"` // Nama pelanggan di platform
+	Items    []OrderRequestItem `json:"items"`
+	PlacedAt time.Time          `json:"placed_at"`
+}
+
+// Fungsi untuk membuat adapter kanal sesuai konfigurasi
+func newChannel(cfg ChannelConfig, restaurant *Restaurant) (Channel, error) {
+	switch cfg.Type {
+	case "simulator":
+		return newSimulatorChannel(cfg, restaurant), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errChannelType, cfg.Type)
+	}
+}
+
+// Struct untuk kanal simulasi yang membuat pesanan acak dari menu
+// Dipakai untuk mencoba alur pesanan online sebelum adapter platform sungguhan tersedia
+type simulatorChannel struct {
+	name  string
+	max   int
+	items []string
+}
+
+// Fungsi untuk membuat kanal simulasi dari item menu yang tersedia
+func newSimulatorChannel(cfg ChannelConfig, restaurant *Restaurant) *simulatorChannel {
+	c := &simulatorChannel{name: cfg.Name, max: cfg.SimulatorOrders}
+	for _, item := range restaurant.Menu {
+		if !item.SoldOut {
+			c.items = append(c.items, item.Name)
+		}
+	}
+	return c
+}
+
+func (c *simulatorChannel) Name() string { return c.name }
+
+func (c *simulatorChannel) Poll() ([]ChannelOrder, error) {
+	if len(c.items) == 0 {
+		return nil, nil
+	}
+	var orders []ChannelOrder
+	for range rand.IntN(c.max + 1) {
+		o := ChannelOrder{Ref: newID("SIM"), Customer: "Pelanggan simulasi", PlacedAt: time.Now()}
+		for range 1 + rand.IntN(3) {
+			o.Items = append(o.Items, OrderRequestItem{Name: c.items[rand.IntN(len(c.items))], Qty: 1 + rand.IntN(2)})
+		}
+		orders = append(orders, o)
+	}
+	return orders, nil
+}
+
+func (c *simulatorChannel) Accept(string) error { return nil }
+
+// Mencari pesanan yang sudah dibuat dari pesanan platform agar pengambilan ulang tidak membuat pesanan ganda
+func (s *Store) ChannelOrder(source, ref string) (Order, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range s.data.Orders {
+		if o.Source == source && o.SourceRef == ref {
+			return o, true
+		}
+	}
+	return Order{}, false
+}
+
+// Fungsi untuk mengubah pesanan platform menjadi Order yang sudah dibayar lewat platform
+// Harga menu dinaikkan sesuai markup kanal, lalu komisi dihitung dari total pesanan
+func convertChannelOrder(cfg *Config, restaurant *Restaurant, ch ChannelConfig, in ChannelOrder) (Order, Payment, error) {
+	order, err := buildOrder(restaurant, OrderRequest{Type: OrderTakeaway, Items: in.Items})
+	if err != nil {
+		return Order{}, Payment{}, err
+	}
+	order.Source, order.SourceRef = ch.Name, in.Ref
+	markup := 1 + ch.MarkupPercent/100
+	for i := range order.Lines {
+		l := &order.Lines[i]
+		l.Item.Price = l.Item.Price.MulRate(markup)
+		for j := range l.Modifiers {
+			l.Modifiers[j].Price = l.Modifiers[j].Price.MulRate(markup)
+		}
+	}
+	pricing := cfg.Pricing.Strategy()
+	pricing.Apply(&order)
+	order.Commission = order.Total.MulRate(ch.CommissionPercent / 100)
+
+	payment := Payment{ID: newID("PAY"), OrderIDs: []string{order.ID}, Method: MethodChannel, ProviderRef: ch.Name + ":" + in.Ref, PaidAt: time.Now()}
+	payment.Amount, payment.Rounding = pricing.RoundPayment(order.Total)
+	payment.Tendered = payment.Amount
+	return order, payment, nil
+}
+
+// Fungsi untuk mengambil pesanan baru dari satu kanal, menyimpannya, dan menerimanya di platform
+// Pesanan yang tidak bisa dibuat (mis. item habis) dilewati dan ditampilkan agar ditolak lewat aplikasi mitra
+func pollChannel(cfg *Config, store *Store, ch ChannelConfig, adapter Channel) ([]Order, error) {
+	incoming, err := adapter.Poll()
+	if err != nil {
+		return nil, err
+	}
+	restaurant := store.Menu()
+	restaurant.Limits = cfg.OrderLimits
+	restaurant.PriceGuards, restaurant.DeliveryFee = cfg.PriceGuards, cfg.Cashier.DeliveryFee
+	var saved []Order
+	for _, in := range incoming {
+		if _, done := store.ChannelOrder(ch.Name, in.Ref); done {
+			continue
+		}
+		order, payment, err := convertChannelOrder(cfg, restaurant, ch, in)
+		if err != nil {
+			fmt.Printf("Pesanan %s %s tidak dapat dibuat: %v\n", ch.Name, in.Ref, err)
+			continue
+		}
+		orders := []Order{order}
+		if err := checkout(cfg, store, orders, payment); err != nil {
+			return saved, err
+		}
+		if err := adapter.Accept(in.Ref); err != nil {
+			fmt.Printf("Pesanan %s %s tersimpan tetapi gagal diterima di platform: %v\n", ch.Name, in.Ref, err)
+		}
+		saved = append(saved, orders[0])
+	}
+	return saved, nil
+}
+
+// Fungsi untuk membuat adapter semua kanal yang dikonfigurasi
+func configuredChannels(cfg *Config, store *Store) (map[string]Channel, error) {
+	restaurant := store.Menu()
+	adapters := map[string]Channel{}
+	for _, ch := range cfg.Channels {
+		adapter, err := newChannel(ch, restaurant)
+		if err != nil {
+			return nil, fmt.Errorf("channels %s: %w", ch.Name, err)
+		}
+		adapters[ch.Name] = adapter
+	}
+	return adapters, nil
+}
+
+// Fungsi untuk mengambil pesanan kanal secara berkala selama server berjalan dan mengirimnya ke dapur
+// Kanal yang sedang dijeda dengan "pause" tidak diambil pesanannya
+func runChannelPolling(s *Server) {
+	adapters, err := configuredChannels(s.cfg, s.store)
+	if err != nil {
+		fmt.Println("Kanal pesanan online tidak dijalankan:", err)
+		return
+	}
+	for _, ch := range s.cfg.Channels {
+		go func() {
+			ticker := time.NewTicker(time.Duration(ch.PollSeconds) * time.Second)
+			defer ticker.Stop()
+			for range ticker.C {
+				if p := s.store.Pause(); p.Active() && slices.Contains(p.Channels, ch.Name) {
+					continue
+				}
+				orders, err := pollChannel(s.cfg, s.store, ch, adapters[ch.Name])
+				if err != nil {
+					fmt.Printf("Gagal mengambil pesanan %s: %v\n", ch.Name, err)
+				}
+				for _, order := range orders {
+					fmt.Printf("Pesanan %s %s masuk: %s\n", ch.Name, order.SourceRef, order.ID)
+					if err := s.kitchen.Submit(order); err != nil {
+						fmt.Println("Pesanan", order.ID, "tidak dikirim ke dapur:", err)
+					}
+				}
+			}
+		}()
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "channel"
+func runChannelCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: channel list|poll [-channel <nama>]")
+	}
+	switch args[0] {
+	case "list":
+		if len(cfg.Channels) == 0 {
+			fmt.Println("Belum ada kanal pesanan online di konfigurasi.")
+		}
+		for _, ch := range cfg.Channels {
+			fmt.Printf("%-12s %-10s komisi %.1f%%, markup %.1f%%\n", ch.Name, ch.Type, ch.CommissionPercent, ch.MarkupPercent)
+		}
+	case "poll":
+		fs := flag.NewFlagSet("channel poll", flag.ContinueOnError)
+		name := fs.String("channel", "", "hanya kanal ini, default semua kanal")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		adapters, err := configuredChannels(cfg, store)
+		if err != nil {
+			return err
+		}
+		for _, ch := range cfg.Channels {
+			if *name != "" && ch.Name != *name {
+				continue
+			}
+			orders, err := pollChannel(cfg, store, ch, adapters[ch.Name])
+			if err != nil {
+				return fmt.Errorf("%s: %w", ch.Name, err)
+			}
+			fmt.Printf("%s: %d pesanan baru\n", ch.Name, len(orders))
+			for _, o := range orders {
+				fmt.Printf("  %s  %s  komisi Rp%.2f\n", o.SourceRef, o.Summary(), o.Commission)
+			}
+		}
+	default:
+		return fmt.Errorf("Sub-perintah channel tidak dikenal: %s", args[0])
+	}
+	return nil
+}
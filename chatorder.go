@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pemisah antar item di pesanan chat: baris baru, koma, titik koma, "dan", "&", atau "+"
+var chatSeparator = regexp.MustCompile(`(?i)[\n,;&+]|\s+dan\s+`)
+
+// Jumlah di item chat: angka di awal atau akhir, mis. "2" atau "2x"; dengan huruf x boleh di mana saja, mis. "migor x2 pedas"
+var (
+	chatQty       = regexp.MustCompile(`(?i)^x?(\d+)x?$`)
+	chatMarkedQty = regexp.MustCompile(`(?i)^(?:x(\d+)|(\d+)x)$`)
+)
+
+// Struct untuk satu item hasil membaca pesanan chat
+type chatLine struct {
+	Line   OrderLine
+	Source string // Teks asli dari chat
+	Guess  bool   // Nama item ditebak dari salah ketik, perlu diperiksa kasir
+}
+
+// Fungsi untuk membaca pesanan chat bebas, mis. "2 nasgor pedas, 3 es teh"
+// Teks yang tidak dikenali dikembalikan di unknown beserta alasannya agar kasir bisa memasukkannya sendiri
+func parseChatOrder(restaurant *Restaurant, text string) (lines []chatLine, unknown []string) {
+	for _, chunk := range chatSeparator.Split(text, -1) {
+		chunk = strings.Trim(strings.TrimSpace(chunk), "-*•.")
+		chunk = strings.TrimSpace(chunk)
+		if chunk == "" {
+			continue
+		}
+		line, err := parseChatChunk(restaurant, chunk)
+		if err != nil {
+			unknown = append(unknown, fmt.Sprintf("%q: %v", chunk, err))
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, unknown
+}
+
+// Fungsi untuk membaca satu item chat: jumlah, nama atau alias item, lalu varian, tambahan, dan catatan
+// Nama item dicari dari kata terpanjang yang cocok, sisa kata dicocokkan ke varian dan tambahan item
+func parseChatChunk(restaurant *Restaurant, chunk string) (chatLine, error) {
+	words := strings.Fields(chunk)
+	qty := 1
+	at := -1
+	if len(words) > 1 {
+		at = slices.IndexFunc(words, chatMarkedQty.MatchString)
+		if at < 0 && chatQty.MatchString(words[0]) {
+			at = 0
+		} else if at < 0 && chatQty.MatchString(words[len(words)-1]) {
+			at = len(words) - 1
+		}
+	}
+	if at >= 0 {
+		qty, _ = strconv.Atoi(strings.Trim(strings.ToLower(words[at]), "x"))
+		words = slices.Delete(words, at, at+1)
+	}
+	if qty <= 0 {
+		return chatLine{}, errors.New("jumlah harus lebih dari 0")
+	}
+
+	item, used, guess, err := matchChatItem(restaurant, words)
+	if err != nil {
+		return chatLine{}, err
+	}
+	modifiers, rest := matchChatModifiers(*item, words[used:])
+	line := OrderLine{Item: *item, Qty: qty, Modifiers: modifiers}
+	if len(rest) > 0 {
+		note := strings.Join(rest, " ")
+		if len(restaurant.KitchenNotes) > 0 {
+			line.Notes, line.FreeNote = parseKitchenNotes(restaurant.KitchenNotes, note)
+		} else {
+			line.FreeNote = note
+		}
+	}
+	return chatLine{Line: line, Source: chunk, Guess: guess}, nil
+}
+
+// Fungsi untuk mencari item dari kata-kata awal item chat
+// Nama dan alias dicoba lebih dulu untuk semua panjang, baru tebakan salah ketik
+func matchChatItem(restaurant *Restaurant, words []string) (item *MenuItem, used int, guess bool, err error) {
+	for n := len(words); n >= 1; n-- {
+		key := strings.Join(words[:n], " ")
+		if _, convErr := strconv.Atoi(key); convErr == nil {
+			continue // Nomor menu tidak dipakai di chat, angka dianggap jumlah
+		}
+		item, err := lookupMenuEntry(restaurant, key)
+		if errors.Is(err, errItemSoldOut) {
+			return nil, 0, false, fmt.Errorf("%s sedang habis", item.Name)
+		}
+		if err == nil {
+			return item, n, false, nil
+		}
+	}
+	for n := len(words); n >= 1; n-- {
+		if g := closestMenuItem(restaurant, strings.Join(words[:n], " ")); g != nil {
+			item, err := validateOrderItem(restaurant, strings.ToLower(g.Name))
+			if err != nil {
+				return nil, 0, false, fmt.Errorf("%s sedang habis", g.Name)
+			}
+			return item, n, true, nil
+		}
+	}
+	return nil, 0, false, errItemNotFound
+}
+
+// Fungsi untuk mencocokkan sisa kata item chat ke varian dan tambahan item
+// Frasa terpanjang dicoba lebih dulu agar "tidak pedas" tidak terbaca sebagai "pedas"; grup yang tidak disebut memakai opsi pertama
+func matchChatModifiers(item MenuItem, words []string) ([]Modifier, []string) {
+	var names, rest []string
+	isOption := func(phrase string) bool {
+		for _, g := range item.Variants {
+			if slices.ContainsFunc(g.Options, func(o VariantOption) bool { return strings.EqualFold(o.Name, phrase) }) {
+				return true
+			}
+		}
+		return slices.ContainsFunc(item.AddOns, func(a AddOn) bool { return strings.EqualFold(a.Name, phrase) })
+	}
+	for i := 0; i < len(words); {
+		n := len(words) - i
+		for ; n >= 1; n-- {
+			if phrase := strings.Join(words[i:i+n], " "); isOption(phrase) {
+				names = append(names, phrase)
+				break
+			}
+		}
+		if n == 0 {
+			rest = append(rest, words[i])
+			n = 1
+		}
+		i += n
+	}
+	modifiers, err := resolveModifiers(item, names)
+	if err != nil {
+		// Pilihan yang bertentangan, mis. dua ukuran sekaligus, dikembalikan sebagai catatan agar kasir memeriksanya
+		modifiers, _ = resolveModifiers(item, nil)
+		rest = append(names, rest...)
+	}
+	return modifiers, rest
+}
+
+// Fungsi untuk menampilkan hasil membaca pesanan chat
+func printChatLines(lines []chatLine, unknown []string) {
+	fmt.Println(tr("Hasil membaca pesanan chat:"))
+	for i, l := range lines {
+		mark := ""
+		if l.Guess {
+			mark = tr("  (tebakan dari %q)", l.Source)
+		}
+		fmt.Printf("%d. %s x%d: Rp%.2f%s\n", i+1, l.Line.Label(), l.Line.Qty, l.Line.Subtotal(), mark)
+		for _, n := range l.Line.Notes {
+			fmt.Println("    *", n)
+		}
+		if l.Line.FreeNote != "" {
+			fmt.Println("    *", l.Line.FreeNote)
+		}
+	}
+	for _, u := range unknown {
+		fmt.Println(tr("Tidak dikenali:"), u)
+	}
+}
+
+// Fungsi untuk memasukkan pesanan yang ditempel dari chat, mis. WhatsApp
+// Kasir memeriksa hasilnya, boleh menghapus atau mengubah jumlah baris, sebelum ditambahkan ke pesanan
+func pasteChatOrder(restaurant *Restaurant, order *Order) error {
+	fmt.Println(tr("Tempel pesanan dari chat, akhiri dengan baris kosong:"))
+	var text []string
+	for {
+		line, err := readLineErr("")
+		if err != nil {
+			return err
+		}
+		if line == "" {
+			break
+		}
+		text = append(text, line)
+	}
+	lines, unknown := parseChatOrder(restaurant, strings.Join(text, "\n"))
+	for {
+		printChatLines(lines, unknown)
+		if len(lines) == 0 {
+			fmt.Println(tr("Tidak ada item yang dikenali."))
+			return nil
+		}
+		answer := strings.ToLower(readLine(tr("y tambahkan, n batal, h<nomor> hapus baris, <nomor> x<jumlah> ubah jumlah:")))
+		switch {
+		case answer == "y" || answer == "ya":
+			addChatLines(restaurant, order, lines)
+			return nil
+		case answer == "n" || answer == "tidak":
+			fmt.Println(tr("Pesanan chat dibatalkan."))
+			return nil
+		case strings.HasPrefix(answer, "h"):
+			if n, err := strconv.Atoi(strings.TrimSpace(answer[1:])); err == nil && n >= 1 && n <= len(lines) {
+				lines = slices.Delete(lines, n-1, n)
+				continue
+			}
+		default:
+			if key, qty := parseItemEntry(answer); qty > 0 {
+				if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= len(lines) {
+					lines[n-1].Line.Qty = qty
+					continue
+				}
+			}
+		}
+		fmt.Println(tr("Pilihan tidak valid. Coba lagi."))
+	}
+}
+
+// Fungsi untuk menambahkan baris dari chat ke pesanan dengan pemeriksaan alergi dan aturan pesanan yang sama seperti input biasa
+func addChatLines(restaurant *Restaurant, order *Order, lines []chatLine) {
+	for _, l := range lines {
+		if err := restaurant.Limits.CheckLine(*order, l.Line); err != nil {
+			fmt.Println(err)
+			fmt.Print(tr("%s tidak ditambahkan.\n", l.Line.Label()))
+			continue
+		}
+		if !confirmAllergens(restaurant, *order, l.Line.Item) {
+			fmt.Print(tr("%s tidak ditambahkan.\n", l.Line.Label()))
+			continue
+		}
+		candidate := *order
+		candidate.Lines = append(slices.Clone(order.Lines), l.Line)
+		overrides, ok := confirmOrderRules(restaurant.Rules, candidate, false)
+		if !ok {
+			fmt.Print(tr("%s tidak ditambahkan.\n", l.Line.Label()))
+			continue
+		}
+		order.Overrides = append(order.Overrides, overrides...)
+		if len(order.Lines) == 0 {
+			order.FirstItemAt = time.Now()
+		}
+		order.Lines = append(order.Lines, l.Line)
+		order.Total += l.Line.Subtotal()
+		restaurant.Events.Publish(OrderEvent{Type: OrderEventItemAdded, Order: *order, Line: l.Line})
+	}
+	openCart.Update(*order)
+	fmt.Print(tr("Total sementara: Rp%.2f\n", order.Total))
+}
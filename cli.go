@@ -0,0 +1,878 @@
+// Pola routing http.ServeMux (metode dan {param}) membutuhkan perilaku mux Go 1.22
+//
+//go:debug httpmuxgo121=0
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const usage = `Penggunaan: tugaskedua [-config file] [-outlet id] [-receipt-format text|pdf] [-tui] [-lang id|en] [-compact] [-detail] [-record file] <perintah> [argumen]
+
+Perintah:
+  (kosong)           Menjalankan kasir interaktif
+  serve              Menjalankan server API untuk sistem eksternal dan terminal (ketik "shutdown" untuk berhenti)
+  terminal           Menjalankan kasir sebagai thin client ke server pusat
+  submit             Mengirim satu pesanan JSON/CSV ke server pusat dan menampilkan struknya (--from, --method, --tendered, --json)
+  customer add       Mendaftarkan pelanggan member
+  customer list      Menampilkan daftar pelanggan
+  order take         Menghitung pesanan dari file/stdin JSON atau CSV tanpa prompt
+  order list         Menelusuri pesanan tersimpan (-date, -from, -to, -table, -customer, -status)
+  order drafts       Menampilkan pesanan terbuka dan pesanan belum dibayar yang disimpan saat kasir berhenti
+  order pay [id...]  Membayar beberapa pesanan terbuka satu pelanggan sekaligus dengan satu struk (-customer)
+  order export       Mengekspor pesanan ke spreadsheet (--from, --to, --format csv|xlsx, -out, -lines)
+  order show <id>    Menampilkan detail pesanan beserta pembayaran dan refund
+  order status <k>   Menampilkan status pesanan dari kode pengambilan di struk (sedang dimasak, siap, sudah diambil)
+  order amend <id>   Menambah (-add) atau membatalkan (-void) item pesanan yang sudah di dapur dengan tiket REVISI dan total baru
+  order decode <e>   Memeriksa tanda tangan dan menampilkan isi pesanan ter-encode
+  order serve <id>   Menandai pesanan sudah disajikan
+  order cancel <id>  Membatalkan pesanan yang belum disajikan
+  order refund <id>  Mengembalikan dana ke metode pembayaran asal (penuh, per item, atau -amount)
+  report daily       Menampilkan laporan harian
+  report top-items   Menampilkan item terlaris (--from, --to, --sort qty|revenue, --csv file)
+  report categories  Menampilkan pendapatan, jumlah, dan harga rata-rata per kategori item (--from, --to, --csv file)
+  report outlets     Menampilkan penjualan gabungan seluruh outlet beserta totalnya (--from, --to)
+  report speed       Menampilkan rata-rata kecepatan input pesanan per kasir (--from, --to, --cashier)
+  report prep        Membandingkan perkiraan dan waktu siap pesanan per item (--from, --to)
+  report notes       Menampilkan pemakaian catatan dapur per item dan saran pilihan menu (--from, --to)
+  report turnover    Menampilkan lama rata-rata meja terisi dan perputaran meja per waktu (--from, --to)
+  report reorder     Menampilkan bahan yang menipis beserta saran jumlah pembelian (--days, --cover)
+  report forecast    Memperkirakan jumlah terjual per item besok atau 7 hari ke depan (--period, --method, --window, --ingredients, --csv)
+  report rebuild     Menyusun ulang rekap penjualan harian yang dipakai laporan dari seluruh pesanan
+  report settlement  Menampilkan rekonsiliasi settlement per metode pembayaran, termasuk sengketa
+  dispute open <id>  Mencatat sengketa/chargeback untuk pembayaran kartu atau QRIS
+  dispute resolve    Menyelesaikan sengketa (-status won|lost|accepted)
+  dispute list       Menampilkan daftar sengketa
+  receipt search     Mencari struk lama (-date, -time, -amount, -item, -customer)
+  receipt browse     Menelusuri struk lama dengan tombol panah (filter sama dengan search)
+  receipt show <id>  Mencetak ulang struk berdasarkan ID pembayaran atau pesanan
+  receipt reprint    Menyusun ulang struk pesanan lama ke layar, file (-out, PDF dengan -receipt-format pdf), atau printer ESC/POS (-printer)
+  email send <id> <a> Mengirim struk pembayaran ke alamat email lewat antrean
+  email queue        Menampilkan antrean email struk (-all termasuk yang terkirim)
+  email retry [id]   Mengirim ulang email yang tertunda, id untuk mengulang email yang gagal permanen
+  template preview <t> Menampilkan template receipt|email|ticket|stub|escpos dengan pesanan contoh (-payment id)
+  template send <t>  Mengirim email (-to alamat) atau struk escpos uji coba dari pesanan contoh (-payment id)
+  webhook queue      Menampilkan antrean webhook kejadian pesanan (-all termasuk yang terkirim)
+  webhook retry <id> Mengirim ulang webhook yang gagal permanen saat server berjalan
+  broker test        Menerbitkan kejadian uji ke NATS atau Kafka dari konfigurasi broker
+  session replay <f> Memutar ulang rekaman -record di data sementara dan membandingkan total dengan rekaman
+  replay             Memutar ulang kejadian satu hari dari jurnal untuk mencari selisih total (-date, -until, -v)
+  backup create <f>  Menyimpan menu, pesanan, pelanggan, jurnal, dan konfigurasi ke satu arsip .zip berversi
+  backup restore <f> Memulihkan data dari arsip backup create setelah memeriksa versinya (-with-config, -force)
+  backup push        Mengirim cadangan data dan laporan harian ke S3 atau Google Drive sekarang (-date)
+  backup list        Menampilkan cadangan yang tersimpan di penyimpanan offsite
+  import history <f> Mengimpor rekap penjualan lama dari file .csv/.xlsx
+  drawer show        Menampilkan pecahan yang tersedia di laci kasir
+  drawer set <list>  Mengatur pecahan yang tersedia, mis. 50000,20000,5000
+  drawer reset       Mengembalikan pecahan laci sesuai konfigurasi
+  drawer open        Membuka shift laci dengan modal awal (-float)
+  drawer status      Menampilkan uang tunai masuk, kembalian, dan uang seharusnya di shift yang terbuka
+  drawer close       Menutup shift dan membandingkan uang seharusnya dengan uang yang dihitung (-counted, -note)
+  drawer shifts      Menampilkan riwayat shift laci beserta selisihnya (-id untuk laporan satu shift)
+  reservation add    Mencatat reservasi dengan deposit lewat tautan pembayaran (-name, -at, -guests, -table, -minutes, -deposit)
+  reservation list   Menampilkan reservasi beserta status deposit dan biaya no-show (-date, -today)
+  reservation deposit <id> Memeriksa pembayaran deposit reservasi di gateway
+  reservation arrive <id>  Mencatat tamu datang; deposit dipotong dari tagihan mejanya
+  reservation cancel <id>  Membatalkan reservasi
+  reservation ics    Mengekspor reservasi sebagai kalender ICS (-from, -out)
+  reservation push   Mengirim reservasi ke kalender CalDAV, mis. Google Calendar (-from)
+  channel list       Menampilkan kanal pesanan online beserta komisi dan markup-nya
+  channel poll       Mengambil pesanan baru dari kanal pesanan online sekali (-channel)
+  pause              Menjeda kanal pesanan online dan memasang banner di halaman /status (-reason, -banner, -for, -until, -channels)
+  pause status       Menampilkan jeda pesanan online yang sedang berlaku
+  resume             Membuka kembali kanal pesanan online yang dijeda
+  voucher create     Membuat voucher hadiah dengan saldo rupiah (-amount, -code, -expires); butuh PIN admin
+  voucher list       Menampilkan voucher hadiah beserta sisa saldonya
+  voucher show <kode> Menampilkan saldo dan riwayat pemakaian voucher hadiah
+  wifi import <f>    Menambahkan voucher Wi-Fi tamu dari kolom pertama file CSV ke pool
+  wifi status        Menampilkan sisa pool dan pemakaian voucher Wi-Fi (-date)
+  wifi redeem <kode> Menandai voucher Wi-Fi sudah dipakai, biasanya dilaporkan portal hotspot
+  table floor        Menampilkan denah meja beserta lama setiap meja terisi (-watch detik)
+  table seat <meja>  Mencatat tamu duduk di meja sebelum memesan (-guests)
+  table clear <meja> Mengosongkan meja tanpa pembayaran, mis. tamu pergi
+  table tab <meja>   Menampilkan tab meja yang masih terbuka beserta setiap rondenya
+  table bill <meja>  Menagih seluruh ronde tab meja dengan satu pembayaran dan satu struk
+  queue display      Menampilkan nomor antrean yang sedang dimasak dan siap diambil (-watch detik)
+  queue list         Menampilkan urutan antrean dapur beserta prioritas dan perkiraan waktu siapnya
+  queue bump         Mendahulukan pesanan ke depan antrean dapur (ID pesanan atau nomor antrean)
+  stock list         Menampilkan stok bahan dan item menu yang memakainya
+  stock set <b> <n>  Mengatur stok bahan, bahan baru ditambahkan ke persediaan (-unit, -min, -par)
+  stock add <b> <n>  Menambah stok bahan yang datang dari pemasok
+  close run          Menjalankan tutup hari: tandai tab meja belum dibayar, laporan harian, ulang nomor antrean, cadangan (-date)
+  close list         Menampilkan riwayat tutup hari beserta langkah yang gagal
+  notes list         Menampilkan daftar catatan dapur baku
+  notes add <nama>   Menambahkan catatan dapur baku (-alias, -category)
+  notes remove <n>   Menghapus catatan dapur baku
+  alias list         Menampilkan kamus alias item menu, termasuk yang dipelajari dari koreksi salah ketik
+  alias add <a> <item> Menambahkan alias item menu, mis. alias add nasgor "Nasi Goreng"
+  alias remove <a>   Menghapus alias item menu
+  staff add          Mendaftarkan staf dengan PIN (-name, -role admin|cashier), perlu PIN admin
+  staff list         Menampilkan daftar staf
+  staff remove <id>  Menghapus staf, perlu PIN admin
+  staff unlock <id>  Membuka kunci staf setelah terlalu banyak PIN salah, perlu PIN admin
+  staff reset-pin    Mengatur PIN sementara staf yang wajib diganti saat masuk, perlu PIN admin
+  staff change-pin   Mengganti PIN sendiri
+  audit list         Menampilkan catatan audit tindakan staf beserta nilai sebelum/sesudah, perlu PIN admin (-date, -action, -actor)
+  audit verify       Memeriksa rantai hash catatan audit untuk mendeteksi catatan yang diubah atau dihapus
+  fleet              Menampilkan kondisi terminal dari heartbeat terakhir
+  menu publish <f>   Merilis menu dari file JSON ke cabang (-at, -branches)
+  menu releases      Menampilkan rilis menu dan konfirmasi setiap cabang
+  menu history       Menampilkan riwayat versi menu beserta perubahannya (-v versi untuk isi lengkap)
+  menu rollback <v>  Mengembalikan menu ke isi versi tertentu sebagai versi baru, perlu PIN admin
+  menu search <kata> Mencari item menu dari sebagian nama, tanpa peduli huruf besar/kecil dan aksen
+  menu soldout <i>   Menandai item menu habis tanpa menghapusnya dari menu, perlu PIN admin
+  menu available <i> Menandai item menu yang habis tersedia kembali, perlu PIN admin
+  menu export        Mengekspor menu ke spreadsheet (--format csv|xlsx, -out)
+  menu sync          Memperbarui menu dari Google Sheets (CSV) atau endpoint JSON (--url, --dry-run)
+  branch sync        Mengambil dan menerapkan rilis menu dari kantor pusat
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// Fungsi untuk membaca flag global lalu menjalankan perintah yang dipilih
+func run(args []string) error {
+	global := flag.NewFlagSet("tugaskedua", flag.ContinueOnError)
+	global.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	configPath := global.String("config", defaultConfigFile, "lokasi file konfigurasi")
+	tui := global.Bool("tui", false, "gunakan navigasi menu dengan tombol panah")
+	language := global.String("lang", "", "bahasa tampilan: id atau en (default dari konfigurasi)")
+	compact := global.Bool("compact", false, "prompt ringkas untuk layar kecil, mis. pelayan dengan ponsel")
+	detail := global.Bool("detail", false, "tampilkan deskripsi dan alergen item di daftar menu")
+	record := global.String("record", "", "rekam input kasir (dianonimkan) dan perubahan total ke file untuk laporan bug")
+	outlet := global.String("outlet", "", "ID outlet yang dijalankan, lihat \"outlets\" di konfigurasi")
+	receiptFormat := global.String("receipt-format", "", "format struk yang ditulis ke file dan email: text atau pdf (default dari konfigurasi)")
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *outlet != "" {
+		if cfg, err = cfg.forOutlet(*outlet); err != nil {
+			return err
+		}
+	}
+	if *tui {
+		cfg.Cashier.TUI = true
+	}
+	if *language != "" {
+		cfg.Language = *language
+	}
+	if err := setLanguage(cfg.Language); err != nil {
+		return err
+	}
+	if *receiptFormat != "" {
+		cfg.Receipt.Format = *receiptFormat
+		if err := cfg.Receipt.validate(); err != nil {
+			return err
+		}
+	}
+	receiptPDF = cfg.Receipt
+	if err := configureIDs(cfg.IDs); err != nil {
+		return err
+	}
+	compactMode = *compact || cfg.Cashier.Compact
+	menuDetail = *detail || cfg.Cashier.MenuDetail
+	activeWatchdog = startWatchdog(cfg.Watchdog)
+	if *record != "" {
+		rec, err := startRecorder(*record)
+		if err != nil {
+			return err
+		}
+		activeRecorder = rec
+		defer rec.Close()
+	}
+
+	// Mode terminal tidak membuka file data sama sekali
+	args = global.Args()
+	if len(args) > 0 && args[0] == "terminal" {
+		backend, err := newRemoteBackend(cfg.Client)
+		if err != nil {
+			return err
+		}
+		backend.startHeartbeat()
+		if err := backend.verifySequence(cfg.IDs); err != nil {
+			fmt.Println(tr("Nomor urut ID tidak dapat dicocokkan dengan server pusat:"), err)
+		}
+		return runCashier(backend, cfg, nil)
+	}
+	if len(args) > 0 && args[0] == "submit" {
+		return runSubmitCommand(cfg, args[1:])
+	}
+	if len(args) > 0 && args[0] == "session" {
+		return runSessionCommand(args[1:])
+	}
+
+	store, err := openConfigStore(cfg)
+	if err != nil {
+		return err
+	}
+	repo, err := openRepository(cfg.Storage)
+	if err != nil {
+		return err
+	}
+	if repo != nil {
+		defer repo.Close()
+		if err := store.UseRepository(repo); err != nil {
+			return err
+		}
+	}
+	if len(args) == 0 {
+		return runCashier(newLocalBackend(cfg, store), cfg, startKitchen(cfg.Kitchen, markReady(store)))
+	}
+	switch args[0] {
+	case "serve":
+		srv := newServer(cfg, store)
+		// Server utama melayani semua outlet; --outlet hanya melayani outlet tersebut
+		if cfg.outlet == nil && len(cfg.Outlets) > 0 {
+			if err := srv.openOutlets(); err != nil {
+				return err
+			}
+		}
+		return srv.ListenAndServe()
+	case "customer":
+		return runCustomerCommand(store, args[1:])
+	case "order":
+		return runOrderCommand(cfg, store, args[1:])
+	case "report":
+		return runReportCommand(cfg, store, args[1:])
+	case "receipt":
+		return runReceiptCommand(cfg, store, args[1:])
+	case "replay":
+		return runReplayCommand(store, args[1:])
+	case "email":
+		return runEmailCommand(cfg, store, args[1:])
+	case "dispute":
+		return runDisputeCommand(store, args[1:])
+	case "staff":
+		return runStaffCommand(cfg, store, args[1:])
+	case "audit":
+		return runAuditCommand(cfg, store, args[1:])
+	case "backup":
+		return runBackupCommand(cfg, store, args[1:])
+	case "import":
+		return runImportCommand(store, args[1:])
+	case "drawer":
+		return runDrawerCommand(cfg, store, args[1:])
+	case "stock":
+		return runStockCommand(store, args[1:])
+	case "close":
+		return runCloseCommand(cfg, store, args[1:])
+	case "template":
+		return runTemplateCommand(cfg, store, args[1:])
+	case "webhook":
+		return runWebhookCommand(cfg, store, args[1:])
+	case "broker":
+		if len(args) != 2 || args[1] != "test" {
+			return fmt.Errorf("Gunakan: broker test")
+		}
+		return runBrokerTest(cfg)
+	case "notes":
+		return runNotesCommand(store, args[1:])
+	case "alias":
+		return runAliasCommand(store, args[1:])
+	case "reservation":
+		return runReservationCommand(cfg, store, args[1:])
+	case "channel":
+		return runChannelCommand(cfg, store, args[1:])
+	case "pause":
+		return runPauseCommand(cfg, store, args[1:])
+	case "resume":
+		staff, err := login(newLocalBackend(cfg, store), "Masukkan PIN untuk membuka kembali pesanan online.")
+		if err != nil {
+			return err
+		}
+		return resumeOrdering(cfg, store, staff)
+	case "voucher":
+		return runVoucherCommand(cfg, store, args[1:])
+	case "wifi":
+		return runWifiCommand(cfg, store, args[1:])
+	case "table":
+		return runTableCommand(cfg, store, args[1:])
+	case "queue":
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: queue display|list|bump")
+		}
+		switch args[1] {
+		case "display":
+			return runQueueDisplay(store, args[2:])
+		case "list":
+			return runQueueList(cfg, store, args[2:])
+		case "bump":
+			return runQueueBump(cfg, store, args[2:])
+		}
+		return fmt.Errorf("Gunakan: queue display|list|bump")
+	case "fleet":
+		printFleet(cfg, store.Terminals())
+		return nil
+	case "menu":
+		return runMenuCommand(cfg, store, args[1:])
+	case "branch":
+		if len(args) < 2 || args[1] != "sync" {
+			return fmt.Errorf("Gunakan: branch sync")
+		}
+		if cfg.HeadOffice.URL == "" {
+			return fmt.Errorf("head_office.url belum diatur di konfigurasi")
+		}
+		return syncBranchMenu(cfg, store)
+	default:
+		global.Usage()
+		return fmt.Errorf("Perintah tidak dikenal: %s", args[0])
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "customer"
+func runCustomerCommand(store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: customer add|list")
+	}
+	switch args[0] {
+	case "add":
+		fs := flag.NewFlagSet("customer add", flag.ContinueOnError)
+		name := fs.String("name", "", "nama pelanggan")
+		phone := fs.String("phone", "", "nomor HP pelanggan")
+		email := fs.String("email", "", "email pelanggan")
+		tier := fs.String("tier", "", "tingkat keanggotaan")
+		sharing := fs.Bool("share", false, "setuju data dibagikan ke program loyalitas")
+		marketing := fs.Bool("marketing", false, "setuju menerima promosi")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *name == "" || *phone == "" {
+			return fmt.Errorf("-name dan -phone wajib diisi")
+		}
+		customer, err := store.AddCustomer(Customer{
+			Name:    *name,
+			Phone:   *phone,
+			Email:   *email,
+			Tier:    *tier,
+			Consent: Consent{DataSharing: *sharing, Marketing: *marketing},
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println("Pelanggan terdaftar dengan ID", customer.ID)
+	case "list":
+		for _, c := range store.Customers() {
+			fmt.Printf("%s  %-20s %-14s tier=%-8s berbagi=%t promosi=%t\n",
+				c.ID, c.Name, c.Phone, c.Tier, c.Consent.DataSharing, c.Consent.Marketing)
+		}
+	default:
+		return fmt.Errorf("Sub-perintah customer tidak dikenal: %s", args[0])
+	}
+	return nil
+}
+
+// Fungsi untuk menjalankan sub-perintah "order"
+func runOrderCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "take":
+			return runBatchOrder(newLocalBackend(cfg, store), cfg.Pricing.Strategy(), cfg.Security.OrderSigningKey, args[1:], os.Stdout)
+		case "list":
+			return runOrderList(store, args[1:])
+		case "pay":
+			return runOrderPay(cfg, store, args[1:])
+		case "export":
+			return runOrderExport(store, args[1:])
+		case "drafts":
+			drafts := store.Drafts()
+			if len(drafts) == 0 {
+				fmt.Println("Tidak ada draf pesanan.")
+			}
+			for _, o := range drafts {
+				if !o.TabFlaggedAt.IsZero() {
+					fmt.Printf("%s  tab belum dibayar sejak tutup hari %s\n", o.Summary(), o.TabFlaggedAt.Local().Format("2006-01-02 15:04"))
+					continue
+				}
+				if o.HoldLabel != "" {
+					fmt.Printf("%s  ditahan: %s\n", o.Summary(), o.HoldLabel)
+					continue
+				}
+				fmt.Println(o.Summary())
+			}
+			return nil
+		}
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("Gunakan: order take|list|drafts|pay|export atau order show|amend|serve|cancel|refund <id> atau order decode <encoded> atau order status <kode>")
+	}
+	action, id := args[0], args[1]
+	switch action {
+	case "status":
+		return runOrderStatus(store, strings.Join(args[1:], " "))
+	case "amend":
+		return runOrderAmend(cfg, store, id, args[2:])
+	case "decode":
+		return runOrderDecode(cfg, id)
+	case "show":
+		order, err := store.Order(id)
+		if err != nil {
+			return err
+		}
+		printOrderDetail(os.Stdout, store, order)
+	case "serve":
+		if err := store.ServeOrder(id); err != nil {
+			return err
+		}
+		fmt.Println("Pesanan", id, "ditandai sudah disajikan")
+	case "cancel":
+		fs := flag.NewFlagSet("order cancel", flag.ContinueOnError)
+		reason := fs.String("reason", "", "alasan pembatalan")
+		if err := fs.Parse(args[2:]); err != nil {
+			return err
+		}
+		admin, err := requireAdmin(cfg, store)
+		if err != nil {
+			return err
+		}
+		providers, err := newPaymentProviders(cfg, store)
+		if err != nil {
+			return err
+		}
+		before := auditOrderState(store, id)
+		refund, err := cancelOrder(providers, store, id, *reason)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Pesanan", id, "dibatalkan")
+		detail := *reason
+		if refund != nil {
+			printRefund(*refund)
+			detail = fmt.Sprintf("refund %s Rp%.2f %s", refund.ID, refund.Amount, *reason)
+		}
+		return store.AuditChange(admin, "order.cancel", id, before, auditOrderState(store, id), detail)
+	case "refund":
+		fs := flag.NewFlagSet("order refund", flag.ContinueOnError)
+		reason := fs.String("reason", "", "alasan refund")
+		amount := fs.String("amount", "", "nominal refund sebagian, mis. 10000")
+		var items itemQtyFlag
+		fs.Var(&items, "item", "item yang dikembalikan, format \"Nama Item:jumlah\" (boleh berulang)")
+		if err := fs.Parse(args[2:]); err != nil {
+			return err
+		}
+		var value Money
+		if *amount != "" {
+			var err error
+			if value, err = validatePrice(*amount); err != nil || value <= 0 {
+				return fmt.Errorf("Nominal refund tidak valid: %s", *amount)
+			}
+		}
+		admin, err := requireAdmin(cfg, store)
+		if err != nil {
+			return err
+		}
+		providers, err := newPaymentProviders(cfg, store)
+		if err != nil {
+			return err
+		}
+		before := auditOrderState(store, id)
+		refund, err := refundOrder(providers, store, id, items.m, value, *reason)
+		if err != nil {
+			return err
+		}
+		printRefund(refund)
+		return store.AuditChange(admin, "order.refund", id, before, auditOrderState(store, id), fmt.Sprintf("%s Rp%.2f via %s %s", refund.ID, refund.Amount, refund.Method, *reason))
+	default:
+		return fmt.Errorf("Sub-perintah order tidak dikenal: %s", action)
+	}
+	return nil
+}
+
+// Fungsi untuk menampilkan detail refund
+func printRefund(r Refund) {
+	fmt.Println("Refund", r.ID, "untuk pesanan", r.OrderID)
+	for _, line := range r.Lines {
+		fmt.Printf("- %s x%d: Rp%.2f\n", line.Name, line.Qty, line.Amount)
+	}
+	fmt.Printf("Total refund: Rp%.2f\n", r.Amount)
+	if r.Voucher > 0 {
+		fmt.Printf("Dikembalikan ke saldo voucher %s: Rp%.2f\n", r.VoucherCode, r.Voucher)
+	}
+	if r.Deposit > 0 {
+		fmt.Printf("Dari deposit reservasi, kembalikan manual lewat gateway: Rp%.2f\n", r.Deposit)
+	}
+	if r.ProviderRef != "" {
+		fmt.Printf("Dikembalikan via %s, referensi %s\n", r.Method, r.ProviderRef)
+	}
+}
+
+// Flag berulang dengan format "Nama Item:jumlah"
+type itemQtyFlag struct {
+	m map[string]int
+}
+
+func (f *itemQtyFlag) String() string { return fmt.Sprint(f.m) }
+
+func (f *itemQtyFlag) Set(v string) error {
+	i := strings.LastIndex(v, ":")
+	if i <= 0 {
+		return fmt.Errorf("Format item harus \"Nama Item:jumlah\"")
+	}
+	qty, err := strconv.Atoi(v[i+1:])
+	if err != nil {
+		return fmt.Errorf("Jumlah item tidak valid: %s", v[i+1:])
+	}
+	if f.m == nil {
+		f.m = map[string]int{}
+	}
+	f.m[strings.TrimSpace(v[:i])] += qty
+	return nil
+}
+
+// Fungsi untuk menjalankan sub-perintah "report"
+func runReportCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: report daily|settlement [-date YYYY-MM-DD] atau report top-items|categories|outlets|speed|prep|notes|turnover [--from] [--to] atau report reorder [--days] [--cover] atau report forecast [--period day|week] [--method weekday|sma]")
+	}
+	switch args[0] {
+	case "top-items":
+		return runTopItemsReport(store, args[1:])
+	case "categories":
+		return runCategoryReport(store, args[1:])
+	case "outlets":
+		return runOutletReport(cfg, args[1:])
+	case "speed":
+		return runSpeedReport(store, args[1:])
+	case "prep":
+		return runPrepReport(store, args[1:])
+	case "notes":
+		return runNotesReport(store, args[1:])
+	case "turnover":
+		return runTurnoverReport(cfg, store, args[1:])
+	case "reorder":
+		return runReorderReport(cfg, store, args[1:])
+	case "forecast":
+		return runForecastReport(store, args[1:])
+	case "rebuild":
+		if err := store.RebuildProjections(); err != nil {
+			return err
+		}
+		fmt.Println("Rekap penjualan disusun ulang dari seluruh pesanan")
+		return nil
+	}
+	fs := flag.NewFlagSet("report "+args[0], flag.ContinueOnError)
+	dateFlag := fs.String("date", time.Now().Format("2006-01-02"), "tanggal laporan")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	date, err := time.ParseInLocation("2006-01-02", *dateFlag, time.Local)
+	if err != nil {
+		return fmt.Errorf("Format tanggal harus YYYY-MM-DD")
+	}
+	switch args[0] {
+	case "daily":
+		buildDailyReport(store, date).Print(os.Stdout)
+	case "settlement":
+		buildSettlementReport(store, date).Print(os.Stdout)
+	default:
+		return fmt.Errorf("Laporan tidak dikenal: %s", args[0])
+	}
+	return nil
+}
+
+// Fungsi untuk menjalankan "report top-items"
+func runTopItemsReport(store *Store, args []string) error {
+	fs := flag.NewFlagSet("report top-items", flag.ContinueOnError)
+	dates := addRangeFlags(fs)
+	sortBy := fs.String("sort", "qty", "urutkan berdasarkan qty atau revenue")
+	limit := fs.Int("limit", 0, "jumlah item yang ditampilkan, 0 untuk semua")
+	csvPath := fs.String("csv", "", "ekspor ke file CSV, \"-\" untuk stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, to, err := dates.parse()
+	if err != nil {
+		return err
+	}
+	if *sortBy != "qty" && *sortBy != "revenue" {
+		return fmt.Errorf("--sort harus qty atau revenue")
+	}
+
+	items := buildTopItems(store, from, to, *sortBy)
+	if *limit > 0 && len(items) > *limit {
+		items = items[:*limit]
+	}
+	switch *csvPath {
+	case "":
+		printTopItems(os.Stdout, items, from, to)
+		return nil
+	case "-":
+		return writeTopItemsCSV(os.Stdout, items)
+	}
+	f, err := os.Create(*csvPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := writeTopItemsCSV(f, items); err != nil {
+		return err
+	}
+	fmt.Println("Laporan diekspor ke", *csvPath)
+	return nil
+}
+
+// Fungsi untuk menjalankan "report categories"
+func runCategoryReport(store *Store, args []string) error {
+	fs := flag.NewFlagSet("report categories", flag.ContinueOnError)
+	dates := addRangeFlags(fs)
+	csvPath := fs.String("csv", "", "ekspor ke file CSV, \"-\" untuk stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, to, err := dates.parse()
+	if err != nil {
+		return err
+	}
+	categories := buildCategorySales(store, from, to)
+	switch *csvPath {
+	case "":
+		printCategorySales(os.Stdout, categories, from, to)
+		return nil
+	case "-":
+		return writeCategorySalesCSV(os.Stdout, categories)
+	}
+	f, err := os.Create(*csvPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := writeCategorySalesCSV(f, categories); err != nil {
+		return err
+	}
+	fmt.Println("Laporan diekspor ke", *csvPath)
+	return nil
+}
+
+// Flag --from dan --to untuk laporan dengan rentang tanggal, default 30 hari terakhir
+type rangeFlags struct {
+	from, to *string
+}
+
+// Fungsi untuk mendaftarkan flag --from dan --to
+func addRangeFlags(fs *flag.FlagSet) rangeFlags {
+	return rangeFlags{
+		from: fs.String("from", time.Now().AddDate(0, 0, -29).Format("2006-01-02"), "tanggal awal YYYY-MM-DD"),
+		to:   fs.String("to", time.Now().Format("2006-01-02"), "tanggal akhir YYYY-MM-DD"),
+	}
+}
+
+// Membaca rentang tanggal dari flag
+func (r rangeFlags) parse() (from, to time.Time, err error) {
+	if from, err = time.ParseInLocation("2006-01-02", *r.from, time.Local); err != nil {
+		return from, to, fmt.Errorf("Format --from harus YYYY-MM-DD")
+	}
+	if to, err = time.ParseInLocation("2006-01-02", *r.to, time.Local); err != nil {
+		return from, to, fmt.Errorf("Format --to harus YYYY-MM-DD")
+	}
+	if to.Before(from) {
+		return from, to, fmt.Errorf("--to tidak boleh sebelum --from")
+	}
+	return from, to, nil
+}
+
+// Fungsi untuk menjalankan "report speed"
+func runSpeedReport(store *Store, args []string) error {
+	fs := flag.NewFlagSet("report speed", flag.ContinueOnError)
+	dates := addRangeFlags(fs)
+	cashier := fs.String("cashier", "", "tampilkan durasi setiap pesanan untuk ID atau nama kasir ini")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, to, err := dates.parse()
+	if err != nil {
+		return err
+	}
+	times := orderEntryTimes(store, from, to)
+	perCashier, overall := buildEntrySpeed(store, times)
+	printEntrySpeed(os.Stdout, perCashier, overall, from, to)
+	if *cashier == "" {
+		return nil
+	}
+	for _, e := range perCashier {
+		if e.CashierID != *cashier && !strings.EqualFold(e.Name, *cashier) {
+			continue
+		}
+		fmt.Printf("\nPesanan %s:\n", e.Name)
+		for _, t := range times {
+			if t.CashierID == e.CashierID {
+				fmt.Printf("%s  %s  %3d item  %s\n", t.OrderID, t.PaidAt.Local().Format("2006-01-02 15:04"), t.Items, t.Duration.Round(time.Second))
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("Kasir %s tidak memiliki pesanan dalam rentang ini", *cashier)
+}
+
+// Fungsi untuk menjalankan sub-perintah "import"
+func runImportCommand(store *Store, args []string) error {
+	if len(args) < 2 || args[0] != "history" {
+		return fmt.Errorf("Gunakan: import history <file.csv|file.xlsx>\nKolom: %s", strings.Join(historyColumns, ", "))
+	}
+	summary, err := importHistory(store, args[1])
+	if err != nil {
+		return err
+	}
+	for _, msg := range summary.Errors {
+		fmt.Println("Dilewati -", msg)
+	}
+	fmt.Printf("%d baris diimpor (%d menggantikan data lama), %d baris dilewati\n",
+		summary.Imported, summary.Replaced, len(summary.Errors))
+	return nil
+}
+
+// Fungsi untuk menjalankan sub-perintah "drawer"
+func runDrawerCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: drawer open|status|close|shifts|show|set <pecahan>|reset")
+	}
+	switch args[0] {
+	case "open", "status", "close", "shifts":
+		return runShiftCommand(cfg, store, args[0], args[1:])
+	case "show":
+	case "set":
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: drawer set 100000,50000,20000")
+		}
+		denoms, err := parseDenominations(args[1])
+		if err != nil {
+			return err
+		}
+		if err := store.SetDrawerDenominations(denoms); err != nil {
+			return err
+		}
+	case "reset":
+		if err := store.SetDrawerDenominations(nil); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Sub-perintah drawer tidak dikenal: %s", args[0])
+	}
+	fmt.Println("Pecahan di laci:", strings.Trim(fmt.Sprint(store.DrawerDenominations(cfg)), "[]"))
+	return nil
+}
+
+// Fungsi untuk menjalankan sub-perintah "menu" di kantor pusat
+func runMenuCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: menu publish <file.json>|releases|history|rollback|search|soldout|available|export")
+	}
+	switch args[0] {
+	case "publish":
+		fs := flag.NewFlagSet("menu publish", flag.ContinueOnError)
+		at := fs.String("at", "", "waktu menu berlaku, format \"YYYY-MM-DD HH:MM\" (default: sekarang)")
+		branches := fs.String("branches", "", "daftar ID cabang dipisah koma (default: semua cabang)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			return fmt.Errorf("Gunakan: menu publish [-at waktu] [-branches a,b] <file.json>")
+		}
+		effective := time.Now()
+		if *at != "" {
+			var err error
+			effective, err = time.ParseInLocation("2006-01-02 15:04", *at, time.Local)
+			if err != nil {
+				return fmt.Errorf("Format waktu harus \"YYYY-MM-DD HH:MM\"")
+			}
+		}
+		var targets []string
+		for _, b := range strings.Split(*branches, ",") {
+			if b = strings.TrimSpace(b); b != "" {
+				targets = append(targets, b)
+			}
+		}
+		menu, err := readMenuFile(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		admin, err := requireAdmin(cfg, store)
+		if err != nil {
+			return err
+		}
+		overrides, err := confirmMenuPrices(cfg.PriceGuards, menu)
+		if err != nil {
+			return err
+		}
+		previous := store.Menu().Menu
+		if releases := store.MenuReleases(); len(releases) > 0 {
+			previous = releases[len(releases)-1].Menu
+		}
+		release, err := publishMenu(store, menu, effective, targets)
+		if err != nil {
+			return err
+		}
+		if err := auditMenuPrices(store, admin, "rilis "+release.ID, previous, release.Menu); err != nil {
+			return err
+		}
+		fmt.Println("Rilis menu", release.ID, "berlaku", release.EffectiveAt.Format("2006-01-02 15:04"))
+		if len(overrides) > 0 {
+			if err := store.Audit(admin, "menu.price_guard", release.ID, strings.Join(overrides, "; ")); err != nil {
+				return err
+			}
+		}
+		return store.Audit(admin, "menu.publish", release.ID, fmt.Sprintf("%d item", len(release.Menu)))
+	case "releases":
+		printMenuReleases(store.MenuReleases())
+	case "history":
+		return runMenuHistory(store, args[1:])
+	case "rollback":
+		return runMenuRollback(cfg, store, args[1:])
+	case "search":
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: menu search <kata kunci>")
+		}
+		query := strings.Join(args[1:], " ")
+		matches := searchMenu(store.Menu().Menu, query)
+		if len(matches) == 0 {
+			fmt.Println(tr("Tidak ada item menu yang cocok dengan %q.", query))
+			return nil
+		}
+		printMenuMatches(os.Stdout, matches)
+	case "soldout", "available":
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: menu %s <nomor atau nama item>", args[0])
+		}
+		item, err := lookupMenuEntry(store.Menu(), strings.Join(args[1:], " "))
+		if errors.Is(err, errItemNotFound) {
+			return err
+		}
+		admin, err := requireAdmin(cfg, store)
+		if err != nil {
+			return err
+		}
+		soldOut := args[0] == "soldout"
+		if err := store.SetItemSoldOut(item.Name, soldOut); err != nil {
+			return err
+		}
+		if soldOut {
+			fmt.Printf("%s ditandai habis.\n", item.Name)
+		} else if current := store.Menu().findMenuItem(item.Name); current != nil && current.SoldOut {
+			// Tanda habis dari persediaan bahan tetap berlaku sampai stok ditambah
+			fmt.Printf("%s tersedia kembali, tetapi bahannya masih kurang sehingga tetap tampil habis.\n", item.Name)
+		} else {
+			fmt.Printf("%s tersedia kembali.\n", item.Name)
+		}
+		return store.AuditChange(admin, "menu."+args[0], item.Name, item.SoldOut, soldOut, "")
+	case "export":
+		return runMenuExport(store, args[1:])
+	case "sync":
+		return runMenuSync(cfg, store, args[1:])
+	default:
+		return fmt.Errorf("Sub-perintah menu tidak dikenal: %s", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Error saat terminal tidak dapat menghubungi server pusat
+var errServerOffline = errors.New("Server pusat tidak dapat dihubungi")
+
+// Struct untuk backend terminal (thin client)
+// Tidak menyimpan data apa pun selain cache menu; semua data dikirim ke server pusat
+type remoteBackend struct {
+	cfg  ClientConfig
+	http *http.Client
+
+	mu          sync.Mutex
+	lastOrderAt time.Time // Waktu pesanan terakhir berhasil disimpan, dikirim lewat heartbeat
+}
+
+// Isi file cache menu di terminal
+type menuCache struct {
+	FetchedAt time.Time  `json:"fetched_at"` // Waktu menu terakhir diambil dari server
+	Menu      []MenuItem `json:"menu"`       // Daftar item menu
+}
+
+// Fungsi untuk membuat backend terminal dari konfigurasi klien
+func newRemoteBackend(cfg ClientConfig) (*remoteBackend, error) {
+	if cfg.ServerURL == "" {
+		return nil, fmt.Errorf("client.server_url belum diatur di konfigurasi")
+	}
+	return &remoteBackend{
+		cfg:  cfg,
+		http: &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second},
+	}, nil
+}
+
+// Mengambil menu dari cache jika masih baru, jika tidak dari server
+// Saat server offline, cache lama tetap digunakan dengan peringatan yang jelas
+func (b *remoteBackend) Menu() (*Restaurant, error) {
+	cache, cacheErr := b.readMenuCache()
+	ttl := time.Duration(b.cfg.MenuCacheTTLSeconds) * time.Second
+	if cacheErr == nil && time.Since(cache.FetchedAt) < ttl {
+		return &Restaurant{Menu: cache.Menu}, nil
+	}
+
+	var menu []MenuItem
+	err := b.do(http.MethodGet, "/api/v1/menu", nil, &menu)
+	if err == nil {
+		b.writeMenuCache(menuCache{FetchedAt: time.Now(), Menu: menu})
+		return &Restaurant{Menu: menu}, nil
+	}
+	if cacheErr != nil {
+		return nil, fmt.Errorf("Belum ada cache menu: %w", err)
+	}
+	fmt.Println("PERINGATAN: server pusat offline, menggunakan cache menu dari", cache.FetchedAt.Format("2006-01-02 15:04"))
+	fmt.Println("Pesanan hanya bisa disimpan setelah server kembali online.")
+	return &Restaurant{Menu: cache.Menu}, nil
+}
+
+func (b *remoteBackend) CustomerByPhone(phone string) (Customer, error) {
+	var c Customer
+	err := b.do(http.MethodGet, "/api/v1/terminal/customers?phone="+url.QueryEscape(phone), nil, &c)
+	return c, err
+}
+
+func (b *remoteBackend) AddCustomer(c Customer) (Customer, error) {
+	var created Customer
+	err := b.do(http.MethodPost, "/api/v1/terminal/customers", c, &created)
+	return created, err
+}
+
+// Mengambil pecahan laci dari server, atau default jika server offline
+func (b *remoteBackend) DrawerDenominations() []int {
+	var denoms []int
+	if err := b.do(http.MethodGet, "/api/v1/terminal/drawer", nil, &denoms); err != nil || len(denoms) == 0 {
+		return defaultDenominations
+	}
+	return denoms
+}
+
+func (b *remoteBackend) Login(name, pin string) (Staff, error) {
+	var staff Staff
+	err := b.do(http.MethodPost, "/api/v1/terminal/login", map[string]string{"name": name, "pin": pin}, &staff)
+	return staff, err
+}
+
+func (b *remoteBackend) ChangePIN(id, oldPIN, newPIN string) error {
+	body := map[string]string{"staff_id": id, "old_pin": oldPIN, "new_pin": newPIN}
+	return b.do(http.MethodPost, "/api/v1/terminal/pin", body, nil)
+}
+
+func (b *remoteBackend) Checkout(orders []Order, payment Payment) error {
+	body := checkoutRequest{Orders: orders, Payment: payment}
+	var resp checkoutResponse
+	// ID pembayaran dipakai sebagai Idempotency-Key agar checkout yang diulang setelah timeout tidak tercatat dua kali
+	if err := b.doWithKey(http.MethodPost, "/api/v1/terminal/checkout", payment.ID, body, &resp); err != nil {
+		return err
+	}
+	for i := range orders {
+		orders[i].QueueNumber = resp.QueueNumbers[orders[i].ID]
+		orders[i].EstimatedReadyAt = resp.ReadyAt[orders[i].ID]
+		orders[i].PickupCode = resp.PickupCodes[orders[i].ID]
+	}
+	b.mu.Lock()
+	b.lastOrderAt = time.Now()
+	b.mu.Unlock()
+	b.sendHeartbeat() // Langsung laporkan pesanan terbaru ke server pusat
+	return nil
+}
+
+func (b *remoteBackend) SaveDrafts(orders []Order) error {
+	return b.do(http.MethodPost, "/api/v1/terminal/drafts", orders, nil)
+}
+
+func (b *remoteBackend) TableTab(table string) (Order, error) {
+	var tab Order
+	err := b.do(http.MethodGet, "/api/v1/terminal/tabs/"+url.PathEscape(table), nil, &tab)
+	return tab, err
+}
+
+func (b *remoteBackend) ResumeHeldOrder(label string) (Order, error) {
+	var order Order
+	err := b.do(http.MethodPost, "/api/v1/terminal/held/"+url.PathEscape(label)+"/resume", nil, &order)
+	return order, err
+}
+
+func (b *remoteBackend) ReservationDeposit(table string) (Reservation, error) {
+	var r Reservation
+	err := b.do(http.MethodGet, "/api/v1/terminal/reservations/deposit?table="+url.QueryEscape(table), nil, &r)
+	return r, err
+}
+
+func (b *remoteBackend) UpcomingReservations() ([]Reservation, error) {
+	var upcoming []Reservation
+	err := b.do(http.MethodGet, "/api/v1/terminal/reservations/upcoming", nil, &upcoming)
+	return upcoming, err
+}
+
+func (b *remoteBackend) LearnAlias(alias, item string) error {
+	return b.do(http.MethodPost, "/api/v1/terminal/aliases", MenuAlias{Alias: alias, Item: item, Learned: true}, nil)
+}
+
+func (b *remoteBackend) GiftVoucher(code string) (GiftVoucher, error) {
+	var v GiftVoucher
+	err := b.do(http.MethodGet, "/api/v1/terminal/gift-vouchers/"+url.PathEscape(normalizeVoucherCode(code)), nil, &v)
+	return v, err
+}
+
+func (b *remoteBackend) StockAlerts() ([]StockAlert, error) {
+	var alerts []StockAlert
+	err := b.do(http.MethodGet, "/api/v1/terminal/stock-alerts", nil, &alerts)
+	return alerts, err
+}
+
+func (b *remoteBackend) WifiVoucher(paymentID string) (string, error) {
+	var resp struct {
+		Code string `json:"code"`
+	}
+	err := b.do(http.MethodPost, "/api/v1/terminal/wifi-vouchers", map[string]string{"payment_id": paymentID}, &resp)
+	return resp.Code, err
+}
+
+func (b *remoteBackend) EmailReceipt(paymentID, to string) error {
+	return b.do(http.MethodPost, "/api/v1/terminal/receipts/"+url.PathEscape(paymentID)+"/email", map[string]string{"to": to}, nil)
+}
+
+// Mengirim request ke server pusat dan mendekode respons JSON
+// Error jaringan dibungkus errServerOffline; status 401/404/409/423 diubah menjadi error yang dikenal
+func (b *remoteBackend) do(method, path string, body, out any) error {
+	return b.doWithKey(method, path, "", body, out)
+}
+
+// Sama dengan do, dengan header Idempotency-Key jika key diisi
+func (b *remoteBackend) doWithKey(method, path, key string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(b.cfg.ServerURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.cfg.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	if b.cfg.Outlet != "" {
+		req.Header.Set(outletHeader, b.cfg.Outlet)
+	}
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errServerOffline, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			if strings.Contains(path, "/customers") {
+				return errCustomerNotFound
+			}
+			if strings.Contains(path, "/tabs/") {
+				return errNoTableTab
+			}
+			if strings.Contains(path, "/held/") {
+				return errNoHeldOrder
+			}
+		case http.StatusConflict:
+			if strings.Contains(path, "/customers") {
+				return errCustomerExists
+			}
+			if strings.HasSuffix(path, "/drafts") {
+				return errHoldLabelTaken
+			}
+		case http.StatusUnauthorized:
+			if strings.HasSuffix(path, "/login") || strings.HasSuffix(path, "/pin") {
+				if apiErr.Error == errPINRequired.Error() {
+					return errPINRequired
+				}
+				return errInvalidPIN
+			}
+		case http.StatusLocked:
+			return fmt.Errorf("%w%s", errStaffLocked, strings.TrimPrefix(apiErr.Error, errStaffLocked.Error()))
+		}
+		return fmt.Errorf("Server menolak request (%d): %s", resp.StatusCode, apiErr.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Membaca cache menu dari disk
+func (b *remoteBackend) readMenuCache() (menuCache, error) {
+	var cache menuCache
+	raw, err := os.ReadFile(b.cfg.MenuCacheFile)
+	if err != nil {
+		return cache, err
+	}
+	if err := json.Unmarshal(raw, &cache); err != nil {
+		return cache, err
+	}
+	if len(cache.Menu) == 0 {
+		return cache, fs.ErrNotExist
+	}
+	return cache, nil
+}
+
+// Menulis cache menu ke disk; kegagalan hanya ditampilkan sebagai peringatan
+func (b *remoteBackend) writeMenuCache(cache menuCache) {
+	raw, err := json.MarshalIndent(cache, "", "  ")
+	if err == nil {
+		err = os.WriteFile(b.cfg.MenuCacheFile, raw, 0o600)
+	}
+	if err != nil {
+		fmt.Println("Peringatan: gagal menyimpan cache menu:", err)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Kategori bawaan untuk item paket
+const comboCategory = "Paket"
+
+// Struct untuk Isi paket, mis. 1 Nasi Goreng di "Paket Hemat"
+type ComboComponent struct {
+	Item string `json:"item"` // Nama item menu yang menjadi isi paket
+	Qty  int    `json:"qty"`  // Jumlah item per satu paket
+
+	Category string `json:"category,omitempty"` // Kategori item isi, untuk memilih stasiun dapur
+}
+
+// Menambahkan paket yang terdiri dari item menu lain dengan harga paket
+// Paket ditagih sebagai satu baris dengan harga paket, sedangkan dapur menerima isinya
+func (r *Restaurant) AddCombo(name string, price Money, components ...ComboComponent) {
+	r.Menu = append(r.Menu, MenuItem{Name: name, Price: price, Category: comboCategory, Components: components})
+}
+
+// Menandakan item adalah paket
+func (m MenuItem) IsCombo() bool {
+	return len(m.Components) > 0
+}
+
+// Selisih harga isi paket jika dibeli satuan dengan harga paket
+func (r *Restaurant) ComboSavings(combo MenuItem) Money {
+	var total Money
+	for _, c := range combo.Components {
+		if item := r.findMenuItem(c.Item); item != nil {
+			total += item.Price.Times(c.Qty)
+		}
+	}
+	return total - combo.Price
+}
+
+// Mengisi resep paket yang kosong dari resep isinya agar stok bahan ikut berkurang
+func (r *Restaurant) resolveComboRecipes() {
+	for i := range r.Menu {
+		combo := &r.Menu[i]
+		if !combo.IsCombo() || len(combo.Recipe) > 0 {
+			continue
+		}
+		for _, c := range combo.Components {
+			item := r.findMenuItem(c.Item)
+			if item == nil {
+				continue
+			}
+			for _, ing := range item.Recipe {
+				combo.Recipe = append(combo.Recipe, RecipeIngredient{Ingredient: ing.Ingredient, Qty: ing.Qty * float64(c.Qty)})
+			}
+		}
+	}
+}
+
+// Mengisi kategori isi paket dari item menunya agar setiap isi dikirim ke stasiun dapurnya
+func (r *Restaurant) resolveComboCategories() {
+	for i := range r.Menu {
+		// Isi paket disalin agar menu tersimpan tidak ikut berubah
+		r.Menu[i].Components = slices.Clone(r.Menu[i].Components)
+		for j := range r.Menu[i].Components {
+			c := &r.Menu[i].Components[j]
+			if item := r.findMenuItem(c.Item); item != nil && c.Category == "" {
+				c.Category = item.Category
+			}
+		}
+	}
+}
+
+// Menandai paket sebagai habis jika salah satu isinya habis atau tidak ada di menu
+func (r *Restaurant) markCombosSoldOut() {
+	for i := range r.Menu {
+		combo := &r.Menu[i]
+		for _, c := range combo.Components {
+			if item := r.findMenuItem(c.Item); item == nil || item.SoldOut {
+				combo.SoldOut = true
+				break
+			}
+		}
+	}
+}
+
+// Mendapatkan keterangan isi paket, mis. "Nasi Goreng + Es Teh"
+func (m MenuItem) ComponentsLabel() string {
+	names := make([]string, len(m.Components))
+	for i, c := range m.Components {
+		names[i] = c.Item
+		if c.Qty > 1 {
+			names[i] = fmt.Sprintf("%dx %s", c.Qty, c.Item)
+		}
+	}
+	return strings.Join(names, " + ")
+}
+
+// Fungsi untuk mencetak isi paket sebagai baris tiket dapur
+// Jumlah setiap isi dikalikan jumlah paket yang dipesan
+func writeComboComponents(w io.Writer, line OrderLine) {
+	for _, c := range line.Item.Components {
+		fmt.Fprintf(w, "%2dx %s  [%s]\n", c.Qty*line.Qty, c.Item, line.Item.Name)
+	}
+}
@@ -0,0 +1,522 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Lokasi default file konfigurasi dan file data
+const (
+	defaultConfigFile = "config.json"
+	defaultDataFile   = "data.json"
+	defaultServerAddr = ":8080"
+	defaultMenuCache  = "menu-cache.json"
+)
+
+// Struct untuk Konfigurasi aplikasi
+// Dibaca dari file JSON, nilai yang kosong diisi dengan nilai default
+type Config struct {
+	DataFile string       `json:"data_file"` // Lokasi file penyimpanan data
+	Server   ServerConfig `json:"server"`    // Konfigurasi mode server
+
+	CashDrawer CashDrawerConfig `json:"cash_drawer"` // Konfigurasi laci kasir
+	Client     ClientConfig     `json:"client"`      // Konfigurasi mode terminal (thin client)
+
+	Cashier      CashierConfig `json:"cashier"`       // Konfigurasi alur kasir
+	OpeningHours OpeningHours  `json:"opening_hours"` // Jam buka restoran
+	Fleet        FleetConfig   `json:"fleet"`         // Konfigurasi pemantauan terminal
+
+	HeadOffice HeadOfficeConfig `json:"head_office"` // Konfigurasi sinkronisasi menu dari kantor pusat
+
+	PaymentGateway GatewayConfig `json:"payment_gateway"` // Gateway untuk pembayaran kartu dan QRIS
+
+	Security SecurityConfig `json:"security"` // Kebijakan PIN staf
+
+	Email SMTPConfig `json:"email"` // Server SMTP untuk mengirim struk lewat email
+
+	Kitchen KitchenConfig `json:"kitchen"` // Konfigurasi worker dapur
+
+	Pricing     PricingConfig `json:"pricing"`      // Aturan pajak dan pembulatan harga
+	PriceGuards []PriceGuard  `json:"price_guards"` // Batas kewajaran harga per kategori untuk perubahan menu dan harga yang diketik kasir
+
+	Language string `json:"language"` // Bahasa tampilan kasir dan struk: id atau en
+
+	Currencies []CurrencyConfig `json:"currencies"` // Mata uang asing yang diterima tunai beserta kursnya, mis. untuk tamu turis
+
+	OrderRules  []OrderRule `json:"order_rules"`  // Aturan kombinasi dan batas item yang diperiksa saat input pesanan
+	OrderLimits OrderLimits `json:"order_limits"` // Batas jumlah per item dan per pesanan untuk kasir dan API
+
+	Webhooks WebhookConfig `json:"webhooks"` // Notifikasi kejadian pesanan ke sistem eksternal dalam mode server
+	Broker   BrokerConfig  `json:"broker"`   // Penerbitan kejadian pesanan ke NATS atau Kafka dalam mode server dan kasir
+
+	Floor FloorConfig `json:"floor"` // Daftar meja untuk denah dan laporan perputaran meja
+
+	Reservations ReservationConfig `json:"reservations"` // Aturan deposit dan no-show reservasi
+	Calendar     CalendarConfig    `json:"calendar"`     // Feed ICS dan sinkronisasi CalDAV untuk reservasi
+
+	Printer PrinterConfig `json:"printer"` // Printer struk thermal ESC/POS
+	Receipt ReceiptConfig `json:"receipt"` // Format struk yang ditulis ke file atau dikirim lewat email, mis. PDF
+
+	Inventory InventoryConfig `json:"inventory"` // Peringatan stok menipis dan saran pembelian bahan
+
+	Close DayCloseConfig `json:"close"` // Tutup hari otomatis: tab meja, laporan harian, nomor antrean, dan cadangan
+
+	Wifi WifiConfig `json:"wifi"` // Voucher Wi-Fi tamu yang dicetak di struk
+
+	Offsite OffsiteConfig `json:"offsite"` // Ekspor cadangan harian ke S3 atau Google Drive dalam mode server
+
+	Watchdog WatchdogConfig `json:"watchdog"` // Deteksi dapur, printer, dan gateway pembayaran yang macet
+
+	IDs IDConfig `json:"ids"` // Strategi penomoran ID pesanan dan pelanggan
+
+	Outage   OutageConfig    `json:"outage"`   // Kanal pemesanan online yang dijeda dengan "pause"
+	Channels []ChannelConfig `json:"channels"` // Platform pemesanan online yang pesanannya diambil dalam mode server
+	Telegram TelegramConfig  `json:"telegram"` // Bot Telegram untuk pemesanan jarak jauh dalam mode server
+
+	Storage StorageConfig `json:"storage"` // Database bersama untuk menu dan pesanan (sqlite atau postgres), default file data; data lain tetap per terminal
+
+	Outlets []OutletConfig `json:"outlets"` // Outlet lain milik pemilik yang sama, dipilih dengan --outlet atau header X-Outlet
+
+	path         string        // Lokasi file konfigurasi yang dibaca, disertakan di arsip cadangan
+	outlet       *OutletConfig // Outlet yang dipilih dengan --outlet, nil untuk konfigurasi utama
+	mainDataFile string        // File data utama saat outlet dipilih, sumber menu outlet
+}
+
+// Struct untuk Konfigurasi reservasi
+type ReservationConfig struct {
+	GraceMinutes int `json:"grace_minutes"` // Batas keterlambatan tamu sebelum reservasi dianggap no-show dan deposit ditahan
+	SlotMinutes  int `json:"slot_minutes"`  // Lama slot meja yang dipesan, dipakai untuk mendeteksi reservasi bentrok
+}
+
+// Struct untuk Konfigurasi denah meja
+type FloorConfig struct {
+	Tables []string `json:"tables"` // Nomor meja yang selalu tampil di denah, mis. ["1", "2", "3"]
+}
+
+// Struct untuk Konfigurasi pajak dan pembulatan
+// Terminal dan server pusat harus memakai aturan yang sama, server menolak checkout yang totalnya berbeda
+type PricingConfig struct {
+	Tax      string  `json:"tax"`      // none, exclusive (ditambahkan ke harga menu), atau inclusive (sudah termasuk di harga menu)
+	TaxRate  float64 `json:"tax_rate"` // Tarif pajak, mis. 0.11 untuk PPN 11%
+	Rounding string  `json:"rounding"` // none, nearest, down, atau up
+	RoundTo  int     `json:"round_to"` // Kelipatan pembulatan dalam rupiah, mis. 100
+	Stage    string  `json:"stage"`    // Tahap pembulatan: line, order, atau payment
+
+	Rules []PriceRule `json:"rules"` // Harga berjadwal, mis. happy hour
+}
+
+// Struct untuk Konfigurasi worker dapur
+type KitchenConfig struct {
+	Workers     int `json:"workers"`      // Jumlah pesanan yang diproses bersamaan
+	PrepSeconds int `json:"prep_seconds"` // Lama simulasi pemrosesan satu pesanan
+	QueueSize   int `json:"queue_size"`   // Kapasitas antrean pesanan ke dapur
+
+	DefaultPrepMinutes int `json:"default_prep_minutes"` // Perkiraan lama masak item tanpa prep_minutes, untuk perkiraan waktu siap
+
+	Stations []KitchenStation `json:"stations"` // Stasiun dapur yang menerima tiket terpisah, kosong berarti satu tiket per pesanan
+}
+
+// Struct untuk Konfigurasi server SMTP
+// Email struk hanya dikirim jika Host diisi
+type SMTPConfig struct {
+	Host         string `json:"host"`          // Alamat server SMTP, mis. "smtp.example.com"
+	Port         int    `json:"port"`          // Port server SMTP
+	Username     string `json:"username"`      // Nama pengguna SMTP, kosong jika tanpa autentikasi
+	Password     string `json:"password"`      // Kata sandi SMTP
+	From         string `json:"from"`          // Alamat pengirim, mis. "Resto <struk@example.com>"
+	RetryMinutes int    `json:"retry_minutes"` // Jeda dasar sebelum email yang gagal dicoba lagi
+	MaxAttempts  int    `json:"max_attempts"`  // Batas percobaan sebelum email dianggap gagal permanen
+
+	SubjectTemplate string `json:"subject_template"` // File text/template judul email struk, kosong untuk bawaan
+	BodyTemplate    string `json:"body_template"`    // File text/template isi email struk; tersedia .Receipt dan .Text
+}
+
+// Struct untuk Kebijakan PIN staf
+type SecurityConfig struct {
+	PINMinLength      int `json:"pin_min_length"`      // Panjang minimal PIN
+	PINMaxLength      int `json:"pin_max_length"`      // Panjang maksimal PIN
+	PINRotationDays   int `json:"pin_rotation_days"`   // PIN harus diganti setelah sekian hari, 0 berarti tidak pernah
+	MaxFailedAttempts int `json:"max_failed_attempts"` // Staf dikunci setelah sekian PIN salah berturut-turut, 0 berarti tidak dikunci
+	LockoutMinutes    int `json:"lockout_minutes"`     // Lama staf dikunci
+
+	OrderSigningKey string `json:"order_signing_key"` // Kunci HMAC-SHA256 untuk menandatangani pesanan ter-encode, kosong berarti tanpa tanda tangan
+}
+
+// Struct untuk Konfigurasi gateway pembayaran kartu/QRIS
+// Gateway hanya dipakai jika Provider diisi; URL tanpa Provider berarti API JSON "http"
+type GatewayConfig struct {
+	Provider       string `json:"provider"`        // mock, http, atau midtrans
+	URL            string `json:"url"`             // Alamat dasar API gateway, mis. "https://pay.example.com/v1"
+	APIKey         string `json:"api_key"`         // Kunci rahasia gateway
+	TimeoutSeconds int    `json:"timeout_seconds"` // Batas waktu setiap request ke gateway
+	WaitSeconds    int    `json:"wait_seconds"`    // Lama kasir menunggu pelanggan menyelesaikan pembayaran
+}
+
+// Struct untuk Konfigurasi cabang yang menerima rilis menu dari kantor pusat
+// Sinkronisasi hanya berjalan jika URL diisi
+type HeadOfficeConfig struct {
+	URL         string `json:"url"`          // Alamat server kantor pusat
+	APIKey      string `json:"api_key"`      // API key dengan scope "branch"
+	BranchID    string `json:"branch_id"`    // ID cabang ini, default nama host
+	PollSeconds int    `json:"poll_seconds"` // Selang waktu pemeriksaan rilis menu baru
+}
+
+// Struct untuk Konfigurasi alur kasir
+type CashierConfig struct {
+	TUI          bool `json:"tui"`           // Gunakan navigasi menu dengan tombol panah jika tersedia TTY
+	EmailReceipt bool `json:"email_receipt"` // Tawarkan pengiriman struk lewat email setelah pembayaran
+	AskTable     bool `json:"ask_table"`     // Tanyakan nomor meja setelah pesanan selesai
+	OpenOrders   bool `json:"open_orders"`   // Tawarkan menyimpan pesanan sebagai pesanan terbuka untuk dibayar nanti
+	TableTabs    bool `json:"table_tabs"`    // Pesanan makan di tempat ditambahkan ke tab meja per ronde dan dibayar saat meja meminta tagihan
+	FoodCourt    bool `json:"food_court"`    // Cetak potongan nomor antrean untuk setiap pesanan, bukan hanya bawa pulang
+	KitchenNotes bool `json:"kitchen_notes"` // Tanyakan catatan dapur baku untuk setiap item
+	Compact      bool `json:"compact"`       // Prompt ringkas dan menu satu kolom untuk layar ponsel
+	OrderTypes   bool `json:"order_types"`   // Tanyakan jenis pesanan (makan di tempat, bawa pulang, antar) di awal pesanan
+	AskAllergies bool `json:"ask_allergies"` // Tanyakan alergi pelanggan dan peringatkan item yang mengandung alergennya
+	MenuDetail   bool `json:"menu_detail"`   // Tampilkan deskripsi dan alergen di daftar menu
+	SkipConfirm  bool `json:"skip_confirm"`  // Kirim pesanan langsung setelah "selesai" tanpa ringkasan dan konfirmasi
+	GiftVouchers bool `json:"gift_vouchers"` // Tanyakan kode voucher hadiah sebelum metode pembayaran
+	Tips         bool `json:"tips"`          // Tanyakan tip dalam nominal atau persen sebelum metode pembayaran
+
+	DeliveryFee Money `json:"delivery_fee"` // Ongkos kirim bawaan untuk pesanan antar
+
+	OrderBuffer int    `json:"order_buffer"` // Kapasitas channel pesanan antara sesi kasir dan penghitungan total
+	IntakeAddr  string `json:"intake_addr"`  // Alamat HTTP untuk menerima pesanan dari tablet pelayan ke keranjang kasir, kosong berarti tidak dipakai
+
+	LockAfterMinutes int `json:"lock_after_minutes"` // Kunci sesi kasir setelah sekian menit tanpa aktivitas, 0 berarti tidak dikunci
+
+	IdleMinutes int    `json:"idle_minutes"` // Pesanan yang tidak disentuh sekian menit di prompt item dibatalkan atau ditahan, 0 berarti tidak pernah
+	IdleAction  string `json:"idle_action"`  // "cancel" (default) atau "hold" untuk pesanan yang ditinggalkan
+
+	PaymentAttempts int `json:"payment_attempts"` // Batas percobaan pembayaran sebelum pesanan disimpan sebagai draf, 0 berarti tanpa batas
+}
+
+// Struct untuk Jam buka restoran dalam format "15:04"
+type OpeningHours struct {
+	Open  string `json:"open"`  // Jam buka, mis. "08:00"
+	Close string `json:"close"` // Jam tutup, mis. "22:00"
+}
+
+// Struct untuk Konfigurasi pemantauan terminal di server pusat
+type FleetConfig struct {
+	SilentAfterSeconds   int    `json:"silent_after_seconds"`   // Terminal dianggap diam setelah sekian detik tanpa heartbeat
+	CheckIntervalSeconds int    `json:"check_interval_seconds"` // Selang waktu pemeriksaan terminal diam
+	AlertWebhook         string `json:"alert_webhook"`          // URL yang menerima peringatan terminal diam, boleh kosong
+}
+
+// Struct untuk Konfigurasi mode terminal
+// Terminal tidak menyimpan data dan hanya berkomunikasi dengan server pusat
+type ClientConfig struct {
+	ServerURL           string `json:"server_url"`             // Alamat server pusat, mis. "http://10.0.0.2:8080"
+	APIKey              string `json:"api_key"`                // API key dengan scope "terminal"
+	MenuCacheFile       string `json:"menu_cache_file"`        // Lokasi cache menu lokal
+	MenuCacheTTLSeconds int    `json:"menu_cache_ttl_seconds"` // Lama cache menu dianggap masih baru
+	TimeoutSeconds      int    `json:"timeout_seconds"`        // Batas waktu setiap request ke server
+	TerminalID          string `json:"terminal_id"`            // ID terminal untuk heartbeat, default nama host
+	HeartbeatSeconds    int    `json:"heartbeat_seconds"`      // Selang waktu pengiriman heartbeat
+	PrinterDevice       string `json:"printer_device"`         // Lokasi perangkat printer struk, boleh kosong
+	Outlet              string `json:"outlet"`                 // Outlet terminal ini di server bersama, dikirim sebagai header X-Outlet
+}
+
+// Struct untuk Konfigurasi laci kasir
+type CashDrawerConfig struct {
+	Denominations []int `json:"denominations"`  // Pecahan rupiah yang tersedia untuk kembalian
+	StartingFloat Money `json:"starting_float"` // Modal awal bawaan saat "drawer open"
+}
+
+// Struct untuk Konfigurasi server HTTP
+type ServerConfig struct {
+	Addr    string   `json:"addr"`     // Alamat listen server, mis. ":8080"
+	APIKeys []APIKey `json:"api_keys"` // Daftar API key untuk sistem eksternal
+}
+
+// Struct untuk API key sistem eksternal (mis. CRM/loyalty)
+// Setiap key hanya boleh mengakses endpoint sesuai scope yang diberikan
+type APIKey struct {
+	Name   string   `json:"name"`   // Nama pemilik key, mis. "crm"
+	Key    string   `json:"key"`    // Nilai rahasia key
+	Scopes []string `json:"scopes"` // Daftar scope, mis. "customers:read"
+}
+
+// Fungsi untuk membaca konfigurasi dari file
+// Jika file tidak ada, konfigurasi default yang digunakan
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("Gagal membaca konfigurasi: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("Format konfigurasi tidak valid: %w", err)
+		}
+	}
+	cfg.applyDefaults()
+	if err := cfg.Pricing.validate(); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := validateOrderRules(cfg.OrderRules); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := cfg.Storage.validate(); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := validateOutlets(cfg); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := cfg.Cashier.validate(); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := validateStations(cfg.Kitchen.Stations); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := cfg.OrderLimits.validate(); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := cfg.Receipt.validate(); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := cfg.Inventory.validate(); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := cfg.Close.validate(); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := cfg.Broker.validate(); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if err := validateCurrencies(cfg.Currencies); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	if _, err := newPaymentGateway(cfg.PaymentGateway); err != nil {
+		return nil, fmt.Errorf("Konfigurasi tidak valid: %w", err)
+	}
+	return cfg, nil
+}
+
+// Mengisi nilai default untuk field konfigurasi yang kosong
+func (c *Config) applyDefaults() {
+	if c.DataFile == "" {
+		c.DataFile = defaultDataFile
+	}
+	if c.Server.Addr == "" {
+		c.Server.Addr = defaultServerAddr
+	}
+	if len(c.CashDrawer.Denominations) == 0 {
+		c.CashDrawer.Denominations = defaultDenominations
+	}
+	if c.Receipt.Logo == "" {
+		c.Receipt.Logo = c.Printer.Logo
+	}
+	if c.Inventory.UsageDays == 0 {
+		c.Inventory.UsageDays = 7
+	}
+	if c.Inventory.CoverDays == 0 {
+		c.Inventory.CoverDays = 3
+	}
+	if c.Close.ReportDir == "" {
+		c.Close.ReportDir = "reports"
+	}
+	if c.Close.BackupDir == "" {
+		c.Close.BackupDir = "backups"
+	}
+	if c.Close.Keep == 0 {
+		c.Close.Keep = 14
+	}
+	if c.Client.MenuCacheFile == "" {
+		c.Client.MenuCacheFile = defaultMenuCache
+	}
+	if c.Client.MenuCacheTTLSeconds == 0 {
+		c.Client.MenuCacheTTLSeconds = 300
+	}
+	if c.Client.TimeoutSeconds == 0 {
+		c.Client.TimeoutSeconds = 5
+	}
+	if c.Client.TerminalID == "" {
+		c.Client.TerminalID, _ = os.Hostname()
+	}
+	if c.Client.HeartbeatSeconds == 0 {
+		c.Client.HeartbeatSeconds = 60
+	}
+	if c.Fleet.SilentAfterSeconds == 0 {
+		c.Fleet.SilentAfterSeconds = 180
+	}
+	if c.Fleet.CheckIntervalSeconds == 0 {
+		c.Fleet.CheckIntervalSeconds = 60
+	}
+	if c.HeadOffice.BranchID == "" {
+		c.HeadOffice.BranchID, _ = os.Hostname()
+	}
+	if c.IDs.Branch == "" {
+		c.IDs.Branch = c.HeadOffice.BranchID
+	}
+	if c.IDs.Digits == 0 {
+		c.IDs.Digits = 6
+	}
+	if c.IDs.SequenceFile == "" {
+		c.IDs.SequenceFile = "id-sequence.json"
+	}
+	if c.Security.PINMinLength == 0 {
+		c.Security.PINMinLength = 4
+	}
+	if c.Security.PINMaxLength < c.Security.PINMinLength {
+		c.Security.PINMaxLength = max(8, c.Security.PINMinLength)
+	}
+	if c.Security.MaxFailedAttempts == 0 {
+		c.Security.MaxFailedAttempts = 5
+	}
+	if c.Security.LockoutMinutes == 0 {
+		c.Security.LockoutMinutes = 15
+	}
+	if c.Telegram.APIURL == "" {
+		c.Telegram.APIURL = "https://api.telegram.org"
+	}
+	if c.Telegram.PollSeconds == 0 {
+		c.Telegram.PollSeconds = 10
+	}
+	if c.Email.Port == 0 {
+		c.Email.Port = 587
+	}
+	if c.Email.RetryMinutes == 0 {
+		c.Email.RetryMinutes = 5
+	}
+	if c.Email.MaxAttempts == 0 {
+		c.Email.MaxAttempts = 10
+	}
+	if c.Webhooks.MaxAttempts == 0 {
+		c.Webhooks.MaxAttempts = 8
+	}
+	if c.Webhooks.BackoffSeconds == 0 {
+		c.Webhooks.BackoffSeconds = 5
+	}
+	if c.Webhooks.TimeoutSeconds == 0 {
+		c.Webhooks.TimeoutSeconds = 10
+	}
+	if c.Broker.Subject == "" {
+		c.Broker.Subject = "tugaskedua.{event}"
+	}
+	if c.Broker.BufferSize == 0 {
+		c.Broker.BufferSize = 1000
+	}
+	if c.Broker.TimeoutSeconds == 0 {
+		c.Broker.TimeoutSeconds = 5
+	}
+	if c.Calendar.DurationMinutes == 0 {
+		c.Calendar.DurationMinutes = 90
+	}
+	if c.Printer.Width == 0 {
+		c.Printer.Width = 32
+	}
+	if c.Printer.FeedLines == 0 {
+		c.Printer.FeedLines = 4
+	}
+	for i := range c.Kitchen.Stations {
+		// Printer stasiun yang tidak diatur lebar kertasnya mengikuti printer struk
+		p := &c.Kitchen.Stations[i].Printer
+		if p.Width == 0 {
+			p.Width = c.Printer.Width
+		}
+		if p.FeedLines == 0 {
+			p.FeedLines = c.Printer.FeedLines
+		}
+	}
+	if !c.Printer.Enabled() {
+		// Terminal lama hanya mengatur client.printer_device
+		c.Printer.Device = c.Client.PrinterDevice
+	}
+	if c.Wifi.LowPool == 0 {
+		c.Wifi.LowPool = 20
+	}
+	if c.Wifi.Minutes == 0 {
+		c.Wifi.Minutes = 120
+	}
+	if c.Offsite.Time == "" {
+		c.Offsite.Time = "23:30"
+	}
+	if c.Offsite.RetentionDays == 0 {
+		c.Offsite.RetentionDays = 30
+	}
+	if c.Reservations.GraceMinutes == 0 {
+		c.Reservations.GraceMinutes = 15
+	}
+	if c.Reservations.SlotMinutes == 0 {
+		c.Reservations.SlotMinutes = defaultSlotMinutes
+	}
+	if c.Kitchen.Workers == 0 {
+		c.Kitchen.Workers = 2
+	}
+	if c.Kitchen.PrepSeconds == 0 {
+		c.Kitchen.PrepSeconds = 2
+	}
+	if c.Kitchen.DefaultPrepMinutes == 0 {
+		c.Kitchen.DefaultPrepMinutes = 10
+	}
+	if c.Kitchen.QueueSize == 0 {
+		c.Kitchen.QueueSize = 16
+	}
+	if c.PaymentGateway.TimeoutSeconds == 0 {
+		c.PaymentGateway.TimeoutSeconds = 15
+	}
+	for i := range c.Channels {
+		if c.Channels[i].PollSeconds == 0 {
+			c.Channels[i].PollSeconds = 30
+		}
+		if c.Channels[i].SimulatorOrders == 0 {
+			c.Channels[i].SimulatorOrders = 1
+		}
+	}
+	if c.Watchdog.CheckSeconds == 0 {
+		c.Watchdog.CheckSeconds = 5
+	}
+	if c.Watchdog.KitchenSeconds == 0 {
+		c.Watchdog.KitchenSeconds = max(30, 10*c.Kitchen.PrepSeconds)
+	}
+	if c.Watchdog.PrintSeconds == 0 {
+		c.Watchdog.PrintSeconds = 15
+	}
+	if c.Watchdog.PaymentSeconds == 0 {
+		c.Watchdog.PaymentSeconds = 2 * c.PaymentGateway.TimeoutSeconds
+	}
+	if c.PaymentGateway.Provider == "" && c.PaymentGateway.URL != "" {
+		c.PaymentGateway.Provider = "http"
+	}
+	if c.PaymentGateway.WaitSeconds == 0 {
+		c.PaymentGateway.WaitSeconds = 120
+	}
+	if c.HeadOffice.PollSeconds == 0 {
+		c.HeadOffice.PollSeconds = 60
+	}
+	if c.Language == "" {
+		c.Language = "id"
+	}
+	c.Pricing.applyDefaults()
+	for i := range c.Outlets {
+		c.Outlets[i].applyDefaults(c)
+	}
+}
+
+// Mengisi nilai default aturan harga yang kosong
+func (p *PricingConfig) applyDefaults() {
+	if p.Tax == "" {
+		p.Tax = "none"
+	}
+	if p.Rounding == "" {
+		p.Rounding = "none"
+	}
+	if p.Stage == "" {
+		p.Stage = string(RoundPerPayment)
+	}
+}
+
+// Memeriksa apakah API key memiliki scope tertentu
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Struct untuk Pelanggan
+// Mewakili pelanggan member beserta tingkat keanggotaan dan persetujuan datanya
+type Customer struct {
+	ID        string    `json:"id"`         // ID unik pelanggan
+	Name      string    `json:"name"`       // Nama pelanggan
+	Phone     string    `json:"phone"`      // Nomor HP, digunakan untuk mencari pelanggan
+	Email     string    `json:"email"`      // Alamat email pelanggan
+	Tier      string    `json:"tier"`       // Tingkat keanggotaan, mis. "silver" atau "gold"
+	Consent   Consent   `json:"consent"`    // Persetujuan penggunaan data pelanggan
+	CreatedAt time.Time `json:"created_at"` // Waktu pelanggan didaftarkan
+}
+
+// Struct untuk Persetujuan data pelanggan
+type Consent struct {
+	DataSharing bool      `json:"data_sharing"` // Boleh dibagikan ke sistem loyalty/CRM eksternal
+	Marketing   bool      `json:"marketing"`    // Boleh dihubungi untuk promosi
+	UpdatedAt   time.Time `json:"updated_at"`   // Waktu persetujuan terakhir diubah
+}
+
+// Error yang dikembalikan saat mengelola pelanggan
+var (
+	errCustomerNotFound = errors.New("Pelanggan tidak ditemukan")
+	errCustomerExists   = errors.New("Nomor HP sudah terdaftar")
+)
+
+// Fungsi untuk menormalkan nomor HP agar pencarian konsisten
+// Contoh: "+62 812-3456" dan "0812 3456" dianggap sama
+func normalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if strings.HasPrefix(digits, "62") {
+		digits = "0" + digits[2:]
+	}
+	return digits
+}
+
+// Menambahkan pelanggan baru
+func (s *Store) AddCustomer(c Customer) (Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Phone = normalizePhone(c.Phone)
+	for _, existing := range s.data.Customers {
+		if c.Phone != "" && existing.Phone == c.Phone {
+			return Customer{}, errCustomerExists
+		}
+	}
+	if c.ID == "" {
+		c.ID = customerIDs.NewID("CUS")
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	c.Consent.UpdatedAt = c.CreatedAt
+	s.data.Customers = append(s.data.Customers, c)
+	return c, s.save()
+}
+
+// Mencari pelanggan berdasarkan ID
+func (s *Store) Customer(id string) (Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.data.Customers {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return Customer{}, errCustomerNotFound
+}
+
+// Mencari pelanggan berdasarkan nomor HP
+func (s *Store) CustomerByPhone(phone string) (Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	phone = normalizePhone(phone)
+	for _, c := range s.data.Customers {
+		if c.Phone == phone {
+			return c, nil
+		}
+	}
+	return Customer{}, errCustomerNotFound
+}
+
+// Mengambil salinan seluruh pelanggan
+func (s *Store) Customers() []Customer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Customer(nil), s.data.Customers...)
+}
+
+// Memperbarui tingkat keanggotaan pelanggan
+func (s *Store) UpdateCustomerTier(id, tier string) (Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.Customers {
+		if s.data.Customers[i].ID == id {
+			s.data.Customers[i].Tier = tier
+			return s.data.Customers[i], s.save()
+		}
+	}
+	return Customer{}, errCustomerNotFound
+}
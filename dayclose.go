@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Struct untuk Konfigurasi tutup hari otomatis dalam mode server
+type DayCloseConfig struct {
+	Time      string `json:"time"`       // Jam tutup hari, mis. "23:30"; kosong berarti tidak dijalankan otomatis
+	OpenTabs  string `json:"open_tabs"`  // Tab meja yang belum dibayar: "flag" (default) hanya ditandai, "close" juga dilepas dari mejanya
+	ReportDir string `json:"report_dir"` // Folder laporan harian, default "reports"
+	BackupDir string `json:"backup_dir"` // Folder arsip cadangan, default "backups"
+	Keep      int    `json:"keep"`       // Jumlah arsip cadangan tutup hari yang disimpan, 0 berarti default 14
+}
+
+// Memeriksa jam dan pilihan tab meja tutup hari
+func (c DayCloseConfig) validate() error {
+	if c.Time != "" {
+		if _, err := time.Parse("15:04", c.Time); err != nil {
+			return fmt.Errorf("close.time %q harus berformat HH:MM", c.Time)
+		}
+	}
+	switch c.OpenTabs {
+	case "", "flag", "close":
+	default:
+		return fmt.Errorf("close.open_tabs %q tidak dikenal (flag atau close)", c.OpenTabs)
+	}
+	if c.Keep < 0 {
+		return fmt.Errorf("close.keep tidak boleh negatif")
+	}
+	return nil
+}
+
+// Tanggal usaha yang ditutup pada waktu tertentu
+// Tutup hari sebelum pukul 12:00 dianggap menutup hari sebelumnya, untuk restoran yang buka lewat tengah malam
+func (c DayCloseConfig) businessDate(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if at, err := time.Parse("15:04", c.Time); err == nil && at.Hour() < 12 {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// Struct untuk hasil satu kali tutup hari
+type DayClose struct {
+	Date        string    `json:"date"`              // Tanggal usaha yang ditutup, YYYY-MM-DD
+	ClosedAt    time.Time `json:"closed_at"`         // Waktu tutup hari dijalankan
+	FlaggedTabs int       `json:"flagged_tabs"`      // Tab meja belum dibayar yang ditandai
+	ClosedTabs  int       `json:"closed_tabs"`       // Tab meja yang dilepas dari mejanya
+	Report      string    `json:"report,omitempty"`  // File laporan harian yang ditulis
+	Backup      string    `json:"backup,omitempty"`  // File arsip cadangan yang ditulis
+	Errors      []string  `json:"errors,omitempty"`  // Langkah yang gagal, kosong jika semua berhasil
+	Manual      bool      `json:"manual,omitempty"`  // Dijalankan dengan "close run", bukan jadwal
+	Outlet      string    `json:"outlet,omitempty"`  // Outlet yang ditutup, kosong untuk file data utama
+	QueueFrom   int       `json:"queue_from"`        // Nomor antrean terakhir sebelum diulang dari 1
+	Removed     int       `json:"removed,omitempty"` // Arsip cadangan lama yang dihapus
+}
+
+// Menandai tab meja yang belum dibayar saat tutup hari
+// release melepas tab dari mejanya sehingga tamu berikutnya membuka tab baru; tab tetap dibayar dengan "order pay"
+func (s *Store) FlagOpenTabs(release bool, now time.Time) (flagged, released int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.Drafts {
+		o := &s.data.Drafts[i]
+		if !o.Tab || o.TabClosed {
+			continue
+		}
+		if o.TabFlaggedAt.IsZero() {
+			o.TabFlaggedAt = now
+		}
+		flagged++
+		if release {
+			o.TabClosed = true
+			if t := s.openTable(o.Table); t != nil {
+				t.ClosedAt = now
+			}
+			released++
+		}
+	}
+	if flagged == 0 {
+		return 0, 0, nil
+	}
+	return flagged, released, s.save()
+}
+
+// Mengulang nomor antrean dari 1 untuk tanggal usaha berikutnya
+// Mengembalikan nomor antrean terakhir sebelum diulang
+func (s *Store) RotateQueue(next time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last := s.data.QueueSeq
+	s.data.QueueDate, s.data.QueueSeq = next.Format("2006-01-02"), 0
+	return last, s.save()
+}
+
+// Menyimpan hasil tutup hari
+func (s *Store) RecordDayClose(c DayClose) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Closes = append(s.data.Closes, c)
+	return s.save()
+}
+
+// Mengambil salinan riwayat tutup hari
+func (s *Store) DayCloses() []DayClose {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DayClose(nil), s.data.Closes...)
+}
+
+// Tanggal usaha sudah ditutup oleh jadwal atau belum
+func (s *Store) scheduledClose(date string) bool {
+	return slices.ContainsFunc(s.DayCloses(), func(c DayClose) bool { return c.Date == date && !c.Manual })
+}
+
+// Fungsi untuk menjalankan tutup hari: menandai tab meja yang belum dibayar, menulis laporan harian,
+// mengulang nomor antrean, lalu membuat arsip cadangan
+// Langkah yang gagal dicatat dan langkah berikutnya tetap dijalankan
+// Nomor urut ID pesanan (ids.strategy sequence) tidak diulang agar ID tetap unik
+func closeDay(cfg *Config, store *Store, date time.Time, manual bool) DayClose {
+	now := time.Now()
+	dc := DayClose{Date: date.Format("2006-01-02"), ClosedAt: now, Manual: manual}
+	if cfg.outlet != nil {
+		dc.Outlet = cfg.outlet.ID
+	}
+	fail := func(step string, err error) {
+		dc.Errors = append(dc.Errors, fmt.Sprintf("%s: %v", step, err))
+	}
+
+	var err error
+	if dc.FlaggedTabs, dc.ClosedTabs, err = store.FlagOpenTabs(cfg.Close.OpenTabs == "close", now); err != nil {
+		fail("tab meja", err)
+	}
+
+	var report bytes.Buffer
+	buildDailyReport(store, date).Print(&report)
+	if dc.FlaggedTabs > 0 {
+		fmt.Fprintf(&report, "Tab meja belum dibayar: %d\n", dc.FlaggedTabs)
+	}
+	if err := os.MkdirAll(cfg.Close.ReportDir, 0o755); err != nil {
+		fail("laporan", err)
+	} else {
+		path := filepath.Join(cfg.Close.ReportDir, closeFileName("report", dc.Outlet, dc.Date, ".txt"))
+		if err := os.WriteFile(path, report.Bytes(), 0o644); err != nil {
+			fail("laporan", err)
+		} else {
+			dc.Report = path
+		}
+	}
+
+	if dc.QueueFrom, err = store.RotateQueue(date.AddDate(0, 0, 1)); err != nil {
+		fail("nomor antrean", err)
+	}
+
+	if path, removed, err := writeCloseBackup(cfg, store, dc); err != nil {
+		fail("cadangan", err)
+	} else {
+		dc.Backup, dc.Removed = path, removed
+	}
+
+	if err := store.RecordDayClose(dc); err != nil {
+		fail("riwayat", err)
+	}
+	return dc
+}
+
+// Nama file laporan dan cadangan tutup hari, mis. "report-2024-01-01.txt" atau "backup-jkt01-2024-01-01.zip"
+func closeFileName(prefix, outlet, date, ext string) string {
+	if outlet != "" {
+		prefix += "-" + strings.ToLower(outlet)
+	}
+	return prefix + "-" + date + ext
+}
+
+// Fungsi untuk menulis arsip cadangan tutup hari lalu menghapus arsip lama di luar jumlah yang disimpan
+func writeCloseBackup(cfg *Config, store *Store, dc DayClose) (string, int, error) {
+	if err := os.MkdirAll(cfg.Close.BackupDir, 0o755); err != nil {
+		return "", 0, err
+	}
+	var archive bytes.Buffer
+	if _, err := writeBackupArchive(&archive, cfg, store); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(cfg.Close.BackupDir, closeFileName("backup", dc.Outlet, dc.Date, ".zip"))
+	if err := os.WriteFile(path, archive.Bytes(), 0o600); err != nil {
+		return "", 0, err
+	}
+	// Nama file berisi tanggal, sehingga urutan nama sama dengan urutan tanggal
+	old, err := filepath.Glob(filepath.Join(cfg.Close.BackupDir, closeFileName("backup", dc.Outlet, "????-??-??", ".zip")))
+	if err != nil || len(old) <= cfg.Close.Keep {
+		return path, 0, err
+	}
+	slices.Sort(old)
+	removed := 0
+	for _, f := range old[:len(old)-cfg.Close.Keep] {
+		if os.Remove(f) == nil {
+			removed++
+		}
+	}
+	return path, removed, nil
+}
+
+// Fungsi untuk menampilkan hasil tutup hari
+func printDayClose(c DayClose) {
+	title := "Tutup hari " + c.Date
+	if c.Outlet != "" {
+		title += " outlet " + c.Outlet
+	}
+	fmt.Printf("%s (%s)\n", title, c.ClosedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("  Tab meja belum dibayar: %d ditandai, %d dilepas dari meja\n", c.FlaggedTabs, c.ClosedTabs)
+	fmt.Printf("  Nomor antrean diulang dari 1 (terakhir %03d)\n", c.QueueFrom)
+	if c.Report != "" {
+		fmt.Println("  Laporan harian:", c.Report)
+	}
+	if c.Backup != "" {
+		fmt.Printf("  Cadangan: %s (%d arsip lama dihapus)\n", c.Backup, c.Removed)
+	}
+	for _, e := range c.Errors {
+		fmt.Println("  GAGAL", e)
+	}
+}
+
+// Fungsi untuk menjalankan tutup hari sesuai jadwal selama server berjalan
+// Tutup hari hanya dijalankan sekali per tanggal usaha, termasuk jika server dijalankan ulang setelah jam tutup
+func runDailyClose(cfg *Config, store *Store) {
+	at, err := time.Parse("15:04", cfg.Close.Time)
+	if err != nil {
+		fmt.Println("close.time tidak valid, tutup hari tidak dijalankan:", cfg.Close.Time)
+		return
+	}
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		if now.Hour()*60+now.Minute() < at.Hour()*60+at.Minute() {
+			continue
+		}
+		date := cfg.Close.businessDate(now)
+		if store.scheduledClose(date.Format("2006-01-02")) {
+			continue
+		}
+		c := closeDay(cfg, store, date, false)
+		printDayClose(c)
+		if len(c.Errors) > 0 {
+			fmt.Println("PERINGATAN: tutup hari", c.Date, "tidak selesai:", strings.Join(c.Errors, "; "))
+		}
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "close"
+func runCloseCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: close run [-date YYYY-MM-DD] atau close list")
+	}
+	switch args[0] {
+	case "run":
+		fs := flag.NewFlagSet("close run", flag.ContinueOnError)
+		dateFlag := fs.String("date", cfg.Close.businessDate(time.Now()).Format("2006-01-02"), "tanggal usaha yang ditutup")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		date, err := time.ParseInLocation("2006-01-02", *dateFlag, time.Local)
+		if err != nil {
+			return fmt.Errorf("Format tanggal harus YYYY-MM-DD")
+		}
+		c := closeDay(cfg, store, date, true)
+		printDayClose(c)
+		if len(c.Errors) > 0 {
+			return fmt.Errorf("Tutup hari tidak selesai: %s", strings.Join(c.Errors, "; "))
+		}
+		return nil
+	case "list":
+		closes := store.DayCloses()
+		if len(closes) == 0 {
+			fmt.Println("Belum ada tutup hari.")
+		}
+		for _, c := range closes {
+			status := "selesai"
+			if len(c.Errors) > 0 {
+				status = fmt.Sprintf("%d langkah gagal", len(c.Errors))
+			}
+			mode := "jadwal"
+			if c.Manual {
+				mode = "manual"
+			}
+			fmt.Printf("%s  %s  %-6s  tab %d  %s\n", c.Date, c.ClosedAt.Local().Format("2006-01-02 15:04"), mode, c.FlaggedTabs, status)
+		}
+		return nil
+	}
+	return fmt.Errorf("Sub-perintah close tidak dikenal: %s", args[0])
+}
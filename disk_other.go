@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+// Fungsi untuk membaca sisa ruang disk; tidak didukung di sistem operasi ini
+func diskFreeBytes(path string) int64 {
+	return -1
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Status sengketa (chargeback) pembayaran
+type DisputeStatus string
+
+const (
+	DisputeOpen     DisputeStatus = "open"     // Sengketa diajukan, dana ditahan gateway
+	DisputeWon      DisputeStatus = "won"      // Sengketa dimenangkan restoran, dana dikembalikan
+	DisputeLost     DisputeStatus = "lost"     // Sengketa dimenangkan pemegang kartu, dana dipotong
+	DisputeAccepted DisputeStatus = "accepted" // Restoran menerima sengketa tanpa pembelaan, dana dipotong
+)
+
+// Struct untuk Sengketa pembayaran dari gateway
+// Dihubungkan dengan pembayaran dan pesanan asal
+type Dispute struct {
+	ID          string        `json:"id"`                   // ID unik sengketa
+	PaymentID   string        `json:"payment_id"`           // Pembayaran yang disengketakan
+	OrderIDs    []string      `json:"order_ids"`            // Pesanan yang dibayar dengan pembayaran tersebut
+	ProviderRef string        `json:"provider_ref"`         // Referensi sengketa dari gateway
+	Amount      Money         `json:"amount"`               // Nominal yang disengketakan
+	Fee         Money         `json:"fee"`                  // Biaya chargeback dari gateway
+	Reason      string        `json:"reason"`               // Alasan sengketa dari pemegang kartu
+	Status      DisputeStatus `json:"status"`               // Status sengketa
+	Note        string        `json:"note,omitempty"`       // Catatan penyelesaian
+	OpenedAt    time.Time     `json:"opened_at"`            // Waktu sengketa diterima
+	ResolvedAt  time.Time     `json:"resolved_at,omitzero"` // Waktu sengketa selesai
+}
+
+var (
+	errDisputeNotFound   = errors.New("Sengketa tidak ditemukan")
+	errDisputeResolved   = errors.New("Sengketa sudah diselesaikan")
+	errPaymentNotFound   = errors.New("Pembayaran tidak ditemukan")
+	errDisputeCashMethod = errors.New("Pembayaran tunai tidak bisa disengketakan")
+)
+
+// Memeriksa apakah sengketa sudah selesai
+func (d Dispute) Resolved() bool {
+	return d.Status != DisputeOpen
+}
+
+// Dampak keuangan sengketa: dana yang ditahan (masih terbuka) atau dipotong (kalah/diterima)
+// Biaya chargeback tetap dipotong walaupun sengketa dimenangkan
+func (d Dispute) Impact() (held, deducted Money) {
+	switch d.Status {
+	case DisputeOpen:
+		return d.Amount, d.Fee
+	case DisputeLost, DisputeAccepted:
+		return 0, d.Amount + d.Fee
+	default:
+		return 0, d.Fee
+	}
+}
+
+// Fungsi untuk memeriksa status sengketa yang valid
+func parseDisputeStatus(value string) (DisputeStatus, error) {
+	status := DisputeStatus(value)
+	if !slices.Contains([]DisputeStatus{DisputeOpen, DisputeWon, DisputeLost, DisputeAccepted}, status) {
+		return "", fmt.Errorf("Status sengketa tidak dikenal: %s", value)
+	}
+	return status, nil
+}
+
+// Mencatat sengketa baru atau memperbarui sengketa dengan referensi gateway yang sama
+// Pembayaran dapat dicari dengan ID pembayaran atau referensi transaksi gateway
+func (s *Store) RecordDispute(d Dispute) (Dispute, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d.ProviderRef != "" {
+		for i := range s.data.Disputes {
+			existing := &s.data.Disputes[i]
+			if existing.ProviderRef != d.ProviderRef {
+				continue
+			}
+			if d.Status != "" && d.Status != existing.Status {
+				existing.Status = d.Status
+				if existing.Resolved() {
+					existing.ResolvedAt = time.Now()
+				}
+			}
+			if d.Amount > 0 {
+				existing.Amount = d.Amount
+			}
+			if d.Fee > 0 {
+				existing.Fee = d.Fee
+			}
+			return *existing, s.save()
+		}
+	}
+
+	index := slices.IndexFunc(s.data.Payments, func(p Payment) bool {
+		return p.ID == d.PaymentID || (d.PaymentID != "" && p.ProviderRef == d.PaymentID)
+	})
+	if index < 0 {
+		return Dispute{}, errPaymentNotFound
+	}
+	payment := s.data.Payments[index]
+	if payment.PaymentMethod() == MethodCash {
+		return Dispute{}, errDisputeCashMethod
+	}
+	d.ID = newID("DSP")
+	d.PaymentID = payment.ID
+	d.OrderIDs = append([]string(nil), payment.OrderIDs...)
+	if d.Amount <= 0 || d.Amount > payment.Amount {
+		d.Amount = payment.Amount
+	}
+	if d.Status == "" {
+		d.Status = DisputeOpen
+	}
+	if d.OpenedAt.IsZero() {
+		d.OpenedAt = time.Now()
+	}
+	if d.Resolved() {
+		d.ResolvedAt = d.OpenedAt
+	}
+	s.data.Disputes = append(s.data.Disputes, d)
+	return d, s.save()
+}
+
+// Menyelesaikan sengketa yang masih terbuka
+func (s *Store) ResolveDispute(id string, status DisputeStatus, note string) (Dispute, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.Disputes {
+		d := &s.data.Disputes[i]
+		if d.ID != id {
+			continue
+		}
+		if d.Resolved() {
+			return Dispute{}, errDisputeResolved
+		}
+		d.Status, d.Note, d.ResolvedAt = status, note, time.Now()
+		return *d, s.save()
+	}
+	return Dispute{}, errDisputeNotFound
+}
+
+// Mengambil salinan seluruh sengketa
+func (s *Store) Disputes() []Dispute {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Dispute(nil), s.data.Disputes...)
+}
+
+// Fungsi untuk menampilkan daftar sengketa
+func printDisputes(disputes []Dispute) {
+	if len(disputes) == 0 {
+		fmt.Println("Tidak ada sengketa.")
+		return
+	}
+	for _, d := range disputes {
+		held, deducted := d.Impact()
+		fmt.Printf("%s  %-8s %s  Rp%.2f  pembayaran %s  pesanan %v\n", d.ID, d.Status, d.OpenedAt.Local().Format("2006-01-02"),
+			d.Amount, d.PaymentID, d.OrderIDs)
+		fmt.Printf("  alasan: %s  ditahan: Rp%.2f  dipotong: Rp%.2f\n", d.Reason, held, deducted)
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "dispute"
+func runDisputeCommand(store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: dispute open <id pembayaran>|resolve <id>|list")
+	}
+	switch args[0] {
+	case "open":
+		fs := flag.NewFlagSet("dispute open", flag.ContinueOnError)
+		amount := fs.Float64("amount", 0, "nominal yang disengketakan (default: seluruh pembayaran)")
+		fee := fs.Float64("fee", 0, "biaya chargeback dari gateway")
+		reason := fs.String("reason", "", "alasan sengketa")
+		ref := fs.String("ref", "", "referensi sengketa dari gateway")
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: dispute open <id pembayaran> [-amount n] [-fee n] [-reason teks] [-ref ref]")
+		}
+		if err := fs.Parse(args[2:]); err != nil {
+			return err
+		}
+		d, err := store.RecordDispute(Dispute{PaymentID: args[1], Amount: Rupiah(*amount), Fee: Rupiah(*fee), Reason: *reason, ProviderRef: *ref})
+		if err != nil {
+			return err
+		}
+		fmt.Println("Sengketa", d.ID, "dicatat untuk pembayaran", d.PaymentID)
+	case "resolve":
+		fs := flag.NewFlagSet("dispute resolve", flag.ContinueOnError)
+		statusFlag := fs.String("status", "", "hasil sengketa: won, lost, atau accepted")
+		note := fs.String("note", "", "catatan penyelesaian")
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: dispute resolve <id> -status won|lost|accepted")
+		}
+		if err := fs.Parse(args[2:]); err != nil {
+			return err
+		}
+		status, err := parseDisputeStatus(*statusFlag)
+		if err != nil || status == DisputeOpen {
+			return fmt.Errorf("-status harus won, lost, atau accepted")
+		}
+		d, err := store.ResolveDispute(args[1], status, *note)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Sengketa", d.ID, "diselesaikan:", d.Status)
+	case "list":
+		fs := flag.NewFlagSet("dispute list", flag.ContinueOnError)
+		statusFlag := fs.String("status", "", "tampilkan hanya status tertentu")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		var disputes []Dispute
+		for _, d := range store.Disputes() {
+			if *statusFlag == "" || string(d.Status) == *statusFlag {
+				disputes = append(disputes, d)
+			}
+		}
+		printDisputes(disputes)
+	default:
+		return fmt.Errorf("Sub-perintah dispute tidak dikenal: %s", args[0])
+	}
+	return nil
+}
+
+// POST /api/v1/gateway/disputes
+// Dipanggil gateway saat sengketa diajukan atau statusnya berubah
+func (s *Server) handleGatewayDispute(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		PaymentReference string `json:"payment_reference"` // ID pembayaran atau referensi transaksi gateway
+		DisputeReference string `json:"dispute_reference"`
+		Amount           Money  `json:"amount"`
+		Fee              Money  `json:"fee"`
+		Reason           string `json:"reason"`
+		Status           string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.DisputeReference == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi dispute_reference")
+		return
+	}
+	var status DisputeStatus
+	if body.Status != "" {
+		var err error
+		if status, err = parseDisputeStatus(body.Status); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	d, err := s.store.RecordDispute(Dispute{
+		PaymentID:   body.PaymentReference,
+		ProviderRef: body.DisputeReference,
+		Amount:      body.Amount,
+		Fee:         body.Fee,
+		Reason:      body.Reason,
+		Status:      status,
+	})
+	switch {
+	case errors.Is(err, errPaymentNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, errDisputeCashMethod):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, d)
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct untuk Email struk yang menunggu dikirim
+// Email yang gagal tetap di antrean dan dicoba lagi nanti
+type QueuedEmail struct {
+	ID            string    `json:"id"`                   // ID unik email
+	PaymentID     string    `json:"payment_id"`           // Pembayaran yang struknya dikirim
+	To            string    `json:"to"`                   // Alamat tujuan
+	Subject       string    `json:"subject"`              // Judul email
+	Body          string    `json:"body"`                 // Isi email (struk)
+	Attempts      int       `json:"attempts"`             // Jumlah percobaan pengiriman
+	LastError     string    `json:"last_error,omitempty"` // Error percobaan terakhir
+	CreatedAt     time.Time `json:"created_at"`           // Waktu email dimasukkan ke antrean
+	NextAttemptAt time.Time `json:"next_attempt_at"`      // Waktu percobaan berikutnya
+	SentAt        time.Time `json:"sent_at,omitzero"`     // Waktu email berhasil dikirim
+
+	PDF bool `json:"pdf,omitempty"` // Struk PDF dilampirkan, disusun ulang dari pembayaran saat dikirim
+}
+
+var (
+	errEmailDisabled = errors.New("Server SMTP belum dikonfigurasi")
+	errEmailNotFound = errors.New("Email tidak ditemukan di antrean")
+)
+
+// Memeriksa apakah email sudah menyerah dikirim setelah batas percobaan
+func (e QueuedEmail) GaveUp(cfg SMTPConfig) bool {
+	return e.SentAt.IsZero() && e.Attempts >= cfg.MaxAttempts
+}
+
+// Memasukkan email ke antrean pengiriman
+func (s *Store) QueueEmail(e QueuedEmail) (QueuedEmail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.ID = newID("EML")
+	e.CreatedAt = time.Now()
+	e.NextAttemptAt = e.CreatedAt
+	s.data.Emails = append(s.data.Emails, e)
+	return e, s.save()
+}
+
+// Mengambil email yang belum terkirim dan sudah waktunya dicoba lagi
+func (s *Store) DueEmails(cfg SMTPConfig, now time.Time) []QueuedEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []QueuedEmail
+	for _, e := range s.data.Emails {
+		if e.SentAt.IsZero() && !e.GaveUp(cfg) && !now.Before(e.NextAttemptAt) {
+			due = append(due, e)
+		}
+	}
+	return due
+}
+
+// Mengambil salinan seluruh antrean email
+func (s *Store) Emails() []QueuedEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]QueuedEmail(nil), s.data.Emails...)
+}
+
+// Mencatat hasil percobaan pengiriman email
+// Percobaan yang gagal dijadwalkan ulang dengan jeda yang makin panjang
+func (s *Store) RecordEmailAttempt(id string, sendErr error, cfg SMTPConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.Emails {
+		e := &s.data.Emails[i]
+		if e.ID != id {
+			continue
+		}
+		e.Attempts++
+		if sendErr == nil {
+			e.SentAt, e.LastError = time.Now(), ""
+		} else {
+			e.LastError = sendErr.Error()
+			e.NextAttemptAt = time.Now().Add(time.Duration(e.Attempts*cfg.RetryMinutes) * time.Minute)
+		}
+		return s.save()
+	}
+	return errEmailNotFound
+}
+
+// Mengatur ulang email yang sudah menyerah agar dicoba lagi dari awal
+func (s *Store) RequeueEmail(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.Emails {
+		if e := &s.data.Emails[i]; e.ID == id {
+			e.Attempts, e.NextAttemptAt = 0, time.Now()
+			return s.save()
+		}
+	}
+	return errEmailNotFound
+}
+
+// Fungsi untuk memasukkan struk pembayaran ke antrean email
+func queueReceiptEmail(cfg *Config, store *Store, paymentID, to string) (QueuedEmail, error) {
+	if cfg.Email.Host == "" {
+		return QueuedEmail{}, errEmailDisabled
+	}
+	addr, err := mail.ParseAddress(to)
+	if err != nil {
+		return QueuedEmail{}, fmt.Errorf("Alamat email tidak valid: %s", to)
+	}
+	receipt, err := store.Receipt(paymentID)
+	if err != nil {
+		return QueuedEmail{}, err
+	}
+	subject, body, err := renderReceiptEmail(cfg.Email, receipt)
+	if err != nil {
+		return QueuedEmail{}, err
+	}
+	return store.QueueEmail(QueuedEmail{
+		PaymentID: paymentID,
+		To:        addr.Address,
+		Subject:   subject,
+		Body:      body,
+		PDF:       cfg.Receipt.PDF(),
+	})
+}
+
+// Fungsi untuk mengirim satu email melalui server SMTP
+// pdf berisi struk PDF yang dilampirkan, nil jika email hanya berisi teks
+func sendEmail(cfg SMTPConfig, e QueuedEmail, pdf []byte) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", e.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", e.Subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	if pdf == nil {
+		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		msg.WriteString(strings.ReplaceAll(e.Body, "\n", "\r\n"))
+	} else {
+		writePDFAttachment(&msg, e, pdf)
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	from := cfg.From
+	if addr, err := mail.ParseAddress(cfg.From); err == nil {
+		from = addr.Address
+	}
+	server := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	return smtp.SendMail(server, auth, from, []string{e.To}, []byte(msg.String()))
+}
+
+// Fungsi untuk menulis isi email teks beserta lampiran struk PDF sebagai multipart/mixed
+func writePDFAttachment(msg *strings.Builder, e QueuedEmail, pdf []byte) {
+	mw := multipart.NewWriter(msg)
+	fmt.Fprintf(msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+	text, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=UTF-8"}})
+	io.WriteString(text, strings.ReplaceAll(e.Body, "\n", "\r\n"))
+	name := "struk-" + e.PaymentID + ".pdf"
+	part, _ := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {fmt.Sprintf("application/pdf; name=%q", name)},
+		"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", name)},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	encoded := base64.StdEncoding.EncodeToString(pdf)
+	for len(encoded) > 76 {
+		io.WriteString(part, encoded[:76]+"\r\n")
+		encoded = encoded[76:]
+	}
+	io.WriteString(part, encoded+"\r\n")
+	mw.Close()
+}
+
+// Fungsi untuk menyusun ulang struk PDF yang dilampirkan ke email
+func receiptEmailPDF(store *Store, e QueuedEmail) ([]byte, error) {
+	if !e.PDF {
+		return nil, nil
+	}
+	r, err := store.Receipt(e.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := r.RenderPDF(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Fungsi untuk mencoba mengirim seluruh email yang sudah waktunya
+func deliverQueuedEmails(cfg *Config, store *Store) (sent, failed int) {
+	for _, e := range store.DueEmails(cfg.Email, time.Now()) {
+		pdf, err := receiptEmailPDF(store, e)
+		if err == nil {
+			err = sendEmail(cfg.Email, e, pdf)
+		}
+		if err != nil {
+			failed++
+		} else {
+			sent++
+		}
+		if err := store.RecordEmailAttempt(e.ID, err, cfg.Email); err != nil {
+			fmt.Println("Gagal mencatat pengiriman email:", err)
+		}
+	}
+	return sent, failed
+}
+
+// Fungsi untuk mengirim ulang antrean email secara berkala di server
+func runEmailRetry(cfg *Config, store *Store) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, failed := deliverQueuedEmails(cfg, store); failed > 0 {
+			fmt.Printf("%d email struk gagal dikirim, akan dicoba lagi\n", failed)
+		}
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "email"
+func runEmailCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: email send <id pembayaran> <alamat>|queue|retry [id]")
+	}
+	switch args[0] {
+	case "send":
+		if len(args) < 3 {
+			return fmt.Errorf("Gunakan: email send <id pembayaran> <alamat>")
+		}
+		e, err := queueReceiptEmail(cfg, store, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Println("Struk dimasukkan ke antrean email:", e.ID)
+	case "queue":
+		fs := flag.NewFlagSet("email queue", flag.ContinueOnError)
+		all := fs.Bool("all", false, "tampilkan juga email yang sudah terkirim")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		shown := 0
+		for _, e := range store.Emails() {
+			status := "menunggu"
+			switch {
+			case !e.SentAt.IsZero():
+				if !*all {
+					continue
+				}
+				status = "terkirim " + e.SentAt.Local().Format("2006-01-02 15:04")
+			case e.GaveUp(cfg.Email):
+				status = "gagal permanen"
+			case e.Attempts > 0:
+				status = "dicoba lagi " + e.NextAttemptAt.Local().Format("15:04")
+			}
+			fmt.Printf("%s  %s  %-28s %-24s percobaan %d  %s\n", e.ID, e.PaymentID, e.To, status, e.Attempts, e.LastError)
+			shown++
+		}
+		if shown == 0 {
+			fmt.Println("Antrean email kosong.")
+		}
+		return nil
+	case "retry":
+		if cfg.Email.Host == "" {
+			return errEmailDisabled
+		}
+		if len(args) > 1 {
+			if err := store.RequeueEmail(args[1]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("Sub-perintah email tidak dikenal: %s", args[0])
+	}
+	sent, failed := deliverQueuedEmails(cfg, store)
+	fmt.Printf("%d email terkirim, %d gagal\n", sent, failed)
+	return nil
+}
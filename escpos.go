@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Struct untuk Konfigurasi printer struk thermal ESC/POS
+// Printer USB/serial ditulis lewat Device, printer jaringan lewat Addr (biasanya port 9100)
+type PrinterConfig struct {
+	Device    string `json:"device"`     // Lokasi perangkat printer, mis. "/dev/usb/lp0"
+	Addr      string `json:"addr"`       // Alamat printer jaringan, mis. "192.168.1.50:9100"
+	Width     int    `json:"width"`      // Jumlah karakter per baris: 32 untuk kertas 58mm, 48 untuk 80mm
+	Logo      string `json:"logo"`       // File PNG/JPEG logo yang dicetak di atas struk, boleh kosong
+	FeedLines int    `json:"feed_lines"` // Baris kosong sebelum kertas dipotong
+}
+
+// Printer struk sudah diatur atau belum
+func (c PrinterConfig) Enabled() bool {
+	return c.Device != "" || c.Addr != ""
+}
+
+// Perintah ESC/POS yang dipakai
+var (
+	escInit        = []byte{0x1b, '@'}
+	escAlignLeft   = []byte{0x1b, 'a', 0}
+	escAlignCenter = []byte{0x1b, 'a', 1}
+	escBoldOn      = []byte{0x1b, 'E', 1}
+	escBoldOff     = []byte{0x1b, 'E', 0}
+	escDoubleSize  = []byte{0x1b, '!', 0x30}
+	escNormalSize  = []byte{0x1b, '!', 0}
+	escPartialCut  = []byte{0x1d, 'V', 1}
+)
+
+// Fungsi untuk mengubah teks ke ASCII agar aman dicetak dengan code page bawaan printer
+func escposText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || (r >= 0x20 && r < 0x7f) {
+			return r
+		}
+		return '?'
+	}, s)
+}
+
+// Fungsi untuk memotong satu baris struk agar muat di lebar kertas
+// Lanjutan baris diberi indentasi dua spasi
+func wrapReceiptLine(line string, width int) []string {
+	var lines []string
+	for len(line) > width {
+		cut := strings.LastIndex(line[:width], " ")
+		if cut <= 2 {
+			cut = width
+		}
+		lines = append(lines, strings.TrimRight(line[:cut], " "))
+		line = "  " + strings.TrimLeft(line[cut:], " ")
+	}
+	return append(lines, line)
+}
+
+// Fungsi untuk mengubah gambar menjadi perintah raster ESC/POS (GS v 0) hitam-putih
+// Gambar yang lebih lebar dari kertas diperkecil
+func escposRaster(img image.Image, maxDots int) []byte {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	w, h := srcW, srcH
+	if w > maxDots {
+		w, h = maxDots, srcH*maxDots/srcW
+	}
+	rowBytes := (w + 7) / 8
+	out := []byte{0x1d, 'v', '0', 0, byte(rowBytes), byte(rowBytes >> 8), byte(h), byte(h >> 8)}
+	for y := range h {
+		row := make([]byte, rowBytes)
+		for x := range w {
+			r, g, b, a := img.At(bounds.Min.X+x*srcW/w, bounds.Min.Y+y*srcH/h).RGBA()
+			// Piksel transparan dianggap putih
+			lum := (299*r + 587*g + 114*b) / 1000
+			if a > 0x8000 && lum < 0x8000 {
+				row[x/8] |= 0x80 >> (x % 8)
+			}
+		}
+		out = append(out, row...)
+	}
+	return out
+}
+
+// Menulis struk sebagai perintah ESC/POS: logo, judul besar di tengah, isi struk, lalu potong kertas
+// Isi struk sama dengan tampilan di layar agar keduanya tidak pernah berbeda.
+// Dengan preview, perintah printer dilewati sehingga tata letak per lebar kertas bisa diperiksa di layar
+func (r Receipt) writeESCPOS(w io.Writer, cfg PrinterConfig, title string, preview bool) error {
+	var text bytes.Buffer
+	r.write(&text, title)
+	lines := strings.Split(strings.TrimRight(text.String(), "\n"), "\n")
+	if len(lines) >= 2 {
+		lines = lines[1 : len(lines)-1] // Garis judul dan penutup versi layar diganti format printer
+	}
+
+	out := bufio.NewWriter(w)
+	cmd := func(b []byte) {
+		if !preview {
+			out.Write(b)
+		}
+	}
+	cmd(escInit)
+	cmd(escAlignCenter)
+	if cfg.Logo != "" {
+		f, err := os.Open(cfg.Logo)
+		if err != nil {
+			return fmt.Errorf("Logo struk tidak dapat dibuka: %w", err)
+		}
+		img, _, err := image.Decode(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("Logo struk harus berupa PNG atau JPEG: %w", err)
+		}
+		if preview {
+			fmt.Fprintf(out, "[logo %dx%d]\n", img.Bounds().Dx(), img.Bounds().Dy())
+		}
+		cmd(escposRaster(img, cfg.Width*12))
+		cmd([]byte("\n"))
+	}
+	cmd(escDoubleSize)
+	for _, l := range wrapReceiptLine(escposText(title), cfg.Width/2) {
+		out.WriteString(l + "\n")
+	}
+	cmd(escNormalSize)
+	cmd(escAlignLeft)
+	out.WriteString(strings.Repeat("-", cfg.Width) + "\n")
+	totalLabel, _, _ := strings.Cut(tr("Total         : Rp%.2f\n", 0.0), "Rp")
+	for _, line := range lines {
+		bold := strings.HasPrefix(line, totalLabel)
+		if bold {
+			cmd(escBoldOn)
+		}
+		for _, l := range wrapReceiptLine(escposText(line), cfg.Width) {
+			out.WriteString(l + "\n")
+		}
+		if bold {
+			cmd(escBoldOff)
+		}
+	}
+	out.WriteString(strings.Repeat("-", cfg.Width) + "\n")
+	cmd([]byte{0x1b, 'd', byte(cfg.FeedLines)})
+	cmd(escPartialCut)
+	return out.Flush()
+}
+
+// Fungsi untuk mencetak struk ke printer ESC/POS di perangkat lokal atau jaringan
+// Printer yang tidak merespons dilepas watchdog sehingga kasir tetap bisa melanjutkan transaksi
+func printReceipt(cfg PrinterConfig, r Receipt, title string) error {
+	return activeWatchdog.Guard(watchPrint, "struk "+r.Payment.ID, func() error {
+		return writeReceiptToPrinter(cfg, r, title)
+	})
+}
+
+// Fungsi untuk menulis struk ke printer, dipanggil lewat printReceipt
+func writeReceiptToPrinter(cfg PrinterConfig, r Receipt, title string) error {
+	w, err := openPrinter(cfg)
+	if err != nil {
+		return fmt.Errorf("Printer struk tidak dapat dibuka: %w", err)
+	}
+	if err := r.writeESCPOS(w, cfg, title, false); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Fungsi untuk membuka printer di jaringan atau perangkat lokal
+func openPrinter(cfg PrinterConfig) (io.WriteCloser, error) {
+	if cfg.Addr != "" {
+		return net.DialTimeout("tcp", cfg.Addr, 5*time.Second)
+	}
+	return os.OpenFile(cfg.Device, os.O_WRONLY, 0)
+}
+
+// Fungsi untuk mencetak tiket teks polos, mis. tiket stasiun dapur, lalu memotong kertas
+func printTicket(cfg PrinterConfig, name, text string) error {
+	return activeWatchdog.Guard(watchPrint, name, func() error {
+		w, err := openPrinter(cfg)
+		if err != nil {
+			return fmt.Errorf("Printer tiket tidak dapat dibuka: %w", err)
+		}
+		out := bufio.NewWriter(w)
+		out.Write(escInit)
+		for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+			for _, l := range wrapReceiptLine(escposText(line), cfg.Width) {
+				out.WriteString(l + "\n")
+			}
+		}
+		out.Write([]byte{0x1b, 'd', byte(cfg.FeedLines)})
+		out.Write(escPartialCut)
+		if err := out.Flush(); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	})
+}
+
+// Fungsi untuk menampilkan struk di layar sekaligus mencetaknya jika printer diatur
+// Printer yang gagal tidak membatalkan transaksi, kasir cukup mencetak ulang dengan "receipt reprint"
+func emitReceipt(cfg PrinterConfig, r Receipt, title string) {
+	r.write(os.Stdout, title)
+	if !cfg.Enabled() {
+		return
+	}
+	if err := printReceipt(cfg, r, title); err != nil {
+		fmt.Println(tr("Struk tidak dapat dicetak:"), err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Jenis kejadian pesanan yang diterbitkan ke event bus
+// Nilainya sama dengan nama kejadian webhook agar kejadian bisa diteruskan apa adanya
+type OrderEventType string
+
+const (
+	OrderEventCreated   OrderEventType = "order.created"   // Data: Order
+	OrderEventItemAdded OrderEventType = "item.added"      // Data: Order dan Line
+	OrderEventPaid      OrderEventType = "order.paid"      // Data: Payment dan Orders
+	OrderEventCancelled OrderEventType = "order.cancelled" // Data: Order
+	OrderEventRefunded  OrderEventType = "order.refunded"  // Data: Refund dan Order
+)
+
+// Struct untuk satu kejadian pesanan
+type OrderEvent struct {
+	Type    OrderEventType
+	Order   Order     // Pesanan yang dibuat, dibatalkan, atau keranjang setelah item ditambahkan
+	Line    OrderLine // Baris yang baru ditambahkan, hanya untuk item.added
+	Payment Payment   // Pembayaran, hanya untuk order.paid
+	Orders  []Order   // Pesanan yang dibayar, hanya untuk order.paid
+	Refund  Refund    // Refund, hanya untuk order.refunded
+}
+
+// Struct untuk satu subscriber event bus
+type eventSubscriber struct {
+	name  string           // Nama untuk log jika subscriber gagal
+	types []OrderEventType // Kejadian yang diterima, kosong berarti semua
+	fn    func(e OrderEvent)
+}
+
+// Struct untuk Event bus kejadian pesanan
+// Kejadian dikirim ke subscriber satu per satu sesuai urutan Subscribe, di goroutine penerbit
+// Nilai kosong siap dipakai, dan bus nil mengabaikan kejadian
+type eventBus struct {
+	mu   sync.RWMutex
+	subs []eventSubscriber
+}
+
+// Mendaftarkan subscriber untuk jenis kejadian tertentu, tanpa jenis berarti semua kejadian
+func (b *eventBus) Subscribe(name string, fn func(e OrderEvent), types ...OrderEventType) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, eventSubscriber{name: name, types: types, fn: fn})
+}
+
+// Menerbitkan kejadian ke seluruh subscriber yang menerimanya
+// Subscriber yang panik dicatat di log dan tidak menghentikan subscriber lain
+func (b *eventBus) Publish(e OrderEvent) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	subs := slices.Clone(b.subs)
+	b.mu.RUnlock()
+	for _, sub := range subs {
+		if len(sub.types) > 0 && !slices.Contains(sub.types, e.Type) {
+			continue
+		}
+		sub.deliver(e)
+	}
+}
+
+// Mengirim satu kejadian ke subscriber
+func (sub eventSubscriber) deliver(e OrderEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "Subscriber %s gagal menangani %s: %v\n", sub.name, e.Type, r)
+		}
+	}()
+	sub.fn(e)
+}
+
+// Mendaftarkan subscriber kejadian pesanan yang tersimpan, termasuk dari server dan tablet pelayan
+// Subscriber dipanggil saat s.mu dipegang, sehingga tidak boleh memanggil method Store lain
+func (s *Store) Subscribe(name string, fn func(e OrderEvent), types ...OrderEventType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events.Subscribe(name, fn, types...)
+}
+
+// Meneruskan kejadian jurnal ke subscriber penyimpanan sebagai kejadian pesanan
+// Pemanggil harus memegang s.mu; dipanggil dari record setelah kejadian masuk jurnal tetapi sebelum data berubah,
+// sehingga pembayaran dicocokkan dengan pesanan yang sudah tersimpan
+func (s *Store) publish(eventType string, data any) {
+	switch eventType {
+	case EventOrderSaved:
+		s.events.Publish(OrderEvent{Type: OrderEventCreated, Order: data.(Order)})
+	case EventPaymentSaved:
+		e := OrderEvent{Type: OrderEventPaid, Payment: data.(Payment)}
+		for _, id := range e.Payment.OrderIDs {
+			if o := s.findOrder(id); o != nil {
+				e.Orders = append(e.Orders, *o)
+			}
+		}
+		s.events.Publish(e)
+	case EventRefundSaved:
+		e := OrderEvent{Type: OrderEventRefunded, Refund: data.(Refund)}
+		if o := s.findOrder(e.Refund.OrderID); o != nil {
+			e.Order = *o
+		}
+		s.events.Publish(e)
+	case EventOrderCancelled:
+		o := s.findOrder(data.(eventOrderRef).OrderID)
+		if o == nil {
+			return
+		}
+		cancelled := *o
+		cancelled.Status = OrderCancelled
+		s.events.Publish(OrderEvent{Type: OrderEventCancelled, Order: cancelled})
+	}
+}
+
+// Fungsi untuk membuat event bus alur kasir dengan subscriber bawaan
+// Urutan subscriber menentukan urutan tampilan: rekaman sesi, lalu struk, peringatan stok, lalu dapur
+// Dapur nil (mode terminal) tetap dipasang karena Submit mengabaikan pesanan; broker nil berarti tidak diatur
+func newCashierEvents(cfg *Config, backend CashierBackend, kitchen *kitchen, broker *brokerQueue) *eventBus {
+	bus := &eventBus{}
+	bus.Subscribe("rekaman sesi", func(e OrderEvent) {
+		activeRecorder.State("order", "%s = Rp%.2f", orderSummary(e.Order), e.Order.Total)
+	}, OrderEventCreated)
+	bus.Subscribe("struk", func(e OrderEvent) {
+		printReadyEstimates(os.Stdout, kitchenOrders(e.Orders), time.Now())
+		printOrderTickets(os.Stdout, kitchenOrders(e.Orders), cfg)
+		if cfg.Printer.Enabled() {
+			receipt := Receipt{Payment: e.Payment, Orders: e.Orders}
+			if err := printReceipt(cfg.Printer, receipt, tr("STRUK PEMBAYARAN")); err != nil {
+				fmt.Println(tr("Struk tidak dapat dicetak:"), err)
+			}
+		}
+		if cfg.Cashier.EmailReceipt {
+			offerEmailReceipt(backend, e.Payment.ID)
+		}
+	}, OrderEventPaid)
+	bus.Subscribe("stok", func(e OrderEvent) {
+		warnLowStock(os.Stdout, backend, e.Orders)
+	}, OrderEventPaid)
+	// Pemrosesan dapur berjalan di worker sendiri; kasir menunggu dengan Drain saat sesi selesai
+	bus.Subscribe("dapur", func(e OrderEvent) {
+		for _, order := range kitchenOrders(e.Orders) {
+			if err := kitchen.Submit(order); err != nil {
+				fmt.Println("Pesanan", order.ID, "tidak dikirim ke dapur:", err)
+			}
+		}
+	}, OrderEventPaid)
+	// order.created di bus kasir terbit saat pesanan disusun, sebelum tersimpan; broker baru menerimanya
+	// setelah checkout berhasil, sama seperti server yang menerbitkannya setelah pesanan masuk jurnal
+	if broker != nil {
+		bus.Subscribe("broker", func(e OrderEvent) {
+			for _, order := range e.Orders {
+				broker.enqueue(OrderEvent{Type: OrderEventCreated, Order: order})
+			}
+			broker.enqueue(e)
+		}, OrderEventPaid, OrderEventCancelled)
+	}
+	return bus
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Struct untuk tabel yang diekspor ke spreadsheet
+// Sel bertipe Money, float64, atau int ditulis sebagai angka agar bisa langsung dijumlahkan, selain itu sebagai teks
+type exportTable struct {
+	Sheet  string // Nama sheet di file XLSX
+	Header []string
+	Rows   [][]any
+}
+
+// Menulis tabel dalam format CSV
+func (t exportTable) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(t.Header)
+	for _, row := range t.Rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			switch v := v.(type) {
+			case Money:
+				record[i] = v.String()
+			case float64:
+				record[i] = strconv.FormatFloat(v, 'f', 2, 64)
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Menulis tabel sebagai file XLSX dengan satu sheet
+// Teks disimpan sebagai inline string sehingga tidak perlu sharedStrings.xml
+func (t exportTable) writeXLSX(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	files := []struct{ name, body string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="` + xmlEscape(t.Sheet) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+		{"xl/worksheets/sheet1.xml", t.sheetXML()},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Menyusun isi sheet1.xml
+func (t exportTable) sheetXML() string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	header := make([]any, len(t.Header))
+	for i, h := range t.Header {
+		header[i] = h
+	}
+	for r, row := range append([][]any{header}, t.Rows...) {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, v := range row {
+			ref := xlsxColumnName(c) + strconv.Itoa(r+1)
+			switch v := v.(type) {
+			case Money:
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v.Float(), 'f', -1, 64))
+			case float64:
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+			case int:
+				fmt.Fprintf(&b, `<c r="%s"><v>%d</v></c>`, ref, v)
+			default:
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, xmlEscape(fmt.Sprint(v)))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// Fungsi untuk mengubah indeks kolom (mulai 0) menjadi nama kolom spreadsheet, mis. 0 -> "A", 27 -> "AB"
+func xlsxColumnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// Fungsi untuk meng-escape teks di dalam XML
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// Fungsi untuk menyusun tabel menu
+func menuExportTable(restaurant *Restaurant) exportTable {
+	t := exportTable{Sheet: "Menu", Header: []string{"no", "item", "kategori", "harga", "habis", "varian", "tambahan", "resep", "kode"}}
+	for i, item := range restaurant.Menu {
+		var variants, addOns, recipe []string
+		for _, g := range item.Variants {
+			var options []string
+			for _, o := range g.Options {
+				options = append(options, o.Name)
+			}
+			variants = append(variants, g.Name+": "+strings.Join(options, "/"))
+		}
+		for _, a := range item.AddOns {
+			addOns = append(addOns, fmt.Sprintf("%s (+%.0f)", a.Name, a.Price))
+		}
+		for _, r := range item.Recipe {
+			recipe = append(recipe, fmt.Sprintf("%s %g", r.Ingredient, r.Qty))
+		}
+		soldOut := "tidak"
+		if item.SoldOut {
+			soldOut = "ya"
+		}
+		t.Rows = append(t.Rows, []any{i + 1, item.Name, item.Category, item.Price, soldOut,
+			strings.Join(variants, "; "), strings.Join(addOns, "; "), strings.Join(recipe, "; "), item.Code})
+	}
+	return t
+}
+
+// Fungsi untuk menyusun tabel pesanan dalam rentang tanggal, diurutkan dari yang terlama
+// lines true berarti satu baris per item, sehingga total pesanan hanya ditulis di baris item pertama
+func orderExportTable(store *Store, q OrderQuery, lines bool) exportTable {
+	payments := map[string]Payment{}
+	for _, p := range store.Payments() {
+		payments[p.ID] = p
+	}
+	customers := map[string]Customer{}
+	for _, c := range store.Customers() {
+		customers[c.ID] = c
+	}
+	orders := searchOrders(store, q)
+	slices.Reverse(orders)
+
+	t := exportTable{Sheet: "Pesanan", Header: []string{"id_pesanan", "waktu", "status", "meja", "pelanggan", "id_pembayaran", "metode"}}
+	if lines {
+		t.Header = append(t.Header, "item", "jumlah", "harga_satuan", "subtotal_item")
+	} else {
+		t.Header = append(t.Header, "item")
+	}
+	t.Header = append(t.Header, "subtotal", "pajak", "pembulatan", "total")
+	for _, o := range orders {
+		method := ""
+		if p, ok := payments[o.PaymentID]; ok {
+			method = string(p.PaymentMethod())
+		}
+		head := []any{o.ID, o.CreatedAt.Local().Format("2006-01-02 15:04:05"), string(o.Status), o.Table, customers[o.CustomerID].Name, o.PaymentID, method}
+		subtotal := o.Subtotal
+		if subtotal == 0 {
+			subtotal = o.ExpectedTotal()
+		}
+		totals := []any{subtotal, o.Tax, o.Rounding, o.Total}
+		if !lines {
+			var items []string
+			for _, l := range o.Lines {
+				items = append(items, fmt.Sprintf("%s x%d", l.Label(), l.Qty))
+			}
+			t.Rows = append(t.Rows, slices.Concat(head, []any{strings.Join(items, ", ")}, totals))
+			continue
+		}
+		for i, l := range o.Lines {
+			row := slices.Concat(head, []any{l.Label(), l.Qty, l.UnitPrice(), l.Subtotal()})
+			if i == 0 {
+				row = append(row, totals...)
+			}
+			t.Rows = append(t.Rows, row)
+		}
+	}
+	return t
+}
+
+// Fungsi untuk menulis tabel ekspor ke file atau stdout
+// Format default mengikuti ekstensi file keluaran, lalu CSV
+func writeExport(t exportTable, format, out string) error {
+	if format == "" {
+		format = "csv"
+		if strings.EqualFold(filepath.Ext(out), ".xlsx") {
+			format = "xlsx"
+		}
+	}
+	write := t.writeCSV
+	switch format {
+	case "csv":
+	case "xlsx":
+		if out == "-" {
+			return fmt.Errorf("Format xlsx harus ditulis ke file, gunakan -out file.xlsx")
+		}
+		write = t.writeXLSX
+	default:
+		return fmt.Errorf("Format %q tidak didukung, gunakan csv atau xlsx", format)
+	}
+	if out == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("%d baris diekspor ke %s\n", len(t.Rows), out)
+	return nil
+}
+
+// Fungsi untuk menjalankan "menu export"
+func runMenuExport(store *Store, args []string) error {
+	fs := flag.NewFlagSet("menu export", flag.ContinueOnError)
+	format := fs.String("format", "", "format file: csv atau xlsx (default: dari ekstensi -out)")
+	out := fs.String("out", "-", "file keluaran, \"-\" untuk stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return writeExport(menuExportTable(store.Menu()), *format, *out)
+}
+
+// Fungsi untuk menjalankan "order export"
+func runOrderExport(store *Store, args []string) error {
+	fs := flag.NewFlagSet("order export", flag.ContinueOnError)
+	dates := addRangeFlags(fs)
+	format := fs.String("format", "", "format file: csv atau xlsx (default: dari ekstensi -out)")
+	out := fs.String("out", "-", "file keluaran, \"-\" untuk stdout")
+	lines := fs.Bool("lines", false, "satu baris per item pesanan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, to, err := dates.parse()
+	if err != nil {
+		return err
+	}
+	return writeExport(orderExportTable(store, OrderQuery{From: from, To: to}, *lines), *format, *out)
+}
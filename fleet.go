@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+// Versi aplikasi, dapat diisi saat build dengan -ldflags "-X main.version=1.2.3"
+var version = "dev"
+
+// Struct untuk Heartbeat terminal
+// Dikirim berkala oleh terminal ke server pusat
+type Heartbeat struct {
+	TerminalID    string    `json:"terminal_id"`             // ID terminal, default nama host
+	Version       string    `json:"version"`                 // Versi aplikasi di terminal
+	LastOrderAt   time.Time `json:"last_order_at,omitempty"` // Waktu pesanan terakhir berhasil disimpan
+	PrinterStatus string    `json:"printer_status"`          // Status printer: "ok", "none", atau pesan error
+	DiskFreeBytes int64     `json:"disk_free_bytes"`         // Sisa ruang disk, -1 jika tidak diketahui
+	SentAt        time.Time `json:"sent_at"`                 // Waktu heartbeat dikirim oleh terminal
+}
+
+// Struct untuk Status terminal di server pusat
+type TerminalStatus struct {
+	Heartbeat
+	ReceivedAt time.Time `json:"received_at"` // Waktu heartbeat terakhir diterima server
+	Alerted    bool      `json:"alerted"`     // Sudah diberi peringatan untuk periode diam saat ini
+}
+
+// Menyimpan heartbeat terbaru dari terminal
+func (s *Store) RecordHeartbeat(hb Heartbeat) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := TerminalStatus{Heartbeat: hb, ReceivedAt: time.Now()}
+	for i := range s.data.Terminals {
+		if s.data.Terminals[i].TerminalID == hb.TerminalID {
+			s.data.Terminals[i] = status
+			return s.save()
+		}
+	}
+	s.data.Terminals = append(s.data.Terminals, status)
+	return s.save()
+}
+
+// Mengambil status seluruh terminal, diurutkan berdasarkan ID
+func (s *Store) Terminals() []TerminalStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	terminals := append([]TerminalStatus(nil), s.data.Terminals...)
+	sort.Slice(terminals, func(i, j int) bool { return terminals[i].TerminalID < terminals[j].TerminalID })
+	return terminals
+}
+
+// Menandai terminal sudah diberi peringatan agar tidak dikirim berulang
+func (s *Store) MarkTerminalAlerted(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.Terminals {
+		if s.data.Terminals[i].TerminalID == id {
+			s.data.Terminals[i].Alerted = true
+		}
+	}
+	return s.save()
+}
+
+// Memeriksa apakah terminal sudah terlalu lama tidak mengirim heartbeat
+func (t TerminalStatus) Silent(now time.Time, after time.Duration) bool {
+	return now.Sub(t.ReceivedAt) > after
+}
+
+// Fungsi untuk memeriksa apakah waktu berada dalam jam buka
+// Jam buka yang melewati tengah malam (mis. 17:00-02:00) juga didukung
+func withinOpeningHours(hours OpeningHours, now time.Time) bool {
+	open, errOpen := time.Parse("15:04", hours.Open)
+	closing, errClose := time.Parse("15:04", hours.Close)
+	if errOpen != nil || errClose != nil {
+		return true // Jam buka tidak diatur, anggap selalu buka
+	}
+	minutes := now.Hour()*60 + now.Minute()
+	from := open.Hour()*60 + open.Minute()
+	to := closing.Hour()*60 + closing.Minute()
+	if from <= to {
+		return minutes >= from && minutes < to
+	}
+	return minutes >= from || minutes < to
+}
+
+// Fungsi untuk memantau terminal yang diam selama jam buka
+// Peringatan ditampilkan di log server dan dikirim ke webhook jika diatur
+func monitorFleet(cfg *Config, store *Store) {
+	silentAfter := time.Duration(cfg.Fleet.SilentAfterSeconds) * time.Second
+	ticker := time.NewTicker(time.Duration(cfg.Fleet.CheckIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		if !withinOpeningHours(cfg.OpeningHours, now) {
+			continue
+		}
+		for _, t := range store.Terminals() {
+			if t.Alerted || !t.Silent(now, silentAfter) {
+				continue
+			}
+			message := fmt.Sprintf("Terminal %s tidak mengirim heartbeat sejak %s", t.TerminalID, t.ReceivedAt.Format("15:04:05"))
+			fmt.Println("PERINGATAN:", message)
+			if cfg.Fleet.AlertWebhook != "" {
+				sendFleetAlert(cfg.Fleet.AlertWebhook, t, message)
+			}
+			if err := store.MarkTerminalAlerted(t.TerminalID); err != nil {
+				fmt.Println("Gagal menyimpan status peringatan:", err)
+			}
+		}
+	}
+}
+
+// Fungsi untuk mengirim peringatan terminal ke webhook
+func sendFleetAlert(url string, t TerminalStatus, message string) {
+	body, _ := json.Marshal(map[string]any{"terminal": t, "message": message})
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Println("Gagal mengirim peringatan ke webhook:", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// Fungsi untuk menampilkan kondisi seluruh terminal
+func printFleet(cfg *Config, terminals []TerminalStatus) {
+	silentAfter := time.Duration(cfg.Fleet.SilentAfterSeconds) * time.Second
+	now := time.Now()
+	if len(terminals) == 0 {
+		fmt.Println("Belum ada terminal yang mengirim heartbeat.")
+		return
+	}
+	fmt.Printf("%-16s %-8s %-8s %-20s %-12s %-10s %s\n", "TERMINAL", "STATUS", "VERSI", "PESANAN TERAKHIR", "PRINTER", "DISK", "HEARTBEAT")
+	for _, t := range terminals {
+		status := "online"
+		if t.Silent(now, silentAfter) {
+			status = "DIAM"
+		}
+		lastOrder := "-"
+		if !t.LastOrderAt.IsZero() {
+			lastOrder = t.LastOrderAt.Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%-16s %-8s %-8s %-20s %-12s %-10s %s\n",
+			t.TerminalID, status, t.Version, lastOrder, t.PrinterStatus, formatBytes(t.DiskFreeBytes), t.ReceivedAt.Format("15:04:05"))
+	}
+}
+
+// Fungsi untuk menampilkan ukuran byte agar mudah dibaca
+func formatBytes(n int64) string {
+	if n < 0 {
+		return "?"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// Fungsi untuk memeriksa status printer terminal
+func printerStatus(device string) string {
+	if device == "" {
+		return "none"
+	}
+	if _, err := os.Stat(device); err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// Fungsi untuk mengirim heartbeat berkala dari terminal ke server pusat
+// Kegagalan pengiriman diabaikan; server akan menandai terminal sebagai diam
+func (b *remoteBackend) startHeartbeat() {
+	interval := time.Duration(b.cfg.HeartbeatSeconds) * time.Second
+	go func() {
+		for {
+			b.sendHeartbeat()
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// Mengirim satu heartbeat ke server pusat
+func (b *remoteBackend) sendHeartbeat() error {
+	hb := Heartbeat{
+		TerminalID:    b.cfg.TerminalID,
+		Version:       version,
+		LastOrderAt:   b.lastOrder(),
+		PrinterStatus: printerStatus(b.cfg.PrinterDevice),
+		DiskFreeBytes: diskFreeBytes("."),
+		SentAt:        time.Now(),
+	}
+	return b.do(http.MethodPost, "/api/v1/terminal/heartbeat", hb, nil)
+}
+
+// Mengambil waktu pesanan terakhir yang berhasil disimpan dari terminal ini
+func (b *remoteBackend) lastOrder() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastOrderAt
+}
+
+// POST /api/v1/terminal/heartbeat
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var hb Heartbeat
+	if err := json.NewDecoder(r.Body).Decode(&hb); err != nil || hb.TerminalID == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi heartbeat dengan terminal_id")
+		return
+	}
+	if err := s.store.RecordHeartbeat(hb); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// GET /api/v1/fleet
+func (s *Server) handleFleet(w http.ResponseWriter, r *http.Request) {
+	silentAfter := time.Duration(s.cfg.Fleet.SilentAfterSeconds) * time.Second
+	now := time.Now()
+	type terminalView struct {
+		TerminalStatus
+		Silent bool `json:"silent"`
+	}
+	views := []terminalView{}
+	for _, t := range s.store.Terminals() {
+		views = append(views, terminalView{TerminalStatus: t, Silent: t.Silent(now, silentAfter)})
+	}
+	writeJSON(w, http.StatusOK, views)
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Nama hari dalam bahasa Indonesia untuk laporan perkiraan penjualan, mengikuti urutan time.Weekday
+var dayLabels = [...]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+
+// Struct untuk perkiraan penjualan satu item menu
+type ItemForecast struct {
+	Name     string
+	Category string
+	Days     []float64 // Perkiraan jumlah terjual per tanggal, sesuai urutan tanggal perkiraan
+}
+
+// Jumlah perkiraan seluruh tanggal
+func (f ItemForecast) Total() float64 {
+	total := 0.0
+	for _, q := range f.Days {
+		total += q
+	}
+	return total
+}
+
+// Struct untuk riwayat penjualan harian per item yang dipakai perkiraan
+type salesHistory struct {
+	days  map[string]map[string]int // Tanggal YYYY-MM-DD -> nama item (huruf kecil) -> jumlah terjual
+	names map[string]ItemSales      // Nama item (huruf kecil) -> nama dan kategori yang ditampilkan
+	until time.Time                 // Hari pertama yang tidak dipakai, mis. hari ini yang penjualannya belum lengkap
+}
+
+// Fungsi untuk mengumpulkan penjualan harian per item dari rekap penjualan dan data impor, sampai sebelum tanggal to
+func buildSalesHistory(store *Store, from, to time.Time) salesHistory {
+	h := salesHistory{days: map[string]map[string]int{}, names: map[string]ItemSales{}, until: to}
+	to = to.AddDate(0, 0, -1)
+	add := func(date time.Time, name, category string, qty int) {
+		day := projectionKey(date)
+		if h.days[day] == nil {
+			h.days[day] = map[string]int{}
+		}
+		key := strings.ToLower(name)
+		h.days[day][key] += qty
+		h.names[key] = ItemSales{Name: name, Category: category}
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, item := range store.DailyProjection(day).Items {
+			if item.Qty != 0 {
+				add(day, item.Name, item.Category, item.Qty)
+			}
+		}
+	}
+	for _, r := range store.History() {
+		if d := r.Date.Local(); !d.Before(from) && !d.After(endOfDay(to)) {
+			add(d, r.Item, r.Category, r.Qty)
+		}
+	}
+	return h
+}
+
+// Fungsi untuk memperkirakan penjualan item pada satu tanggal
+// Metode "weekday" merata-rata hari yang sama pada minggu-minggu sebelumnya dengan bobot lebih besar untuk minggu terbaru;
+// metode "sma" merata-rata seluruh hari dalam jendela riwayat
+// Hari tanpa penjualan sama sekali dianggap tutup dan tidak ikut dirata-rata
+func (h salesHistory) forecast(key string, date time.Time, method string, window int) float64 {
+	var sum, weight float64
+	for i := 1; i <= window; i++ {
+		day, w := date.AddDate(0, 0, -i), 1.0
+		if method == "weekday" {
+			day, w = date.AddDate(0, 0, -7*i), float64(window-i+1)
+		}
+		sales, open := h.days[projectionKey(day)]
+		if !open || !day.Before(h.until) {
+			continue
+		}
+		sum += w * float64(sales[key])
+		weight += w
+	}
+	if weight == 0 {
+		return 0
+	}
+	return sum / weight
+}
+
+// Fungsi untuk menyusun perkiraan penjualan per item untuk tanggal-tanggal setelah today
+// Riwayat diambil sampai kemarin karena penjualan hari ini belum lengkap
+func buildForecast(store *Store, today time.Time, days int, method string, window int) ([]time.Time, []ItemForecast) {
+	span := window
+	if method == "weekday" {
+		span = 7 * window
+	}
+	h := buildSalesHistory(store, today.AddDate(0, 0, -span), today)
+	dates := make([]time.Time, days)
+	for i := range dates {
+		dates[i] = today.AddDate(0, 0, i+1)
+	}
+	var forecasts []ItemForecast
+	for key, name := range h.names {
+		f := ItemForecast{Name: name.Name, Category: name.Category, Days: make([]float64, days)}
+		for i, date := range dates {
+			// Perkiraan metode sma sama untuk setiap tanggal, dihitung dari hari-hari sebelum today
+			ref := date
+			if method == "sma" {
+				ref = today
+			}
+			f.Days[i] = h.forecast(key, ref, method, window)
+		}
+		if f.Total() > 0 {
+			forecasts = append(forecasts, f)
+		}
+	}
+	slices.SortFunc(forecasts, func(a, b ItemForecast) int {
+		if c := cmp.Compare(b.Total(), a.Total()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return dates, forecasts
+}
+
+// Struct untuk kebutuhan satu bahan dari perkiraan penjualan
+type IngredientNeed struct {
+	Ingredient Ingredient
+	Need       float64 // Perkiraan pemakaian selama tanggal perkiraan
+}
+
+// Fungsi untuk menghitung kebutuhan bahan dari perkiraan penjualan dan resep item menu
+func forecastIngredients(store *Store, forecasts []ItemForecast) []IngredientNeed {
+	restaurant := store.Menu()
+	need := map[string]float64{}
+	for _, f := range forecasts {
+		item := restaurant.findMenuItem(f.Name)
+		if item == nil {
+			continue
+		}
+		for _, r := range item.Recipe {
+			need[strings.ToLower(r.Ingredient)] += r.Qty * f.Total()
+		}
+	}
+	var needs []IngredientNeed
+	for _, ing := range store.Ingredients() {
+		if n := need[strings.ToLower(ing.Name)]; n > 0 {
+			needs = append(needs, IngredientNeed{Ingredient: ing, Need: n})
+		}
+	}
+	return needs
+}
+
+// Fungsi untuk menampilkan perkiraan penjualan per item, satu kolom per tanggal
+func printForecast(w io.Writer, dates []time.Time, forecasts []ItemForecast, method string, window int) {
+	basis := fmt.Sprintf("rata-rata %d hari terakhir", window)
+	if method == "weekday" {
+		basis = fmt.Sprintf("hari yang sama %d minggu terakhir, minggu terbaru berbobot lebih besar", window)
+	}
+	fmt.Fprintf(w, "Perkiraan Penjualan %s s.d. %s (%s)\n", dates[0].Format("2006-01-02"), dates[len(dates)-1].Format("2006-01-02"), basis)
+	if len(forecasts) == 0 {
+		fmt.Fprintln(w, "Belum ada riwayat penjualan untuk perkiraan.")
+		return
+	}
+	fmt.Fprintf(w, "%-24s", "Item")
+	for _, d := range dates {
+		fmt.Fprintf(w, " %10s", fmt.Sprintf("%.3s %02d/%02d", dayLabels[d.Weekday()], d.Day(), d.Month()))
+	}
+	if len(dates) > 1 {
+		fmt.Fprintf(w, " %8s", "Total")
+	}
+	fmt.Fprintln(w)
+	for _, f := range forecasts {
+		fmt.Fprintf(w, "%-24s", f.Name)
+		for _, q := range f.Days {
+			fmt.Fprintf(w, " %10.0f", math.Ceil(q))
+		}
+		if len(dates) > 1 {
+			fmt.Fprintf(w, " %8.0f", math.Ceil(f.Total()))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// Fungsi untuk menampilkan kebutuhan bahan dibandingkan stok saat ini
+func printIngredientNeeds(w io.Writer, needs []IngredientNeed) {
+	fmt.Fprintln(w, "\nKebutuhan bahan:")
+	if len(needs) == 0 {
+		fmt.Fprintln(w, "Tidak ada bahan di persediaan yang dipakai item perkiraan.")
+		return
+	}
+	fmt.Fprintf(w, "%-16s %12s %12s %12s %s\n", "Bahan", "Kebutuhan", "Stok", "Kurang", "Satuan")
+	for _, n := range needs {
+		short := max(math.Ceil(n.Need-n.Ingredient.Stock), 0)
+		fmt.Fprintf(w, "%-16s %12.2f %12.2f %12.0f %s\n", n.Ingredient.Name, n.Need, n.Ingredient.Stock, short, n.Ingredient.Unit)
+	}
+}
+
+// Fungsi untuk menulis perkiraan penjualan ke CSV, satu baris per item dan tanggal
+func writeForecastCSV(w io.Writer, dates []time.Time, forecasts []ItemForecast) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"tanggal", "item", "kategori", "perkiraan"})
+	for _, f := range forecasts {
+		for i, d := range dates {
+			cw.Write([]string{d.Format("2006-01-02"), f.Name, f.Category, strconv.FormatFloat(f.Days[i], 'f', 2, 64)})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Fungsi untuk menjalankan "report forecast"
+func runForecastReport(store *Store, args []string) error {
+	fs := flag.NewFlagSet("report forecast", flag.ContinueOnError)
+	period := fs.String("period", "day", "perkiraan untuk besok (day) atau 7 hari ke depan (week)")
+	method := fs.String("method", "weekday", "weekday (hari yang sama minggu-minggu sebelumnya) atau sma (rata-rata harian)")
+	window := fs.Int("window", 0, "jumlah minggu (weekday, default 4) atau hari (sma, default 14) riwayat yang dipakai")
+	ingredients := fs.Bool("ingredients", false, "tampilkan kebutuhan bahan dari resep dibandingkan stok")
+	csvPath := fs.String("csv", "", "ekspor ke file CSV, \"-\" untuk stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	days := 1
+	switch *period {
+	case "day":
+	case "week":
+		days = 7
+	default:
+		return fmt.Errorf("--period harus day atau week")
+	}
+	switch {
+	case *method != "weekday" && *method != "sma":
+		return fmt.Errorf("--method harus weekday atau sma")
+	case *window < 0:
+		return fmt.Errorf("--window tidak boleh negatif")
+	case *window == 0 && *method == "weekday":
+		*window = 4
+	case *window == 0:
+		*window = 14
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	dates, forecasts := buildForecast(store, today, days, *method, *window)
+	switch *csvPath {
+	case "":
+		printForecast(os.Stdout, dates, forecasts, *method, *window)
+		if *ingredients {
+			printIngredientNeeds(os.Stdout, forecastIngredients(store, forecasts))
+		}
+		return nil
+	case "-":
+		return writeForecastCSV(os.Stdout, dates, forecasts)
+	}
+	f, err := os.Create(*csvPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := writeForecastCSV(f, dates, forecasts); err != nil {
+		return err
+	}
+	fmt.Println("Perkiraan penjualan diekspor ke", *csvPath)
+	return nil
+}
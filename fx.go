@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Struct untuk Mata uang asing yang diterima kasir, mis. dari tamu turis
+// Nominal mata uang asing disimpan sebagai Money dalam sen mata uang tersebut, mis. USD 4.10 = 410
+type CurrencyConfig struct {
+	Code string `json:"code"` // Kode ISO 4217, mis. "USD" atau "SGD"
+	Rate Money  `json:"rate"` // Kurs: nilai rupiah untuk satu unit mata uang, mis. 16250
+}
+
+// Memeriksa daftar mata uang asing: kode tiga huruf, tidak berulang, bukan rupiah, dan kurs positif
+func validateCurrencies(currencies []CurrencyConfig) error {
+	seen := map[string]bool{}
+	for _, c := range currencies {
+		code := strings.ToUpper(c.Code)
+		if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+			return fmt.Errorf("currencies: kode mata uang %q harus tiga huruf, mis. USD", c.Code)
+		}
+		if code == "IDR" {
+			return fmt.Errorf("currencies: IDR adalah mata uang utama dan tidak perlu kurs")
+		}
+		if seen[code] {
+			return fmt.Errorf("currencies: mata uang %s tercantum lebih dari sekali", code)
+		}
+		seen[code] = true
+		if c.Rate <= 0 {
+			return fmt.Errorf("currencies: kurs %s harus lebih dari 0", code)
+		}
+	}
+	return nil
+}
+
+// Tagihan rupiah dalam mata uang ini, dibulatkan ke atas ke sen agar pembayaran tidak kurang
+func (c CurrencyConfig) Quote(total Money) Money {
+	return (total*100 + c.Rate - 1) / c.Rate
+}
+
+// Nilai rupiah dari nominal mata uang ini, dibulatkan ke bawah ke sen
+func (c CurrencyConfig) ToRupiah(foreign Money) Money {
+	return foreign * c.Rate / 100
+}
+
+// Mencari mata uang asing dari kodenya
+func findCurrency(currencies []CurrencyConfig, code string) (CurrencyConfig, bool) {
+	i := slices.IndexFunc(currencies, func(c CurrencyConfig) bool { return strings.EqualFold(c.Code, code) })
+	if i < 0 {
+		return CurrencyConfig{}, false
+	}
+	c := currencies[i]
+	c.Code = strings.ToUpper(c.Code)
+	return c, true
+}
+
+// Fungsi untuk menampilkan tagihan dalam setiap mata uang asing yang diterima
+func printForeignQuotes(w io.Writer, total Money, currencies []CurrencyConfig) {
+	if len(currencies) == 0 {
+		return
+	}
+	var quotes []string
+	for _, c := range currencies {
+		quotes = append(quotes, fmt.Sprintf("%s %.2f", strings.ToUpper(c.Code), c.Quote(total)))
+	}
+	fmt.Fprintln(w, tr("Tagihan dalam mata uang asing:"), strings.Join(quotes, " / "))
+	fmt.Fprintln(w, tr("Ketik mis. \"20 USD\" untuk membayar dengan mata uang asing, kembalian diberikan dalam rupiah."))
+}
+
+// Struct untuk uang tunai yang diterima kasir
+type CashTender struct {
+	Amount   Money  // Nilai rupiah yang diterima
+	Currency string // Mata uang asing, kosong berarti rupiah
+	Foreign  Money  // Nominal dalam mata uang asing
+	Rate     Money  // Kurs yang dipakai
+}
+
+// Fungsi untuk membaca uang tunai yang diterima, mis. "50000", "20 USD", atau "sgd 10.50"
+func parseTender(input string, currencies []CurrencyConfig) (CashTender, error) {
+	fields := strings.Fields(strings.ToUpper(input))
+	if len(fields) != 2 {
+		price, err := validatePrice(strings.TrimSpace(input))
+		return CashTender{Amount: price}, err
+	}
+	amount, code := fields[0], fields[1]
+	if _, err := validatePrice(code); err == nil {
+		amount, code = code, amount
+	}
+	c, ok := findCurrency(currencies, code)
+	if !ok {
+		return CashTender{}, fmt.Errorf("Mata uang %s tidak diterima", code)
+	}
+	foreign, err := validatePrice(amount)
+	if err != nil {
+		return CashTender{}, err
+	}
+	return CashTender{Amount: c.ToRupiah(foreign), Currency: c.Code, Foreign: foreign, Rate: c.Rate}, nil
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status tagihan di gateway pembayaran
+type ChargeStatus string
+
+const (
+	ChargePending ChargeStatus = "pending" // Menunggu pelanggan menyelesaikan pembayaran
+	ChargePaid    ChargeStatus = "paid"    // Dana sudah diterima
+	ChargeFailed  ChargeStatus = "failed"  // Ditolak, dibatalkan, atau kedaluwarsa
+)
+
+var errChargeFailed = errors.New("Pembayaran ditolak gateway")
+
+// Struct untuk permintaan tagihan ke gateway
+type ChargeRequest struct {
+	PaymentID string        // ID pembayaran di toko, dipakai sebagai idempotency key
+	Method    PaymentMethod // Kartu atau QRIS
+	Amount    Money         // Nominal yang ditagih
+}
+
+// Struct untuk hasil tagihan dari gateway
+type ChargeResult struct {
+	Reference string       // Referensi transaksi di gateway, disimpan sebagai ProviderRef
+	Status    ChargeStatus // Status tagihan saat ini
+	Action    string       // Isi QR atau URL yang harus ditunjukkan ke pelanggan, boleh kosong
+}
+
+// Interface untuk gateway pembayaran kartu dan e-wallet
+// Alur kasir dan refund hanya bergantung pada interface ini sehingga gateway dapat diganti lewat konfigurasi
+type PaymentGateway interface {
+	Charge(req ChargeRequest) (ChargeResult, error)                      // Membuat tagihan
+	Status(ref string) (ChargeStatus, error)                             // Memeriksa status tagihan
+	Refund(ref string, amount Money, key, reason string) (string, error) // Mengembalikan dana, menghasilkan referensi refund
+}
+
+// Fungsi untuk membuat gateway sesuai konfigurasi, nil jika gateway tidak dipakai
+func newPaymentGateway(cfg GatewayConfig) (PaymentGateway, error) {
+	client := &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second}
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "mock":
+		return newMockGateway(), nil
+	case "http":
+		return httpGateway{cfg: cfg, http: client}, nil
+	case "midtrans":
+		return midtransGateway{cfg: cfg, http: client}, nil
+	}
+	return nil, fmt.Errorf("payment_gateway.provider tidak dikenal: %s (mock, http, atau midtrans)", cfg.Provider)
+}
+
+// Gateway tiruan untuk latihan dan pengujian tanpa jaringan
+// Setiap tagihan langsung lunas kecuali nominalnya berakhir dengan 13 rupiah, yang selalu ditolak
+type mockGateway struct {
+	mu      sync.Mutex
+	charges map[string]ChargeStatus
+}
+
+// Fungsi untuk membuat gateway tiruan
+func newMockGateway() *mockGateway {
+	return &mockGateway{charges: map[string]ChargeStatus{}}
+}
+
+func (g *mockGateway) Charge(req ChargeRequest) (ChargeResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ref := "MOCK-" + req.PaymentID
+	status := ChargePaid
+	if int64(req.Amount/Rp)%100 == 13 {
+		status = ChargeFailed
+	}
+	g.charges[ref] = status
+	return ChargeResult{Reference: ref, Status: status, Action: "mock://" + string(req.Method) + "/" + ref}, nil
+}
+
+func (g *mockGateway) Status(ref string) (ChargeStatus, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	status, ok := g.charges[ref]
+	if !ok {
+		return "", fmt.Errorf("Tagihan %s tidak dikenal", ref)
+	}
+	return status, nil
+}
+
+func (g *mockGateway) Refund(ref string, amount Money, key, reason string) (string, error) {
+	return "MOCK-" + key, nil
+}
+
+// Gateway dengan API JSON sederhana: POST /charges, GET /charges/{ref}, POST /refunds
+type httpGateway struct {
+	cfg  GatewayConfig
+	http *http.Client
+}
+
+func (g httpGateway) Charge(req ChargeRequest) (ChargeResult, error) {
+	var result struct {
+		Reference string       `json:"reference"`
+		Status    ChargeStatus `json:"status"`
+		Action    string       `json:"action"`
+	}
+	body := map[string]any{"payment_id": req.PaymentID, "method": req.Method, "amount": req.Amount}
+	if err := g.do(http.MethodPost, "/charges", req.PaymentID, body, &result); err != nil {
+		return ChargeResult{}, err
+	}
+	return ChargeResult{Reference: result.Reference, Status: result.Status, Action: result.Action}, nil
+}
+
+func (g httpGateway) Status(ref string) (ChargeStatus, error) {
+	var result struct {
+		Status ChargeStatus `json:"status"`
+	}
+	err := g.do(http.MethodGet, "/charges/"+ref, "", nil, &result)
+	return result.Status, err
+}
+
+// ID refund dipakai sebagai idempotency key agar refund yang diulang tidak tercatat dua kali
+func (g httpGateway) Refund(ref string, amount Money, key, reason string) (string, error) {
+	var result struct {
+		RefundReference string `json:"refund_reference"`
+	}
+	body := map[string]any{"payment_reference": ref, "amount": amount, "reason": reason}
+	if err := g.do(http.MethodPost, "/refunds", key, body, &result); err != nil {
+		return "", err
+	}
+	if result.RefundReference == "" {
+		return "", errors.New("Gateway tidak mengembalikan referensi refund")
+	}
+	return result.RefundReference, nil
+}
+
+// Mengirim request ke gateway dan membaca respons JSON
+func (g httpGateway) do(method, path, key string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, g.cfg.URL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.cfg.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("Gateway pembayaran tidak dapat dihubungi: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(raw, &e); e.Error == "" {
+			e.Error = resp.Status
+		}
+		return fmt.Errorf("Gateway menolak permintaan: %s", e.Error)
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// Kerangka adaptor untuk API bergaya Midtrans (Core API)
+// URL berisi alamat dasar, mis. "https://api.sandbox.midtrans.com", dan APIKey berisi server key
+// Kartu membutuhkan token kartu dari SDK di sisi pelanggan, sehingga baru QRIS yang bisa ditagih dari kasir
+type midtransGateway struct {
+	cfg  GatewayConfig
+	http *http.Client
+}
+
+func (g midtransGateway) Charge(req ChargeRequest) (ChargeResult, error) {
+	if req.Method != MethodQRIS {
+		return ChargeResult{}, fmt.Errorf("Adaptor midtrans belum mendukung metode %s", req.Method)
+	}
+	body := map[string]any{
+		"payment_type": "qris",
+		"transaction_details": map[string]any{
+			"order_id":     req.PaymentID,
+			"gross_amount": int64(req.Amount / Rp), // Midtrans menerima rupiah bulat
+		},
+	}
+	var result struct {
+		TransactionStatus string `json:"transaction_status"`
+		Actions           []struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"actions"`
+	}
+	if err := g.do(http.MethodPost, "/v2/charge", body, &result); err != nil {
+		return ChargeResult{}, err
+	}
+	charge := ChargeResult{Reference: req.PaymentID, Status: midtransStatus(result.TransactionStatus)}
+	for _, a := range result.Actions {
+		if a.Name == "generate-qr-code" {
+			charge.Action = a.URL
+		}
+	}
+	return charge, nil
+}
+
+func (g midtransGateway) Status(ref string) (ChargeStatus, error) {
+	var result struct {
+		TransactionStatus string `json:"transaction_status"`
+	}
+	if err := g.do(http.MethodGet, "/v2/"+ref+"/status", nil, &result); err != nil {
+		return "", err
+	}
+	return midtransStatus(result.TransactionStatus), nil
+}
+
+func (g midtransGateway) Refund(ref string, amount Money, key, reason string) (string, error) {
+	body := map[string]any{"refund_key": key, "amount": int64(amount / Rp), "reason": reason}
+	var result struct {
+		RefundKey string `json:"refund_key"`
+	}
+	if err := g.do(http.MethodPost, "/v2/"+ref+"/refund", body, &result); err != nil {
+		return "", err
+	}
+	return result.RefundKey, nil
+}
+
+// Mengubah transaction_status Midtrans ke status tagihan
+func midtransStatus(status string) ChargeStatus {
+	switch status {
+	case "settlement", "capture":
+		return ChargePaid
+	case "pending", "authorize":
+		return ChargePending
+	}
+	return ChargeFailed
+}
+
+// Mengirim request ke API Midtrans; server key dikirim sebagai Basic auth tanpa kata sandi
+// Midtrans juga mengirim kode status di body, kode selain 2xx dianggap gagal
+func (g midtransGateway) do(method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(g.cfg.URL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(g.cfg.APIKey, "")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("Gateway pembayaran tidak dapat dihubungi: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	var status struct {
+		StatusCode    string `json:"status_code"`
+		StatusMessage string `json:"status_message"`
+	}
+	json.Unmarshal(raw, &status)
+	if resp.StatusCode >= 300 || (status.StatusCode != "" && !strings.HasPrefix(status.StatusCode, "2")) {
+		if status.StatusMessage == "" {
+			status.StatusMessage = resp.Status
+		}
+		return fmt.Errorf("Gateway menolak permintaan: %s", status.StatusMessage)
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// Fungsi untuk menagih pembayaran kartu/QRIS dan menunggu sampai lunas atau gagal
+// Setiap request ke gateway diawasi watchdog; gateway yang menggantung dianggap gagal sehingga kasir bisa memilih metode lain
+func chargeGateway(gateway PaymentGateway, req ChargeRequest, timeout time.Duration) (string, error) {
+	var result ChargeResult
+	err := activeWatchdog.Guard(watchPayment, "tagihan "+req.PaymentID, func() (err error) {
+		result, err = gateway.Charge(req)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if result.Action != "" {
+		fmt.Println(tr("Tunjukkan ke pelanggan:"), result.Action)
+	}
+	deadline := time.Now().Add(timeout)
+	for result.Status == ChargePending {
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("Pembayaran %s belum selesai setelah %s", result.Reference, timeout)
+		}
+		time.Sleep(2 * time.Second)
+		var status ChargeStatus
+		err := activeWatchdog.Guard(watchPayment, "status "+result.Reference, func() (err error) {
+			status, err = gateway.Status(result.Reference)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		result.Status = status
+	}
+	if result.Status != ChargePaid {
+		return "", errChargeFailed
+	}
+	return result.Reference, nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	errVoucherNotFound = errors.New("Voucher hadiah tidak ditemukan")
+	errVoucherExists   = errors.New("Kode voucher hadiah sudah dipakai")
+	errVoucherEmpty    = errors.New("Saldo voucher hadiah sudah habis")
+	errVoucherExpired  = errors.New("Voucher hadiah sudah kedaluwarsa")
+	errVoucherBalance  = errors.New("Saldo voucher hadiah tidak cukup")
+)
+
+// Struct untuk Voucher hadiah (store credit) dengan saldo rupiah
+// Saldo boleh dipakai sebagian, sisanya tersimpan untuk pembayaran berikutnya
+type GiftVoucher struct {
+	Code        string              `json:"code"`                // Kode voucher, disimpan dalam huruf besar
+	Initial     Money               `json:"initial"`             // Saldo saat voucher dibuat
+	Balance     Money               `json:"balance"`             // Sisa saldo
+	CreatedAt   time.Time           `json:"created_at"`          // Waktu voucher dibuat
+	CreatedBy   string              `json:"created_by"`          // Admin yang membuat voucher
+	ExpiresAt   time.Time           `json:"expires_at,omitzero"` // Batas pemakaian, kosong berarti tidak kedaluwarsa
+	Redemptions []VoucherRedemption `json:"redemptions,omitempty"`
+}
+
+// Struct untuk satu pemakaian saldo voucher hadiah
+type VoucherRedemption struct {
+	PaymentID string    `json:"payment_id"`          // Pembayaran yang dipotong saldo voucher
+	RefundID  string    `json:"refund_id,omitempty"` // Refund yang mengembalikan saldo, Amount-nya negatif
+	Amount    Money     `json:"amount"`              // Saldo yang dipakai
+	At        time.Time `json:"at"`
+}
+
+// Memeriksa apakah voucher masih bisa dipakai pada waktu tertentu
+func (v GiftVoucher) Usable(now time.Time) error {
+	if !v.ExpiresAt.IsZero() && now.After(v.ExpiresAt) {
+		return errVoucherExpired
+	}
+	if v.Balance <= 0 {
+		return errVoucherEmpty
+	}
+	return nil
+}
+
+// Fungsi untuk menyeragamkan kode voucher: huruf besar tanpa spasi di tepi
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Fungsi untuk membuat kode voucher acak yang mudah dibaca, mis. "GV-7K2M9QXA"
+func newVoucherCode() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	for i := range b {
+		b[i] = crockford[b[i]%32]
+	}
+	return "GV-" + string(b)
+}
+
+// Menyimpan voucher hadiah baru
+func (s *Store) CreateGiftVoucher(v GiftVoucher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v.Code = normalizeVoucherCode(v.Code)
+	if s.findGiftVoucher(v.Code) != nil {
+		return errVoucherExists
+	}
+	s.data.GiftVouchers = append(s.data.GiftVouchers, v)
+	return s.save()
+}
+
+// Mencari voucher hadiah berdasarkan kode
+func (s *Store) GiftVoucher(code string) (GiftVoucher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := s.findGiftVoucher(normalizeVoucherCode(code))
+	if v == nil {
+		return GiftVoucher{}, errVoucherNotFound
+	}
+	return *v, nil
+}
+
+// Mengambil salinan seluruh voucher hadiah
+func (s *Store) GiftVouchers() []GiftVoucher {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]GiftVoucher(nil), s.data.GiftVouchers...)
+}
+
+// Mencari voucher hadiah; pemanggil harus memegang s.mu
+func (s *Store) findGiftVoucher(code string) *GiftVoucher {
+	for i := range s.data.GiftVouchers {
+		if s.data.GiftVouchers[i].Code == code {
+			return &s.data.GiftVouchers[i]
+		}
+	}
+	return nil
+}
+
+// Memotong saldo voucher hadiah yang dipakai pembayaran; pemanggil harus memegang s.mu
+// Pembayaran yang sudah tercatat (mis. replay jurnal) tidak memotong saldo dua kali
+func (s *Store) redeemGiftVoucher(p Payment) {
+	if p.VoucherCode == "" || p.GiftVoucher <= 0 {
+		return
+	}
+	v := s.findGiftVoucher(normalizeVoucherCode(p.VoucherCode))
+	if v == nil || slices.ContainsFunc(v.Redemptions, func(r VoucherRedemption) bool { return r.PaymentID == p.ID }) {
+		return
+	}
+	used := min(p.GiftVoucher, v.Balance)
+	v.Balance -= used
+	v.Redemptions = append(v.Redemptions, VoucherRedemption{PaymentID: p.ID, Amount: used, At: p.PaidAt})
+}
+
+// Mengembalikan bagian refund ke saldo voucher hadiah asal; pemanggil harus memegang s.mu
+// Refund yang sudah tercatat (mis. replay jurnal) tidak menambah saldo dua kali
+func (s *Store) creditGiftVoucher(r Refund) {
+	if r.VoucherCode == "" || r.Voucher <= 0 {
+		return
+	}
+	v := s.findGiftVoucher(normalizeVoucherCode(r.VoucherCode))
+	if v == nil || slices.ContainsFunc(v.Redemptions, func(x VoucherRedemption) bool { return x.RefundID == r.ID }) {
+		return
+	}
+	v.Balance += r.Voucher
+	v.Redemptions = append(v.Redemptions, VoucherRedemption{PaymentID: r.PaymentID, RefundID: r.ID, Amount: -r.Voucher, At: r.CreatedAt})
+}
+
+// Memastikan voucher hadiah pada pembayaran masih berlaku dan saldonya cukup
+func checkGiftVoucher(store *Store, p Payment) error {
+	if p.VoucherCode == "" {
+		return nil
+	}
+	v, err := store.GiftVoucher(p.VoucherCode)
+	if err != nil {
+		return err
+	}
+	if err := v.Usable(time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", v.Code, err)
+	}
+	if p.GiftVoucher > v.Balance {
+		return fmt.Errorf("%s: %w (sisa Rp%.2f)", v.Code, errVoucherBalance, v.Balance)
+	}
+	return nil
+}
+
+// Fungsi untuk menanyakan voucher hadiah dan memotong tagihan dengan saldonya
+// Saldo yang melebihi tagihan hanya dipakai sebesar tagihan, sisanya tetap di voucher
+func applyGiftVoucher(backend CashierBackend, pricing Pricing, payment *Payment) {
+	for payment.Amount > 0 {
+		code := readLine(tr("Kode voucher hadiah (Enter untuk lewati):"))
+		if code == "" {
+			return
+		}
+		v, err := backend.GiftVoucher(code)
+		if err == nil {
+			err = v.Usable(time.Now())
+		}
+		if err != nil {
+			fmt.Println(tr("Voucher hadiah tidak dapat dipakai:"), err)
+			continue
+		}
+		unrounded := payment.Amount - payment.Rounding
+		payment.VoucherCode = v.Code
+		payment.GiftVoucher = min(v.Balance, unrounded)
+		payment.Amount, payment.Rounding = pricing.RoundPayment(unrounded - payment.GiftVoucher)
+		fmt.Print(tr("Voucher %s: -Rp%.2f, sisa saldo Rp%.2f\n", v.Code, payment.GiftVoucher, v.Balance-payment.GiftVoucher))
+		if payment.Rounding != 0 {
+			fmt.Print(tr("Pembulatan    : Rp%.2f\n", payment.Rounding))
+		}
+		fmt.Print(tr("Sisa tagihan: Rp%.2f\n", payment.Amount))
+		return
+	}
+}
+
+// GET /api/v1/terminal/gift-vouchers/{code}
+func (s *Server) handleTerminalGiftVoucher(w http.ResponseWriter, r *http.Request) {
+	v, err := s.local.GiftVoucher(r.PathValue("code"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// Fungsi untuk menampilkan satu voucher hadiah
+func printGiftVoucher(v GiftVoucher) {
+	status := "aktif"
+	if err := v.Usable(time.Now()); err != nil {
+		status = err.Error()
+	}
+	expires := ""
+	if !v.ExpiresAt.IsZero() {
+		expires = " s.d. " + v.ExpiresAt.Local().Format("2006-01-02")
+	}
+	fmt.Printf("%-12s saldo Rp%.2f dari Rp%.2f%s (%s)\n", v.Code, v.Balance, v.Initial, expires, status)
+}
+
+// Fungsi untuk menjalankan sub-perintah "voucher"
+func runVoucherCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: voucher create -amount <rupiah>|list|show <kode>")
+	}
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("voucher create", flag.ContinueOnError)
+		amount := fs.String("amount", "", "saldo voucher dalam rupiah, mis. 100000")
+		code := fs.String("code", "", "kode voucher, kosong berarti dibuat acak")
+		expires := fs.String("expires", "", "tanggal terakhir voucher bisa dipakai YYYY-MM-DD")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		value, err := validatePrice(*amount)
+		if err != nil || value <= 0 {
+			return fmt.Errorf("Saldo voucher tidak valid: %s", *amount)
+		}
+		v := GiftVoucher{Code: *code, Initial: value, Balance: value, CreatedAt: time.Now()}
+		if v.Code == "" {
+			v.Code = newVoucherCode()
+		}
+		if *expires != "" {
+			date, err := time.ParseInLocation("2006-01-02", *expires, time.Local)
+			if err != nil {
+				return fmt.Errorf("Tanggal -expires tidak valid: %w", err)
+			}
+			v.ExpiresAt = endOfDay(date)
+		}
+		admin, err := requireAdmin(cfg, store)
+		if err != nil {
+			return err
+		}
+		v.CreatedBy = admin.Name
+		if err := store.CreateGiftVoucher(v); err != nil {
+			return err
+		}
+		v, _ = store.GiftVoucher(v.Code)
+		printGiftVoucher(v)
+		return store.Audit(admin, "voucher.create", v.Code, fmt.Sprintf("Rp%.2f", v.Initial))
+	case "list":
+		vouchers := store.GiftVouchers()
+		if len(vouchers) == 0 {
+			fmt.Println("Belum ada voucher hadiah.")
+		}
+		for _, v := range vouchers {
+			printGiftVoucher(v)
+		}
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: voucher show <kode>")
+		}
+		v, err := store.GiftVoucher(args[1])
+		if err != nil {
+			return err
+		}
+		printGiftVoucher(v)
+		for _, r := range v.Redemptions {
+			if r.RefundID != "" {
+				fmt.Printf("  %s  %s  +Rp%.2f (refund)\n", r.At.Local().Format("2006-01-02 15:04"), r.RefundID, -r.Amount)
+				continue
+			}
+			fmt.Printf("  %s  %s  -Rp%.2f\n", r.At.Local().Format("2006-01-02 15:04"), r.PaymentID, r.Amount)
+		}
+	default:
+		return fmt.Errorf("Sub-perintah voucher tidak dikenal: %s", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Mode ringkas untuk layar kecil, mis. pelayan yang memakai SSH/Termux dari ponsel
+// Diatur dari flag -compact atau cashier.compact
+var compactMode bool
+
+// Katalog prompt ringkas, kunci adalah teks lengkap seperti di tr
+// Teks ringkas diterjemahkan lagi lewat messagesEN, sehingga mode ringkas tetap mengikuti -lang
+var messagesCompact = map[string]string{
+	"Masukkan nomor atau nama item, boleh diikuti jumlah mis. '1 x2' (ketik 'batal' untuk menghapus item terakhir, 'tempel' untuk pesanan dari chat, 'selesai' untuk menyelesaikan): ": "Item [xJml] / batal / tempel / selesai:",
+	"Masukkan jumlah: ":                                                   "Jml:",
+	"Kirim pesanan? (Ya/Tidak)":                                           "Kirim? (y/t)",
+	"Nomor pilihan (Enter untuk opsi 1): ":                                "No [1]:",
+	"Nomor tambahan, pisahkan dengan koma (Enter jika tidak ada): ":       "Tambahan (1,2) [-]:",
+	"Catatan dapur (nomor/nama dipisah koma, Enter jika tidak ada): ":     "Catatan [-]:",
+	"Tekan nomor pengganti (Enter untuk batal): ":                         "Pengganti [batal]:",
+	"Nomor meja (Enter untuk bawa pulang):":                               "Meja [bawa]:",
+	"Nomor meja:":                                                         "Meja:",
+	"Alamat pengantaran:":                                                 "Alamat:",
+	"Ongkos kirim (Enter untuk Rp%.0f):":                                  "Ongkir [%.0f]:",
+	"Nomor HP pelanggan member (Enter untuk lewati):":                     "HP [-]:",
+	"Masukkan jumlah yang dibayar:":                                       "Bayar:",
+	"Email untuk struk (Enter untuk lewati):":                             "Email [-]:",
+	"Bayar sekarang? (y/n, n untuk simpan sebagai pesanan terbuka)":       "Bayar? (y/n)",
+	"Metode pembayaran: 1. Tunai, 2. Kartu, 3. QRIS (Enter untuk tunai):": "1 Tunai 2 Kartu 3 QRIS [1]:",
+	"Tetap lanjutkan (override dicatat)? (y/n)":                           "Lanjut? (y/n)",
+}
+
+// Fungsi untuk mengganti pesan dengan versi ringkas jika mode ringkas aktif
+func compactMessage(msg string) string {
+	if short, ok := messagesCompact[msg]; ok && compactMode {
+		return short
+	}
+	return msg
+}
+
+// Fungsi untuk menampilkan harga ringkas, mis. 25000 menjadi "25rb" dan 17600 menjadi "17,6rb"
+func shortPrice(price Money) string {
+	if price < 1000*Rp {
+		return strconv.FormatFloat(price.Float(), 'f', -1, 64)
+	}
+	return strings.Replace(strconv.FormatFloat(price.Float()/1000, 'f', -1, 64), ".", ",", 1) + "rb"
+}
+
+// Menampilkan menu dalam satu kolom sempit tanpa format Rp
+func (r *Restaurant) printMenuCompact() {
+	for i, item := range r.Menu {
+		mark := ""
+		if item.SoldOut {
+			mark = " x"
+		}
+		fmt.Printf("%d %s %s%s\n", i+1, item.Name, shortPrice(item.Price), mark)
+	}
+}
+
+// Fungsi untuk menampilkan daftar pilihan bernomor di bawah judul
+// Mode ringkas menampilkan judul pendek dan semua pilihan pada satu baris agar tidak perlu menggulir
+func printChoices(header, short string, labels []string) {
+	if compactMode {
+		choices := make([]string, len(labels))
+		for i, l := range labels {
+			choices[i] = fmt.Sprintf("%d %s", i+1, l)
+		}
+		fmt.Printf("%s: %s\n", short, strings.Join(choices, " | "))
+		return
+	}
+	fmt.Print(header)
+	for i, l := range labels {
+		fmt.Printf("%d. %s\n", i+1, l)
+	}
+}
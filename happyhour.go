@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Singkatan hari untuk jadwal aturan harga, mengikuti urutan time.Weekday
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Struct untuk Aturan harga berjadwal, mis. diskon 20% Mie Goreng 14:00-17:00 di hari kerja
+// Aturan berlaku untuk item dengan nama Item, atau semua item di Category jika Item kosong
+type PriceRule struct {
+	Name     string   `json:"name"`               // Nama aturan yang dicatat di baris pesanan, mis. "Happy Hour"
+	Item     string   `json:"item,omitempty"`     // Nama item yang mendapat harga khusus
+	Category string   `json:"category,omitempty"` // Kategori item, dipakai jika Item kosong
+	Percent  float64  `json:"percent,omitempty"`  // Potongan dari harga menu, mis. 0.2 untuk 20%
+	Price    Money    `json:"price,omitempty"`    // Harga khusus, dipakai jika Percent kosong
+	Days     []string `json:"days,omitempty"`     // Hari berlaku: mon..sun, weekday, atau weekend; kosong berarti setiap hari
+	From     string   `json:"from"`               // Jam mulai "15:04"
+	To       string   `json:"to"`                 // Jam berakhir "15:04" (tidak termasuk), boleh melewati tengah malam
+}
+
+// Fungsi untuk memeriksa aturan harga berjadwal
+func validatePriceRules(rules []PriceRule) error {
+	for i, r := range rules {
+		if r.Name == "" {
+			return fmt.Errorf("pricing.rules[%d]: name harus diisi", i)
+		}
+		if r.Item == "" && r.Category == "" {
+			return fmt.Errorf("pricing.rules[%d]: item atau category harus diisi", i)
+		}
+		if r.Percent < 0 || r.Percent >= 1 || r.Price < 0 || (r.Percent == 0 && r.Price == 0) {
+			return fmt.Errorf("pricing.rules[%d]: isi percent antara 0 dan 1 atau price", i)
+		}
+		for _, d := range r.Days {
+			if d != "weekday" && d != "weekend" && !slices.Contains(weekdayNames, d) {
+				return fmt.Errorf("pricing.rules[%d]: hari tidak dikenal: %s", i, d)
+			}
+		}
+		for _, clock := range []string{r.From, r.To} {
+			if _, err := time.Parse("15:04", clock); err != nil {
+				return fmt.Errorf("pricing.rules[%d]: jam harus berformat HH:MM", i)
+			}
+		}
+	}
+	return nil
+}
+
+// Menandakan aturan berlaku untuk item pada waktu tertentu
+func (r PriceRule) appliesAt(item MenuItem, at time.Time) bool {
+	if r.Item != "" && !strings.EqualFold(item.Name, r.Item) || r.Item == "" && !strings.EqualFold(item.Category, r.Category) {
+		return false
+	}
+	at = at.Local()
+	clock := at.Format("15:04")
+	day := at.Weekday()
+	if r.From > r.To && clock < r.To {
+		// Jadwal melewati tengah malam: jam setelah tengah malam termasuk hari sebelumnya
+		day = (day + 6) % 7
+	}
+	if len(r.Days) > 0 && !slices.ContainsFunc(r.Days, func(d string) bool {
+		weekend := day == time.Saturday || day == time.Sunday
+		return d == weekdayNames[day] || d == "weekend" && weekend || d == "weekday" && !weekend
+	}) {
+		return false
+	}
+	if r.From <= r.To {
+		return clock >= r.From && clock < r.To
+	}
+	return clock >= r.From || clock < r.To
+}
+
+// Harga item setelah aturan diterapkan
+func (r PriceRule) apply(price Money) Money {
+	if r.Percent > 0 {
+		return price.MulRate(1 - r.Percent)
+	}
+	return min(r.Price, price)
+}
+
+// Menentukan harga menu yang berlaku untuk baris pada waktu pesanan
+// Harga normal disimpan di BasePrice agar perhitungan ulang (mis. di server pusat) menghasilkan harga yang sama
+// Jika beberapa aturan berlaku, harga termurah yang dipakai
+func (p Pricing) resolveLinePrice(line *OrderLine, at time.Time) {
+	if len(p.Rules) == 0 {
+		return
+	}
+	if line.BasePrice == 0 {
+		line.BasePrice = line.Item.Price
+	}
+	line.Item.Price, line.PriceRule = line.BasePrice, ""
+	for _, r := range p.Rules {
+		if price := r.apply(line.BasePrice); r.appliesAt(line.Item, at) && price < line.Item.Price {
+			line.Item.Price, line.PriceRule = price, r.Name
+		}
+	}
+}
+
+// Fungsi untuk mencetak keterangan aturan harga di bawah baris pesanan
+func writePriceRuleNote(w io.Writer, line OrderLine) {
+	if line.PriceRule != "" {
+		fmt.Fprint(w, tr("  %s, harga normal Rp%.2f\n", line.PriceRule, line.BasePrice))
+	}
+}
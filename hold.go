@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	errNoHeldOrder    = errors.New("Tidak ada pesanan yang ditahan dengan label ini")
+	errHoldLabelTaken = errors.New("Label sudah dipakai pesanan lain yang ditahan")
+)
+
+// Label pesanan yang ditahan sudah dipakai draf lain; pemanggil harus memegang s.mu
+func (s *Store) holdLabelTaken(order Order) bool {
+	if order.HoldLabel == "" {
+		return false
+	}
+	return slices.ContainsFunc(s.data.Drafts, func(o Order) bool {
+		return o.ID != order.ID && strings.EqualFold(o.HoldLabel, order.HoldLabel)
+	})
+}
+
+// Mengambil pesanan yang ditahan dengan label tersebut lalu melepasnya dari draf
+// Pesanan yang dilanjutkan kembali menjadi keranjang kasir dan disimpan ulang jika kasir berhenti
+func (s *Store) ResumeHeldOrder(label string) (Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.data.Drafts, func(o Order) bool {
+		return o.HoldLabel != "" && strings.EqualFold(o.HoldLabel, strings.TrimSpace(label))
+	})
+	if i < 0 {
+		return Order{}, errNoHeldOrder
+	}
+	order := s.data.Drafts[i]
+	s.data.Drafts = slices.Delete(s.data.Drafts, i, i+1)
+	return order, s.save()
+}
+
+// Fungsi untuk menahan pesanan yang sedang diinput agar kasir bisa melayani pelanggan lain dulu
+// Pesanan disimpan sebagai draf berlabel; mengembalikan false jika pesanan tidak jadi ditahan
+func holdOrder(restaurant *Restaurant, order *Order, label string) bool {
+	if restaurant.HoldOrder == nil {
+		fmt.Println(tr("Menahan pesanan tidak tersedia di sesi ini."))
+		return false
+	}
+	if len(order.Lines) == 0 {
+		fmt.Println(tr("Belum ada item untuk ditahan."))
+		return false
+	}
+	if label = strings.TrimSpace(label); label == "" {
+		label = strings.TrimSpace(readLine(tr("Label pesanan yang ditahan (mis. nama pelanggan):")))
+		if label == "" {
+			return false
+		}
+	}
+	order.HoldLabel = label
+	if err := restaurant.HoldOrder(*order); err != nil {
+		fmt.Println(tr("Pesanan tidak dapat ditahan:"), err)
+		order.HoldLabel = ""
+		return false
+	}
+	fmt.Print(tr("Pesanan ditahan sebagai %q (%d item, Rp%.2f). Ketik 'lanjut %s' untuk melanjutkan.\n", label, len(order.Lines), order.Total, label))
+	return true
+}
+
+// Fungsi untuk melanjutkan pesanan yang ditahan beserta item dan total sementaranya
+// Label tetap dibawa agar pesanan yang disimpan saat kasir berhenti masih bisa dilanjutkan
+func resumeHeldOrder(restaurant *Restaurant, order *Order, label string) bool {
+	if restaurant.HeldOrder == nil {
+		fmt.Println(tr("Menahan pesanan tidak tersedia di sesi ini."))
+		return false
+	}
+	if len(order.Lines) > 0 {
+		fmt.Println(tr("Pesanan saat ini masih berisi item, ketik 'tahan <label>' atau 'selesai' dulu."))
+		return false
+	}
+	held, err := restaurant.HeldOrder(label)
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+	*order = held
+	fmt.Print(tr("Melanjutkan pesanan %q:\n", held.HoldLabel))
+	for _, line := range held.Lines {
+		fmt.Printf("- %s x%d: Rp%.2f\n", line.Label(), line.Qty, line.Subtotal())
+	}
+	fmt.Print(tr("Total sementara: Rp%.2f\n", held.Total))
+	openCart.Update(*order)
+	return true
+}
+
+// Fungsi untuk memulai pesanan baru beserta jenis pesanan dan alerginya jika ditanyakan
+func startOrder(restaurant *Restaurant) (Order, error) {
+	order := Order{ID: orderIDs.NewID("ORD"), Status: OrderPending, CreatedAt: time.Now()}
+	if restaurant.OrderTypes {
+		if err := promptOrderType(restaurant, &order); err != nil {
+			return order, err
+		}
+	}
+	if restaurant.AskAllergies {
+		if err := promptAllergies(&order); err != nil {
+			return order, err
+		}
+	}
+	return order, nil
+}
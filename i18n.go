@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bahasa tampilan yang sedang dipakai, diatur dari flag -lang atau konfigurasi
+var lang = "id"
+
+// Katalog pesan bahasa Inggris
+// Kunci adalah teks asli bahasa Indonesia (termasuk format fmt), sehingga pesan tanpa terjemahan tetap tampil dalam bahasa Indonesia
+var messagesEN = map[string]string{
+	// Menu dan input pesanan
+	"Menu:":                       "Menu:",
+	"   Isi: %s (hemat Rp%.2f)\n": "   Includes: %s (save Rp%.2f)\n",
+	"%d. %s: Rp%.2f (HABIS)\n":    "%d. %s: Rp%.2f (SOLD OUT)\n",
+	"%s sedang habis. Pengganti yang tersedia:\n":              "%s is sold out. Available substitutes:\n",
+	"%s sedang habis dan tidak ada pengganti yang tersedia.\n": "%s is sold out and no substitutes are available.\n",
+	"Tekan nomor pengganti (Enter untuk batal): ":              "Enter substitute number (Enter to cancel): ",
+	"Masukkan nomor atau nama item, boleh diikuti jumlah mis. '1 x2' (ketik 'cari <kata>' untuk mencari menu, 'batal' untuk menghapus item terakhir, 'tahan'/'lanjut <label>' untuk menahan pesanan, 'prioritas <rider|vip>' untuk mendahulukan di dapur, 'tempel' untuk pesanan dari chat, 'selesai' untuk menyelesaikan): ": "Enter item number or name, optionally followed by quantity e.g. '1 x2' (type 'search <word>' to search the menu, 'undo' to remove the last item, 'hold'/'resume <label>' to hold an order, 'priority <rider|vip>' to rush it in the kitchen, 'paste' for a chat order, 'done' to finish): ",
+	"selesai": "done",
+	"batal":   "undo",
+	"tempel":  "paste",
+	"cari":    "search",
+	"Tempel pesanan dari chat, akhiri dengan baris kosong:": "Paste the chat order, end with an empty line:",
+	"Hasil membaca pesanan chat:":                           "Parsed chat order:",
+	"  (tebakan dari %q)":                                   "  (guessed from %q)",
+	"Tidak dikenali:":                                       "Not recognized:",
+	"Tidak ada item yang dikenali.":                         "No items recognized.",
+	"y tambahkan, n batal, h<nomor> hapus baris, <nomor> x<jumlah> ubah jumlah:": "y add, n cancel, h<number> remove line, <number> x<qty> change quantity:",
+	"Pesanan chat dibatalkan.":                                        "Chat order cancelled.",
+	"%s tidak ditambahkan.\n":                                         "%s not added.\n",
+	"Total sementara: Rp%.2f\n":                                       "Running total: Rp%.2f\n",
+	"Dihapus: %s x%d. Total sementara: Rp%.2f\n":                      "Removed: %s x%d. Running total: Rp%.2f\n",
+	"Belum ada item untuk dihapus.":                                   "No item to remove yet.",
+	"Maksud Anda %q? (y/n)":                                           "Did you mean %q? (y/n)",
+	"Ringkasan pesanan:":                                              "Order summary:",
+	"Kirim pesanan? (Ya/Tidak)":                                       "Submit order? (Yes/No)",
+	"Pesanan belum dikirim, silakan ubah pesanan.":                    "Order not submitted, continue editing.",
+	"Jawab Ya atau Tidak.":                                            "Answer Yes or No.",
+	"Alias tidak dapat disimpan:":                                     "Alias could not be saved:",
+	"Item tidak valid. Coba lagi.":                                    "Invalid item. Try again.",
+	"Masukkan jumlah: ":                                               "Enter quantity: ",
+	"Catatan dapur (nomor/nama dipisah koma, Enter jika tidak ada): ": "Kitchen notes (numbers/names separated by commas, Enter for none): ",
+	"Jumlah tidak valid. Coba lagi.":                                  "Invalid quantity. Try again.",
+	"Pilih %s:\n":                                                     "Choose %s:\n",
+	"Nomor pilihan (Enter untuk opsi 1): ":                            "Option number (Enter for option 1): ",
+	"Pilihan tidak valid. Coba lagi.":                                 "Invalid choice. Try again.",
+	"Tambahan:":                                                       "Add-ons:",
+	"Nomor tambahan, pisahkan dengan koma (Enter jika tidak ada): ":   "Add-on numbers, comma separated (Enter for none): ",
+	"Harga sudah termasuk pajak %g%%.":                                "Prices include %g%% tax.",
+	"Harga belum termasuk pajak %g%%.":                                "Prices exclude %g%% tax.",
+
+	// Jenis pesanan
+	"Jenis pesanan:\n":                   "Order type:\n",
+	"Jenis":                              "Type",
+	"Makan di tempat":                    "Dine-in",
+	"Antar":                              "Delivery",
+	"Nomor meja:":                        "Table number:",
+	"Alamat pengantaran:":                "Delivery address:",
+	"Ongkos kirim (Enter untuk Rp%.0f):": "Delivery fee (Enter for Rp%.0f):",
+	"Ongkos kirim  : Rp%.2f\n":           "Delivery fee  : Rp%.2f\n",
+	"Jenis         :":                    "Type          :",
+	"Alamat        :":                    "Address       :",
+
+	// Alur kasir
+	"Menerima pesanan tambahan di":                                  "Accepting extra orders at",
+	"Kasir:":                                                        "Cashier:",
+	"Reservasi hari ini:":                                           "Today's reservations:",
+	"Reservasi hari ini tidak dapat dimuat:":                        "Today's reservations could not be loaded:",
+	"Pesanan Anda:":                                                 "Your order:",
+	"Total Pesanan: Rp%.2f\n":                                       "Order total: Rp%.2f\n",
+	"Pesanan (encoded base64):":                                     "Order (base64 encoded):",
+	"Nomor meja (Enter untuk bawa pulang):":                         "Table number (Enter for takeaway):",
+	"Nomor HP pelanggan member (Enter untuk lewati):":               "Member phone number (Enter to skip):",
+	"Gagal mencari pelanggan:":                                      "Failed to look up customer:",
+	"Pelanggan: %s (%s)\n":                                          "Customer: %s (%s)\n",
+	"Pelanggan baru. Masukkan nama:":                                "New customer. Enter name:",
+	"Setuju data pembelian dibagikan ke program loyalitas? (y/n)":   "Agree to share purchase data with the loyalty program? (y/n)",
+	"Setuju menerima promosi? (y/n)":                                "Agree to receive promotions? (y/n)",
+	"Gagal mendaftarkan pelanggan:":                                 "Failed to register customer:",
+	"Bayar sekarang? (y/n, n untuk simpan sebagai pesanan terbuka)": "Pay now? (y/n, n to keep as an open order)",
+	"Pesanan terbuka disimpan:":                                     "Open order saved:",
+	"Gagal menyimpan pesanan:":                                      "Failed to save order:",
+	"Coba lagi? (y/n)":                                              "Try again? (y/n)",
+	"ID pesanan:":                                                   "Order ID:",
+	"Program selesai":                                               "Program finished",
+	"\nMenghentikan kasir, pesanan baru tidak diterima...":          "\nStopping cashier, no new orders accepted...",
+	"Email untuk struk (Enter untuk lewati):":                       "Email for receipt (Enter to skip):",
+	"Struk akan dikirim ke":                                         "Receipt will be sent to",
+	"Struk tidak dapat dikirim:":                                    "Receipt could not be sent:",
+
+	// Pembayaran
+	"Metode pembayaran: 1. Tunai, 2. Kartu, 3. QRIS (Enter untuk tunai):": "Payment method: 1. Cash, 2. Card, 3. QRIS (Enter for cash):",
+	"Pembayaran %s gagal: %v\n":                                      "%s payment failed: %v\n",
+	"Pembayaran %s berhasil, referensi: %s\n":                        "%s payment succeeded, reference: %s\n",
+	"Tunjukkan ke pelanggan:":                                        "Show to customer:",
+	"Masukkan jumlah yang dibayar:":                                  "Enter amount paid:",
+	"Jumlah yang dibayar valid. Kembalian: Rp%.2f\n":                 "Amount accepted. Change: Rp%.2f\n",
+	"Jumlah yang dibayar kurang dari total pesanan. Coba lagi.":      "Amount paid is less than the order total. Try again.",
+	"Input pembayaran tidak valid. Harap masukkan angka yang benar.": "Invalid payment input. Please enter a valid number.",
+	"Saran pecahan kembalian:":                                       "Suggested change:",
+	"  Sisa Rp%d tidak bisa dipecah dengan pecahan yang tersedia\n":  "  Remaining Rp%d cannot be made with available denominations\n",
+
+	// Batas kewajaran harga
+	"Tetap gunakan harga ini? (y/n)": "Use this price anyway? (y/n)",
+	"Persetujuan admin gagal:":       "Admin approval failed:",
+	"Ongkos kirim":                   "Delivery fee",
+	"Masukkan PIN admin.":            "Enter admin PIN.",
+
+	// Pencocokan total sebelum pembayaran
+	"  %s %s: tercatat Rp%.2f, dihitung ulang Rp%.2f\n": "  %s %s: recorded Rp%.2f, recomputed Rp%.2f\n",
+	"Laporan selisih disimpan di":                       "Discrepancy report saved to",
+	"Laporan selisih gagal disimpan:":                   "Could not save discrepancy report:",
+
+	// Perkiraan waktu siap
+	"Perkiraan siap %s: pukul %s (sekitar %d menit)\n": "%s ready at about %s (around %d minutes)\n",
+	"Perkiraan siap pukul %s":                          "Ready at about %s",
+
+	// Pencocokan nomor urut ID dengan server pusat
+	"Nomor urut ID tidak dapat dicocokkan dengan server pusat:": "Could not verify ID sequence with the central server:",
+
+	// Batas ukuran pesanan
+	"jumlah %s maksimal %d per pesanan (diminta %d)": "%s is limited to %d per order (requested %d)",
+	"maksimal %d item per pesanan (diminta %d)":      "at most %d items per order (requested %d)",
+
+	// Pencarian menu
+	"Tidak ada item menu yang cocok dengan %q.":      "No menu items match %q.",
+	"Item yang cocok, ketik nomornya untuk memesan:": "Matching items, type the number to order:",
+
+	// Tab meja
+	"Tab meja %s (%s):\n":         "Table %s tab (%s):\n",
+	"  Ronde %d:\n":               "  Round %d:\n",
+	"Total tab     : Rp%.2f\n":    "Tab total     : Rp%.2f\n",
+	"RONDE %d":                    "ROUND %d",
+	"Meja meminta tagihan? (y/n)": "Is the table asking for the bill? (y/n)",
+	"Tab meja disimpan:":          "Table tab saved:",
+	"Tab meja ditutup:":           "Table tab closed:",
+
+	// Tip
+	"Tip (nominal atau persen mis. 10%, Enter jika tidak ada):":    "Tip (amount or percentage e.g. 10%, Enter for none):",
+	"Tip tidak valid. Coba lagi.":                                  "Invalid tip. Try again.",
+	"Pelayan penerima tip (ID atau nama staf, Enter untuk kasir):": "Waiter receiving the tip (staff ID or name, Enter for cashier):",
+	"Tip Rp%.2f, total dibayar Rp%.2f\n":                           "Tip Rp%.2f, total to pay Rp%.2f\n",
+	"Tip           : Rp%.2f\n":                                     "Tip           : Rp%.2f\n",
+
+	// Stasiun dapur
+	"Stasiun: %s\n":                         "Station: %s\n",
+	"Tiket stasiun %s tidak dapat dicetak:": "Station %s ticket could not be printed:",
+
+	// Menahan pesanan
+	"Menahan pesanan tidak tersedia di sesi ini.":                                          "Holding orders is not available in this session.",
+	"Belum ada item untuk ditahan.":                                                        "No items to hold yet.",
+	"Label pesanan yang ditahan (mis. nama pelanggan):":                                    "Label for the held order (e.g. customer name):",
+	"Pesanan tidak dapat ditahan:":                                                         "Order could not be held:",
+	"Pesanan ditahan sebagai %q (%d item, Rp%.2f). Ketik 'lanjut %s' untuk melanjutkan.\n": "Order held as %q (%d items, Rp%.2f). Type 'resume %s' to continue.\n",
+	"Pesanan saat ini masih berisi item, ketik 'tahan <label>' atau 'selesai' dulu.":       "The current order still has items, type 'hold <label>' or 'done' first.",
+	"Melanjutkan pesanan %q:\n":                                                            "Resuming order %q:\n",
+
+	// Pesanan yang ditinggalkan
+	"\nTidak ada aktivitas selama %d menit, pesanan ditahan sebagai %q. Ketik 'lanjut %s' untuk melanjutkan.\n": "\nNo activity for %d minutes, order held as %q. Type 'resume %s' to continue.\n",
+	"\nTidak ada aktivitas selama %d menit, pesanan dibatalkan (%d item, Rp%.2f).\n":                            "\nNo activity for %d minutes, order cancelled (%d items, Rp%.2f).\n",
+
+	// Prioritas antrean dapur
+	"Kurir menunggu": "Rider waiting",
+	"PRIORITAS:":     "PRIORITY:",
+	"Prioritas pesanan (rider, vip, atau normal):": "Order priority (rider, vip, or normal):",
+	"Pesanan mengantre seperti biasa.":             "The order queues as usual.",
+	"Pesanan didahulukan di dapur: %s.\n":          "Order rushed in the kitchen: %s.\n",
+
+	// Hasil pembayaran
+	"Pembayaran tidak selesai:": "Payment not completed:",
+
+	// Kode barcode/PLU
+	"+ %s x%d: Rp%.2f. Total sementara: Rp%.2f\n": "+ %s x%d: Rp%.2f. Running total: Rp%.2f\n",
+
+	// Peringatan stok
+	"PERINGATAN STOK:":                       "LOW STOCK:",
+	"%s tinggal %d porsi (batas %d porsi)":   "%s has %d portions left (limit %d portions)",
+	"Bahan %s tinggal %g %s (minimal %g %s)": "Ingredient %s has %g %s left (minimum %g %s)",
+
+	// Kode pengambilan
+	"Cek status: kode %s":       "Check status: code %s",
+	"Kode status   :":           "Status code   :",
+	"Pesanan %s":                "Order %s",
+	" (antrean %03d)":           " (queue %03d)",
+	"Siap diambil":              "Ready for pickup",
+	"Sudah diambil":             "Picked up",
+	"Dibatalkan":                "Cancelled",
+	"Sedang dimasak":            "Preparing",
+	"Perkiraan siap pukul %s\n": "Ready at about %s\n",
+
+	// Revisi pesanan
+	"REVISI %d":                "REVISION %d",
+	"  - %dx %s (batal)\n":     "  - %dx %s (void)\n",
+	"Total revisi  : Rp%.2f\n": "Revised total : Rp%.2f\n",
+
+	// Mata uang asing
+	"Tagihan dalam mata uang asing:": "Total in foreign currency:",
+	"Ketik mis. \"20 USD\" untuk membayar dengan mata uang asing, kembalian diberikan dalam rupiah.": "Type e.g. \"20 USD\" to pay in foreign currency, change is given in rupiah.",
+	"Diterima %s %.2f x kurs Rp%.2f = Rp%.2f\n":                                                      "Received %s %.2f x rate Rp%.2f = Rp%.2f\n",
+	"                %s %.2f x kurs Rp%.2f\n":                                                        "                %s %.2f x rate Rp%.2f\n",
+
+	// Voucher hadiah
+	"Kode voucher hadiah (Enter untuk lewati):":          "Gift voucher code (Enter to skip):",
+	"Voucher hadiah tidak dapat dipakai:":                "Gift voucher cannot be used:",
+	"Voucher %s: -Rp%.2f, sisa saldo Rp%.2f\n":           "Voucher %s: -Rp%.2f, remaining balance Rp%.2f\n",
+	"Voucher %s : -Rp%.2f\n":                             "Voucher %s : -Rp%.2f\n",
+	"Tagihan sudah lunas, tidak ada yang perlu dibayar.": "Bill fully paid, nothing left to pay.",
+
+	// Struk dan tiket
+	"STRUK PEMBAYARAN":                  "PAYMENT RECEIPT",
+	"SALINAN STRUK":                     "RECEIPT COPY",
+	"Struk tidak dapat dicetak:":        "Receipt could not be printed:",
+	"Wi-Fi tamu    :":                   "Guest Wi-Fi   :",
+	"Kode Wi-Fi:":                       "Wi-Fi code:",
+	"Voucher Wi-Fi tidak tersedia:":     "Wi-Fi voucher unavailable:",
+	"No. pembayaran:":                   "Payment no.   :",
+	"Waktu         :":                   "Time          :",
+	"Meja          :":                   "Table         :",
+	"Pelanggan     : %s (%s)\n":         "Customer      : %s (%s)\n",
+	"Pesanan %s [%s]\n":                 "Order %s [%s]\n",
+	"Subtotal      : Rp%.2f\n":          "Subtotal      : Rp%.2f\n",
+	"  DPP         : Rp%.2f\n":          "  Tax base    : Rp%.2f\n",
+	"  Pajak (incl): Rp%.2f\n":          "  Tax (incl.) : Rp%.2f\n",
+	"Pajak         : Rp%.2f\n":          "Tax           : Rp%.2f\n",
+	"Pembulatan    : Rp%.2f\n":          "Rounding      : Rp%.2f\n",
+	"Total         : Rp%.2f\n":          "Total         : Rp%.2f\n",
+	"Dibayar       : Rp%.2f\n":          "Paid          : Rp%.2f\n",
+	"Kembalian     : Rp%.2f\n":          "Change        : Rp%.2f\n",
+	"Deposit       : -Rp%.2f\n":         "Deposit       : -Rp%.2f\n",
+	"Deposit reservasi %s: -Rp%.2f\n":   "Reservation deposit %s: -Rp%.2f\n",
+	"Sisa tagihan: Rp%.2f\n":            "Amount due: Rp%.2f\n",
+	"Refund %s    : -Rp%.2f %s\n":       "Refund %s    : -Rp%.2f %s\n",
+	"---------- TIKET DAPUR ----------": "--------- KITCHEN TICKET --------",
+	"Antrean %03d   %s\n":               "Queue %03d   %s\n",
+	"Meja:":                             "Table:",
+	"Bawa pulang":                       "Takeaway",
+	"NOMOR ANTREAN":                     "QUEUE NUMBER",
+	"Ambil saat nomor Anda tampil":      "Collect when your number shows",
+
+	// Aturan pesanan
+	"menu %s":                            "%s menu",
+	"%s maksimal pilihan ke-%d untuk %s": "%s allows at most option %d for %s",
+	"%s maksimal %d per pesanan":         "%s is limited to %d per order",
+	"%s hanya bisa dipesan bersama %s":   "%s can only be ordered with %s",
+	"%s tidak bisa dipesan bersama %s":   "%s cannot be ordered with %s",
+	"PERINGATAN:":                        "WARNING:",
+	"   Alergen:":                        "   Allergens:",
+	"Alergi pelanggan, pisahkan dengan koma (Enter jika tidak ada):": "Customer allergies, comma separated (Enter if none):",
+	"PERINGATAN: %s mengandung %s yang menjadi alergi pelanggan\n":   "WARNING: %s contains %s which the customer is allergic to\n",
+	"Tetap tambahkan? (y/n)": "Add anyway? (y/n)",
+	"ALERGI:":                "ALLERGY:",
+	"Tetap lanjutkan (override dicatat)? (y/n)": "Continue anyway (override is logged)? (y/n)",
+	"  %s, harga normal Rp%.2f\n":               "  %s, regular price Rp%.2f\n",
+	// Prompt mode ringkas
+	"Item [xJml] / selesai:":      "Item [xQty] / done:",
+	"Jml:":                        "Qty:",
+	"Tambahan (1,2) [-]:":         "Add-ons (1,2) [-]:",
+	"Catatan [-]:":                "Notes [-]:",
+	"Pengganti [batal]:":          "Substitute [cancel]:",
+	"Meja [bawa]:":                "Table [takeaway]:",
+	"Alamat:":                     "Address:",
+	"Ongkir [%.0f]:":              "Fee [%.0f]:",
+	"HP [-]:":                     "Phone [-]:",
+	"Bayar:":                      "Paid:",
+	"Bayar? (y/n)":                "Pay now? (y/n)",
+	"1 Tunai 2 Kartu 3 QRIS [1]:": "1 Cash 2 Card 3 QRIS [1]:",
+	"Lanjut? (y/n)":               "Continue? (y/n)",
+	"Tambahan":                    "Add-ons",
+	"Catatan":                     "Notes",
+	"bawa pulang":                 "takeaway",
+	"meja %s":                     "table %s",
+	"Item tidak ditambahkan.":     "Item not added.",
+}
+
+// Fungsi untuk memilih bahasa tampilan
+func setLanguage(code string) error {
+	switch code = strings.ToLower(code); code {
+	case "id", "en":
+		lang = code
+		return nil
+	}
+	return fmt.Errorf("Bahasa tidak dikenal: %s (id atau en)", code)
+}
+
+// Fungsi untuk menerjemahkan pesan ke bahasa yang sedang dipakai
+// Jika ada argumen, pesan dipakai sebagai format fmt
+func tr(msg string, args ...any) string {
+	msg = compactMessage(msg)
+	if lang == "en" {
+		if translated, ok := messagesEN[msg]; ok {
+			msg = translated
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
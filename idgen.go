@@ -0,0 +1,209 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Struct untuk Konfigurasi pembuatan ID pesanan dan pelanggan
+// Strategi: default ("ORD-20240101-1a2b3c"), ulid, uuidv7, atau sequence ("ORD-JKT01-000042")
+type IDConfig struct {
+	Orders       string `json:"orders"`        // Strategi ID pesanan
+	Customers    string `json:"customers"`     // Strategi ID pelanggan
+	Branch       string `json:"branch"`        // Kode cabang di ID sequence, default head_office.branch_id; harus unik per terminal
+	Digits       int    `json:"digits"`        // Panjang nomor urut sequence
+	SequenceFile string `json:"sequence_file"` // File nomor urut terakhir untuk strategi sequence
+}
+
+// Interface untuk pembuat ID
+type IDGenerator interface {
+	NewID(prefix string) string // Membuat ID baru; prefix mis. "ORD" atau "CUS", boleh diabaikan strategi tanpa prefix
+}
+
+// Pembuat ID pesanan dan pelanggan yang dipakai, diatur dari konfigurasi saat program mulai
+var (
+	orderIDs    IDGenerator = randomIDs{}
+	customerIDs IDGenerator = randomIDs{}
+)
+
+// Fungsi untuk mengatur pembuat ID pesanan dan pelanggan dari konfigurasi
+func configureIDs(cfg IDConfig) error {
+	var err error
+	if orderIDs, err = newIDGenerator(cfg.Orders, cfg); err != nil {
+		return fmt.Errorf("ids.orders: %w", err)
+	}
+	if customerIDs, err = newIDGenerator(cfg.Customers, cfg); err != nil {
+		return fmt.Errorf("ids.customers: %w", err)
+	}
+	return nil
+}
+
+// Fungsi untuk membuat pembuat ID sesuai strategi
+func newIDGenerator(strategy string, cfg IDConfig) (IDGenerator, error) {
+	switch strategy {
+	case "", "default":
+		return randomIDs{}, nil
+	case "ulid":
+		return ulidIDs{}, nil
+	case "uuidv7":
+		return uuidV7IDs{}, nil
+	case "sequence":
+		if cfg.Branch == "" {
+			return nil, errors.New("Strategi sequence membutuhkan ids.branch")
+		}
+		return sharedSequence(cfg), nil
+	default:
+		return nil, fmt.Errorf("Strategi ID tidak dikenal: %s (pilih default, ulid, uuidv7, atau sequence)", strategy)
+	}
+}
+
+// Struct untuk ID acak bawaan dengan prefix dan tanggal
+type randomIDs struct{}
+
+func (randomIDs) NewID(prefix string) string {
+	return newID(prefix)
+}
+
+// Struct untuk ULID: 26 karakter Crockford base32, urut menurut waktu pembuatan
+type ulidIDs struct{}
+
+// Alfabet Crockford base32 yang dipakai ULID
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func (ulidIDs) NewID(string) string {
+	var b [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+	rand.Read(b[6:])
+	// 128 bit dibaca per 5 bit dari kiri, diawali 2 bit nol agar genap 130 bit
+	hi, lo := binary.BigEndian.Uint64(b[0:8]), binary.BigEndian.Uint64(b[8:16])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
+
+// Struct untuk UUID versi 7 (RFC 9562): timestamp milidetik diikuti bit acak
+type uuidV7IDs struct{}
+
+func (uuidV7IDs) NewID(string) string {
+	var b [16]byte
+	rand.Read(b[:])
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+	b[6] = b[6]&0x0f | 0x70 // Versi 7
+	b[8] = b[8]&0x3f | 0x80 // Varian RFC 9562
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// Struct untuk nomor urut per prefix dengan kode cabang, mis. "ORD-JKT01-000042"
+// Nomor terakhir disimpan ke file setelah setiap ID agar tidak terulang setelah program dijalankan ulang
+type sequenceIDs struct {
+	branch string
+	digits int
+	path   string
+
+	mu sync.Mutex
+}
+
+// Pembuat sequence per file, dipakai bersama oleh pesanan dan pelanggan agar tidak saling menimpa file
+var (
+	sequencesMu sync.Mutex
+	sequences   = map[string]*sequenceIDs{}
+)
+
+// Fungsi untuk mengambil pembuat sequence untuk file yang sama
+func sharedSequence(cfg IDConfig) *sequenceIDs {
+	sequencesMu.Lock()
+	defer sequencesMu.Unlock()
+	if g, ok := sequences[cfg.SequenceFile]; ok {
+		return g
+	}
+	g := &sequenceIDs{branch: cfg.Branch, digits: cfg.Digits, path: cfg.SequenceFile}
+	sequences[cfg.SequenceFile] = g
+	return g
+}
+
+func (g *sequenceIDs) NewID(prefix string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	next, err := g.advance(prefix)
+	if err != nil {
+		// Nomor urut yang tidak tersimpan bisa terulang, jadi ID acak dipakai agar pesanan tetap tercatat
+		fmt.Println("Nomor urut ID tidak dapat disimpan, memakai ID acak:", err)
+		return newID(prefix)
+	}
+	return fmt.Sprintf("%s-%s-%0*d", prefix, strings.ToUpper(g.branch), g.digits, next)
+}
+
+// Menaikkan dan menyimpan nomor urut prefix; pemanggil harus memegang g.mu
+func (g *sequenceIDs) advance(prefix string) (int, error) {
+	last, err := g.read()
+	if err != nil {
+		return 0, err
+	}
+	last[prefix]++
+	return last[prefix], g.write(last)
+}
+
+// Membaca nomor urut terakhir per prefix dari file; pemanggil harus memegang g.mu
+func (g *sequenceIDs) read() (map[string]int, error) {
+	last := map[string]int{}
+	raw, err := os.ReadFile(g.path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(raw, &last); err != nil {
+			return nil, fmt.Errorf("File %s rusak: %w", g.path, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+	return last, nil
+}
+
+// Menyimpan nomor urut terakhir per prefix ke file; pemanggil harus memegang g.mu
+func (g *sequenceIDs) write(last map[string]int) error {
+	raw, _ := json.MarshalIndent(last, "", "  ")
+	tmp := g.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, g.path)
+}
+
+// Mengambil nomor urut terakhir per prefix
+func (g *sequenceIDs) Last() (map[string]int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.read()
+}
+
+// Melompatkan nomor urut ke nomor yang sudah terpakai agar ID berikutnya tidak terulang
+// Prefix yang nomornya sudah lebih tinggi tidak diubah
+func (g *sequenceIDs) FastForward(issued map[string]int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	last, err := g.read()
+	if err != nil {
+		return err
+	}
+	for prefix, n := range issued {
+		last[prefix] = max(last[prefix], n)
+	}
+	return g.write(last)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var errInputIdle = errors.New("Tidak ada input sampai batas waktu")
+
+// Struct untuk satu baris dari goroutine pembaca input
+type inputLine struct {
+	text string
+	ok   bool // false jika input sudah habis atau gagal dibaca
+}
+
+var (
+	inputReaderOnce sync.Once
+	inputLines      chan inputLine // Diisi goroutine pembaca setelah prompt pertama dengan batas waktu
+	inputReaderErr  error          // Error scanner setelah input habis, dibaca setelah inputLines ditutup
+)
+
+// Fungsi untuk memindahkan pembacaan input ke satu goroutine pembaca agar prompt bisa dibatasi waktunya
+// Goroutine ini dipakai ulang oleh semua prompt berikutnya, sehingga prompt yang habis waktunya
+// tidak meninggalkan goroutine baru yang menunggu Scan
+func startInputReader() {
+	inputReaderOnce.Do(func() {
+		inputLines = make(chan inputLine)
+		scanner := input
+		go func() {
+			for scanner.Scan() {
+				inputLines <- inputLine{text: scanner.Text(), ok: true}
+			}
+			inputReaderErr = scanner.Err()
+			close(inputLines)
+		}()
+	})
+}
+
+// Fungsi untuk membaca satu baris mentah dari input; timeout 0 berarti menunggu tanpa batas
+// Setelah goroutine pembaca berjalan, semua prompt membaca lewat channel agar Scan tidak dipanggil bersamaan
+func scanInput(timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		startInputReader()
+	}
+	if inputLines == nil {
+		if !input.Scan() {
+			if err := input.Err(); err != nil {
+				return "", err
+			}
+			return "", errInputClosed
+		}
+		return input.Text(), nil
+	}
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case line := <-inputLines:
+		if !line.ok {
+			if inputReaderErr != nil {
+				return "", inputReaderErr
+			}
+			return "", errInputClosed
+		}
+		return line.text, nil
+	case <-expired:
+		return "", errInputIdle
+	}
+}
+
+// Memeriksa pengaturan pesanan yang ditinggalkan dan batas percobaan pembayaran
+func (c CashierConfig) validate() error {
+	if c.IdleMinutes < 0 {
+		return fmt.Errorf("cashier.idle_minutes tidak boleh negatif")
+	}
+	if c.PaymentAttempts < 0 {
+		return fmt.Errorf("cashier.payment_attempts tidak boleh negatif")
+	}
+	switch c.IdleAction {
+	case "", "cancel", "hold":
+		return nil
+	}
+	return fmt.Errorf("cashier.idle_action %q tidak dikenal (cancel atau hold)", c.IdleAction)
+}
+
+// Fungsi untuk menangani pesanan yang ditinggalkan di tengah input
+// Pesanan ditahan jika kasir mengatur idle_action "hold", selain itu dibatalkan; menu ditampilkan ulang untuk pelanggan berikutnya
+func abandonIdleOrder(restaurant *Restaurant, order *Order) {
+	minutes := int(restaurant.IdleTimeout.Minutes())
+	if restaurant.IdleHold && restaurant.HoldOrder != nil {
+		order.HoldLabel = "idle-" + time.Now().Format("150405")
+		err := restaurant.HoldOrder(*order)
+		if err == nil {
+			fmt.Print(tr("\nTidak ada aktivitas selama %d menit, pesanan ditahan sebagai %q. Ketik 'lanjut %s' untuk melanjutkan.\n", minutes, order.HoldLabel, order.HoldLabel))
+			restaurant.PrintMenu()
+			return
+		}
+		fmt.Println(tr("Pesanan tidak dapat ditahan:"), err)
+	}
+	openCart.Clear()
+	fmt.Print(tr("\nTidak ada aktivitas selama %d menit, pesanan dibatalkan (%d item, Rp%.2f).\n", minutes, len(order.Lines), order.Total))
+	restaurant.PrintMenu()
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct untuk Data penjualan historis
+// Rekap penjualan per item per hari yang diimpor dari spreadsheet lama
+type HistoricalRecord struct {
+	Date         time.Time `json:"date"`         // Tanggal penjualan
+	Item         string    `json:"item"`         // Nama item menu
+	Category     string    `json:"category"`     // Kategori item, boleh kosong
+	Qty          int       `json:"qty"`          // Jumlah terjual pada tanggal tersebut
+	Revenue      Money     `json:"revenue"`      // Pendapatan dari item pada tanggal tersebut
+	Transactions int       `json:"transactions"` // Jumlah transaksi, boleh 0 jika tidak dicatat
+	Source       string    `json:"source"`       // Nama file asal impor
+	ImportedAt   time.Time `json:"imported_at"`  // Waktu impor
+}
+
+// Format spreadsheet yang didukung untuk impor histori (file .csv atau .xlsx, sheet pertama):
+//
+//	tanggal    | item        | kategori | jumlah | pendapatan | transaksi
+//	2024-01-05 | Nasi Goreng | Makanan  | 12     | 300000     | 10
+//
+// Baris pertama wajib berisi judul kolom di atas (urutan bebas, huruf besar/kecil diabaikan).
+// Kolom "kategori" dan "transaksi" boleh tidak ada. Tanggal boleh berformat YYYY-MM-DD,
+// DD/MM/YYYY, atau tanggal Excel. Pendapatan boleh berformat "Rp25.000" atau "25000,50".
+// File CSV boleh memakai pemisah koma atau titik koma (format Excel Indonesia).
+var historyColumns = []string{"tanggal", "item", "kategori", "jumlah", "pendapatan", "transaksi"}
+
+// Kolom yang wajib ada pada spreadsheet histori
+var requiredHistoryColumns = []string{"tanggal", "item", "jumlah", "pendapatan"}
+
+// Ringkasan hasil impor histori
+type ImportSummary struct {
+	Imported int      // Jumlah baris yang berhasil diimpor
+	Replaced int      // Jumlah baris yang menggantikan data tanggal dan item yang sama
+	Errors   []string // Pesan error per baris yang dilewati
+}
+
+// Fungsi untuk mengimpor data penjualan historis dari file spreadsheet
+func importHistory(store *Store, path string) (ImportSummary, error) {
+	rows, err := readSpreadsheet(path)
+	if err != nil {
+		return ImportSummary{}, err
+	}
+	if len(rows) == 0 {
+		return ImportSummary{}, fmt.Errorf("File %s kosong", path)
+	}
+
+	index := map[string]int{}
+	for i, name := range rows[0] {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, col := range requiredHistoryColumns {
+		if _, ok := index[col]; !ok {
+			return ImportSummary{}, fmt.Errorf("Kolom %q tidak ditemukan, kolom yang didukung: %s", col, strings.Join(historyColumns, ", "))
+		}
+	}
+	cell := func(row []string, col string) string {
+		if i, ok := index[col]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var summary ImportSummary
+	var records []HistoricalRecord
+	seen := map[string]int{} // Baris pertama setiap tanggal dan item
+	now := time.Now()
+	for n, row := range rows[1:] {
+		line := n + 2 // Nomor baris di spreadsheet, termasuk judul kolom
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		date, err := parseSheetDate(cell(row, "tanggal"))
+		if err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("Baris %d: %v", line, err))
+			continue
+		}
+		qty, err := strconv.Atoi(cell(row, "jumlah"))
+		if err != nil || qty < 0 {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("Baris %d: jumlah tidak valid", line))
+			continue
+		}
+		revenue, err := parseSheetAmount(cell(row, "pendapatan"))
+		if err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("Baris %d: pendapatan tidak valid", line))
+			continue
+		}
+		item := cell(row, "item")
+		if item == "" {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("Baris %d: item kosong", line))
+			continue
+		}
+		// Kolom transaksi boleh kosong, tetapi isinya harus angka
+		var transactions int
+		if raw := cell(row, "transaksi"); raw != "" {
+			if transactions, err = strconv.Atoi(raw); err != nil || transactions < 0 {
+				summary.Errors = append(summary.Errors, fmt.Sprintf("Baris %d: transaksi tidak valid", line))
+				continue
+			}
+		}
+		record := HistoricalRecord{
+			Date:         date,
+			Item:         item,
+			Category:     cell(row, "kategori"),
+			Qty:          qty,
+			Revenue:      revenue,
+			Transactions: transactions,
+			Source:       filepath.Base(path),
+			ImportedAt:   now,
+		}
+		if first, ok := seen[record.key()]; ok {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("Baris %d: tanggal dan item sama dengan baris %d", line, first))
+			continue
+		}
+		seen[record.key()] = line
+		records = append(records, record)
+	}
+
+	replaced, err := store.ImportHistory(records)
+	if err != nil {
+		return summary, err
+	}
+	summary.Imported = len(records)
+	summary.Replaced = replaced
+	return summary, nil
+}
+
+// Kunci data histori: tanggal dan nama item tanpa membedakan huruf besar
+func (r HistoricalRecord) key() string {
+	return r.Date.Format("2006-01-02") + "|" + strings.ToLower(r.Item)
+}
+
+// Menyimpan data histori; data dengan tanggal dan item yang sama akan diganti
+// agar impor ulang file yang sama tidak menggandakan angka penjualan
+// Jika records sendiri berisi tanggal dan item yang sama, data terakhir yang dipakai
+func (s *Store) ImportHistory(records []HistoricalRecord) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	incoming := map[string]int{}
+	var unique []HistoricalRecord
+	for _, r := range records {
+		if i, ok := incoming[r.key()]; ok {
+			unique[i] = r
+			continue
+		}
+		incoming[r.key()] = len(unique)
+		unique = append(unique, r)
+	}
+	kept := s.data.History[:0]
+	replaced := 0
+	for _, r := range s.data.History {
+		if _, ok := incoming[r.key()]; ok {
+			replaced++
+			continue
+		}
+		kept = append(kept, r)
+	}
+	s.data.History = append(kept, unique...)
+	return replaced, s.save()
+}
+
+// Mengambil salinan seluruh data histori
+func (s *Store) History() []HistoricalRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]HistoricalRecord(nil), s.data.History...)
+}
+
+// Fungsi untuk membaca seluruh baris spreadsheet sesuai ekstensi file
+func readSpreadsheet(path string) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readCSVFile(path)
+	case ".xlsx":
+		return readXLSXFile(path)
+	default:
+		return nil, fmt.Errorf("Format file tidak didukung, gunakan .csv atau .xlsx")
+	}
+}
+
+// Fungsi untuk membaca file CSV dengan pemisah koma atau titik koma
+func readCSVFile(path string) ([][]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseCSV(raw)
+}
+
+// Fungsi untuk membaca isi CSV dengan pemisah koma atau titik koma
+func parseCSV(raw []byte) ([][]string, error) {
+	text := strings.TrimPrefix(string(raw), "\ufeff") // Excel sering menambahkan BOM
+	firstLine, _, _ := strings.Cut(text, "\n")
+
+	r := csv.NewReader(strings.NewReader(text))
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		r.Comma = ';'
+	}
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
+
+// Fungsi untuk membaca sheet pertama dari file XLSX
+// File XLSX adalah arsip zip berisi XML; hanya nilai sel yang dibaca
+func readXLSXFile(path string) ([][]string, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("Gagal membuka file XLSX: %w", err)
+	}
+	defer zr.Close()
+
+	var shared []string
+	if f := findZipFile(&zr.Reader, "xl/sharedStrings.xml"); f != nil {
+		var sst struct {
+			Items []struct {
+				Text string `xml:"t"`
+				Runs []struct {
+					Text string `xml:"t"`
+				} `xml:"r"`
+			} `xml:"si"`
+		}
+		if err := decodeZipXML(f, &sst); err != nil {
+			return nil, err
+		}
+		for _, si := range sst.Items {
+			text := si.Text
+			for _, run := range si.Runs {
+				text += run.Text
+			}
+			shared = append(shared, text)
+		}
+	}
+
+	sheet := findZipFile(&zr.Reader, "xl/worksheets/sheet1.xml")
+	if sheet == nil {
+		return nil, fmt.Errorf("Sheet pertama tidak ditemukan di file XLSX")
+	}
+	var ws struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeZipXML(sheet, &ws); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range ws.Rows {
+		var values []string
+		for _, c := range row.Cells {
+			col := xlsxColumnIndex(c.Ref)
+			for len(values) < col {
+				values = append(values, "")
+			}
+			value := c.Value
+			switch c.Type {
+			case "s":
+				if i, err := strconv.Atoi(c.Value); err == nil && i < len(shared) {
+					value = shared[i]
+				}
+			case "inlineStr":
+				value = c.Inline
+			}
+			values = append(values, value)
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// Mencari file di dalam arsip zip berdasarkan nama
+func findZipFile(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// Mendekode file XML di dalam arsip zip
+func decodeZipXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("Gagal membaca %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// Mengubah referensi sel seperti "C12" menjadi indeks kolom berbasis 0
+func xlsxColumnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
+
+// Fungsi untuk membaca tanggal dari sel spreadsheet
+func parseSheetDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	// Excel menyimpan tanggal sebagai jumlah hari sejak 30 Desember 1899
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.Local)
+		return base.AddDate(0, 0, int(serial)), nil
+	}
+	return time.Time{}, fmt.Errorf("format tanggal %q tidak dikenali", value)
+}
+
+// Regex untuk angka dengan titik sebagai pemisah ribuan, mis. "25.000"
+var thousandsPattern = regexp.MustCompile(`^[0-9]{1,3}(\.[0-9]{3})+$`)
+
+// Fungsi untuk membaca nominal rupiah dari sel spreadsheet
+// Mendukung format Indonesia (titik ribuan, koma desimal) sebelum divalidasi oleh validatePrice
+func parseSheetAmount(value string) (Money, error) {
+	value = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "Rp"))
+	value = strings.ReplaceAll(value, " ", "")
+	switch {
+	case strings.Contains(value, ","):
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	case thousandsPattern.MatchString(value):
+		value = strings.ReplaceAll(value, ".", "")
+	}
+	return validatePrice(value)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var errIntakeClosed = errors.New("Kasir sudah menutup pesanan, pesanan tidak diterima")
+
+// Struct untuk penerima pesanan dari beberapa sesi kasir ke satu loop penghitungan
+// Sesi (mis. input keyboard kasir) dijalankan dengan Go; pesanan sekali kirim (mis. tablet pelayan) lewat Submit.
+// Channel ditutup setelah sesi terakhir selesai dan semua kiriman yang sedang berjalan masuk ke channel,
+// sehingga loop penghitungan adalah satu-satunya pembaca dan tidak ada pengiriman ke channel yang sudah ditutup
+type orderIntake struct {
+	ch chan Order
+
+	mu       sync.Mutex
+	sessions int            // Sesi yang masih berjalan
+	closed   bool           // Tidak menerima pesanan baru
+	pending  sync.WaitGroup // Sesi dan kiriman yang belum selesai menulis ke channel
+	errs     []error        // Error dari sesi yang berhenti
+}
+
+// Fungsi untuk membuat penerima pesanan dengan kapasitas channel tertentu, 0 berarti tanpa buffer
+func newOrderIntake(size int) *orderIntake {
+	return &orderIntake{ch: make(chan Order, size)}
+}
+
+// Channel pesanan untuk loop penghitungan, ditutup setelah semua sesi selesai
+func (in *orderIntake) Orders() <-chan Order {
+	return in.ch
+}
+
+// Menjalankan satu sesi kasir di goroutine sendiri
+func (in *orderIntake) Go(session func(ch chan<- Order) error) {
+	in.mu.Lock()
+	in.sessions++
+	in.pending.Add(1)
+	in.mu.Unlock()
+	go func() {
+		err := session(in.ch)
+		in.mu.Lock()
+		if err != nil {
+			in.errs = append(in.errs, err)
+		}
+		in.sessions--
+		last := in.sessions == 0
+		if last {
+			in.closed = true
+		}
+		in.mu.Unlock()
+		in.pending.Done()
+		if last {
+			in.pending.Wait()
+			close(in.ch)
+		}
+	}()
+}
+
+// Mengirim satu pesanan lengkap ke loop penghitungan selama masih ada sesi yang berjalan
+func (in *orderIntake) Submit(order Order) error {
+	in.mu.Lock()
+	if in.closed {
+		in.mu.Unlock()
+		return errIntakeClosed
+	}
+	in.pending.Add(1)
+	in.mu.Unlock()
+	defer in.pending.Done()
+	in.ch <- order
+	return nil
+}
+
+// Error dari sesi yang berhenti, dibaca setelah channel ditutup
+func (in *orderIntake) Err() error {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return errors.Join(in.errs...)
+}
+
+// Fungsi untuk menerima pesanan dari tablet pelayan atau klien API lain selama kasir berjalan
+// POST /orders dengan body seperti "order take" masuk ke keranjang kasir yang sama; butuh API key dengan scope terminal
+func serveOrderIntake(addr string, keys []APIKey, restaurant *Restaurant, intake *orderIntake) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /orders", func(w http.ResponseWriter, r *http.Request) {
+		key, ok := findAPIKey(keys, r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "API key tidak valid")
+			return
+		}
+		if !key.HasScope(scopeTerminal) {
+			writeError(w, http.StatusForbidden, "API key tidak memiliki scope "+scopeTerminal)
+			return
+		}
+		var req OrderRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Body harus berisi pesanan JSON")
+			return
+		}
+		order, err := buildOrder(restaurant, req)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if err := intake.Submit(order); err != nil {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]string{"id": order.ID})
+	})
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Println("Penerima pesanan tambahan berhenti:", err)
+		}
+	}()
+	fmt.Println(tr("Menerima pesanan tambahan di"), addr)
+	return srv
+}
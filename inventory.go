@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var errIngredientNotFound = errors.New("Bahan tidak ditemukan")
+
+// Struct untuk Bahan di persediaan dapur
+type Ingredient struct {
+	Name  string  `json:"name"`  // Nama bahan, mis. "Nasi"
+	Unit  string  `json:"unit"`  // Satuan stok, mis. "g" atau "butir"
+	Stock float64 `json:"stock"` // Sisa stok dalam satuan di atas
+
+	Min float64 `json:"min,omitempty"` // Batas stok minimal, kasir diperingatkan jika stok di bawahnya
+	Par float64 `json:"par,omitempty"` // Stok ideal setelah pembelian, dipakai untuk saran pembelian
+}
+
+// Struct untuk satu bahan di resep item menu
+type RecipeIngredient struct {
+	Ingredient string  `json:"ingredient"` // Nama bahan di persediaan
+	Qty        float64 `json:"qty"`        // Jumlah yang dipakai untuk satu porsi
+}
+
+// Mengatur resep item menu
+func (r *Restaurant) SetRecipe(name string, recipe ...RecipeIngredient) {
+	if item := r.findMenuItem(name); item != nil {
+		item.Recipe = recipe
+	}
+}
+
+// Mencari pointer bahan berdasarkan nama; pemanggil harus memegang s.mu
+func (s *Store) findIngredient(name string) *Ingredient {
+	for i := range s.data.Ingredients {
+		if strings.EqualFold(s.data.Ingredients[i].Name, name) {
+			return &s.data.Ingredients[i]
+		}
+	}
+	return nil
+}
+
+// Mengambil salinan seluruh bahan di persediaan
+func (s *Store) Ingredients() []Ingredient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Ingredient(nil), s.data.Ingredients...)
+}
+
+// Mengatur stok bahan; bahan yang belum ada ditambahkan ke persediaan
+// Satuan kosong mempertahankan satuan yang sudah tercatat
+func (s *Store) SetIngredientStock(name, unit string, stock float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ing := s.findIngredient(name); ing != nil {
+		ing.Stock = stock
+		if unit != "" {
+			ing.Unit = unit
+		}
+		return s.save()
+	}
+	s.data.Ingredients = append(s.data.Ingredients, Ingredient{Name: name, Unit: unit, Stock: stock})
+	return s.save()
+}
+
+// Mengatur batas stok minimal dan stok ideal bahan; nilai negatif mempertahankan nilai yang sudah tercatat
+func (s *Store) SetIngredientLevels(name string, minStock, par float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ing := s.findIngredient(name)
+	if ing == nil {
+		return errIngredientNotFound
+	}
+	if minStock >= 0 {
+		ing.Min = minStock
+	}
+	if par >= 0 {
+		ing.Par = par
+	}
+	return s.save()
+}
+
+// Menambah stok bahan yang sudah ada, mis. saat barang datang dari pemasok
+func (s *Store) AddIngredientStock(name string, qty float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ing := s.findIngredient(name)
+	if ing == nil {
+		return errIngredientNotFound
+	}
+	ing.Stock += qty
+	return s.save()
+}
+
+// Mengurangi stok bahan sesuai resep setiap baris pesanan; pemanggil harus memegang s.mu
+// Bahan yang tidak tercatat di persediaan tidak dilacak
+// Stok boleh minus karena pembayaran sudah diterima; item tersebut langsung tampil habis di menu
+func (s *Store) consumeIngredients(order Order) {
+	for _, line := range order.Lines {
+		for _, r := range line.Item.Recipe {
+			if ing := s.findIngredient(r.Ingredient); ing != nil {
+				ing.Stock -= r.Qty * float64(line.Qty)
+			}
+		}
+	}
+}
+
+// Menandai item menu yang bahannya tidak cukup untuk satu porsi sebagai habis; pemanggil harus memegang s.mu
+func (s *Store) markUnavailable(restaurant *Restaurant) {
+	for i := range restaurant.Menu {
+		item := &restaurant.Menu[i]
+		for _, r := range item.Recipe {
+			if ing := s.findIngredient(r.Ingredient); ing != nil && ing.Stock < r.Qty {
+				item.SoldOut = true
+				break
+			}
+		}
+	}
+}
+
+// Fungsi untuk menampilkan persediaan bahan beserta item menu yang memakainya
+func printIngredients(store *Store) {
+	ingredients := store.Ingredients()
+	if len(ingredients) == 0 {
+		fmt.Println("Belum ada bahan di persediaan.")
+		return
+	}
+	menu := store.Menu().Menu
+	for _, ing := range ingredients {
+		var usedBy []string
+		for _, item := range menu {
+			if slices.ContainsFunc(item.Recipe, func(r RecipeIngredient) bool { return strings.EqualFold(r.Ingredient, ing.Name) }) {
+				usedBy = append(usedBy, item.Name)
+			}
+		}
+		status := ""
+		switch {
+		case ing.Stock <= 0:
+			status = " (HABIS)"
+		case ing.Stock < ing.Min:
+			status = " (MENIPIS)"
+		}
+		fmt.Printf("%-16s %10.2f %-6s%s  %s\n", ing.Name, ing.Stock, ing.Unit, status, strings.Join(usedBy, ", "))
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "stock"
+func runStockCommand(store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: stock list|set|add")
+	}
+	switch args[0] {
+	case "list":
+	case "set", "add":
+		fs := flag.NewFlagSet("stock "+args[0], flag.ContinueOnError)
+		unit := fs.String("unit", "", "satuan stok, mis. g atau butir")
+		minStock := fs.Float64("min", -1, "batas stok minimal untuk peringatan, hanya untuk stock set")
+		par := fs.Float64("par", -1, "stok ideal setelah pembelian, hanya untuk stock set")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("Gunakan: stock %s [-unit satuan] [-min n] [-par n] <bahan> <jumlah>", args[0])
+		}
+		qty, err := strconv.ParseFloat(fs.Arg(1), 64)
+		if err != nil {
+			return fmt.Errorf("Jumlah stok tidak valid: %s", fs.Arg(1))
+		}
+		if args[0] == "set" {
+			err = store.SetIngredientStock(fs.Arg(0), *unit, qty)
+			if err == nil && (*minStock >= 0 || *par >= 0) {
+				err = store.SetIngredientLevels(fs.Arg(0), *minStock, *par)
+			}
+		} else {
+			err = store.AddIngredientStock(fs.Arg(0), qty)
+		}
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Sub-perintah stock tidak dikenal: %s", args[0])
+	}
+	printIngredients(store)
+	return nil
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Jenis kejadian di jurnal
+const (
+	EventOrderSaved     = "order.saved"     // Data: Order
+	EventPaymentSaved   = "payment.saved"   // Data: Payment
+	EventRefundSaved    = "refund.saved"    // Data: Refund
+	EventOrderServed    = "order.served"    // Data: eventOrderRef
+	EventOrderCancelled = "order.cancelled" // Data: eventOrderRef
+	EventOrderReady     = "order.ready"     // Data: eventOrderRef
+	EventOrderBumped    = "order.bumped"    // Data: eventOrderRef
+	EventOrderRevised   = "order.revised"   // Data: eventOrderRef
+)
+
+// Struct untuk satu kejadian di jurnal
+// Jurnal hanya ditambah, tidak pernah diubah, sehingga satu hari bisa diputar ulang persis seperti aslinya
+type Event struct {
+	Seq  int64           `json:"seq"`  // Nomor urut kejadian, naik terus sejak jurnal dibuat
+	Time time.Time       `json:"time"` // Waktu kejadian dicatat
+	Type string          `json:"type"` // Jenis kejadian
+	Data json.RawMessage `json:"data"` // Isi kejadian sesuai jenisnya
+}
+
+// Isi kejadian yang hanya merujuk ke pesanan
+type eventOrderRef struct {
+	OrderID string `json:"order_id"`
+}
+
+// Fungsi untuk mendapatkan lokasi jurnal dari lokasi file data, mis. "data.json" -> "data-journal.jsonl"
+func journalPath(dataPath string) string {
+	return strings.TrimSuffix(dataPath, filepath.Ext(dataPath)) + "-journal.jsonl"
+}
+
+// Menambahkan kejadian ke jurnal lalu meneruskannya ke subscriber; pemanggil harus memegang s.mu
+// Penyimpanan di memori (tanpa path) tidak memiliki jurnal
+func (s *Store) record(eventType string, data any) error {
+	if s.path != "" {
+		if err := s.appendJournal(eventType, data); err != nil {
+			return err
+		}
+	}
+	// Subscriber hanya menerima kejadian yang berhasil dicatat
+	s.publish(eventType, data)
+	return nil
+}
+
+// Menulis satu kejadian ke akhir file jurnal; pemanggil harus memegang s.mu
+func (s *Store) appendJournal(eventType string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	s.data.JournalSeq++
+	line, err := json.Marshal(Event{Seq: s.data.JournalSeq, Time: time.Now(), Type: eventType, Data: raw})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(journalPath(s.path), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("Gagal menulis jurnal: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("Gagal menulis jurnal: %w", err)
+	}
+	return f.Close()
+}
+
+// Fungsi untuk membuat penyimpanan kosong di memori, tidak pernah ditulis ke disk
+func newMemoryStore() *Store {
+	s := &Store{}
+	s.subscribeProjections()
+	return s
+}
+
+// Fungsi untuk membaca seluruh kejadian dari jurnal
+func readJournal(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("Jurnal baris %d tidak valid: %w", line, err)
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// Menerapkan kejadian ke penyimpanan
+// Mengembalikan ID objek yang disentuh untuk ditampilkan saat replay
+func (e Event) apply(s *Store) (string, error) {
+	switch e.Type {
+	case EventOrderSaved:
+		var o Order
+		if err := json.Unmarshal(e.Data, &o); err != nil {
+			return "", err
+		}
+		return o.ID, s.SaveOrder(o)
+	case EventPaymentSaved:
+		var p Payment
+		if err := json.Unmarshal(e.Data, &p); err != nil {
+			return "", err
+		}
+		return p.ID, s.SavePayment(p)
+	case EventRefundSaved:
+		var r Refund
+		if err := json.Unmarshal(e.Data, &r); err != nil {
+			return "", err
+		}
+		return r.ID, s.SaveRefund(r)
+	case EventOrderServed, EventOrderCancelled, EventOrderReady, EventOrderBumped, EventOrderRevised:
+		var ref eventOrderRef
+		if err := json.Unmarshal(e.Data, &ref); err != nil {
+			return "", err
+		}
+		switch e.Type {
+		case EventOrderServed:
+			return ref.OrderID, s.ServeOrder(ref.OrderID)
+		case EventOrderReady:
+			return ref.OrderID, s.MarkReady(ref.OrderID)
+		case EventOrderBumped:
+			return ref.OrderID, s.BumpOrder(ref.OrderID)
+		case EventOrderRevised:
+			_, err := s.ReviseOrder(ref.OrderID)
+			return ref.OrderID, err
+		}
+		return ref.OrderID, s.CancelOrder(ref.OrderID)
+	}
+	return "", fmt.Errorf("Jenis kejadian tidak dikenal: %s", e.Type)
+}
+
+// Fungsi untuk memeriksa kejanggalan total setelah kejadian diterapkan
+// Total pesanan dihitung ulang dari baris dan rincian pajaknya, lalu total pembayaran dibandingkan dengan pesanan yang dibayar
+func checkEventTotals(s *Store, e Event) []string {
+	var warnings []string
+	switch e.Type {
+	case EventOrderSaved:
+		var o Order
+		json.Unmarshal(e.Data, &o)
+		var subtotal Money
+		for _, l := range o.Lines {
+			subtotal += l.Subtotal()
+		}
+		if o.Subtotal != 0 && subtotal != o.Subtotal {
+			warnings = append(warnings, fmt.Sprintf("subtotal pesanan Rp%.2f, dihitung dari baris Rp%.2f", o.Subtotal, subtotal))
+		}
+		if total := o.ExpectedTotal(); total != o.Total {
+			warnings = append(warnings, fmt.Sprintf("total pesanan Rp%.2f, dihitung dari rincian Rp%.2f", o.Total, total))
+		}
+	case EventPaymentSaved:
+		var p Payment
+		json.Unmarshal(e.Data, &p)
+		var total Money
+		for _, id := range p.OrderIDs {
+			o, err := s.Order(id)
+			if err != nil {
+				warnings = append(warnings, "pesanan "+id+" tidak ada di jurnal")
+				continue
+			}
+			total += o.Total
+		}
+		total += p.Rounding - p.Deposit - p.GiftVoucher
+		if total != p.Amount {
+			warnings = append(warnings, fmt.Sprintf("pembayaran Rp%.2f, total pesanan Rp%.2f", p.Amount, total))
+		}
+		if p.PaymentMethod() == MethodCash && p.Tendered-p.Due() != p.Change {
+			warnings = append(warnings, fmt.Sprintf("dibayar Rp%.2f - total Rp%.2f tidak sama dengan kembalian Rp%.2f", p.Tendered, p.Due(), p.Change))
+		}
+	}
+	return warnings
+}
+
+// Fungsi untuk memutar ulang kejadian satu hari ke penyimpanan baru di memori
+// Kejadian sebelum hari tersebut diterapkan diam-diam agar refund atas pesanan lama tetap bisa diputar
+// Rekap historis hasil impor tidak tercatat di jurnal, sehingga disalin apa adanya
+// until lebih dari 0 berarti replay berhenti setelah kejadian dengan nomor urut tersebut
+func replayDay(w io.Writer, events []Event, history []HistoricalRecord, date time.Time, until int64, verbose bool) (*Store, bool) {
+	state := newMemoryStore()
+	state.data.History = history
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	replayed := 0
+	for _, e := range events {
+		if until > 0 && e.Seq > until {
+			return state, true
+		}
+		t := e.Time.Local()
+		if t.After(endOfDay(date)) {
+			break
+		}
+		id, err := e.apply(state)
+		if t.Before(start) {
+			continue
+		}
+		replayed++
+		warnings := checkEventTotals(state, e)
+		if err != nil {
+			warnings = append(warnings, "gagal diterapkan: "+err.Error())
+		}
+		if verbose || len(warnings) > 0 {
+			report := buildDailyReport(state, date)
+			fmt.Fprintf(w, "#%-5d %s  %-16s %-22s kotor Rp%.2f  bersih Rp%.2f\n", e.Seq, t.Format("15:04:05"), e.Type, id, report.GrossSales, report.NetSales)
+		}
+		for _, warning := range warnings {
+			fmt.Fprintln(w, "       ! "+warning)
+		}
+	}
+	if replayed == 0 {
+		fmt.Fprintln(w, "Tidak ada kejadian di jurnal untuk tanggal ini.")
+	}
+	return state, false
+}
+
+// Fungsi untuk membandingkan laporan harian hasil replay dengan data tersimpan
+func compareDailyReports(w io.Writer, replayed, stored DailyReport) {
+	fields := []struct {
+		name           string
+		replay, stored float64
+	}{
+		{"Pesanan dibayar", float64(replayed.Orders), float64(stored.Orders)},
+		{"Penjualan kotor", replayed.GrossSales.Float(), stored.GrossSales.Float()},
+		{"Pesanan dibatalkan", float64(replayed.Cancelled), float64(stored.Cancelled)},
+		{"Refund", replayed.RefundedAmount.Float(), stored.RefundedAmount.Float()},
+		{"Penjualan bersih", replayed.NetSales.Float(), stored.NetSales.Float()},
+	}
+	mismatch := false
+	for _, f := range fields {
+		if math.Abs(f.replay-f.stored) > 0.01 {
+			mismatch = true
+			fmt.Fprintf(w, "SELISIH %-19s replay %.2f, data tersimpan %.2f\n", f.name+":", f.replay, f.stored)
+		}
+	}
+	if !mismatch {
+		fmt.Fprintln(w, "Hasil replay cocok dengan data tersimpan.")
+	}
+}
+
+// Fungsi untuk menjalankan perintah "replay"
+func runReplayCommand(store *Store, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	dateFlag := fs.String("date", time.Now().Format("2006-01-02"), "tanggal yang diputar ulang")
+	until := fs.Int64("until", 0, "berhenti setelah kejadian dengan nomor urut ini")
+	verbose := fs.Bool("v", false, "tampilkan setiap kejadian, bukan hanya yang janggal")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	date, err := time.ParseInLocation("2006-01-02", *dateFlag, time.Local)
+	if err != nil {
+		return fmt.Errorf("Format tanggal harus YYYY-MM-DD")
+	}
+	events, err := readJournal(journalPath(store.path))
+	if err != nil {
+		return err
+	}
+	state, stopped := replayDay(os.Stdout, events, store.History(), date, *until, *verbose || *until > 0)
+	fmt.Println()
+	replayed := buildDailyReport(state, date)
+	replayed.Print(os.Stdout)
+	if stopped {
+		fmt.Printf("Replay dihentikan setelah kejadian #%d.\n", *until)
+		return nil
+	}
+	compareDailyReports(os.Stdout, replayed, buildDailyReport(store, date))
+	return nil
+}
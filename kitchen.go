@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	errKitchenClosed  = errors.New("Dapur sudah berhenti menerima pesanan")
+	errKitchenStalled = errors.New("Antrean dapur penuh dan tidak bergerak")
+)
+
+// Struct untuk Dapur
+// Sekumpulan worker memproses pesanan dari channel; Drain menunggu semua pesanan di channel selesai
+// Pesanan berprioritas masuk ke channel rush yang selalu diambil worker lebih dulu
+type kitchen struct {
+	orders   chan Order
+	rush     chan Order
+	prepTime time.Duration
+	onReady  func(Order)     // Dipanggil setelah pesanan selesai diproses, boleh nil
+	done     []chan struct{} // Ditutup oleh masing-masing worker saat berhenti
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Fungsi untuk menjalankan dapur dengan sejumlah worker
+func startKitchen(cfg KitchenConfig, onReady func(Order)) *kitchen {
+	k := &kitchen{
+		orders:   make(chan Order, cfg.QueueSize),
+		rush:     make(chan Order, cfg.QueueSize),
+		prepTime: time.Duration(cfg.PrepSeconds) * time.Second,
+		onReady:  onReady,
+	}
+	for i := 1; i <= cfg.Workers; i++ {
+		done := make(chan struct{})
+		k.done = append(k.done, done)
+		go k.work(i, done)
+	}
+	return k
+}
+
+// Worker dapur; berhenti setelah channel ditutup dan kosong
+func (k *kitchen) work(id int, done chan<- struct{}) {
+	defer close(done)
+	for {
+		order, ok := k.next()
+		if !ok {
+			return
+		}
+		op := activeWatchdog.Begin(watchKitchen, fmt.Sprintf("worker %d pesanan %s", id, order.ID))
+		fmt.Printf("Dapur %d: memproses pesanan %s...\n", id, order.ID)
+		time.Sleep(k.prepTime) // Simulasi pemrosesan
+		fmt.Printf("Dapur %d: pesanan %s siap\n", id, order.ID)
+		if k.onReady != nil {
+			k.onReady(order)
+		}
+		op.End()
+	}
+}
+
+// Mengambil pesanan berikutnya, pesanan berprioritas lebih dulu
+// Kedua channel ditutup bersamaan oleh Drain, sehingga false berarti keduanya sudah kosong
+func (k *kitchen) next() (Order, bool) {
+	select {
+	case order, ok := <-k.rush:
+		if ok {
+			return order, true
+		}
+		order, ok = <-k.orders
+		return order, ok
+	default:
+	}
+	select {
+	case order, ok := <-k.rush:
+		if ok {
+			return order, true
+		}
+		order, ok = <-k.orders
+		return order, ok
+	case order, ok := <-k.orders:
+		if ok {
+			return order, true
+		}
+		order, ok = <-k.rush
+		return order, ok
+	}
+}
+
+// Fungsi untuk menandai pesanan siap di penyimpanan setelah dapur selesai, dipakai sebagai onReady
+func markReady(store *Store) func(Order) {
+	return func(order Order) {
+		if err := store.MarkReady(order.ID); err != nil {
+			fmt.Println("Gagal menandai pesanan", order.ID, "siap:", err)
+		}
+	}
+}
+
+// Mengirim pesanan ke dapur
+// Channel ditutup hanya di bawah mu sehingga pengiriman tidak pernah ke channel yang sudah ditutup
+// Dapur nil (mode terminal, pesanan diproses di server pusat) mengabaikan pesanan
+// Jika antrean penuh dan worker macet, pengiriman dihentikan watchdog agar kasir tidak ikut membeku
+func (k *kitchen) Submit(order Order) error {
+	if k == nil {
+		return nil
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.closed {
+		return errKitchenClosed
+	}
+	op := activeWatchdog.Begin(watchKitchen, "antrean pesanan "+order.ID)
+	defer op.End()
+	queue := k.orders
+	if order.Priority != PriorityNormal {
+		queue = k.rush
+	}
+	select {
+	case queue <- order:
+		return nil
+	case <-op.Expired():
+		op.Report()
+		return errKitchenStalled
+	}
+}
+
+// Berhenti menerima pesanan baru lalu menunggu worker menyelesaikan isi channel
+func (k *kitchen) Drain() {
+	if k == nil {
+		return
+	}
+	k.mu.Lock()
+	if !k.closed {
+		k.closed = true
+		close(k.orders)
+		close(k.rush)
+	}
+	k.mu.Unlock()
+	for _, done := range k.done {
+		<-done
+	}
+}
+
+// Struct untuk Keranjang yang belum dibayar
+// Dipakai saat shutdown agar pesanan yang sedang diinput disimpan sebagai draf
+type cartTracker struct {
+	mu     sync.Mutex
+	orders []Order
+}
+
+// Keranjang kasir yang sedang berjalan
+var openCart cartTracker
+
+// Mencatat isi terbaru pesanan yang belum dibayar
+func (c *cartTracker) Update(order Order) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if i := slices.IndexFunc(c.orders, func(o Order) bool { return o.ID == order.ID }); i >= 0 {
+		c.orders[i] = order
+		return
+	}
+	c.orders = append(c.orders, order)
+}
+
+// Mengosongkan keranjang setelah pesanan dibayar atau disimpan sebagai draf
+func (c *cartTracker) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.orders = nil
+}
+
+// Mengambil pesanan di keranjang yang berisi item
+func (c *cartTracker) Snapshot() []Order {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var orders []Order
+	for _, o := range c.orders {
+		if len(o.Lines) > 0 {
+			orders = append(orders, o)
+		}
+	}
+	return orders
+}
+
+// Menyimpan pesanan yang belum selesai sebagai draf
+// Draf dengan ID yang sama diperbarui, bukan digandakan
+func (s *Store) SaveDrafts(orders []Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, order := range orders {
+		if s.holdLabelTaken(order) {
+			return errHoldLabelTaken
+		}
+	}
+	for _, order := range orders {
+		if i := slices.IndexFunc(s.data.Drafts, func(o Order) bool { return o.ID == order.ID }); i >= 0 {
+			s.data.Drafts[i] = order
+			continue
+		}
+		s.data.Drafts = append(s.data.Drafts, order)
+	}
+	return s.save()
+}
+
+// Mengambil salinan seluruh draf pesanan
+func (s *Store) Drafts() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Order(nil), s.data.Drafts...)
+}
+
+// Menghapus draf yang sudah dibayar
+func (s *Store) RemoveDrafts(ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.data.Drafts)
+	s.data.Drafts = slices.DeleteFunc(s.data.Drafts, func(o Order) bool { return slices.Contains(ids, o.ID) })
+	if len(s.data.Drafts) == n {
+		return nil
+	}
+	return s.save()
+}
+
+// Fungsi untuk menyimpan keranjang yang belum dibayar sebagai draf
+func saveOpenCart(backend CashierBackend) {
+	drafts := openCart.Snapshot()
+	if len(drafts) == 0 {
+		return
+	}
+	if err := backend.SaveDrafts(drafts); err != nil {
+		fmt.Println("Gagal menyimpan draf pesanan:", err)
+		return
+	}
+	openCart.Clear()
+	for _, o := range drafts {
+		fmt.Println("Pesanan belum dibayar disimpan sebagai draf:", o.ID)
+	}
+}
+
+// Fungsi untuk menunggu sinyal berhenti (Ctrl-C atau SIGTERM)
+// Penerimaan sinyal dihentikan dengan signal.Stop
+func stopSignal() chan os.Signal {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	return ch
+}
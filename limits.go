@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errOrderLimit = errors.New("Pesanan melebihi batas")
+
+// Struct untuk Batas ukuran pesanan untuk mencegah salah ketik seperti jumlah 1000
+// Berbeda dengan aturan max_qty di order_rules, batas ini tidak dapat di-override manajer
+type OrderLimits struct {
+	MaxItemQty int            `json:"max_item_qty"`           // Jumlah maksimal satu item dalam satu pesanan, 0 berarti tanpa batas
+	MaxItems   int            `json:"max_items"`              // Jumlah seluruh item dalam satu pesanan, 0 berarti tanpa batas
+	ItemMaxQty map[string]int `json:"item_max_qty,omitempty"` // Batas per nama item yang menggantikan max_item_qty, mis. {"Es Teh": 50}
+}
+
+// Jumlah maksimal satu item, 0 berarti tanpa batas
+func (l OrderLimits) maxQty(item string) int {
+	for name, n := range l.ItemMaxQty {
+		if strings.EqualFold(name, item) {
+			return n
+		}
+	}
+	return l.MaxItemQty
+}
+
+// Memeriksa pesanan terhadap batas ukuran pesanan
+// Jumlah item yang sama di beberapa baris (mis. beda varian) dijumlahkan
+func (l OrderLimits) Check(order Order) error {
+	qty := map[string]int{}
+	var names []string
+	total := 0
+	for _, line := range order.Lines {
+		if _, seen := qty[line.Item.Name]; !seen {
+			names = append(names, line.Item.Name)
+		}
+		qty[line.Item.Name] += line.Qty
+		total += line.Qty
+	}
+	for _, name := range names {
+		if n := l.maxQty(name); n > 0 && qty[name] > n {
+			return fmt.Errorf("%w: %s", errOrderLimit, tr("jumlah %s maksimal %d per pesanan (diminta %d)", name, n, qty[name]))
+		}
+	}
+	if l.MaxItems > 0 && total > l.MaxItems {
+		return fmt.Errorf("%w: %s", errOrderLimit, tr("maksimal %d item per pesanan (diminta %d)", l.MaxItems, total))
+	}
+	return nil
+}
+
+// Memeriksa batas pesanan jika satu baris ditambahkan
+func (l OrderLimits) CheckLine(order Order, line OrderLine) error {
+	order.Lines = append(order.Lines[:len(order.Lines):len(order.Lines)], line)
+	return l.Check(order)
+}
+
+// Fungsi untuk memeriksa batas pesanan di konfigurasi
+func (l OrderLimits) validate() error {
+	if l.MaxItemQty < 0 || l.MaxItems < 0 {
+		return fmt.Errorf("order_limits: batas tidak boleh negatif")
+	}
+	for name, n := range l.ItemMaxQty {
+		if n < 1 {
+			return fmt.Errorf("order_limits.item_max_qty[%q]: batas harus lebih dari 0", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Format sumber "menu sync": JSON berisi daftar item menu (sama dengan file "menu publish"),
+// atau CSV, mis. Google Sheets yang dipublikasikan sebagai CSV:
+//
+//	item        | kategori | harga  | habis | kode
+//	Nasi Goreng | Makanan  | 25.000 | tidak | 8991001
+//
+// Kolom "item" dan "harga" wajib ada; kolom "kategori", "habis", dan "kode" (barcode/PLU) boleh tidak ada.
+// Judul kolom dari "menu export" dan padanan bahasa Inggris (name, category, price, sold_out, code) juga diterima.
+var menuFeedColumns = map[string]string{
+	"item": "item", "nama": "item", "name": "item",
+	"harga": "harga", "price": "harga",
+	"kategori": "kategori", "category": "kategori",
+	"habis": "habis", "sold_out": "habis",
+	"kode": "kode", "code": "kode", "plu": "kode", "barcode": "kode",
+}
+
+// Struct untuk satu baris menu dari sumber sinkronisasi
+type menuFeedItem struct {
+	Name     string
+	Price    Money
+	Category string
+	SoldOut  *bool   // nil jika sumber tidak memiliki kolom habis
+	Code     *string // nil jika sumber tidak memiliki kolom kode
+}
+
+// Struct untuk perbedaan menu sumber dengan menu yang berlaku
+type MenuDiff struct {
+	Added   []MenuItem
+	Updated []string // Keterangan perubahan per item, mis. "Es Teh: harga Rp5000 -> Rp6000"
+	Removed []MenuItem
+	Menu    []MenuItem // Menu baru setelah perubahan diterapkan
+}
+
+// Menandakan tidak ada perubahan
+func (d MenuDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Updated) == 0 && len(d.Removed) == 0
+}
+
+// Fungsi untuk mengambil isi sumber menu dari URL
+func fetchMenuFeed(url string) ([]byte, string, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, "", fmt.Errorf("Sumber menu tidak dapat dihubungi: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("Sumber menu membalas %s", resp.Status)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	return raw, resp.Header.Get("Content-Type"), err
+}
+
+// Fungsi untuk membaca sumber menu JSON atau CSV
+// Seluruh baris yang tidak valid dikumpulkan agar bisa diperbaiki sekaligus di sheet
+func parseMenuFeed(raw []byte, contentType string) ([]menuFeedItem, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(raw, []byte("\ufeff")))
+	if strings.Contains(contentType, "json") || bytes.HasPrefix(trimmed, []byte("[")) {
+		var menu []MenuItem
+		if err := json.Unmarshal(trimmed, &menu); err != nil {
+			return nil, fmt.Errorf("Format JSON menu tidak valid: %w", err)
+		}
+		var items []menuFeedItem
+		var errs []error
+		for i, m := range menu {
+			// Harga dari JSON sudah dibaca ke sen, sehingga hanya tanda yang perlu diperiksa
+			price := m.Price
+			if price < 0 {
+				errs = append(errs, fmt.Errorf("Item %d: harga %s tidak valid", i+1, m.Name))
+				continue
+			}
+			soldOut, code := m.SoldOut, m.Code
+			items = append(items, menuFeedItem{Name: strings.TrimSpace(m.Name), Price: price, Category: m.Category, SoldOut: &soldOut, Code: &code})
+		}
+		return items, errors.Join(errs...)
+	}
+
+	rows, err := parseCSV(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Format CSV menu tidak valid: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("Sumber menu kosong")
+	}
+	index := map[string]int{}
+	for i, name := range rows[0] {
+		if col, ok := menuFeedColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			index[col] = i
+		}
+	}
+	for _, col := range []string{"item", "harga"} {
+		if _, ok := index[col]; !ok {
+			return nil, fmt.Errorf("Kolom %q tidak ditemukan di sumber menu", col)
+		}
+	}
+	cell := func(row []string, col string) string {
+		if i, ok := index[col]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	var items []menuFeedItem
+	var errs []error
+	for n, row := range rows[1:] {
+		name := cell(row, "item")
+		if name == "" {
+			continue // Baris kosong di akhir sheet
+		}
+		price, err := parseSheetAmount(cell(row, "harga"))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Baris %d (%s): harga %q tidak valid", n+2, name, cell(row, "harga")))
+			continue
+		}
+		item := menuFeedItem{Name: name, Price: price, Category: cell(row, "kategori")}
+		if _, ok := index["habis"]; ok {
+			soldOut := slices.Contains([]string{"ya", "y", "yes", "true", "1"}, strings.ToLower(cell(row, "habis")))
+			item.SoldOut = &soldOut
+		}
+		if _, ok := index["kode"]; ok {
+			code := cell(row, "kode")
+			item.Code = &code
+		}
+		items = append(items, item)
+	}
+	return items, errors.Join(errs...)
+}
+
+// Fungsi untuk membandingkan menu sumber dengan menu yang berlaku
+// Item yang sudah ada hanya diubah harga, kategori, status habis, dan kodenya; varian, resep, dan alergen tetap
+func diffMenu(current []MenuItem, feed []menuFeedItem) MenuDiff {
+	var diff MenuDiff
+	seen := map[string]bool{}
+	for _, f := range feed {
+		seen[strings.ToLower(f.Name)] = true
+	}
+	for _, item := range current {
+		if !seen[strings.ToLower(item.Name)] {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+	for _, f := range feed {
+		i := slices.IndexFunc(current, func(m MenuItem) bool { return strings.EqualFold(m.Name, f.Name) })
+		if i < 0 {
+			item := MenuItem{Name: f.Name, Price: f.Price, Category: f.Category}
+			if f.SoldOut != nil {
+				item.SoldOut = *f.SoldOut
+			}
+			if f.Code != nil {
+				item.Code = *f.Code
+			}
+			diff.Added = append(diff.Added, item)
+			diff.Menu = append(diff.Menu, item)
+			continue
+		}
+		item := current[i]
+		var changes []string
+		if item.Price != f.Price {
+			changes = append(changes, fmt.Sprintf("harga Rp%.0f -> Rp%.0f", item.Price, f.Price))
+			item.Price = f.Price
+		}
+		if f.Category != "" && item.Category != f.Category {
+			changes = append(changes, fmt.Sprintf("kategori %q -> %q", item.Category, f.Category))
+			item.Category = f.Category
+		}
+		if f.SoldOut != nil && item.SoldOut != *f.SoldOut {
+			changes = append(changes, fmt.Sprintf("habis %t -> %t", item.SoldOut, *f.SoldOut))
+			item.SoldOut = *f.SoldOut
+		}
+		if f.Code != nil && item.Code != *f.Code {
+			changes = append(changes, fmt.Sprintf("kode %q -> %q", item.Code, *f.Code))
+			item.Code = *f.Code
+		}
+		if len(changes) > 0 {
+			diff.Updated = append(diff.Updated, item.Name+": "+strings.Join(changes, ", "))
+		}
+		diff.Menu = append(diff.Menu, item)
+	}
+	return diff
+}
+
+// Menampilkan ringkasan perubahan menu
+func (d MenuDiff) Print(w io.Writer) {
+	if d.Empty() {
+		fmt.Fprintln(w, "Menu sudah sama dengan sumber, tidak ada perubahan.")
+		return
+	}
+	for _, item := range d.Added {
+		fmt.Fprintf(w, "+ %s (%s) Rp%.0f\n", item.Name, item.Category, item.Price)
+	}
+	for _, u := range d.Updated {
+		fmt.Fprintln(w, "~", u)
+	}
+	for _, item := range d.Removed {
+		fmt.Fprintf(w, "- %s\n", item.Name)
+	}
+	fmt.Fprintf(w, "%d ditambahkan, %d diubah, %d dihapus\n", len(d.Added), len(d.Updated), len(d.Removed))
+}
+
+// Fungsi untuk menjalankan "menu sync"
+func runMenuSync(cfg *Config, store *Store, args []string) error {
+	fs := flag.NewFlagSet("menu sync", flag.ContinueOnError)
+	url := fs.String("url", "", "URL sheet yang dipublikasikan sebagai CSV, atau endpoint JSON")
+	dryRun := fs.Bool("dry-run", false, "hanya tampilkan perubahan tanpa menerapkannya")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *url == "" {
+		return fmt.Errorf("Gunakan: menu sync --url <csv-atau-json-url> [--dry-run]")
+	}
+	raw, contentType, err := fetchMenuFeed(*url)
+	if err != nil {
+		return err
+	}
+	feed, err := parseMenuFeed(raw, contentType)
+	if err != nil {
+		return fmt.Errorf("Sumber menu tidak diterapkan:\n%w", err)
+	}
+	current := store.Menu().Menu
+	diff := diffMenu(current, feed)
+	if err := validateMenu(diff.Menu); err != nil {
+		return err
+	}
+	diff.Print(os.Stdout)
+	if *dryRun || diff.Empty() {
+		return nil
+	}
+	admin, err := requireAdmin(cfg, store)
+	if err != nil {
+		return err
+	}
+	overrides, err := confirmMenuPrices(cfg.PriceGuards, diff.Menu)
+	if err != nil {
+		return err
+	}
+	if err := store.SetMenu(diff.Menu, "menu sync "+*url); err != nil {
+		return err
+	}
+	if len(overrides) > 0 {
+		if err := store.Audit(admin, "menu.price_guard", *url, strings.Join(overrides, "; ")); err != nil {
+			return err
+		}
+	}
+	fmt.Println("Menu diperbarui dari", *url)
+	if err := auditMenuPrices(store, admin, "menu sync "+*url, current, diff.Menu); err != nil {
+		return err
+	}
+	return store.Audit(admin, "menu.sync", *url, fmt.Sprintf("%d ditambahkan, %d diubah, %d dihapus", len(diff.Added), len(diff.Updated), len(diff.Removed)))
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Status rilis menu dari kantor pusat
+type ReleaseStatus string
+
+const (
+	ReleaseActive     ReleaseStatus = "active"      // Rilis berlaku dan akan diterapkan cabang
+	ReleaseRolledBack ReleaseStatus = "rolled_back" // Rilis dibatalkan karena ada cabang yang gagal
+)
+
+// Status konfirmasi (acknowledgment) rilis menu dari cabang
+type AckStatus string
+
+const (
+	AckApplied    AckStatus = "applied"     // Menu berhasil diterapkan di cabang
+	AckFailed     AckStatus = "failed"      // Cabang gagal menerapkan menu
+	AckRolledBack AckStatus = "rolled_back" // Cabang sudah kembali ke menu sebelumnya
+)
+
+// Struct untuk Rilis menu
+// Dibuat kantor pusat dan diterapkan cabang pada waktu yang dijadwalkan
+type MenuRelease struct {
+	ID          string           `json:"id"`           // ID unik rilis
+	Menu        []MenuItem       `json:"menu"`         // Menu baru
+	EffectiveAt time.Time        `json:"effective_at"` // Waktu menu mulai berlaku di cabang
+	Branches    []string         `json:"branches"`     // Cabang tujuan, kosong berarti semua cabang
+	Status      ReleaseStatus    `json:"status"`       // Status rilis
+	Acks        []MenuReleaseAck `json:"acks"`         // Konfirmasi dari setiap cabang
+	CreatedAt   time.Time        `json:"created_at"`   // Waktu rilis dibuat
+}
+
+// Struct untuk konfirmasi penerapan rilis menu dari cabang
+type MenuReleaseAck struct {
+	Branch string    `json:"branch"`          // ID cabang
+	Status AckStatus `json:"status"`          // Hasil penerapan
+	Error  string    `json:"error,omitempty"` // Pesan error jika gagal
+	At     time.Time `json:"at"`              // Waktu konfirmasi
+}
+
+// Struct untuk status menu di cabang
+// Menyimpan menu sebelumnya agar bisa kembali jika rilis dibatalkan
+type BranchMenuState struct {
+	AppliedReleaseID string     `json:"applied_release_id,omitempty"` // Rilis yang sedang berlaku
+	PreviousMenu     []MenuItem `json:"previous_menu,omitempty"`      // Menu sebelum rilis diterapkan
+}
+
+// Mendapatkan konfirmasi terakhir dari cabang untuk rilis ini
+func (r MenuRelease) AckFor(branch string) (MenuReleaseAck, bool) {
+	for i := len(r.Acks) - 1; i >= 0; i-- {
+		if r.Acks[i].Branch == branch {
+			return r.Acks[i], true
+		}
+	}
+	return MenuReleaseAck{}, false
+}
+
+// Memeriksa apakah rilis ditujukan untuk cabang tertentu
+func (r MenuRelease) Targets(branch string) bool {
+	return len(r.Branches) == 0 || slices.Contains(r.Branches, branch)
+}
+
+// Fungsi untuk memvalidasi menu sebelum diterapkan
+func validateMenu(menu []MenuItem) error {
+	if len(menu) == 0 {
+		return errors.New("Menu kosong")
+	}
+	seen := map[string]bool{}
+	codes := map[string]string{}
+	for i, item := range menu {
+		name := strings.ToLower(strings.TrimSpace(item.Name))
+		if name == "" {
+			return fmt.Errorf("Item %d tidak memiliki nama", i+1)
+		}
+		if seen[name] {
+			return fmt.Errorf("Item %s tercantum lebih dari sekali", item.Name)
+		}
+		seen[name] = true
+		if _, err := validatePrice(fmt.Sprintf("%.2f", item.Price)); err != nil || item.Price < 0 {
+			return fmt.Errorf("Harga %s tidak valid", item.Name)
+		}
+		if item.Code == "" {
+			continue
+		}
+		if err := validateItemCode(item.Code); err != nil {
+			return fmt.Errorf("Item %s: %w", item.Name, err)
+		}
+		if other, ok := codes[item.Code]; ok {
+			return fmt.Errorf("Kode %s dipakai %s dan %s", item.Code, other, item.Name)
+		}
+		codes[item.Code] = item.Name
+	}
+	return nil
+}
+
+// Menyimpan rilis menu baru di kantor pusat
+func (s *Store) PublishMenuRelease(r MenuRelease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.MenuReleases = append(s.data.MenuReleases, r)
+	return s.save()
+}
+
+// Mengambil salinan seluruh rilis menu
+func (s *Store) MenuReleases() []MenuRelease {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]MenuRelease(nil), s.data.MenuReleases...)
+}
+
+// Mengambil rilis yang masih perlu diproses oleh cabang:
+// rilis aktif yang belum dikonfirmasi, dan rilis yang dibatalkan padahal sudah diterapkan cabang
+func (s *Store) PendingReleasesFor(branch string) []MenuRelease {
+	var pending []MenuRelease
+	for _, r := range s.MenuReleases() {
+		if !r.Targets(branch) {
+			continue
+		}
+		ack, acked := r.AckFor(branch)
+		switch {
+		case r.Status == ReleaseActive && !acked:
+			pending = append(pending, r)
+		case r.Status == ReleaseRolledBack && acked && ack.Status == AckApplied:
+			pending = append(pending, r)
+		}
+	}
+	return pending
+}
+
+// Mencatat konfirmasi cabang; kegagalan di satu cabang membatalkan rilis untuk semua cabang
+func (s *Store) AckMenuRelease(id string, ack MenuReleaseAck) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.MenuReleases {
+		r := &s.data.MenuReleases[i]
+		if r.ID != id {
+			continue
+		}
+		r.Acks = append(r.Acks, ack)
+		if ack.Status == AckFailed {
+			r.Status = ReleaseRolledBack
+		}
+		return s.save()
+	}
+	return fmt.Errorf("Rilis menu %s tidak ditemukan", id)
+}
+
+// Menerapkan menu rilis di cabang dan menyimpan menu sebelumnya
+func (s *Store) ApplyMenuRelease(r MenuRelease) error {
+	if err := validateMenu(r.Menu); err != nil {
+		return err
+	}
+	previous := s.Menu().Menu
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.BranchMenu = BranchMenuState{AppliedReleaseID: r.ID, PreviousMenu: previous}
+	s.replaceMenu(r.Menu, "rilis "+r.ID)
+	return s.save()
+}
+
+// Mengembalikan menu cabang seperti sebelum rilis diterapkan
+func (s *Store) RollbackMenuRelease(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.BranchMenu.AppliedReleaseID != id {
+		return nil // Rilis lain sudah menggantikan, tidak ada yang perlu dikembalikan
+	}
+	s.replaceMenu(s.data.BranchMenu.PreviousMenu, "pembatalan rilis "+id)
+	s.data.BranchMenu = BranchMenuState{}
+	return s.save()
+}
+
+// Fungsi untuk membaca dan memvalidasi file JSON berisi daftar item menu
+func readMenuFile(path string) ([]MenuItem, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var menu []MenuItem
+	if err := json.Unmarshal(raw, &menu); err != nil {
+		return nil, fmt.Errorf("Format file menu tidak valid: %w", err)
+	}
+	return menu, validateMenu(menu)
+}
+
+// Fungsi untuk membuat rilis menu dari daftar item menu
+func publishMenu(store *Store, menu []MenuItem, at time.Time, branches []string) (MenuRelease, error) {
+	release := MenuRelease{
+		ID:          newID("REL"),
+		Menu:        menu,
+		EffectiveAt: at,
+		Branches:    branches,
+		Status:      ReleaseActive,
+		CreatedAt:   time.Now(),
+	}
+	return release, store.PublishMenuRelease(release)
+}
+
+// Fungsi untuk menampilkan daftar rilis menu beserta konfirmasi cabang
+func printMenuReleases(releases []MenuRelease) {
+	if len(releases) == 0 {
+		fmt.Println("Belum ada rilis menu.")
+		return
+	}
+	for _, r := range releases {
+		target := "semua cabang"
+		if len(r.Branches) > 0 {
+			target = strings.Join(r.Branches, ", ")
+		}
+		fmt.Printf("%s  %s  berlaku %s  (%d item, %s)\n", r.ID, r.Status, r.EffectiveAt.Format("2006-01-02 15:04"), len(r.Menu), target)
+		for _, ack := range r.Acks {
+			line := fmt.Sprintf("  - %s: %s pada %s", ack.Branch, ack.Status, ack.At.Format("2006-01-02 15:04"))
+			if ack.Error != "" {
+				line += " (" + ack.Error + ")"
+			}
+			fmt.Println(line)
+		}
+		for _, b := range r.Branches {
+			if _, ok := r.AckFor(b); !ok {
+				fmt.Printf("  - %s: menunggu\n", b)
+			}
+		}
+	}
+}
+
+// Fungsi untuk memproses rilis menu dari kantor pusat satu kali
+// Rilis yang belum waktunya dibiarkan hingga sinkronisasi berikutnya
+func syncBranchMenu(cfg *Config, store *Store) error {
+	ho := cfg.HeadOffice
+	client := &remoteBackend{cfg: ClientConfig{ServerURL: ho.URL, APIKey: ho.APIKey}, http: &http.Client{Timeout: 10 * time.Second}}
+
+	var releases []MenuRelease
+	if err := client.do(http.MethodGet, "/api/v1/branch/menu-releases?branch="+url.QueryEscape(ho.BranchID), nil, &releases); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, r := range releases {
+		ack := MenuReleaseAck{Branch: ho.BranchID, At: now}
+		switch {
+		case r.Status == ReleaseRolledBack:
+			before := store.Menu().Menu
+			if err := store.RollbackMenuRelease(r.ID); err != nil {
+				return err
+			}
+			if err := auditMenuPrices(store, Staff{Name: "system"}, "pembatalan rilis "+r.ID, before, store.Menu().Menu); err != nil {
+				return err
+			}
+			ack.Status = AckRolledBack
+			fmt.Println("Rilis menu", r.ID, "dibatalkan pusat, menu dikembalikan")
+		case now.Before(r.EffectiveAt):
+			continue
+		default:
+			// Harga sudah dikonfirmasi admin pusat saat rilis, cabang hanya mencatat peringatannya
+			for _, v := range checkMenuPrices(cfg.PriceGuards, r.Menu) {
+				fmt.Println("PERINGATAN: rilis", r.ID+":", v.Message)
+			}
+			before := store.Menu().Menu
+			if err := store.ApplyMenuRelease(r); err != nil {
+				ack.Status, ack.Error = AckFailed, err.Error()
+				fmt.Println("Gagal menerapkan rilis menu", r.ID+":", err)
+			} else {
+				ack.Status = AckApplied
+				if err := auditMenuPrices(store, Staff{Name: "system"}, "rilis "+r.ID, before, r.Menu); err != nil {
+					fmt.Println("Gagal mencatat audit:", err)
+				}
+				fmt.Println("Rilis menu", r.ID, "diterapkan")
+			}
+		}
+		if err := client.do(http.MethodPost, "/api/v1/branch/menu-releases/"+r.ID+"/ack", ack, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fungsi untuk menjalankan sinkronisasi menu cabang secara berkala
+func runBranchMenuSync(cfg *Config, store *Store) {
+	interval := time.Duration(cfg.HeadOffice.PollSeconds) * time.Second
+	for {
+		if err := syncBranchMenu(cfg, store); err != nil {
+			fmt.Println("Sinkronisasi menu dengan pusat gagal:", err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// GET /api/v1/branch/menu-releases?branch=cabang-1
+func (s *Server) handleBranchReleases(w http.ResponseWriter, r *http.Request) {
+	branch := r.URL.Query().Get("branch")
+	if branch == "" {
+		writeError(w, http.StatusBadRequest, "Parameter branch wajib diisi")
+		return
+	}
+	releases := s.store.PendingReleasesFor(branch)
+	if releases == nil {
+		releases = []MenuRelease{}
+	}
+	writeJSON(w, http.StatusOK, releases)
+}
+
+// POST /api/v1/branch/menu-releases/{id}/ack
+func (s *Server) handleBranchReleaseAck(w http.ResponseWriter, r *http.Request) {
+	var ack MenuReleaseAck
+	if err := json.NewDecoder(r.Body).Decode(&ack); err != nil || ack.Branch == "" || ack.Status == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi branch dan status")
+		return
+	}
+	if err := s.store.AckMenuRelease(r.PathValue("id"), ack); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errMenuVersionNotFound = errors.New("Versi menu tidak ditemukan")
+
+// Struct untuk satu versi menu
+// Setiap perubahan menu menyimpan salinan menu lengkap sehingga versi mana pun dapat dikembalikan
+type MenuVersion struct {
+	Version   int        `json:"version"`    // Nomor versi, dimulai dari 1 dan terus bertambah
+	CreatedAt time.Time  `json:"created_at"` // Waktu menu versi ini mulai berlaku
+	Note      string     `json:"note"`       // Asal perubahan, mis. "menu sync <url>" atau "rollback ke versi 3"
+	Menu      []MenuItem `json:"menu"`       // Menu lengkap versi ini
+}
+
+// Kunci pembanding menu tanpa tanda habis; menandai item habis bukan perubahan menu
+func menuVersionKey(menu []MenuItem) string {
+	items := append([]MenuItem(nil), menu...)
+	for i := range items {
+		items[i].SoldOut = false
+	}
+	raw, _ := json.Marshal(items)
+	return string(raw)
+}
+
+// Nomor versi menu yang sedang berlaku, 0 jika menu belum pernah diubah; pemanggil harus memegang s.mu
+func (s *Store) menuVersion() int {
+	if n := len(s.data.MenuVersions); n > 0 {
+		return s.data.MenuVersions[n-1].Version
+	}
+	return 0
+}
+
+// Mengganti menu yang berlaku dan mencatatnya sebagai versi baru; pemanggil harus memegang s.mu
+// Perubahan pertama juga mencatat menu sebelumnya sebagai versi 1 agar menu awal dapat dikembalikan
+// Menu yang sama dengan versi terakhir tidak dicatat ulang
+func (s *Store) replaceMenu(menu []MenuItem, note string) {
+	now := time.Now()
+	if len(s.data.MenuVersions) == 0 {
+		s.data.MenuVersions = append(s.data.MenuVersions, MenuVersion{Version: 1, CreatedAt: now, Note: "menu awal", Menu: s.storedMenu().Menu})
+	}
+	s.data.Menu = append([]MenuItem(nil), menu...)
+	last := s.data.MenuVersions[len(s.data.MenuVersions)-1]
+	if menuVersionKey(last.Menu) == menuVersionKey(menu) {
+		return
+	}
+	s.data.MenuVersions = append(s.data.MenuVersions, MenuVersion{Version: last.Version + 1, CreatedAt: now, Note: note, Menu: append([]MenuItem(nil), menu...)})
+}
+
+// Mengambil salinan seluruh versi menu, dari yang terlama
+func (s *Store) MenuVersions() []MenuVersion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]MenuVersion(nil), s.data.MenuVersions...)
+}
+
+// Mengembalikan menu ke isi versi tertentu dengan mencatatnya sebagai versi baru
+// Riwayat versi tidak dihapus, sehingga rollback pun dapat dikembalikan lagi
+// Tanda habis item mengikuti keadaan sekarang, bukan keadaan saat versi tersebut dibuat
+func (s *Store) RollbackMenu(version int) (MenuVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.data.MenuVersions, func(v MenuVersion) bool { return v.Version == version })
+	if i < 0 {
+		return MenuVersion{}, errMenuVersionNotFound
+	}
+	current := s.storedMenu()
+	menu := append([]MenuItem(nil), s.data.MenuVersions[i].Menu...)
+	for j := range menu {
+		item := current.findMenuItem(menu[j].Name)
+		menu[j].SoldOut = item != nil && item.SoldOut
+	}
+	before := s.menuVersion()
+	s.replaceMenu(menu, fmt.Sprintf("rollback ke versi %d", version))
+	if s.menuVersion() == before {
+		return MenuVersion{}, fmt.Errorf("Menu yang berlaku sudah sama dengan versi %d", version)
+	}
+	if err := s.save(); err != nil {
+		return MenuVersion{}, err
+	}
+	return s.data.MenuVersions[len(s.data.MenuVersions)-1], nil
+}
+
+// Fungsi untuk membandingkan dua versi menu dengan ringkasan yang sama seperti "menu sync"
+func diffMenuVersions(before, after []MenuItem) MenuDiff {
+	feed := make([]menuFeedItem, len(after))
+	for i, item := range after {
+		feed[i] = menuFeedItem{Name: item.Name, Price: item.Price, Category: item.Category, Code: &item.Code}
+	}
+	return diffMenu(before, feed)
+}
+
+// Fungsi untuk menampilkan riwayat versi menu beserta perubahannya dan jumlah pesanan yang memakainya
+func printMenuHistory(w io.Writer, versions []MenuVersion, orders []Order) {
+	if len(versions) == 0 {
+		fmt.Fprintln(w, "Menu belum pernah diubah.")
+		return
+	}
+	used := map[int]int{}
+	for _, o := range orders {
+		used[o.MenuVersion]++
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		current := ""
+		if i == len(versions)-1 {
+			current = "  [berlaku]"
+		}
+		fmt.Fprintf(w, "Versi %d  %s  %s  (%d item, %d pesanan)%s\n", v.Version, v.CreatedAt.Local().Format("2006-01-02 15:04"), v.Note, len(v.Menu), used[v.Version], current)
+		if i == 0 {
+			continue
+		}
+		diff := diffMenuVersions(versions[i-1].Menu, v.Menu)
+		for _, item := range diff.Added {
+			fmt.Fprintf(w, "  + %s Rp%.0f\n", item.Name, item.Price)
+		}
+		for _, u := range diff.Updated {
+			fmt.Fprintln(w, "  ~", u)
+		}
+		for _, item := range diff.Removed {
+			fmt.Fprintf(w, "  - %s\n", item.Name)
+		}
+	}
+}
+
+// Fungsi untuk menampilkan isi satu versi menu
+func printMenuVersion(w io.Writer, v MenuVersion) {
+	fmt.Fprintf(w, "Versi %d  %s  %s\n", v.Version, v.CreatedAt.Local().Format("2006-01-02 15:04"), v.Note)
+	for _, item := range v.Menu {
+		fmt.Fprintf(w, "  %-24s %-12s Rp%.2f\n", item.Name, item.Category, item.Price)
+	}
+}
+
+// Fungsi untuk menjalankan "menu history"
+func runMenuHistory(store *Store, args []string) error {
+	fs := flag.NewFlagSet("menu history", flag.ContinueOnError)
+	version := fs.Int("v", 0, "tampilkan isi lengkap versi menu ini")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	versions := store.MenuVersions()
+	if *version == 0 {
+		printMenuHistory(os.Stdout, versions, store.Orders())
+		return nil
+	}
+	i := slices.IndexFunc(versions, func(v MenuVersion) bool { return v.Version == *version })
+	if i < 0 {
+		return errMenuVersionNotFound
+	}
+	printMenuVersion(os.Stdout, versions[i])
+	return nil
+}
+
+// Fungsi untuk menjalankan "menu rollback <versi>", perlu PIN admin
+func runMenuRollback(cfg *Config, store *Store, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Gunakan: menu rollback <versi>")
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(args[0], "v"))
+	if err != nil {
+		return fmt.Errorf("Versi menu harus berupa angka, lihat \"menu history\"")
+	}
+	versions := store.MenuVersions()
+	i := slices.IndexFunc(versions, func(v MenuVersion) bool { return v.Version == version })
+	if i < 0 {
+		return errMenuVersionNotFound
+	}
+	if menuVersionKey(versions[i].Menu) == menuVersionKey(versions[len(versions)-1].Menu) {
+		return fmt.Errorf("Menu yang berlaku sudah sama dengan versi %d", version)
+	}
+	current := store.Menu().Menu
+	diffMenuVersions(current, versions[i].Menu).Print(os.Stdout)
+	admin, err := requireAdmin(cfg, store)
+	if err != nil {
+		return err
+	}
+	restored, err := store.RollbackMenu(version)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Menu dikembalikan ke isi versi %d sebagai versi %d.\n", version, restored.Version)
+	if err := auditMenuPrices(store, admin, restored.Note, current, restored.Menu); err != nil {
+		return err
+	}
+	return store.Audit(admin, "menu.rollback", fmt.Sprintf("versi %d", version), fmt.Sprintf("versi baru %d", restored.Version))
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Nama grup untuk modifier tambahan berbayar pada baris pesanan
+const addOnGroup = "Tambahan"
+
+// Struct untuk Grup varian
+// Mewakili pilihan yang wajib dipilih salah satu, mis. ukuran atau level pedas
+type VariantGroup struct {
+	Name    string          `json:"name"`    // Nama grup, mis. "Ukuran"
+	Options []VariantOption `json:"options"` // Pilihan varian, opsi pertama menjadi default
+}
+
+// Struct untuk Pilihan varian
+type VariantOption struct {
+	Name       string `json:"name"`        // Nama pilihan, mis. "Besar"
+	PriceDelta Money  `json:"price_delta"` // Tambahan harga untuk pilihan ini, boleh 0
+}
+
+// Struct untuk Tambahan berbayar, mis. "Extra Telur" +5000
+type AddOn struct {
+	Name  string `json:"name"`  // Nama tambahan
+	Price Money  `json:"price"` // Harga tambahan per porsi
+}
+
+// Struct untuk Modifier yang dipilih pada baris pesanan
+type Modifier struct {
+	Group string `json:"group"` // Nama grup varian, atau "Tambahan" untuk add-on
+	Name  string `json:"name"`  // Nama pilihan atau tambahan
+	Price Money  `json:"price"` // Tambahan harga per porsi
+}
+
+// Mengatur grup varian untuk item menu
+func (r *Restaurant) SetVariants(name string, groups ...VariantGroup) {
+	if item := r.findMenuItem(name); item != nil {
+		item.Variants = groups
+	}
+}
+
+// Mengatur daftar tambahan berbayar untuk item menu
+func (r *Restaurant) SetAddOns(name string, addOns ...AddOn) {
+	if item := r.findMenuItem(name); item != nil {
+		item.AddOns = addOns
+	}
+}
+
+// Mendapatkan harga per porsi termasuk varian dan tambahan
+func (l OrderLine) UnitPrice() Money {
+	price := l.Item.Price
+	for _, m := range l.Modifiers {
+		price += m.Price
+	}
+	return price
+}
+
+// Mendapatkan nama baris beserta modifier-nya, mis. "Es Teh (Besar, Sedikit Es)"
+func (l OrderLine) Label() string {
+	if len(l.Modifiers) == 0 {
+		return l.Item.Name
+	}
+	names := make([]string, len(l.Modifiers))
+	for i, m := range l.Modifiers {
+		names[i] = m.Name
+	}
+	return fmt.Sprintf("%s (%s)", l.Item.Name, strings.Join(names, ", "))
+}
+
+// Fungsi untuk meminta kasir memilih varian dan tambahan untuk item
+func promptModifiers(item MenuItem) []Modifier {
+	var modifiers []Modifier
+	for _, group := range item.Variants {
+		if len(group.Options) == 0 {
+			continue
+		}
+		labels := make([]string, len(group.Options))
+		for i, opt := range group.Options {
+			labels[i] = opt.Name + formatPriceDelta(opt.PriceDelta)
+		}
+		printChoices(tr("Pilih %s:\n", group.Name), group.Name, labels)
+		choice := 1
+		for {
+			text := readLine(tr("Nomor pilihan (Enter untuk opsi 1): "))
+			if text == "" {
+				break
+			}
+			n, err := strconv.Atoi(text)
+			if err == nil && n >= 1 && n <= len(group.Options) {
+				choice = n
+				break
+			}
+			fmt.Println(tr("Pilihan tidak valid. Coba lagi."))
+		}
+		opt := group.Options[choice-1]
+		modifiers = append(modifiers, Modifier{Group: group.Name, Name: opt.Name, Price: opt.PriceDelta})
+	}
+
+	if len(item.AddOns) == 0 {
+		return modifiers
+	}
+	labels := make([]string, len(item.AddOns))
+	for i, addOn := range item.AddOns {
+		labels[i] = addOn.Name + formatPriceDelta(addOn.Price)
+	}
+	printChoices(tr("Tambahan:")+"\n", tr("Tambahan"), labels)
+	for {
+		text := readLine(tr("Nomor tambahan, pisahkan dengan koma (Enter jika tidak ada): "))
+		selected, ok := parseAddOnChoice(text, item.AddOns)
+		if ok {
+			return append(modifiers, selected...)
+		}
+		fmt.Println(tr("Pilihan tidak valid. Coba lagi."))
+	}
+}
+
+// Fungsi untuk membaca pilihan tambahan seperti "1,3"
+func parseAddOnChoice(text string, addOns []AddOn) ([]Modifier, bool) {
+	var selected []Modifier
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 || n > len(addOns) {
+			return nil, false
+		}
+		addOn := addOns[n-1]
+		selected = append(selected, Modifier{Group: addOnGroup, Name: addOn.Name, Price: addOn.Price})
+	}
+	return selected, true
+}
+
+// Fungsi untuk menampilkan tambahan harga, mis. " (+Rp5000.00)"
+func formatPriceDelta(delta Money) string {
+	if delta == 0 {
+		return ""
+	}
+	if compactMode {
+		return " +" + shortPrice(delta)
+	}
+	return fmt.Sprintf(" (+Rp%.2f)", delta)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Nominal uang dalam sen (1/100 rupiah)
+// Disimpan sebagai bilangan bulat agar penjumlahan harga dan total tidak meleset satu rupiah
+// seperti pada float64; di JSON dan keluaran %f tetap tampil sebagai rupiah, mis. 30000 atau 30000.50
+type Money int64
+
+// Satu rupiah, dipakai untuk nominal tetap di kode, mis. 25000 * Rp
+const Rp Money = 100
+
+// Fungsi untuk mengubah nominal rupiah ke Money, dibulatkan ke sen terdekat (0.5 sen menjauhi nol)
+func Rupiah(amount float64) Money {
+	return Money(math.Round(amount * 100))
+}
+
+// Fungsi untuk membaca nominal rupiah dari teks, mis. "30000" atau "12500.50"
+// Nominal dibaca langsung ke sen tanpa melalui float, sehingga "0.29" tetap 29 sen
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	// Hanya angka 0-9; ParseInt sendiri masih menerima tanda + atau - di depan bagian mana pun
+	if !isDigits(whole) || (frac != "" && !isDigits(frac)) {
+		return 0, fmt.Errorf("Nominal %q tidak valid", s)
+	}
+	if len(frac) > 2 && strings.TrimRight(frac[2:], "0") != "" {
+		return 0, fmt.Errorf("Nominal %q tidak valid, maksimal dua angka di belakang titik", s)
+	}
+	frac = (frac + "00")[:2]
+	rp, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || rp < 0 || rp > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("Nominal %q tidak valid", s)
+	}
+	sen, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Nominal %q tidak valid", s)
+	}
+	m := Money(rp*100 + sen)
+	if neg {
+		m = -m
+	}
+	return m, nil
+}
+
+// Fungsi untuk memeriksa teks tidak kosong yang hanya berisi angka 0-9
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// Nominal dalam rupiah sebagai float64, untuk perhitungan persen dan keluaran CSV
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// Nominal dikali jumlah porsi
+func (m Money) Times(qty int) Money {
+	return m * Money(qty)
+}
+
+// Nominal dikali tarif, mis. pajak 0.11 atau komisi 0.2, dibulatkan ke sen terdekat
+func (m Money) MulRate(rate float64) Money {
+	return Money(math.Round(float64(m) * rate))
+}
+
+// Nominal dibagi faktor, mis. DPP = harga / 1.11, dibulatkan ke sen terdekat
+func (m Money) DivRate(factor float64) Money {
+	return Money(math.Round(float64(m) / factor))
+}
+
+// Bagian yang sebanding dari nominal, mis. refund 1 dari 3 porsi, dibulatkan ke sen terdekat
+func (m Money) Share(part, whole int) Money {
+	if whole == 0 {
+		return 0
+	}
+	return Money(math.Round(float64(m) * float64(part) / float64(whole)))
+}
+
+// Membulatkan nominal ke kelipatan unit dengan fungsi pembulatan (math.Round, math.Floor, atau math.Ceil)
+func (m Money) RoundTo(unit Money, fn func(float64) float64) Money {
+	if unit <= 0 {
+		return m
+	}
+	// Kelipatan dihitung dari sisa bagi bilangan bulat, sehingga 1500 tepat tidak pernah bergeser
+	if m%unit == 0 {
+		return m
+	}
+	return Money(fn(float64(m)/float64(unit))) * unit
+}
+
+// Nilai mutlak nominal
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// Menampilkan nominal dalam rupiah dengan dua angka desimal, mis. "30000.00"
+func (m Money) String() string {
+	return m.format(2)
+}
+
+// Menyusun nominal rupiah dengan jumlah desimal tertentu (0 atau 2) tanpa melalui float
+func (m Money) format(decimals int) string {
+	sign, abs := "", uint64(m)
+	if m < 0 {
+		sign, abs = "-", uint64(-m)
+	}
+	if decimals == 0 {
+		if abs = (abs + 50) / 100; abs == 0 {
+			sign = ""
+		}
+		return sign + strconv.FormatUint(abs, 10)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/100, abs%100)
+}
+
+// Implementasi fmt.Formatter agar format %f, %g, dan %v tetap menampilkan rupiah seperti float64
+func (m Money) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'd':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), int64(m))
+		return
+	case 's', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), m.String())
+		return
+	}
+	// Dua desimal disusun dari sen agar tidak ada pembulatan float pada nominal besar
+	if prec, ok := f.Precision(); ok && verb == 'f' && (prec == 2 || prec == 0) {
+		s := m.format(prec)
+		if f.Flag('+') && m >= 0 {
+			s = "+" + s
+		}
+		if w, ok := f.Width(); ok && len(s) < w {
+			pad := strings.Repeat(" ", w-len(s))
+			switch {
+			case f.Flag('-'):
+				s += pad
+			case f.Flag('0'):
+				sign := ""
+				if s[0] == '-' || s[0] == '+' {
+					sign, s = s[:1], s[1:]
+				}
+				s = sign + strings.Repeat("0", w-len(s)-len(sign)) + s
+			default:
+				s = pad + s
+			}
+		}
+		fmt.Fprint(f, s)
+		return
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), m.Float())
+}
+
+// Menyimpan nominal di JSON sebagai angka rupiah, sama seperti data lama yang memakai float64
+func (m Money) MarshalJSON() ([]byte, error) {
+	return strconv.AppendFloat(nil, m.Float(), 'f', -1, 64), nil
+}
+
+// Membaca nominal rupiah dari JSON; angka dengan lebih dari dua desimal dibulatkan ke sen terdekat
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if v, err := ParseMoney(string(data)); err == nil {
+		*m = v
+		return nil
+	}
+	// Notasi eksponen, mis. 1.5e4, dibaca sebagai float
+	f, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("Nominal uang harus berupa angka: %s", data)
+	}
+	*m = Rupiah(f)
+	return nil
+}
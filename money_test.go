@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "30000", want: 30000 * Rp},
+		{in: "12500.50", want: 1250050},
+		{in: "0.29", want: 29},
+		{in: "0.5", want: 50},
+		{in: "1.", want: 100},
+		{in: "1.230", want: 123},
+		{in: " 750 ", want: 750 * Rp},
+		{in: "-0.5", want: -50},
+		{in: "-12500.05", want: -1250005},
+		{in: "0", want: 0},
+		{in: "1.234", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: ".5", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "--5", wantErr: true},
+		{in: "+5", wantErr: true},
+		{in: "1.-5", wantErr: true},
+		{in: "5000.+5", wantErr: true},
+		{in: "-+5", wantErr: true},
+		{in: "1e4", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "92233720368547758", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %d, ingin error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, %v; ingin %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyRoundTo(t *testing.T) {
+	tests := []struct {
+		m    Money
+		unit Money
+		fn   func(float64) float64
+		want Money
+	}{
+		{m: 1450, unit: 100, fn: math.Round, want: 1500},
+		{m: 1449, unit: 100, fn: math.Round, want: 1400},
+		{m: -1450, unit: 100, fn: math.Round, want: -1500},
+		{m: -1449, unit: 100, fn: math.Round, want: -1400},
+		{m: 1401, unit: 100, fn: math.Ceil, want: 1500},
+		{m: -1499, unit: 100, fn: math.Ceil, want: -1400},
+		{m: 1499, unit: 100, fn: math.Floor, want: 1400},
+		{m: -1401, unit: 100, fn: math.Floor, want: -1500},
+		{m: 1500, unit: 100, fn: math.Ceil, want: 1500},
+		{m: -1500, unit: 100, fn: math.Floor, want: -1500},
+		{m: 3335000, unit: 100 * Rp, fn: math.Round, want: 3340000},
+		{m: 3334999, unit: 100 * Rp, fn: math.Round, want: 3330000},
+		{m: 3330050, unit: 500 * Rp, fn: math.Ceil, want: 3350000},
+		{m: 1234, unit: 0, fn: math.Round, want: 1234},
+		{m: 1234, unit: -100, fn: math.Round, want: 1234},
+	}
+	for _, tt := range tests {
+		if got := tt.m.RoundTo(tt.unit, tt.fn); got != tt.want {
+			t.Errorf("Money(%d).RoundTo(%d) = %d, ingin %d", tt.m, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMulRate(t *testing.T) {
+	tests := []struct {
+		m    Money
+		rate float64
+		want Money
+	}{
+		{m: 30000 * Rp, rate: 0.11, want: 3300 * Rp},
+		{m: 1, rate: 0.5, want: 1},
+		{m: 3, rate: 0.5, want: 2},
+		{m: 5, rate: 0.5, want: 3},
+		{m: -1, rate: 0.5, want: -1},
+		{m: -3, rate: 0.5, want: -2},
+		{m: 250, rate: 0.1, want: 25},
+		{m: 5, rate: 0.1, want: 1},
+		{m: -5, rate: 0.1, want: -1},
+		{m: 4, rate: 0.1, want: 0},
+		{m: 1000, rate: 0, want: 0},
+		{m: 1000, rate: -0.2, want: -200},
+	}
+	for _, tt := range tests {
+		if got := tt.m.MulRate(tt.rate); got != tt.want {
+			t.Errorf("Money(%d).MulRate(%v) = %d, ingin %d", tt.m, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyDivRate(t *testing.T) {
+	tests := []struct {
+		m      Money
+		factor float64
+		want   Money
+	}{
+		{m: 33300 * Rp, factor: 1.11, want: 30000 * Rp},
+		{m: 3, factor: 2, want: 2},
+		{m: 1, factor: 2, want: 1},
+		{m: -1, factor: 2, want: -1},
+		{m: -3, factor: 2, want: -2},
+		{m: 5, factor: 4, want: 1},
+		{m: 6, factor: 4, want: 2},
+		{m: -6, factor: 4, want: -2},
+		{m: 1000, factor: 1, want: 1000},
+	}
+	for _, tt := range tests {
+		if got := tt.m.DivRate(tt.factor); got != tt.want {
+			t.Errorf("Money(%d).DivRate(%v) = %d, ingin %d", tt.m, tt.factor, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		m      Money
+		want   string
+	}{
+		{format: "%.2f", m: 1250050, want: "12500.50"},
+		{format: "%.2f", m: -5, want: "-0.05"},
+		{format: "%.0f", m: 1250050, want: "12501"},
+		{format: "%.0f", m: 1250049, want: "12500"},
+		{format: "%.0f", m: -1250050, want: "-12501"},
+		{format: "%.0f", m: -49, want: "0"},
+		{format: "%12.2f", m: 1250050, want: "    12500.50"},
+		{format: "%-12.2f|", m: 1250050, want: "12500.50    |"},
+		{format: "%012.2f", m: 1250050, want: "000012500.50"},
+		{format: "%012.2f", m: -1250050, want: "-00012500.50"},
+		{format: "%+.2f", m: 1250050, want: "+12500.50"},
+		{format: "%+.2f", m: -1250050, want: "-12500.50"},
+		{format: "%+012.2f", m: 1250050, want: "+00012500.50"},
+		{format: "%8.0f", m: 30000 * Rp, want: "   30000"},
+		{format: "%4.2f", m: 1250050, want: "12500.50"},
+		{format: "%.2f", m: math.MaxInt64, want: "92233720368547758.07"},
+		{format: "%.1f", m: 1250050, want: "12500.5"},
+		{format: "%v", m: 1250050, want: "12500.5"},
+		{format: "%g", m: 30000 * Rp, want: "30000"},
+		{format: "%d", m: 1250050, want: "1250050"},
+		{format: "%s", m: 1250050, want: "12500.50"},
+		{format: "%q", m: -50, want: `"-0.50"`},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.m); got != tt.want {
+			t.Errorf("Sprintf(%q, %d) = %q, ingin %q", tt.format, tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		m    Money
+		json string
+	}{
+		{m: 0, json: "0"},
+		{m: 30000 * Rp, json: "30000"},
+		{m: 1250050, json: "12500.5"},
+		{m: 29, json: "0.29"},
+		{m: 1, json: "0.01"},
+		{m: -50, json: "-0.5"},
+		{m: -1250005, json: "-12500.05"},
+	}
+	for _, tt := range tests {
+		raw, err := json.Marshal(tt.m)
+		if err != nil || string(raw) != tt.json {
+			t.Errorf("json.Marshal(%d) = %s, %v; ingin %s", tt.m, raw, err, tt.json)
+			continue
+		}
+		var got Money
+		if err := json.Unmarshal(raw, &got); err != nil || got != tt.m {
+			t.Errorf("json.Unmarshal(%s) = %d, %v; ingin %d", raw, got, err, tt.m)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "1.5e4", want: 15000 * Rp},
+		{in: "0.125", want: 13},
+		{in: "-0.125", want: -13},
+		{in: "12.340", want: 1234},
+		{in: `"30000"`, wantErr: true},
+		{in: "true", wantErr: true},
+	}
+	for _, tt := range tests {
+		var got Money
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("json.Unmarshal(%s) = %d, ingin error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("json.Unmarshal(%s) = %d, %v; ingin %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	// null tidak mengubah nilai yang sudah ada
+	v := struct {
+		Price Money `json:"price"`
+	}{Price: 500 * Rp}
+	if err := json.Unmarshal([]byte(`{"price": null}`), &v); err != nil || v.Price != 500*Rp {
+		t.Errorf("null mengubah nominal menjadi %d, %v", v.Price, err)
+	}
+}
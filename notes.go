@@ -0,0 +1,301 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errNoteNotFound = errors.New("Catatan dapur tidak ditemukan")
+	errNoteExists   = errors.New("Catatan dapur sudah ada")
+)
+
+// Struct untuk Catatan dapur yang baku, mis. "Tanpa Bawang"
+// Catatan bebas dari kasir dicocokkan ke nama atau alias agar tiket dapur seragam
+type KitchenNote struct {
+	Name     string   `json:"name"`              // Nama baku yang dicetak di tiket dapur
+	Aliases  []string `json:"aliases,omitempty"` // Penulisan lain yang sering dipakai kasir, mis. "no onion"
+	Category string   `json:"category"`          // Kelompok catatan, mis. "Bahan" atau "Rasa"
+}
+
+// Daftar catatan dapur bawaan jika belum diatur
+func defaultKitchenNotes() []KitchenNote {
+	return []KitchenNote{
+		{Name: "Tanpa Bawang", Aliases: []string{"gak pake bawang", "no onion"}, Category: "Bahan"},
+		{Name: "Tanpa Sayur", Aliases: []string{"gak pake sayur", "no veggie"}, Category: "Bahan"},
+		{Name: "Tanpa Kecap", Category: "Bahan"},
+		{Name: "Extra Pedas", Aliases: []string{"pedas banget", "extra spicy"}, Category: "Rasa"},
+		{Name: "Tidak Pedas", Aliases: []string{"gak pedas", "no spicy"}, Category: "Rasa"},
+		{Name: "Kurang Manis", Aliases: []string{"less sugar"}, Category: "Rasa"},
+		{Name: "Kuah Dipisah", Aliases: []string{"kuah pisah"}, Category: "Penyajian"},
+		{Name: "Sambal Dipisah", Aliases: []string{"sambal pisah"}, Category: "Penyajian"},
+	}
+}
+
+// Mengambil salinan catatan dapur, catatan bawaan jika belum diatur
+func (s *Store) KitchenNotes() []KitchenNote {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.kitchenNotes()
+}
+
+// Catatan dapur yang berlaku; pemanggil harus memegang s.mu
+func (s *Store) kitchenNotes() []KitchenNote {
+	if len(s.data.KitchenNotes) == 0 {
+		return defaultKitchenNotes()
+	}
+	return append([]KitchenNote(nil), s.data.KitchenNotes...)
+}
+
+// Menambahkan catatan dapur baru ke daftar
+// Daftar bawaan disalin lebih dulu agar tidak hilang saat catatan pertama ditambahkan
+func (s *Store) AddKitchenNote(note KitchenNote) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notes := s.kitchenNotes()
+	if _, ok := matchKitchenNote(notes, note.Name); ok {
+		return errNoteExists
+	}
+	s.data.KitchenNotes = append(notes, note)
+	return s.save()
+}
+
+// Menghapus catatan dapur berdasarkan nama; pesanan lama tetap menyimpan namanya
+func (s *Store) RemoveKitchenNote(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notes := s.kitchenNotes()
+	i := slices.IndexFunc(notes, func(n KitchenNote) bool { return strings.EqualFold(n.Name, name) })
+	if i < 0 {
+		return errNoteNotFound
+	}
+	s.data.KitchenNotes = slices.Delete(notes, i, i+1)
+	return s.save()
+}
+
+// Fungsi untuk mencocokkan satu catatan ke daftar baku berdasarkan nama, alias, atau salah ketik kecil
+func matchKitchenNote(notes []KitchenNote, text string) (KitchenNote, bool) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	for _, n := range notes {
+		if strings.ToLower(n.Name) == text || slices.ContainsFunc(n.Aliases, func(a string) bool { return strings.ToLower(a) == text }) {
+			return n, true
+		}
+	}
+	// Salah ketik kecil tetap dicocokkan, dengan batas yang sama seperti pencarian item menu
+	for _, n := range notes {
+		for _, name := range append([]string{n.Name}, n.Aliases...) {
+			name = strings.ToLower(name)
+			if d := editDistance(text, name); d <= 2 && d*3 < len(name) {
+				return n, true
+			}
+		}
+	}
+	return KitchenNote{}, false
+}
+
+// Fungsi untuk memisahkan masukan catatan kasir menjadi catatan baku dan catatan bebas
+// Masukan dipisah koma; nomor merujuk urutan daftar catatan yang ditampilkan
+func parseKitchenNotes(notes []KitchenNote, entry string) (matched []string, free string) {
+	var rest []string
+	for _, part := range strings.Split(entry, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(part); err == nil && n >= 1 && n <= len(notes) {
+			matched = append(matched, notes[n-1].Name)
+			continue
+		}
+		if note, ok := matchKitchenNote(notes, part); ok {
+			matched = append(matched, note.Name)
+			continue
+		}
+		rest = append(rest, part)
+	}
+	return slices.Compact(matched), strings.Join(rest, ", ")
+}
+
+// Fungsi untuk meminta catatan dapur untuk satu baris pesanan
+func promptKitchenNotes(notes []KitchenNote) ([]string, string) {
+	names := make([]string, len(notes))
+	for i, n := range notes {
+		names[i] = n.Name
+	}
+	if compactMode {
+		printChoices("", tr("Catatan"), names)
+	} else {
+		for i, n := range names {
+			names[i] = fmt.Sprintf("%d. %s", i+1, n)
+		}
+		fmt.Println(strings.Join(names, "  "))
+	}
+	entry := readLine(tr("Catatan dapur (nomor/nama dipisah koma, Enter jika tidak ada): "))
+	return parseKitchenNotes(notes, entry)
+}
+
+// Struct untuk pemakaian satu catatan dapur
+type NoteUsage struct {
+	Name  string         // Nama catatan baku atau teks catatan bebas
+	Lines int            // Jumlah baris pesanan yang memakai catatan ini
+	Items map[string]int // Jumlah baris per item menu
+}
+
+// Struct untuk Laporan pemakaian catatan dapur
+type NotesReport struct {
+	From, To  time.Time
+	Lines     map[string]int // Jumlah baris per item menu, untuk menghitung persentase
+	Notes     []NoteUsage    // Catatan baku, terbanyak lebih dulu
+	FreeNotes []NoteUsage    // Catatan bebas yang belum masuk daftar baku
+}
+
+// Batas persentase baris item yang memakai catatan agar disarankan menjadi pilihan menu
+const noteSuggestShare = 0.2
+
+// Fungsi untuk menghitung pemakaian catatan dapur pada pesanan dalam rentang tanggal
+func buildNotesReport(store *Store, from, to time.Time) NotesReport {
+	report := NotesReport{From: from, To: to, Lines: map[string]int{}}
+	notes := map[string]*NoteUsage{}
+	free := map[string]*NoteUsage{}
+	count := func(m map[string]*NoteUsage, name, item string) {
+		key := strings.ToLower(name)
+		if m[key] == nil {
+			m[key] = &NoteUsage{Name: name, Items: map[string]int{}}
+		}
+		m[key].Lines++
+		m[key].Items[item]++
+	}
+	for _, o := range store.Orders() {
+		t := o.CreatedAt.Local()
+		if o.Status == OrderCancelled || t.Before(from) || t.After(endOfDay(to)) {
+			continue
+		}
+		for _, l := range o.Lines {
+			report.Lines[l.Item.Name]++
+			for _, n := range l.Notes {
+				count(notes, n, l.Item.Name)
+			}
+			// Catatan bebas dihitung per bagian agar teks yang sama di baris berbeda terkumpul
+			for _, part := range strings.Split(l.FreeNote, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					count(free, part, l.Item.Name)
+				}
+			}
+		}
+	}
+	sorted := func(m map[string]*NoteUsage) []NoteUsage {
+		var usage []NoteUsage
+		for _, u := range m {
+			usage = append(usage, *u)
+		}
+		slices.SortFunc(usage, func(a, b NoteUsage) int {
+			if a.Lines != b.Lines {
+				return b.Lines - a.Lines
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+		return usage
+	}
+	report.Notes, report.FreeNotes = sorted(notes), sorted(free)
+	return report
+}
+
+// Fungsi untuk mencetak laporan pemakaian catatan dapur beserta saran pilihan menu
+func (r NotesReport) Print(w io.Writer) {
+	fmt.Fprintf(w, "Catatan Dapur %s s.d. %s\n", r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+	if len(r.Notes) == 0 && len(r.FreeNotes) == 0 {
+		fmt.Fprintln(w, "Tidak ada catatan dapur.")
+		return
+	}
+	var suggestions []string
+	for _, n := range r.Notes {
+		fmt.Fprintf(w, "%-20s %5d baris\n", n.Name, n.Lines)
+		items := make([]string, 0, len(n.Items))
+		for item := range n.Items {
+			items = append(items, item)
+		}
+		slices.Sort(items)
+		for _, item := range items {
+			share := float64(n.Items[item]) / float64(r.Lines[item])
+			fmt.Fprintf(w, "  %-18s %5d  %3.0f%%\n", item, n.Items[item], share*100)
+			if share >= noteSuggestShare {
+				suggestions = append(suggestions, fmt.Sprintf("%q untuk %s (%.0f%% pesanan)", n.Name, item, share*100))
+			}
+		}
+	}
+	if len(r.FreeNotes) > 0 {
+		fmt.Fprintln(w, "\nCatatan bebas (kandidat catatan baku):")
+		for _, n := range r.FreeNotes {
+			fmt.Fprintf(w, "  %-30s %5d baris\n", n.Name, n.Lines)
+		}
+	}
+	if len(suggestions) > 0 {
+		fmt.Fprintln(w, "\nPertimbangkan menjadikan pilihan menu:")
+		for _, s := range suggestions {
+			fmt.Fprintln(w, "  -", s)
+		}
+	}
+}
+
+// Fungsi untuk menjalankan "report notes"
+func runNotesReport(store *Store, args []string) error {
+	fs := flag.NewFlagSet("report notes", flag.ContinueOnError)
+	dates := addRangeFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, to, err := dates.parse()
+	if err != nil {
+		return err
+	}
+	buildNotesReport(store, from, to).Print(os.Stdout)
+	return nil
+}
+
+// Fungsi untuk menjalankan sub-perintah "notes"
+func runNotesCommand(store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: notes list|add|remove")
+	}
+	switch args[0] {
+	case "list":
+	case "add":
+		fs := flag.NewFlagSet("notes add", flag.ContinueOnError)
+		aliases := fs.String("alias", "", "penulisan lain dipisah koma, mis. \"no onion,gak pake bawang\"")
+		category := fs.String("category", "", "kelompok catatan, mis. Bahan")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("Gunakan: notes add [-alias a,b] [-category kategori] <nama>")
+		}
+		note := KitchenNote{Name: fs.Arg(0), Category: *category}
+		for _, a := range strings.Split(*aliases, ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				note.Aliases = append(note.Aliases, a)
+			}
+		}
+		if err := store.AddKitchenNote(note); err != nil {
+			return err
+		}
+	case "remove":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: notes remove <nama>")
+		}
+		if err := store.RemoveKitchenNote(args[1]); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Sub-perintah notes tidak dikenal: %s", args[0])
+	}
+	for i, n := range store.KitchenNotes() {
+		fmt.Printf("%2d. %-18s %-10s %s\n", i+1, n.Name, n.Category, strings.Join(n.Aliases, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Struct untuk Konfigurasi ekspor cadangan ke penyimpanan di luar restoran
+// Server mengirim salinan file data dan laporan harian setiap hari pada jam Time,
+// lalu menghapus salinan yang lebih tua dari RetentionDays
+type OffsiteConfig struct {
+	Provider      string      `json:"provider"`       // s3 (AWS S3, MinIO, Cloudflare R2, dll.) atau gdrive, kosong berarti tidak aktif
+	Time          string      `json:"time"`           // Jam ekspor harian, mis. "23:30"; laporan yang dikirim adalah laporan hari itu
+	RetentionDays int         `json:"retention_days"` // Lama salinan disimpan sebelum dihapus, 0 berarti default 30 hari
+	AlertWebhook  string      `json:"alert_webhook"`  // URL yang menerima peringatan jika ekspor gagal, boleh kosong
+	S3            S3Config    `json:"s3"`
+	Drive         DriveConfig `json:"gdrive"`
+}
+
+// Struct untuk Konfigurasi bucket S3-compatible
+type S3Config struct {
+	Endpoint  string `json:"endpoint"`   // Alamat layanan, mis. "https://s3.ap-southeast-1.amazonaws.com", kosong untuk AWS sesuai region
+	Region    string `json:"region"`     // Region bucket, mis. "ap-southeast-1"; R2 memakai "auto"
+	Bucket    string `json:"bucket"`     // Nama bucket
+	Prefix    string `json:"prefix"`     // Folder di dalam bucket, mis. "cabang-1/"
+	AccessKey string `json:"access_key"` // Access key ID
+	SecretKey string `json:"secret_key"` // Secret access key
+}
+
+// Struct untuk Konfigurasi folder Google Drive
+// Token akses diperbarui dari refresh token OAuth setiap kali ekspor berjalan
+type DriveConfig struct {
+	FolderID     string `json:"folder_id"`     // ID folder tujuan, bagian terakhir alamat folder di Drive
+	ClientID     string `json:"client_id"`     // OAuth client ID
+	ClientSecret string `json:"client_secret"` // OAuth client secret
+	RefreshToken string `json:"refresh_token"` // Refresh token dengan scope drive.file
+}
+
+// Struct untuk satu file cadangan di penyimpanan offsite
+type offsiteObject struct {
+	ID       string // ID file; untuk S3 sama dengan nama objek
+	Name     string
+	Modified time.Time
+}
+
+// Penyimpanan offsite tujuan ekspor
+type offsiteTarget interface {
+	Put(name, contentType string, body []byte) error
+	List() ([]offsiteObject, error)
+	Delete(obj offsiteObject) error
+}
+
+var offsiteClient = &http.Client{Timeout: 60 * time.Second}
+
+// Fungsi untuk membuat tujuan ekspor sesuai provider di konfigurasi
+func newOffsiteTarget(cfg OffsiteConfig) (offsiteTarget, error) {
+	switch cfg.Provider {
+	case "s3":
+		if cfg.S3.Bucket == "" || cfg.S3.AccessKey == "" || cfg.S3.SecretKey == "" {
+			return nil, fmt.Errorf("offsite.s3 membutuhkan bucket, access_key, dan secret_key")
+		}
+		return s3Target{cfg.S3}, nil
+	case "gdrive":
+		if cfg.Drive.FolderID == "" || cfg.Drive.RefreshToken == "" {
+			return nil, fmt.Errorf("offsite.gdrive membutuhkan folder_id dan refresh_token")
+		}
+		return &driveTarget{cfg: cfg.Drive}, nil
+	case "":
+		return nil, fmt.Errorf("offsite.provider belum diatur di konfigurasi")
+	}
+	return nil, fmt.Errorf("offsite.provider tidak dikenal: %s (s3 atau gdrive)", cfg.Provider)
+}
+
+// Tujuan ekspor bucket S3-compatible dengan tanda tangan AWS Signature V4
+type s3Target struct {
+	cfg S3Config
+}
+
+// Alamat objek dengan path-style, didukung AWS, MinIO, dan R2
+func (t s3Target) objectURL(key string, query url.Values) *url.URL {
+	endpoint := t.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + t.cfg.Region + ".amazonaws.com"
+	}
+	u, _ := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	u.Path += "/" + t.cfg.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawQuery = query.Encode()
+	return u
+}
+
+// Meng-encode teks sesuai aturan URI encoding Signature V4
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// Mengirim request yang ditandatangani dengan AWS Signature V4
+func (t s3Target) do(method string, u *url.URL, contentType string, body []byte) (*http.Response, error) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, s3Escape(k, false)+"="+s3Escape(query.Get(k), false))
+	}
+	canonical := strings.Join([]string{
+		method,
+		s3Escape(u.Path, true),
+		strings.Join(pairs, "&"),
+		"host:" + u.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n",
+		"host;x-amz-content-sha256;x-amz-date",
+		payloadHash,
+	}, "\n")
+	canonicalSum := sha256.Sum256([]byte(canonical))
+	scope := day + "/" + t.cfg.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalSum[:])
+	key := hmacSHA256([]byte("AWS4"+t.cfg.SecretKey), day)
+	key = hmacSHA256(key, t.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+t.cfg.AccessKey+"/"+scope+
+		", SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="+signature)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := offsiteClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Bucket S3 tidak dapat dihubungi: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("Bucket S3 menolak %s: %s %s", method, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+func (t s3Target) Put(name, contentType string, body []byte) error {
+	resp, err := t.do(http.MethodPut, t.objectURL(t.cfg.Prefix+name, nil), contentType, body)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (t s3Target) List() ([]offsiteObject, error) {
+	var objects []offsiteObject
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {t.cfg.Prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := t.do(http.MethodGet, t.objectURL("", query), "", nil)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Daftar isi bucket tidak valid: %w", err)
+		}
+		for _, c := range result.Contents {
+			objects = append(objects, offsiteObject{ID: c.Key, Name: strings.TrimPrefix(c.Key, t.cfg.Prefix), Modified: c.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (t s3Target) Delete(obj offsiteObject) error {
+	resp, err := t.do(http.MethodDelete, t.objectURL(obj.ID, nil), "", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Tujuan ekspor folder Google Drive lewat Drive API v3
+type driveTarget struct {
+	cfg   DriveConfig
+	token string // Token akses dari refresh token, diambil sekali per ekspor
+}
+
+// Mengirim request ke Drive API dengan token akses
+func (t *driveTarget) do(method, target, contentType string, body io.Reader) (*http.Response, error) {
+	if t.token == "" {
+		resp, err := offsiteClient.PostForm("https://oauth2.googleapis.com/token", url.Values{
+			"client_id":     {t.cfg.ClientID},
+			"client_secret": {t.cfg.ClientSecret},
+			"refresh_token": {t.cfg.RefreshToken},
+			"grant_type":    {"refresh_token"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Google OAuth tidak dapat dihubungi: %w", err)
+		}
+		var token struct {
+			AccessToken string `json:"access_token"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&token)
+		resp.Body.Close()
+		if err != nil || token.AccessToken == "" {
+			return nil, fmt.Errorf("Refresh token Google Drive ditolak (%s)", resp.Status)
+		}
+		t.token = token.AccessToken
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := offsiteClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Google Drive tidak dapat dihubungi: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("Google Drive menolak %s: %s %s", method, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// Mengunggah file dengan uploadType=multipart: metadata JSON lalu isi file
+func (t *driveTarget) Put(name, contentType string, body []byte) error {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	meta, _ := json.Marshal(map[string]any{"name": name, "parents": []string{t.cfg.FolderID}})
+	part, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=UTF-8"}})
+	part.Write(meta)
+	part, _ = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	part.Write(body)
+	mw.Close()
+	resp, err := t.do(http.MethodPost, "https://www.googleapis.com/upload/drive/v3/files?uploadType=multipart",
+		"multipart/related; boundary="+mw.Boundary(), &buf)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (t *driveTarget) List() ([]offsiteObject, error) {
+	var objects []offsiteObject
+	pageToken := ""
+	for {
+		query := url.Values{
+			"q":      {fmt.Sprintf("'%s' in parents and trashed = false", t.cfg.FolderID)},
+			"fields": {"nextPageToken,files(id,name,createdTime)"},
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		resp, err := t.do(http.MethodGet, "https://www.googleapis.com/drive/v3/files?"+query.Encode(), "", nil)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Files []struct {
+				ID          string    `json:"id"`
+				Name        string    `json:"name"`
+				CreatedTime time.Time `json:"createdTime"`
+			} `json:"files"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Daftar isi folder Drive tidak valid: %w", err)
+		}
+		for _, f := range result.Files {
+			objects = append(objects, offsiteObject{ID: f.ID, Name: f.Name, Modified: f.CreatedTime})
+		}
+		if result.NextPageToken == "" {
+			return objects, nil
+		}
+		pageToken = result.NextPageToken
+	}
+}
+
+func (t *driveTarget) Delete(obj offsiteObject) error {
+	resp, err := t.do(http.MethodDelete, "https://www.googleapis.com/drive/v3/files/"+url.PathEscape(obj.ID), "", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Mengambil salinan seluruh isi file data untuk dicadangkan
+func (s *Store) Backup() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.MarshalIndent(s.data, "", "  ")
+}
+
+// Nama file cadangan; hanya file dengan awalan ini yang dihapus oleh retensi
+const (
+	offsiteBackupPrefix = "backup-"
+	offsiteReportPrefix = "report-"
+)
+
+// Fungsi untuk mengirim cadangan data dan laporan harian satu tanggal ke penyimpanan offsite
+// Setelah terkirim, cadangan yang lebih tua dari masa retensi dihapus
+func exportOffsite(cfg OffsiteConfig, store *Store, date time.Time) (removed int, err error) {
+	target, err := newOffsiteTarget(cfg)
+	if err != nil {
+		return 0, err
+	}
+	backup, err := store.Backup()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	if err := target.Put(offsiteBackupPrefix+now.Format("20060102-150405")+".json", "application/json", backup); err != nil {
+		return 0, err
+	}
+	var report bytes.Buffer
+	buildDailyReport(store, date).Print(&report)
+	if err := target.Put(offsiteReportPrefix+date.Format("2006-01-02")+".txt", "text/plain; charset=utf-8", report.Bytes()); err != nil {
+		return 0, err
+	}
+
+	objects, err := target.List()
+	if err != nil {
+		return 0, fmt.Errorf("Cadangan terkirim, tetapi retensi gagal: %w", err)
+	}
+	cutoff := now.AddDate(0, 0, -cfg.RetentionDays)
+	for _, obj := range objects {
+		ours := strings.HasPrefix(obj.Name, offsiteBackupPrefix) || strings.HasPrefix(obj.Name, offsiteReportPrefix)
+		if !ours || obj.Modified.IsZero() || !obj.Modified.Before(cutoff) {
+			continue
+		}
+		if err := target.Delete(obj); err != nil {
+			return removed, fmt.Errorf("Cadangan terkirim, tetapi retensi gagal: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Fungsi untuk mengirim peringatan ekspor gagal ke log server dan webhook
+func alertOffsiteFailure(cfg OffsiteConfig, err error) {
+	message := "Ekspor cadangan offsite gagal: " + err.Error()
+	fmt.Println("PERINGATAN:", message)
+	if cfg.AlertWebhook == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]any{"event": "offsite.failed", "provider": cfg.Provider, "message": message})
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(cfg.AlertWebhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Println("Gagal mengirim peringatan ke webhook:", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// Fungsi untuk menjalankan ekspor offsite harian selama server berjalan
+// Ekspor yang gagal diulang setiap 15 menit sampai berhasil atau berganti hari
+func runOffsiteExport(cfg OffsiteConfig, store *Store) {
+	at, err := time.Parse("15:04", cfg.Time)
+	if err != nil {
+		fmt.Println("offsite.time tidak valid, ekspor offsite tidak dijalankan:", cfg.Time)
+		return
+	}
+	var done string
+	var retryAt time.Time
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		today := now.Format("2006-01-02")
+		due := now.Hour()*60+now.Minute() >= at.Hour()*60+at.Minute()
+		if done == today || !due || now.Before(retryAt) {
+			continue
+		}
+		removed, err := exportOffsite(cfg, store, now)
+		if err != nil {
+			alertOffsiteFailure(cfg, err)
+			retryAt = now.Add(15 * time.Minute)
+			continue
+		}
+		done = today
+		fmt.Printf("Cadangan offsite terkirim ke %s, %d salinan lama dihapus\n", cfg.Provider, removed)
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "backup"
+func runBackupCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: backup create|restore|push|list")
+	}
+	switch args[0] {
+	case "create", "restore":
+		return runBackupArchive(cfg, store, args)
+	case "push":
+		fs := flag.NewFlagSet("backup push", flag.ContinueOnError)
+		dateFlag := fs.String("date", time.Now().Format("2006-01-02"), "tanggal laporan harian YYYY-MM-DD")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		date, err := time.ParseInLocation("2006-01-02", *dateFlag, time.Local)
+		if err != nil {
+			return fmt.Errorf("Format tanggal harus YYYY-MM-DD")
+		}
+		removed, err := exportOffsite(cfg.Offsite, store, date)
+		if err != nil {
+			alertOffsiteFailure(cfg.Offsite, err)
+			return err
+		}
+		fmt.Printf("Cadangan dan laporan %s terkirim ke %s, %d salinan lama dihapus\n", *dateFlag, cfg.Offsite.Provider, removed)
+		return nil
+	case "list":
+		target, err := newOffsiteTarget(cfg.Offsite)
+		if err != nil {
+			return err
+		}
+		objects, err := target.List()
+		if err != nil {
+			return err
+		}
+		slices.SortFunc(objects, func(a, b offsiteObject) int { return a.Modified.Compare(b.Modified) })
+		if len(objects) == 0 {
+			fmt.Println("Belum ada cadangan di penyimpanan offsite.")
+		}
+		for _, obj := range objects {
+			fmt.Printf("%s  %s\n", obj.Modified.Local().Format("2006-01-02 15:04"), obj.Name)
+		}
+		return nil
+	}
+	return fmt.Errorf("Gunakan: backup create|restore|push|list")
+}
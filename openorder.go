@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errMixedCustomers = errors.New("Pesanan terbuka yang dibayar sekaligus harus milik pelanggan yang sama")
+
+// Fungsi untuk memilih pesanan terbuka yang dibayar
+// ids kosong berarti kasir memilih dari daftar pesanan yang cocok dengan pelanggan
+func selectOpenOrders(store *Store, ids []string, customer string) ([]Order, error) {
+	drafts := store.Drafts()
+	if len(ids) > 0 {
+		var selected []Order
+		for _, id := range ids {
+			i := slices.IndexFunc(drafts, func(o Order) bool { return o.ID == id })
+			if i < 0 {
+				return nil, fmt.Errorf("Pesanan terbuka %s tidak ditemukan", id)
+			}
+			selected = append(selected, drafts[i])
+		}
+		return selected, nil
+	}
+
+	customers := map[string]Customer{}
+	for _, c := range store.Customers() {
+		customers[c.ID] = c
+	}
+	q := OrderQuery{Customer: customer}
+	var found []Order
+	for _, o := range drafts {
+		if q.Match(o, customers) {
+			found = append(found, o)
+		}
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("Tidak ada pesanan terbuka yang cocok")
+	}
+	for i, o := range found {
+		fmt.Printf("%d. %s\n", i+1, o.Summary())
+	}
+	for {
+		choice := readLine("Pilih pesanan yang dibayar, pisahkan dengan koma (Enter untuk semua):")
+		if choice == "" {
+			return found, nil
+		}
+		var selected []Order
+		valid := true
+		for _, part := range strings.Split(choice, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || n < 1 || n > len(found) || slices.ContainsFunc(selected, func(o Order) bool { return o.ID == found[n-1].ID }) {
+				valid = false
+				break
+			}
+			selected = append(selected, found[n-1])
+		}
+		if valid {
+			return selected, nil
+		}
+		fmt.Println("Pilihan tidak valid. Coba lagi.")
+	}
+}
+
+// Fungsi untuk menjalankan "order pay"
+// Beberapa pesanan terbuka satu pelanggan dibayar dengan satu pembayaran dan satu struk gabungan,
+// lalu setiap pesanan dikirim ke dapur dengan nomor pesanannya sendiri sebagai referensi pengambilan
+func runOrderPay(cfg *Config, store *Store, args []string) error {
+	fs := flag.NewFlagSet("order pay", flag.ContinueOnError)
+	customer := fs.String("customer", "", "nama, nomor HP, atau ID pelanggan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	orders, err := selectOpenOrders(store, fs.Args(), *customer)
+	if err != nil {
+		return err
+	}
+	for _, o := range orders[1:] {
+		if o.CustomerID != orders[0].CustomerID {
+			return errMixedCustomers
+		}
+	}
+
+	backend := newLocalBackend(cfg, store)
+	cashier, err := login(backend, "Masuk sebagai kasir.")
+	if err != nil {
+		return err
+	}
+	payment, err := payOrders(cfg, backend, cashier, orders)
+	if err != nil {
+		return err
+	}
+
+	receipt, err := store.Receipt(payment.ID)
+	if err != nil {
+		return err
+	}
+	emitReceipt(cfg.Printer, receipt, tr("STRUK PEMBAYARAN"))
+	orders = kitchenOrders(orders)
+	printReadyEstimates(os.Stdout, orders, time.Now())
+	// Setiap pesanan mendapat tiket dapur dan nomor antreannya sendiri sebagai referensi pengambilan
+	printOrderTickets(os.Stdout, orders, cfg)
+
+	kitchen := startKitchen(cfg.Kitchen, markReady(store))
+	for _, o := range orders {
+		if err := kitchen.Submit(o); err != nil {
+			fmt.Println("Pesanan", o.ID, "tidak dikirim ke dapur:", err)
+		}
+	}
+	kitchen.Drain()
+	return nil
+}
+
+// Fungsi untuk menghitung ulang, menagih, dan menyimpan beberapa pesanan dengan satu pembayaran
+// Nomor antrean hasil checkout ditulis ke slice orders milik pemanggil
+func payOrders(cfg *Config, backend CashierBackend, cashier Staff, orders []Order) (Payment, error) {
+	// Draf disimpan tanpa aturan harga, sehingga total dihitung ulang saat dibayar
+	pricing := cfg.Pricing.Strategy()
+	var total Money
+	payment := Payment{ID: newID("PAY"), CashierID: cashier.ID}
+	for i := range orders {
+		pricing.Apply(&orders[i])
+		total += orders[i].Total
+		payment.OrderIDs = append(payment.OrderIDs, orders[i].ID)
+		fmt.Println(orders[i].Summary())
+	}
+	total, payment.Rounding = pricing.RoundPayment(total)
+	writePriceBreakdown(os.Stdout, orders, payment.Rounding)
+	fmt.Printf("Total %d pesanan: Rp%.2f\n", len(orders), total)
+
+	payment.Amount = total
+	applyReservationDeposit(backend, pricing, &payment, orders[0].Table)
+	if cfg.Cashier.GiftVouchers {
+		applyGiftVoucher(backend, pricing, &payment)
+	}
+	if err := verifyPaymentTotals(cfg, pricing, orders, payment); err != nil {
+		return payment, err
+	}
+	if cfg.Cashier.Tips {
+		promptTip(&payment)
+	}
+	gateway, err := newPaymentGateway(cfg.PaymentGateway)
+	if err != nil {
+		return payment, err
+	}
+	if result := handlePayment(&payment, gateway, time.Duration(cfg.PaymentGateway.WaitSeconds)*time.Second, backend.DrawerDenominations(), cfg.Cashier.PaymentAttempts, cfg.Currencies); result.Err != nil {
+		return payment, result.Err
+	}
+	payment.PaidAt = time.Now()
+	attachWifiVoucher(cfg.Wifi, backend, &payment)
+	return payment, backend.Checkout(orders, payment)
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Status pesanan
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"   // Pesanan sudah diterima, belum disajikan
+	OrderServed    OrderStatus = "served"    // Pesanan sudah disajikan
+	OrderCancelled OrderStatus = "cancelled" // Pesanan dibatalkan sebelum disajikan
+)
+
+// Struct untuk Pembayaran
+// Mewakili satu pembayaran untuk satu atau beberapa pesanan
+type Payment struct {
+	ID       string    `json:"id"`        // ID unik pembayaran
+	OrderIDs []string  `json:"order_ids"` // Pesanan yang dibayar
+	Amount   Money     `json:"amount"`    // Total tagihan yang dibayar
+	Tendered Money     `json:"tendered"`  // Jumlah uang yang diterima
+	Change   Money     `json:"change"`    // Kembalian yang diberikan
+	PaidAt   time.Time `json:"paid_at"`   // Waktu pembayaran
+
+	Method      PaymentMethod `json:"method,omitempty"`       // Metode pembayaran, kosong berarti tunai
+	ProviderRef string        `json:"provider_ref,omitempty"` // Referensi transaksi dari gateway untuk kartu/QRIS
+	CashierID   string        `json:"cashier_id,omitempty"`   // Staf yang menerima pembayaran
+
+	Currency        string `json:"currency,omitempty"`         // Mata uang asing uang tunai yang diterima, kosong berarti rupiah
+	ForeignTendered Money  `json:"foreign_tendered,omitempty"` // Nominal uang asing yang diterima; Tendered berisi nilai rupiahnya
+	FXRate          Money  `json:"fx_rate,omitempty"`          // Kurs rupiah per unit mata uang asing saat pembayaran
+
+	Rounding Money `json:"rounding,omitempty"` // Selisih pembulatan di tahap pembayaran, sudah termasuk di Amount
+
+	Deposit       Money  `json:"deposit,omitempty"`        // Deposit reservasi yang memotong tagihan, sudah dikurangkan dari Amount
+	ReservationID string `json:"reservation_id,omitempty"` // Reservasi asal deposit
+
+	GiftVoucher Money  `json:"gift_voucher,omitempty"` // Saldo voucher hadiah yang dipakai, sudah dikurangkan dari Amount
+	VoucherCode string `json:"voucher_code,omitempty"` // Kode voucher hadiah yang dipakai
+
+	Tip   Money  `json:"tip,omitempty"`    // Tip pelanggan di luar tagihan, tidak termasuk Amount dan penjualan
+	TipTo string `json:"tip_to,omitempty"` // ID atau nama staf pelayan penerima tip
+
+	WifiCode string `json:"wifi_code,omitempty"` // Kode Wi-Fi tamu yang dicetak di struk
+
+	IdempotencyKey string `json:"idempotency_key,omitempty"` // Idempotency-Key checkout lewat API, request ulang mendapat hasil yang sama
+}
+
+// Struct untuk Refund
+// Disimpan bersama pembayaran untuk mencatat pengembalian dana penuh atau per baris
+type Refund struct {
+	ID        string       `json:"id"`         // ID unik refund
+	PaymentID string       `json:"payment_id"` // Pembayaran yang dikembalikan
+	OrderID   string       `json:"order_id"`   // Pesanan yang dikembalikan
+	Lines     []RefundLine `json:"lines"`      // Baris item yang dikembalikan
+	Amount    Money        `json:"amount"`     // Total dana yang dikembalikan
+	Reason    string       `json:"reason"`     // Alasan refund
+	CreatedAt time.Time    `json:"created_at"` // Waktu refund dicatat
+
+	Method      PaymentMethod `json:"method,omitempty"`       // Metode pengembalian dana, sama dengan pembayaran asal
+	ProviderRef string        `json:"provider_ref,omitempty"` // Referensi refund dari gateway
+
+	Voucher     Money  `json:"voucher,omitempty"`      // Bagian refund yang dikembalikan ke saldo voucher hadiah asal, termasuk di Amount
+	VoucherCode string `json:"voucher_code,omitempty"` // Voucher hadiah yang saldonya dikembalikan
+	Deposit     Money  `json:"deposit,omitempty"`      // Bagian refund dari deposit reservasi, dikembalikan manual lewat gateway, termasuk di Amount
+}
+
+// Bagian refund yang dikembalikan lewat metode pembayaran asal, mis. tunai dari laci atau gateway
+func (r Refund) ProviderAmount() Money {
+	return r.Amount - r.Voucher - r.Deposit
+}
+
+// Struct untuk baris item pada refund
+type RefundLine struct {
+	Line   int    `json:"line"`   // Indeks baris pada pesanan
+	Name   string `json:"name"`   // Nama item menu beserta modifier-nya
+	Qty    int    `json:"qty"`    // Jumlah yang dikembalikan
+	Amount Money  `json:"amount"` // Nilai refund untuk baris ini
+}
+
+// Error yang dikembalikan saat mengelola pesanan
+var (
+	errOrderNotFound    = errors.New("Pesanan tidak ditemukan")
+	errOrderNotPending  = errors.New("Pesanan sudah disajikan atau dibatalkan")
+	errOrderNotPaid     = errors.New("Pesanan belum dibayar")
+	errOrderPaid        = errors.New("Pesanan sudah dibayar dengan pembayaran lain")
+	errIdempotencyReuse = errors.New("Idempotency-Key sudah dipakai untuk pembayaran lain")
+	errNothingToRefund  = errors.New("Tidak ada item yang bisa dikembalikan")
+	errRefundQtyTooHigh = errors.New("Jumlah refund melebihi jumlah yang dibeli")
+
+	errRefundAmountTooHigh = errors.New("Nominal refund melebihi sisa dana yang bisa dikembalikan")
+)
+
+// Mendapatkan subtotal baris pesanan
+func (l OrderLine) Subtotal() Money {
+	return l.UnitPrice().Times(l.Qty)
+}
+
+// Mencari pesanan berdasarkan ID
+func (s *Store) Order(id string) (Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range s.data.Orders {
+		if o.ID == id {
+			return o, nil
+		}
+	}
+	return Order{}, errOrderNotFound
+}
+
+// Menyimpan pembayaran dan menandai pesanan terkait sebagai sudah dibayar
+// Pembayaran dengan ID yang sudah tersimpan dilewati, sama seperti SaveOrder
+func (s *Store) SavePayment(p Payment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.data.Payments, func(x Payment) bool { return x.ID == p.ID }) {
+		return nil
+	}
+	// Pesanan ditandai dibayar sebelum dicatat agar subscriber melihat pesanan yang sudah lunas,
+	// lalu dikembalikan jika jurnal gagal ditulis sehingga tidak ada pesanan lunas tanpa pembayaran
+	previous := map[string]string{}
+	for i := range s.data.Orders {
+		for _, id := range p.OrderIDs {
+			if s.data.Orders[i].ID == id {
+				previous[id] = s.data.Orders[i].PaymentID
+				s.data.Orders[i].PaymentID = p.ID
+			}
+		}
+	}
+	if err := s.record(EventPaymentSaved, p); err != nil {
+		for id, paymentID := range previous {
+			s.findOrder(id).PaymentID = paymentID
+		}
+		return err
+	}
+	s.data.Payments = append(s.data.Payments, p)
+	s.closeTables(p)
+	s.applyDeposit(p)
+	s.redeemGiftVoucher(p)
+	return s.save()
+}
+
+// Mencari pembayaran checkout sebelumnya berdasarkan Idempotency-Key atau ID pembayaran
+// Key yang sama untuk ID pembayaran berbeda ditolak agar satu key tidak pernah menghasilkan dua pembayaran
+func (s *Store) IdempotentPayment(key, id string) (Payment, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.data.Payments {
+		if key != "" && p.IdempotencyKey == key {
+			if p.ID != id {
+				return Payment{}, false, errIdempotencyReuse
+			}
+			return p, true, nil
+		}
+	}
+	for _, p := range s.data.Payments {
+		if p.ID == id {
+			return p, true, nil
+		}
+	}
+	return Payment{}, false, nil
+}
+
+// Memeriksa apakah pembayaran dengan ID tertentu sudah tersimpan
+func (s *Store) HasPayment(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.data.Payments {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Mengambil salinan seluruh pembayaran
+func (s *Store) Payments() []Payment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Payment(nil), s.data.Payments...)
+}
+
+// Mengambil salinan seluruh refund
+func (s *Store) Refunds() []Refund {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Refund(nil), s.data.Refunds...)
+}
+
+// Menandai pesanan sebagai sudah disajikan
+func (s *Store) ServeOrder(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(id)
+	if order == nil {
+		return errOrderNotFound
+	}
+	if order.Status != OrderPending {
+		return errOrderNotPending
+	}
+	if err := s.record(EventOrderServed, eventOrderRef{OrderID: id}); err != nil {
+		return err
+	}
+	order.Status = OrderServed
+	return s.save()
+}
+
+// Menandai pesanan yang belum disajikan sebagai dibatalkan
+// Pengembalian dana dilakukan terlebih dahulu oleh cancelOrder
+func (s *Store) CancelOrder(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(id)
+	if order == nil {
+		return errOrderNotFound
+	}
+	if order.Status != OrderPending {
+		return errOrderNotPending
+	}
+	if err := s.record(EventOrderCancelled, eventOrderRef{OrderID: id}); err != nil {
+		return err
+	}
+	order.Status = OrderCancelled
+	return s.save()
+}
+
+// Menyusun refund pesanan yang sudah dibayar tanpa menyimpannya
+// lines berisi nama item dan jumlah yang dikembalikan; nil berarti seluruh sisa item
+// amount lebih dari 0 berarti nominal refund ditentukan sendiri (refund sebagian)
+func (s *Store) PrepareRefund(id string, lines map[string]int, amount Money, reason string) (Refund, Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(id)
+	if order == nil {
+		return Refund{}, Payment{}, errOrderNotFound
+	}
+	if order.PaymentID == "" {
+		return Refund{}, Payment{}, errOrderNotPaid
+	}
+	var payment Payment
+	for _, p := range s.data.Payments {
+		if p.ID == order.PaymentID {
+			payment = p
+		}
+	}
+	refundable := s.refundable(order)
+	if refundable <= 0 {
+		return Refund{}, Payment{}, errNothingToRefund
+	}
+
+	var refund Refund
+	if lines == nil && amount > 0 {
+		refund = Refund{ID: newID("RFD"), PaymentID: order.PaymentID, OrderID: order.ID, Reason: reason, CreatedAt: time.Now()}
+	} else {
+		var err error
+		if refund, err = s.buildRefund(order, lines, reason); err != nil {
+			return Refund{}, Payment{}, err
+		}
+	}
+	switch {
+	case amount > 0 && (amount > refundable || (lines != nil && amount > refund.Amount)):
+		return Refund{}, Payment{}, errRefundAmountTooHigh
+	case amount > 0:
+		refund.Amount = amount
+	case lines == nil:
+		// Refund penuh setelah refund nominal hanya mengembalikan sisa dana
+		refund.Amount = min(refund.Amount, refundable)
+	case refund.Amount > refundable:
+		return Refund{}, Payment{}, errRefundAmountTooHigh
+	}
+	if err := s.splitRefund(&refund, payment); err != nil {
+		return Refund{}, Payment{}, err
+	}
+	if refund.Amount <= 0 {
+		return Refund{}, Payment{}, errNothingToRefund
+	}
+	return refund, payment, nil
+}
+
+// Membagi refund ke sumber dana pembayaran; pemanggil harus memegang s.mu
+// Saldo voucher hadiah dikembalikan lebih dulu agar store credit tidak berubah menjadi uang tunai,
+// lalu metode pembayaran asal paling banyak sebesar yang ditagih (Payment.Amount), dan sisanya dari deposit reservasi
+// Refund sebelumnya untuk pembayaran yang sama mengurangi sisa setiap sumber
+func (s *Store) splitRefund(refund *Refund, payment Payment) error {
+	voucher, provider, deposit := payment.GiftVoucher, payment.Amount, payment.Deposit
+	for _, r := range s.data.Refunds {
+		if r.PaymentID == payment.ID {
+			voucher, provider, deposit = voucher-r.Voucher, provider-r.ProviderAmount(), deposit-r.Deposit
+		}
+	}
+	refund.Voucher, refund.VoucherCode, refund.Deposit = 0, "", 0
+	if payment.VoucherCode != "" && voucher > 0 {
+		refund.Voucher, refund.VoucherCode = min(refund.Amount, voucher), payment.VoucherCode
+	}
+	rest := refund.Amount - refund.Voucher - max(provider, 0)
+	if rest <= 0 {
+		return nil
+	}
+	refund.Deposit = min(rest, max(deposit, 0))
+	// Total pesanan bisa sedikit melebihi sumber dana jika tagihan dibulatkan ke bawah saat pembayaran
+	if excess := rest - refund.Deposit; excess > 0 {
+		if excess > max(-payment.Rounding, 0) {
+			return errRefundAmountTooHigh
+		}
+		refund.Amount -= excess
+	}
+	return nil
+}
+
+// Menyimpan refund yang sudah diproses penyedia pembayaran
+func (s *Store) SaveRefund(refund Refund) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(refund.OrderID)
+	if order == nil {
+		return errOrderNotFound
+	}
+	if refund.Amount > s.refundable(order) {
+		return errRefundAmountTooHigh
+	}
+	if err := s.record(EventRefundSaved, refund); err != nil {
+		return err
+	}
+	s.data.Refunds = append(s.data.Refunds, refund)
+	s.creditGiftVoucher(refund)
+	return s.save()
+}
+
+// Menghitung sisa dana pesanan yang masih bisa dikembalikan; pemanggil harus memegang s.mu
+func (s *Store) refundable(order *Order) Money {
+	remaining := order.Total
+	for _, r := range s.data.Refunds {
+		if r.OrderID == order.ID {
+			remaining -= r.Amount
+		}
+	}
+	return remaining
+}
+
+// Mencari pointer pesanan di dalam data; pemanggil harus memegang s.mu
+func (s *Store) findOrder(id string) *Order {
+	for i := range s.data.Orders {
+		if s.data.Orders[i].ID == id {
+			return &s.data.Orders[i]
+		}
+	}
+	return nil
+}
+
+// Menyusun refund dari sisa item yang belum dikembalikan
+// Refund per item mengambil dari baris pesanan dengan nama item tersebut secara berurutan
+// Pemanggil harus memegang s.mu
+func (s *Store) buildRefund(order *Order, lines map[string]int, reason string) (Refund, error) {
+	// Hitung jumlah yang masih bisa dikembalikan per baris pesanan
+	remaining := make([]int, len(order.Lines))
+	for i, line := range order.Lines {
+		remaining[i] = line.Qty
+	}
+	for _, r := range s.data.Refunds {
+		if r.OrderID != order.ID {
+			continue
+		}
+		for _, line := range r.Lines {
+			if line.Line < len(remaining) {
+				remaining[line.Line] -= line.Qty
+			}
+		}
+	}
+
+	refund := Refund{
+		ID:        newID("RFD"),
+		PaymentID: order.PaymentID,
+		OrderID:   order.ID,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	// Pajak dan pembulatan pesanan dibagi ke setiap baris sebanding harganya
+	ratio := 1.0
+	if order.Subtotal > 0 {
+		ratio = float64(order.Total) / float64(order.Subtotal)
+	}
+	addLine := func(i, qty int) {
+		line := order.Lines[i]
+		amount := line.UnitPrice().Times(qty).MulRate(ratio)
+		refund.Lines = append(refund.Lines, RefundLine{Line: i, Name: line.Label(), Qty: qty, Amount: amount})
+		refund.Amount += amount
+		remaining[i] -= qty
+	}
+
+	if lines == nil {
+		for i := range order.Lines {
+			if remaining[i] > 0 {
+				addLine(i, remaining[i])
+			}
+		}
+	} else {
+		for name, qty := range lines {
+			available, found := 0, false
+			for i, line := range order.Lines {
+				if strings.EqualFold(line.Item.Name, name) {
+					available += remaining[i]
+					found = true
+				}
+			}
+			if !found {
+				return Refund{}, fmt.Errorf("Item %s tidak ada di pesanan", name)
+			}
+			if qty <= 0 || qty > available {
+				return Refund{}, errRefundQtyTooHigh
+			}
+			for i, line := range order.Lines {
+				if qty == 0 {
+					break
+				}
+				if strings.EqualFold(line.Item.Name, name) && remaining[i] > 0 {
+					take := min(qty, remaining[i])
+					addLine(i, take)
+					qty -= take
+				}
+			}
+		}
+	}
+	if len(refund.Lines) == 0 {
+		return Refund{}, errNothingToRefund
+	}
+	return refund, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Struct untuk kriteria penelusuran pesanan
+// Field yang kosong tidak dipakai sebagai filter
+type OrderQuery struct {
+	From, To time.Time   // Rentang tanggal pesanan (inklusif)
+	Table    string      // Nomor meja
+	Customer string      // Sebagian nama, nomor HP, atau ID pelanggan
+	Status   OrderStatus // Status pesanan
+}
+
+// Memeriksa apakah pesanan cocok dengan kriteria
+// Pelanggan dicari lewat daftar pelanggan agar bisa dicocokkan dengan nama atau nomor HP
+func (q OrderQuery) Match(o Order, customers map[string]Customer) bool {
+	created := o.CreatedAt.Local()
+	if !q.From.IsZero() && created.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && created.After(endOfDay(q.To)) {
+		return false
+	}
+	if q.Table != "" && !strings.EqualFold(o.Table, strings.TrimSpace(q.Table)) {
+		return false
+	}
+	if q.Status != "" && o.Status != q.Status {
+		return false
+	}
+	if q.Customer != "" {
+		c, ok := customers[o.CustomerID]
+		if !ok {
+			return false
+		}
+		phone := normalizePhone(q.Customer)
+		if !containsFold(c.Name, q.Customer) && c.ID != q.Customer && (phone == "" || !strings.Contains(c.Phone, phone)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Fungsi untuk mencari pesanan yang cocok, diurutkan dari yang terbaru
+func searchOrders(store *Store, q OrderQuery) []Order {
+	customers := map[string]Customer{}
+	for _, c := range store.Customers() {
+		customers[c.ID] = c
+	}
+	var found []Order
+	for _, o := range store.Orders() {
+		if q.Match(o, customers) {
+			found = append(found, o)
+		}
+	}
+	slices.SortFunc(found, func(a, b Order) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return found
+}
+
+// Ringkasan satu baris pesanan untuk daftar hasil pencarian
+func (o Order) Summary() string {
+	table := o.Table
+	if table == "" {
+		table = "-"
+	}
+	items := 0
+	for _, l := range o.Lines {
+		items += l.Qty
+	}
+	return fmt.Sprintf("%s  %s  meja %-4s %-10s %3d item  Rp%10.2f", o.ID, o.CreatedAt.Local().Format("2006-01-02 15:04"),
+		table, o.Status, items, o.Total)
+}
+
+// Fungsi untuk menampilkan detail pesanan beserta pembayaran dan refund-nya
+func printOrderDetail(w io.Writer, store *Store, o Order) {
+	fmt.Fprintln(w, "Pesanan   :", o.ID)
+	fmt.Fprintln(w, "Waktu     :", o.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(w, "Status    :", o.Status)
+	if o.Table != "" {
+		fmt.Fprintln(w, "Meja      :", o.Table)
+	}
+	if o.Type != "" {
+		fmt.Fprintln(w, "Jenis     :", o.Type.Label())
+	}
+	if o.DeliveryAddress != "" {
+		fmt.Fprintf(w, "Antar ke  : %s (ongkos kirim Rp%.2f)\n", o.DeliveryAddress, o.DeliveryFee)
+	}
+	if o.CustomerID != "" {
+		if c, err := store.Customer(o.CustomerID); err == nil {
+			fmt.Fprintf(w, "Pelanggan : %s (%s)\n", c.Name, c.Phone)
+		} else {
+			fmt.Fprintln(w, "Pelanggan :", o.CustomerID)
+		}
+	}
+	writeAmendments(w, store, o)
+	for _, l := range o.Lines {
+		fmt.Fprintf(w, "- %s x%d: Rp%.2f\n", l.Label(), l.Qty, l.Subtotal())
+		writePriceRuleNote(w, l)
+	}
+	writePriceBreakdown(w, []Order{o}, 0)
+	fmt.Fprintf(w, "Total     : Rp%.2f\n", o.Total)
+	if o.PaymentID == "" {
+		fmt.Fprintln(w, "Pembayaran: belum dibayar")
+		return
+	}
+	fmt.Fprintln(w, "Pembayaran:", o.PaymentID)
+	for _, r := range store.Refunds() {
+		if r.OrderID == o.ID {
+			fmt.Fprintf(w, "Refund %s: -Rp%.2f %s\n", r.ID, r.Amount, r.Reason)
+		}
+	}
+}
+
+// Fungsi untuk menjalankan "order list"
+func runOrderList(store *Store, args []string) error {
+	var q OrderQuery
+	fs := flag.NewFlagSet("order list", flag.ContinueOnError)
+	date := fs.String("date", "", "tanggal pesanan YYYY-MM-DD, atau \"kemarin\"/\"hari-ini\"")
+	from := fs.String("from", "", "tanggal awal YYYY-MM-DD")
+	to := fs.String("to", "", "tanggal akhir YYYY-MM-DD")
+	status := fs.String("status", "", "status pesanan: pending, served, atau cancelled")
+	limit := fs.Int("limit", 50, "jumlah pesanan yang ditampilkan, 0 untuk semua")
+	fs.StringVar(&q.Table, "table", "", "nomor meja")
+	fs.StringVar(&q.Customer, "customer", "", "nama, nomor HP, atau ID pelanggan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	parse := func(name, value string) (time.Time, error) {
+		switch strings.ToLower(value) {
+		case "":
+			return time.Time{}, nil
+		case "hari-ini", "today":
+			return time.Now(), nil
+		case "kemarin", "yesterday":
+			return time.Now().AddDate(0, 0, -1), nil
+		}
+		t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return t, fmt.Errorf("Format -%s harus YYYY-MM-DD", name)
+		}
+		return t, nil
+	}
+	var err error
+	if *date != "" {
+		if *from != "" || *to != "" {
+			return fmt.Errorf("-date tidak bisa digabung dengan -from/-to")
+		}
+		*from, *to = *date, *date
+	}
+	if q.From, err = parse("from", *from); err != nil {
+		return err
+	}
+	if q.To, err = parse("to", *to); err != nil {
+		return err
+	}
+	if !q.From.IsZero() {
+		y, m, d := q.From.Date()
+		q.From = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	}
+	if *status != "" {
+		q.Status = OrderStatus(*status)
+		if !slices.Contains([]OrderStatus{OrderPending, OrderServed, OrderCancelled}, q.Status) {
+			return fmt.Errorf("-status harus pending, served, atau cancelled")
+		}
+	}
+
+	found := searchOrders(store, q)
+	if len(found) == 0 {
+		fmt.Println("Tidak ada pesanan yang cocok.")
+		return nil
+	}
+	shown := found
+	if *limit > 0 && len(shown) > *limit {
+		shown = shown[:*limit]
+	}
+	for _, o := range shown {
+		fmt.Println(o.Summary())
+	}
+	fmt.Printf("%d dari %d pesanan ditampilkan. Detail dengan: order show <id>\n", len(shown), len(found))
+	return nil
+}
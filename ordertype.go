@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Jenis pesanan
+type OrderType string
+
+const (
+	OrderDineIn   OrderType = "dine_in"  // Makan di tempat, wajib nomor meja
+	OrderTakeaway OrderType = "takeaway" // Bawa pulang, tanpa meja
+	OrderDelivery OrderType = "delivery" // Diantar ke alamat pelanggan dengan ongkos kirim
+)
+
+var (
+	errTableRequired   = errors.New("Pesanan makan di tempat wajib memiliki nomor meja")
+	errAddressRequired = errors.New("Pesanan antar wajib memiliki alamat")
+	errUnknownType     = errors.New("Jenis pesanan tidak dikenal (dine_in, takeaway, atau delivery)")
+	errFeeNegative     = errors.New("Ongkos kirim tidak boleh negatif")
+	errFeeNotDelivery  = errors.New("Ongkos kirim hanya untuk pesanan antar")
+)
+
+// Mendapatkan jenis pesanan; pesanan lama tanpa jenis ditentukan dari ada tidaknya nomor meja
+func (o Order) OrderType() OrderType {
+	switch {
+	case o.Type != "":
+		return o.Type
+	case o.Table != "":
+		return OrderDineIn
+	}
+	return OrderTakeaway
+}
+
+// Nama jenis pesanan untuk struk dan tiket dapur
+func (t OrderType) Label() string {
+	switch t {
+	case OrderDineIn:
+		return tr("Makan di tempat")
+	case OrderDelivery:
+		return tr("Antar")
+	}
+	return tr("Bawa pulang")
+}
+
+// Fungsi untuk memeriksa kelengkapan data sesuai jenis pesanan
+// Ongkos kirim hanya boleh ada di pesanan antar dan tidak boleh negatif
+func validateOrderType(order Order) error {
+	if order.DeliveryFee < 0 {
+		return errFeeNegative
+	}
+	if order.DeliveryFee != 0 && order.Type != OrderDelivery {
+		return errFeeNotDelivery
+	}
+	switch order.Type {
+	case "":
+		return nil
+	case OrderDineIn:
+		if order.Table == "" {
+			return errTableRequired
+		}
+	case OrderTakeaway:
+	case OrderDelivery:
+		if strings.TrimSpace(order.DeliveryAddress) == "" {
+			return errAddressRequired
+		}
+	default:
+		return errUnknownType
+	}
+	return nil
+}
+
+// Fungsi untuk memeriksa ongkos kirim dari permintaan tanpa prompt terhadap batas kewajaran
+// Tanpa kasir yang bisa mengonfirmasi, ongkos di luar batas langsung ditolak; ongkos bawaan restoran selalu diterima
+func checkDeliveryFee(restaurant *Restaurant, order Order) error {
+	if order.Type != OrderDelivery || order.DeliveryFee == restaurant.DeliveryFee {
+		return nil
+	}
+	if v, ok := checkPriceGuard(restaurant.PriceGuards, deliveryFeeCategory, "ongkos kirim", order.DeliveryFee); !ok {
+		return errors.New(v.Message)
+	}
+	return nil
+}
+
+// Fungsi untuk menanyakan jenis pesanan di awal input pesanan
+// Makan di tempat langsung menanyakan meja, antar menanyakan alamat dan ongkos kirim
+func promptOrderType(restaurant *Restaurant, order *Order) error {
+	types := []OrderType{OrderDineIn, OrderTakeaway, OrderDelivery}
+	labels := make([]string, len(types))
+	for i, t := range types {
+		labels[i] = t.Label()
+	}
+	printChoices(tr("Jenis pesanan:\n"), tr("Jenis"), labels)
+	for order.Type == "" {
+		choice, err := readLineErr(tr("Nomor pilihan (Enter untuk opsi 1): "))
+		if err != nil {
+			return err
+		}
+		switch choice {
+		case "", "1":
+			order.Type = OrderDineIn
+		case "2":
+			order.Type = OrderTakeaway
+		case "3":
+			order.Type = OrderDelivery
+		default:
+			fmt.Println(tr("Pilihan tidak valid. Coba lagi."))
+		}
+	}
+
+	switch order.Type {
+	case OrderDineIn:
+		for order.Table == "" {
+			table, err := readLineErr(tr("Nomor meja:"))
+			if err != nil {
+				return err
+			}
+			if order.Table = strings.TrimSpace(table); order.Table == "" {
+				fmt.Println(errTableRequired)
+			}
+		}
+	case OrderDelivery:
+		for order.DeliveryAddress == "" {
+			address, err := readLineErr(tr("Alamat pengantaran:"))
+			if err != nil {
+				return err
+			}
+			if order.DeliveryAddress = strings.TrimSpace(address); order.DeliveryAddress == "" {
+				fmt.Println(errAddressRequired)
+			}
+		}
+		order.DeliveryFee = restaurant.DeliveryFee
+		for {
+			fee, err := readLineErr(tr("Ongkos kirim (Enter untuk Rp%.0f):", restaurant.DeliveryFee))
+			if err != nil {
+				return err
+			}
+			if fee == "" {
+				break
+			}
+			if order.DeliveryFee, err = validatePrice(fee); err == nil {
+				override, ok := confirmOpenPrice(restaurant, deliveryFeeCategory, tr("Ongkos kirim"), order.DeliveryFee)
+				if !ok {
+					order.DeliveryFee = restaurant.DeliveryFee
+					continue
+				}
+				if override != "" {
+					order.Overrides = append(order.Overrides, override)
+				}
+				break
+			}
+			fmt.Println(tr("Input pembayaran tidak valid. Harap masukkan angka yang benar."))
+		}
+	}
+	return nil
+}
+
+// Fungsi untuk menulis jenis pesanan beserta meja atau alamat pengantaran
+func writeOrderType(w io.Writer, order Order) {
+	switch t := order.OrderType(); t {
+	case OrderDineIn:
+		if order.Table != "" {
+			fmt.Fprintln(w, tr("Meja:"), order.Table)
+		} else {
+			fmt.Fprintln(w, t.Label())
+		}
+	case OrderDelivery:
+		fmt.Fprintln(w, t.Label()+":", order.DeliveryAddress)
+	default:
+		fmt.Fprintln(w, t.Label())
+	}
+}
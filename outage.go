@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+var errNotPaused = errors.New("Pesanan online tidak sedang dijeda")
+
+// Struct untuk Konfigurasi jeda pesanan online saat dapur kewalahan atau bahan habis
+type OutageConfig struct {
+	Channels []OutageChannel `json:"channels"` // Kanal pemesanan online yang ikut dijeda, mis. GoFood dan GrabFood
+}
+
+// Struct untuk satu kanal pemesanan online
+// Jeda dan lanjut dikirim sebagai POST JSON {"paused","reason","resume_at"} ke API adapter kanal
+type OutageChannel struct {
+	Name      string `json:"name"`       // Nama kanal, mis. "gofood"
+	PauseURL  string `json:"pause_url"`  // Endpoint adapter untuk menutup toko di kanal
+	ResumeURL string `json:"resume_url"` // Endpoint adapter untuk membuka kembali, kosong berarti sama dengan pause_url
+	Token     string `json:"token"`      // Token Bearer untuk API adapter, boleh kosong
+}
+
+// Struct untuk Jeda pesanan online yang sedang berlaku
+// Banner ditampilkan di halaman status publik yang ditautkan dari QR menu di meja
+type StorePause struct {
+	Reason   string    `json:"reason"`             // Alasan jeda untuk audit, mis. "gas habis"
+	Banner   string    `json:"banner"`             // Pesan untuk pelanggan
+	Channels []string  `json:"channels"`           // Kanal yang berhasil dijeda
+	PausedAt time.Time `json:"paused_at"`          // Waktu jeda dimulai
+	ResumeAt time.Time `json:"resume_at,omitzero"` // Waktu dibuka kembali otomatis, kosong berarti sampai "resume"
+	PausedBy string    `json:"paused_by"`          // Staf yang menjeda
+}
+
+// Menandakan jeda sedang berlaku
+func (p StorePause) Active() bool {
+	return !p.PausedAt.IsZero()
+}
+
+// Mengambil jeda pesanan online yang sedang berlaku
+func (s *Store) Pause() StorePause {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.Pause
+}
+
+// Menyimpan jeda pesanan online
+func (s *Store) SetPause(p StorePause) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Pause = p
+	return s.save()
+}
+
+// Fungsi untuk mengirim status jeda ke API adapter satu kanal
+func notifyOutageChannel(ch OutageChannel, paused bool, p StorePause) error {
+	url := ch.PauseURL
+	if !paused && ch.ResumeURL != "" {
+		url = ch.ResumeURL
+	}
+	body, _ := json.Marshal(map[string]any{"paused": paused, "reason": p.Reason, "resume_at": p.ResumeAt})
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if ch.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+ch.Token)
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("adapter membalas %s", resp.Status)
+	}
+	return nil
+}
+
+// Fungsi untuk menjeda kanal online dan memasang banner di halaman status
+// Kanal yang gagal dijeda ditampilkan agar kasir menutupnya manual dari aplikasi mitra
+func pauseOrdering(cfg *Config, store *Store, p StorePause, only []string) error {
+	for _, ch := range cfg.Outage.Channels {
+		if len(only) > 0 && !slices.Contains(only, ch.Name) {
+			continue
+		}
+		if err := notifyOutageChannel(ch, true, p); err != nil {
+			fmt.Printf("Kanal %s gagal dijeda: %v\n", ch.Name, err)
+			continue
+		}
+		p.Channels = append(p.Channels, ch.Name)
+		fmt.Println("Kanal", ch.Name, "dijeda")
+	}
+	return store.SetPause(p)
+}
+
+// Fungsi untuk membuka kembali kanal yang dijeda dan melepas banner
+func resumeOrdering(cfg *Config, store *Store, actor Staff) error {
+	p := store.Pause()
+	if !p.Active() {
+		return errNotPaused
+	}
+	for _, ch := range cfg.Outage.Channels {
+		if !slices.Contains(p.Channels, ch.Name) {
+			continue
+		}
+		if err := notifyOutageChannel(ch, false, p); err != nil {
+			fmt.Printf("Kanal %s gagal dibuka kembali: %v\n", ch.Name, err)
+			continue
+		}
+		fmt.Println("Kanal", ch.Name, "dibuka kembali")
+	}
+	if err := store.SetPause(StorePause{}); err != nil {
+		return err
+	}
+	return store.Audit(actor, "outage.resume", strings.Join(p.Channels, ","), p.Reason)
+}
+
+// Fungsi untuk membuka kembali pesanan online yang waktu jedanya sudah lewat
+func resumeDuePause(cfg *Config, store *Store, now time.Time) error {
+	p := store.Pause()
+	if !p.Active() || p.ResumeAt.IsZero() || now.Before(p.ResumeAt) {
+		return nil
+	}
+	fmt.Println("Jeda pesanan online berakhir, kanal dibuka kembali")
+	return resumeOrdering(cfg, store, Staff{Name: "system"})
+}
+
+// Fungsi untuk membuka kembali pesanan online sesuai jadwal selama server berjalan
+func runPauseExpiry(cfg *Config, store *Store) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		if err := resumeDuePause(cfg, store, now); err != nil {
+			fmt.Println("Gagal membuka kembali pesanan online:", err)
+		}
+	}
+}
+
+// Struct untuk isi halaman status publik
+type statusPage struct {
+	Open     bool      `json:"open"`               // Pesanan online diterima
+	Banner   string    `json:"banner,omitempty"`   // Pesan untuk pelanggan
+	Channels []string  `json:"channels,omitempty"` // Kanal yang dijeda
+	ResumeAt time.Time `json:"resume_at,omitzero"` // Perkiraan buka kembali
+}
+
+// GET /status
+// Halaman publik untuk pelanggan, ditautkan dari QR menu dan profil toko di aplikasi mitra
+func (s *Server) handleStatusPage(w http.ResponseWriter, r *http.Request) {
+	p := s.store.Pause()
+	page := statusPage{Open: !p.Active(), Banner: p.Banner, Channels: p.Channels, ResumeAt: p.ResumeAt}
+	if r.URL.Query().Get("format") == "json" {
+		writeJSON(w, http.StatusOK, page)
+		return
+	}
+	var b strings.Builder
+	if page.Open {
+		b.WriteString("Kami buka dan menerima pesanan.")
+	} else {
+		b.WriteString(page.Banner)
+		if !page.ResumeAt.IsZero() {
+			fmt.Fprintf(&b, "\nPesanan online dibuka kembali pukul %s.", page.ResumeAt.Local().Format("15:04"))
+		}
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><meta http-equiv="refresh" content="60"><meta name="viewport" content="width=device-width"><title>Status</title></head>
+<body style="font-family:sans-serif;font-size:1.5em"><pre style="white-space:pre-wrap">%s</pre></body></html>`, html.EscapeString(b.String()))
+}
+
+// Fungsi untuk membaca waktu buka kembali dari -for (mis. 45m) atau -until (HH:MM hari ini)
+func parseResumeAt(now time.Time, duration, until string) (time.Time, error) {
+	switch {
+	case duration != "" && until != "":
+		return time.Time{}, errors.New("Pilih salah satu dari -for atau -until")
+	case duration != "":
+		d, err := time.ParseDuration(duration)
+		if err != nil || d <= 0 {
+			return time.Time{}, fmt.Errorf("Lama jeda tidak valid: %s", duration)
+		}
+		return now.Add(d), nil
+	case until != "":
+		t, err := time.ParseInLocation("15:04", until, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Jam -until tidak valid: %s", until)
+		}
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+	return time.Time{}, nil
+}
+
+// Fungsi untuk menjalankan perintah "pause"
+func runPauseCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) > 0 && args[0] == "status" {
+		if err := resumeDuePause(cfg, store, time.Now()); err != nil {
+			return err
+		}
+		p := store.Pause()
+		if !p.Active() {
+			fmt.Println("Pesanan online berjalan normal")
+			return nil
+		}
+		fmt.Printf("Dijeda sejak %s oleh %s: %s\n", p.PausedAt.Local().Format("15:04"), p.PausedBy, p.Reason)
+		fmt.Println("Banner :", p.Banner)
+		fmt.Println("Kanal  :", strings.Join(p.Channels, ", "))
+		if !p.ResumeAt.IsZero() {
+			fmt.Println("Buka kembali otomatis:", p.ResumeAt.Local().Format("2006-01-02 15:04"))
+		}
+		return nil
+	}
+	fs := flag.NewFlagSet("pause", flag.ContinueOnError)
+	reason := fs.String("reason", "", "alasan jeda, mis. \"dapur penuh\" atau \"gas habis\"")
+	banner := fs.String("banner", "", "pesan untuk pelanggan di halaman status, default dari alasan")
+	duration := fs.String("for", "", "lama jeda, mis. 45m atau 2h")
+	until := fs.String("until", "", "jam buka kembali HH:MM")
+	only := fs.String("channels", "", "hanya jeda kanal ini, pisahkan dengan koma; default semua kanal")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *reason == "" {
+		return fmt.Errorf("Gunakan: pause -reason <alasan> [-banner <pesan>] [-for 45m | -until HH:MM] [-channels gofood,grabfood]")
+	}
+	now := time.Now()
+	resumeAt, err := parseResumeAt(now, *duration, *until)
+	if err != nil {
+		return err
+	}
+	if store.Pause().Active() {
+		return errors.New("Pesanan online sudah dijeda, jalankan \"resume\" lebih dulu")
+	}
+	staff, err := login(newLocalBackend(cfg, store), "Masukkan PIN untuk menjeda pesanan online.")
+	if err != nil {
+		return err
+	}
+	if *banner == "" {
+		*banner = fmt.Sprintf("Maaf, pesanan online sedang kami tutup sementara (%s).", *reason)
+	}
+	var channels []string
+	for _, name := range strings.Split(*only, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			channels = append(channels, name)
+		}
+	}
+	p := StorePause{Reason: *reason, Banner: *banner, PausedAt: now, ResumeAt: resumeAt, PausedBy: staff.Name}
+	if err := pauseOrdering(cfg, store, p, channels); err != nil {
+		return err
+	}
+	if !resumeAt.IsZero() {
+		fmt.Println("Pesanan online dibuka kembali otomatis pukul", resumeAt.Local().Format("15:04"), "selama server berjalan")
+	}
+	return store.Audit(staff, "outage.pause", strings.Join(store.Pause().Channels, ","), *reason)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Struct untuk Konfigurasi satu outlet milik pemilik yang sama
+// Setiap outlet memakai file data sendiri, sehingga pesanan, nomor antrean, dan laporannya terpisah
+type OutletConfig struct {
+	ID           string         `json:"id"`                // ID outlet untuk --outlet dan header X-Outlet, mis. "kemang"
+	Name         string         `json:"name"`              // Nama outlet di laporan
+	DataFile     string         `json:"data_file"`         // File data outlet, default "data-<id>.json"
+	Pricing      *PricingConfig `json:"pricing,omitempty"` // Pajak dan pembulatan outlet, kosong berarti mengikuti pricing utama
+	Menu         []MenuOverride `json:"menu"`              // Perubahan menu utama yang hanya berlaku di outlet ini
+	IDBranch     string         `json:"id_branch"`         // Kode cabang di ID sequence, default ids.branch ditambah ID outlet
+	SequenceFile string         `json:"sequence_file"`     // File nomor urut ID outlet, default "id-sequence-<id>.json"
+}
+
+// Struct untuk perubahan satu item menu di outlet
+type MenuOverride struct {
+	Item   string `json:"item"`             // Nama item di menu utama
+	Price  *Money `json:"price,omitempty"`  // Harga di outlet ini, kosong berarti harga menu utama
+	Remove bool   `json:"remove,omitempty"` // Item tidak dijual di outlet ini
+}
+
+// Header yang dipakai terminal dan sistem lain untuk memilih outlet di server bersama
+const outletHeader = "X-Outlet"
+
+var errUnknownOutlet = errors.New("Outlet tidak dikenal")
+
+// Nama outlet untuk laporan, ID jika nama kosong
+func (o OutletConfig) Label() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	return o.ID
+}
+
+// Mengisi nilai default outlet dari konfigurasi utama
+func (o *OutletConfig) applyDefaults(c *Config) {
+	if o.Pricing != nil {
+		o.Pricing.applyDefaults()
+	}
+	if o.DataFile == "" {
+		o.DataFile = "data-" + o.ID + ".json"
+	}
+	if o.IDBranch == "" {
+		o.IDBranch = c.IDs.Branch + "-" + o.ID
+	}
+	if o.SequenceFile == "" {
+		o.SequenceFile = "id-sequence-" + o.ID + ".json"
+	}
+}
+
+// Memeriksa daftar outlet: ID dan file data wajib unik dan tidak sama dengan file data utama
+func validateOutlets(c *Config) error {
+	if len(c.Outlets) > 0 && c.Storage.Driver != "" && c.Storage.Driver != "file" {
+		return fmt.Errorf("outlets belum bisa dipakai bersama storage.driver %s", c.Storage.Driver)
+	}
+	seen := map[string]bool{}
+	files := map[string]bool{c.DataFile: true}
+	for _, o := range c.Outlets {
+		if o.ID == "" || strings.ContainsAny(o.ID, " /\\") {
+			return fmt.Errorf("outlets: ID outlet %q tidak valid", o.ID)
+		}
+		if seen[strings.ToLower(o.ID)] {
+			return fmt.Errorf("outlets: outlet %q ditulis lebih dari sekali", o.ID)
+		}
+		seen[strings.ToLower(o.ID)] = true
+		if files[o.DataFile] {
+			return fmt.Errorf("outlets: file data %s dipakai lebih dari satu outlet", o.DataFile)
+		}
+		files[o.DataFile] = true
+		if o.Pricing != nil {
+			if err := o.Pricing.validate(); err != nil {
+				return fmt.Errorf("outlets %s: %w", o.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Mengambil konfigurasi untuk satu outlet: file data, pajak, dan penomoran ID outlet menggantikan milik konfigurasi utama
+func (c *Config) forOutlet(id string) (*Config, error) {
+	i := slices.IndexFunc(c.Outlets, func(o OutletConfig) bool { return strings.EqualFold(o.ID, id) })
+	if i < 0 {
+		ids := make([]string, len(c.Outlets))
+		for j, o := range c.Outlets {
+			ids[j] = o.ID
+		}
+		return nil, fmt.Errorf("%w: %s (tersedia: %s)", errUnknownOutlet, id, strings.Join(ids, ", "))
+	}
+	o := c.Outlets[i]
+	oc := *c
+	oc.DataFile = o.DataFile
+	if o.Pricing != nil {
+		oc.Pricing = *o.Pricing
+	}
+	oc.IDs.Branch, oc.IDs.SequenceFile = o.IDBranch, o.SequenceFile
+	oc.Client.Outlet = o.ID
+	oc.outlet, oc.mainDataFile = &o, c.DataFile
+	return &oc, nil
+}
+
+// Fungsi untuk membuka file data sesuai konfigurasi
+// Outlet yang belum memiliki menu sendiri memakai menu di file data utama beserta perubahan menunya
+func openConfigStore(cfg *Config) (*Store, error) {
+	store, err := openStore(cfg.DataFile)
+	if err != nil || cfg.outlet == nil {
+		return store, err
+	}
+	main, err := openStore(cfg.mainDataFile)
+	if err != nil {
+		return nil, err
+	}
+	store.baseMenu, store.menuOverrides = main.data.Menu, cfg.outlet.Menu
+	return store, nil
+}
+
+// Menerapkan perubahan menu outlet: item dihapus atau harganya diganti
+func applyMenuOverrides(restaurant *Restaurant, overrides []MenuOverride) {
+	for _, o := range overrides {
+		if o.Remove {
+			restaurant.Menu = slices.DeleteFunc(restaurant.Menu, func(m MenuItem) bool { return strings.EqualFold(m.Name, o.Item) })
+			continue
+		}
+		if item := restaurant.findMenuItem(o.Item); item != nil && o.Price != nil {
+			item.Price = *o.Price
+		}
+	}
+}
+
+// Handler yang meneruskan request ke server outlet sesuai header X-Outlet, tanpa header ke server utama
+func (s *Server) outletRouter(main http.Handler) http.Handler {
+	routes := make(map[string]http.Handler, len(s.outlets))
+	for id, o := range s.outlets {
+		routes[strings.ToLower(id)] = o.routes()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSpace(r.Header.Get(outletHeader))
+		if id == "" {
+			main.ServeHTTP(w, r)
+			return
+		}
+		h, ok := routes[strings.ToLower(id)]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s: %s", errUnknownOutlet, id))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Fungsi untuk menyiapkan server setiap outlet yang dilayani bersama server utama
+func (s *Server) openOutlets() error {
+	s.outlets = map[string]*Server{}
+	for _, o := range s.cfg.Outlets {
+		cfg, err := s.cfg.forOutlet(o.ID)
+		if err != nil {
+			return err
+		}
+		store, err := openConfigStore(cfg)
+		if err != nil {
+			return fmt.Errorf("Outlet %s: %w", o.ID, err)
+		}
+		s.outlets[o.ID] = newServer(cfg, store)
+	}
+	return nil
+}
+
+// Struct untuk baris laporan gabungan satu outlet
+type OutletSales struct {
+	Outlet   string // Nama outlet
+	Orders   int    // Jumlah pesanan yang dibayar
+	Sales    Money  // Total pembayaran
+	Tax      Money  // Pajak
+	Refunded Money  // Total refund
+}
+
+// Penjualan bersih setelah refund
+func (o OutletSales) Net() Money {
+	return o.Sales - o.Refunded
+}
+
+// Fungsi untuk menjumlahkan rekap penjualan harian satu file data dalam rentang tanggal (inklusif)
+func outletSales(name string, store *Store, from, to time.Time) OutletSales {
+	sales := OutletSales{Outlet: name}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		p := store.DailyProjection(day)
+		sales.Orders += p.Orders
+		sales.Sales += p.GrossSales
+		sales.Tax += p.Tax
+		sales.Refunded += p.RefundedAmount
+	}
+	return sales
+}
+
+// Fungsi untuk menyusun laporan gabungan seluruh outlet; file data utama ikut dihitung jika berisi pesanan
+func buildOutletReport(cfg *Config, from, to time.Time) ([]OutletSales, error) {
+	var rows []OutletSales
+	main, err := openStore(cfg.DataFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(main.Orders()) > 0 || len(cfg.Outlets) == 0 {
+		rows = append(rows, outletSales("(utama)", main, from, to))
+	}
+	for _, o := range cfg.Outlets {
+		store, err := openStore(o.DataFile)
+		if err != nil {
+			return nil, fmt.Errorf("Outlet %s: %w", o.ID, err)
+		}
+		rows = append(rows, outletSales(o.Label(), store, from, to))
+	}
+	return rows, nil
+}
+
+// Fungsi untuk menampilkan laporan gabungan outlet beserta totalnya
+func printOutletReport(w io.Writer, rows []OutletSales, from, to time.Time) {
+	fmt.Fprintf(w, "Penjualan per Outlet %s s.d. %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	fmt.Fprintf(w, "%-20s %7s %15s %13s %13s %15s\n", "Outlet", "Pesanan", "Penjualan", "Pajak", "Refund", "Bersih")
+	var total OutletSales
+	for _, r := range rows {
+		fmt.Fprintf(w, "%-20s %7d %15.2f %13.2f %13.2f %15.2f\n", r.Outlet, r.Orders, r.Sales, r.Tax, r.Refunded, r.Net())
+		total.Orders += r.Orders
+		total.Sales += r.Sales
+		total.Tax += r.Tax
+		total.Refunded += r.Refunded
+	}
+	fmt.Fprintf(w, "%-20s %7d %15.2f %13.2f %13.2f %15.2f\n", "Total", total.Orders, total.Sales, total.Tax, total.Refunded, total.Net())
+}
+
+// Fungsi untuk menjalankan "report outlets"
+// Selalu membaca konfigurasi utama, sehingga --outlet tidak membatasi laporan ke satu outlet
+func runOutletReport(cfg *Config, args []string) error {
+	fs := flag.NewFlagSet("report outlets", flag.ContinueOnError)
+	dates := addRangeFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, to, err := dates.parse()
+	if err != nil {
+		return err
+	}
+	if cfg.outlet != nil {
+		cfg = &Config{DataFile: cfg.mainDataFile, Outlets: cfg.Outlets}
+	}
+	rows, err := buildOutletReport(cfg, from, to)
+	if err != nil {
+		return err
+	}
+	printOutletReport(os.Stdout, rows, from, to)
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Metode pembayaran
+type PaymentMethod string
+
+const (
+	MethodCash PaymentMethod = "cash" // Tunai dari laci kasir
+	MethodCard PaymentMethod = "card" // Kartu debit/kredit melalui gateway
+	MethodQRIS PaymentMethod = "qris" // QRIS melalui gateway
+)
+
+// Interface untuk penyedia pembayaran
+// Setiap metode pembayaran mengembalikan dana melalui jalurnya sendiri
+type PaymentProvider interface {
+	Method() PaymentMethod                                         // Metode yang ditangani
+	Refund(payment Payment, refund Refund) (ref string, err error) // Mengembalikan dana, menghasilkan referensi refund penyedia
+}
+
+var (
+	errNoProvider      = errors.New("Tidak ada penyedia pembayaran untuk metode ini")
+	errGatewayDisabled = errors.New("Gateway pembayaran belum dikonfigurasi")
+)
+
+// Mendapatkan metode pembayaran; data lama tanpa metode dianggap tunai
+func (p Payment) PaymentMethod() PaymentMethod {
+	if p.Method == "" {
+		return MethodCash
+	}
+	return p.Method
+}
+
+// Struct untuk pengembalian dana tunai dari laci kasir
+type cashProvider struct {
+	denominations func() []int // Pecahan yang sedang tersedia di laci
+}
+
+func (p cashProvider) Method() PaymentMethod { return MethodCash }
+
+// Dana tunai diambil dari laci; kasir diberi saran pecahan yang diserahkan
+func (p cashProvider) Refund(payment Payment, refund Refund) (string, error) {
+	fmt.Printf("Serahkan tunai dari laci: Rp%.2f\n", refund.Amount)
+	printChangeBreakdown(refund.Amount, p.denominations())
+	return "", nil
+}
+
+// Struct untuk pengembalian dana kartu/QRIS melalui gateway pembayaran
+type gatewayProvider struct {
+	method  PaymentMethod
+	gateway PaymentGateway // nil jika gateway belum dikonfigurasi
+}
+
+func (p gatewayProvider) Method() PaymentMethod { return p.method }
+
+// Mengirim permintaan refund ke gateway
+// ID refund dipakai sebagai idempotency key agar refund yang diulang tidak tercatat dua kali
+func (p gatewayProvider) Refund(payment Payment, refund Refund) (string, error) {
+	if p.gateway == nil {
+		return "", errGatewayDisabled
+	}
+	if payment.ProviderRef == "" {
+		return "", fmt.Errorf("Pembayaran %s tidak memiliki referensi gateway", payment.ID)
+	}
+	ref, err := p.gateway.Refund(payment.ProviderRef, refund.Amount, refund.ID, refund.Reason)
+	if err != nil {
+		return "", fmt.Errorf("Gateway menolak refund: %w", err)
+	}
+	return ref, nil
+}
+
+// Kumpulan penyedia pembayaran berdasarkan metode
+type paymentProviders map[PaymentMethod]PaymentProvider
+
+// Fungsi untuk membuat penyedia pembayaran sesuai konfigurasi
+func newPaymentProviders(cfg *Config, store *Store) (paymentProviders, error) {
+	gateway, err := newPaymentGateway(cfg.PaymentGateway)
+	if err != nil {
+		return nil, err
+	}
+	providers := paymentProviders{}
+	for _, p := range []PaymentProvider{
+		cashProvider{denominations: func() []int { return store.DrawerDenominations(cfg) }},
+		gatewayProvider{method: MethodCard, gateway: gateway},
+		gatewayProvider{method: MethodQRIS, gateway: gateway},
+	} {
+		providers[p.Method()] = p
+	}
+	return providers, nil
+}
+
+// Mendapatkan penyedia untuk metode pembayaran
+func (ps paymentProviders) For(method PaymentMethod) (PaymentProvider, error) {
+	p, ok := ps[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errNoProvider, method)
+	}
+	return p, nil
+}
+
+// Fungsi untuk mengembalikan dana pesanan ke sumber dananya
+// Bagian voucher hadiah dikembalikan ke saldo voucher saat refund dicatat, bagian metode pembayaran asal
+// lewat penyedianya; refund hanya dicatat setelah penyedia pembayaran berhasil mengembalikan dana
+func refundOrder(providers paymentProviders, store *Store, id string, lines map[string]int, amount Money, reason string) (Refund, error) {
+	refund, payment, err := store.PrepareRefund(id, lines, amount, reason)
+	if err != nil {
+		return Refund{}, err
+	}
+	refund.Method = payment.PaymentMethod()
+	if share := refund.ProviderAmount(); share > 0 {
+		provider, err := providers.For(payment.PaymentMethod())
+		if err != nil {
+			return Refund{}, err
+		}
+		charged := refund
+		charged.Amount = share
+		ref, err := provider.Refund(payment, charged)
+		if err != nil {
+			return Refund{}, fmt.Errorf("Refund %s gagal: %w", payment.PaymentMethod(), err)
+		}
+		refund.ProviderRef = ref
+	}
+	return refund, store.SaveRefund(refund)
+}
+
+// Fungsi untuk membatalkan pesanan yang belum disajikan
+// Pesanan yang sudah dibayar di-refund penuh ke metode asalnya sebelum dibatalkan
+func cancelOrder(providers paymentProviders, store *Store, id, reason string) (*Refund, error) {
+	order, err := store.Order(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != OrderPending {
+		return nil, errOrderNotPending
+	}
+	var refund *Refund
+	if order.PaymentID != "" {
+		r, err := refundOrder(providers, store, id, nil, 0, reason)
+		if err != nil && !errors.Is(err, errNothingToRefund) {
+			return nil, err
+		}
+		if err == nil {
+			refund = &r
+		}
+	}
+	return refund, store.CancelOrder(id)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+var errPickupCodeNotFound = errors.New("Kode pengambilan tidak ditemukan")
+
+// Huruf kode pengambilan; huruf dan angka yang mirip (0/O, 1/I, 2/Z, 5/S, 8/B) tidak dipakai agar mudah dibaca dari struk
+const pickupAlphabet = "ACDEFGHJKLMNPQRTUVWXY34679"
+
+// Panjang kode pengambilan dan lama kode dapat dicek pelanggan setelah pesanan dibuat
+const (
+	pickupCodeLength = 5
+	pickupCodeTTL    = 24 * time.Hour
+)
+
+// Status pesanan yang ditampilkan ke pelanggan
+type PickupStatus string
+
+const (
+	PickupPreparing PickupStatus = "preparing" // Sedang dimasak
+	PickupReady     PickupStatus = "ready"     // Siap diambil
+	PickupServed    PickupStatus = "served"    // Sudah diambil atau disajikan
+	PickupCancelled PickupStatus = "cancelled" // Dibatalkan
+)
+
+// Keterangan status untuk pelanggan
+func (p PickupStatus) Label() string {
+	switch p {
+	case PickupReady:
+		return tr("Siap diambil")
+	case PickupServed:
+		return tr("Sudah diambil")
+	case PickupCancelled:
+		return tr("Dibatalkan")
+	}
+	return tr("Sedang dimasak")
+}
+
+// Status pengambilan pesanan dari status dan waktu siapnya
+func (o Order) PickupStatus() PickupStatus {
+	switch {
+	case o.Status == OrderCancelled:
+		return PickupCancelled
+	case o.Status == OrderServed:
+		return PickupServed
+	case !o.ReadyAt.IsZero():
+		return PickupReady
+	}
+	return PickupPreparing
+}
+
+// Struct untuk status pesanan yang boleh dilihat pelanggan tanpa login
+// Tidak berisi ID pesanan, pelanggan, atau harga
+type OrderStatusView struct {
+	Code             string       `json:"code"`
+	QueueNumber      int          `json:"queue_number,omitempty"`
+	Status           PickupStatus `json:"status"`
+	Label            string       `json:"label"`
+	EstimatedReadyAt time.Time    `json:"estimated_ready_at,omitzero"`
+	ReadyAt          time.Time    `json:"ready_at,omitzero"`
+}
+
+// Fungsi untuk menyusun status pesanan untuk pelanggan
+func newOrderStatusView(o Order) OrderStatusView {
+	status := o.PickupStatus()
+	v := OrderStatusView{Code: o.PickupCode, QueueNumber: o.QueueNumber, Status: status, Label: status.Label(), ReadyAt: o.ReadyAt}
+	if status == PickupPreparing {
+		v.EstimatedReadyAt = o.EstimatedReadyAt
+	}
+	return v
+}
+
+// Menampilkan status pesanan dalam bentuk teks
+func (v OrderStatusView) Print(w io.Writer) {
+	fmt.Fprint(w, tr("Pesanan %s", v.Code))
+	if v.QueueNumber > 0 {
+		fmt.Fprint(w, tr(" (antrean %03d)", v.QueueNumber))
+	}
+	fmt.Fprintln(w, ":", v.Label)
+	if !v.EstimatedReadyAt.IsZero() {
+		fmt.Fprint(w, tr("Perkiraan siap pukul %s\n", v.EstimatedReadyAt.Local().Format("15:04")))
+	}
+}
+
+// Fungsi untuk membuat kode pengambilan acak
+func newPickupCode() string {
+	b := make([]byte, pickupCodeLength)
+	rand.Read(b)
+	for i := range b {
+		b[i] = pickupAlphabet[int(b[i])%len(pickupAlphabet)]
+	}
+	return string(b)
+}
+
+// Fungsi untuk menyeragamkan kode yang diketik pelanggan, mis. "k7 x4m" menjadi "K7X4M"
+func normalizePickupCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), ""))
+}
+
+// Memberi kode pengambilan ke pesanan yang belum memilikinya; pemanggil harus memegang s.mu
+// Kode tidak sama dengan kode pesanan lain yang masih dapat dicek
+// Pesanan tambahan dari "order amend" dicek lewat kode pesanan asalnya sehingga tidak mendapat kode sendiri
+func (s *Store) assignPickupCodes(orders []Order) {
+	since := time.Now().Add(-pickupCodeTTL)
+	taken := map[string]bool{}
+	for _, o := range s.data.Orders {
+		if o.PickupCode != "" && o.CreatedAt.After(since) {
+			taken[o.PickupCode] = true
+		}
+	}
+	for i := range orders {
+		if orders[i].PickupCode != "" || orders[i].ParentID != "" {
+			continue
+		}
+		code := newPickupCode()
+		for taken[code] {
+			code = newPickupCode()
+		}
+		taken[code] = true
+		orders[i].PickupCode = code
+	}
+}
+
+// Mencari pesanan dari kode pengambilan; hanya pesanan yang dibuat dalam 24 jam terakhir yang dapat dicek
+// Pesanan yang masih menunggu tambahannya dimasak ditampilkan belum siap, dengan perkiraan waktu tambahan terakhir
+func (s *Store) OrderByPickupCode(code string) (Order, error) {
+	code = normalizePickupCode(code)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	since := time.Now().Add(-pickupCodeTTL)
+	for i := len(s.data.Orders) - 1; i >= 0; i-- {
+		o := s.data.Orders[i]
+		if code == "" || o.PickupCode != code || !o.CreatedAt.After(since) {
+			continue
+		}
+		for _, a := range s.data.Orders {
+			if a.ParentID != o.ID || a.Status != OrderPending || !a.ReadyAt.IsZero() || o.Status != OrderPending {
+				continue
+			}
+			o.ReadyAt = time.Time{}
+			if a.EstimatedReadyAt.After(o.EstimatedReadyAt) {
+				o.EstimatedReadyAt = a.EstimatedReadyAt
+			}
+		}
+		return o, nil
+	}
+	return Order{}, errPickupCodeNotFound
+}
+
+// GET /orders/status/{code}
+// Halaman cek status pesanan untuk pelanggan; hanya berisi status sehingga tidak memerlukan API key
+func (s *Server) handleOrderStatus(w http.ResponseWriter, r *http.Request) {
+	order, err := s.store.OrderByPickupCode(r.PathValue("code"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	v := newOrderStatusView(order)
+	if r.URL.Query().Get("format") == "json" {
+		writeJSON(w, http.StatusOK, v)
+		return
+	}
+	var b strings.Builder
+	v.Print(&b)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><meta http-equiv="refresh" content="15"><meta name="viewport" content="width=device-width"><title>Status Pesanan</title></head>
+<body style="font-family:sans-serif;font-size:1.5em"><pre style="white-space:pre-wrap">%s</pre></body></html>`, html.EscapeString(b.String()))
+}
+
+// Fungsi untuk menjalankan "order status <kode>"
+func runOrderStatus(store *Store, code string) error {
+	order, err := store.OrderByPickupCode(code)
+	if err != nil {
+		return err
+	}
+	newOrderStatusView(order).Print(os.Stdout)
+	return nil
+}
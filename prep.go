@@ -0,0 +1,187 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Perkiraan lama masak item yang belum memiliki prep_minutes
+func (c KitchenConfig) DefaultPrepTime() time.Duration {
+	return time.Duration(c.DefaultPrepMinutes) * time.Minute
+}
+
+// Mengatur perkiraan lama masak item menu dalam menit
+func (r *Restaurant) SetPrepTime(name string, minutes int) {
+	if item := r.findMenuItem(name); item != nil {
+		item.PrepMinutes = minutes
+	}
+}
+
+// Perkiraan lama masak satu item, memakai waktu bawaan dapur jika item belum diisi
+func (item MenuItem) PrepTime(fallback time.Duration) time.Duration {
+	if item.PrepMinutes > 0 {
+		return time.Duration(item.PrepMinutes) * time.Minute
+	}
+	return fallback
+}
+
+// Fungsi untuk memperkirakan lama masak satu pesanan
+// Baris pesanan dimasak bersamaan di stasiun masing-masing, sehingga yang menentukan adalah item terlama
+func orderPrepTime(o Order, fallback time.Duration) time.Duration {
+	var longest time.Duration
+	for _, l := range o.Lines {
+		longest = max(longest, l.Item.PrepTime(fallback))
+	}
+	return longest
+}
+
+// Mengisi perkiraan waktu siap pesanan dari lama masaknya dan antrean dapur saat ini
+// Antrean adalah pesanan hari ini yang sudah dibayar tetapi belum siap dan berada di depan pesanan ini, dibagi rata ke worker dapur
+// Pesanan berprioritas hanya menunggu pesanan yang didahulukan atau berprioritas sebelumnya
+// Perkiraan ditulis langsung ke slice orders; pesanan berikutnya dalam checkout yang sama ikut mengantre di belakangnya
+// Pesanan yang sudah memiliki perkiraan tidak dihitung ulang, tetapi lama masaknya tetap ikut antrean
+func (s *Store) EstimateReadyTimes(orders []Order, cfg KitchenConfig, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fallback := cfg.DefaultPrepTime()
+	queue := s.kitchenQueue(now)
+	workers := time.Duration(max(cfg.Workers, 1))
+	var added time.Duration
+	for i := range orders {
+		if !orders[i].EstimatedReadyAt.IsZero() {
+			added += orderPrepTime(orders[i], fallback)
+			continue
+		}
+		backlog := added
+		for _, o := range queue {
+			if compareQueue(o, orders[i]) < 0 {
+				backlog += remainingPrepTime(o, fallback, now)
+			}
+		}
+		prep := orderPrepTime(orders[i], fallback)
+		orders[i].PrepMinutes = int(prep.Round(time.Minute) / time.Minute)
+		orders[i].EstimatedReadyAt = now.Add(backlog/workers + prep).Truncate(time.Minute)
+		added += prep
+	}
+}
+
+// Fungsi untuk menampilkan perkiraan waktu siap setelah pesanan dikonfirmasi
+func printReadyEstimates(w io.Writer, orders []Order, now time.Time) {
+	for _, o := range orders {
+		if o.EstimatedReadyAt.IsZero() {
+			continue
+		}
+		minutes := int(max(o.EstimatedReadyAt.Sub(now), 0).Round(time.Minute) / time.Minute)
+		fmt.Fprint(w, tr("Perkiraan siap %s: pukul %s (sekitar %d menit)\n", o.ID, o.EstimatedReadyAt.Local().Format("15:04"), minutes))
+	}
+}
+
+// Struct untuk perbandingan perkiraan dan waktu siap sebenarnya
+// Waktu tunggu dihitung dari pembayaran sampai dapur menandai pesanan siap
+type PrepAccuracy struct {
+	Name      string        // Nama item, atau "Semua pesanan"
+	Orders    int           // Jumlah pesanan yang dapat diukur
+	Estimated time.Duration // Total perkiraan waktu tunggu
+	Actual    time.Duration // Total waktu tunggu sebenarnya
+	Late      int           // Pesanan yang siap setelah perkiraan
+}
+
+func (a *PrepAccuracy) add(estimated, actual time.Duration) {
+	a.Orders++
+	a.Estimated += estimated
+	a.Actual += actual
+	if actual > estimated+time.Minute {
+		a.Late++
+	}
+}
+
+// Rata-rata selisih waktu siap sebenarnya dengan perkiraan; positif berarti lebih lambat dari perkiraan
+func (a PrepAccuracy) AverageDelay() time.Duration {
+	if a.Orders == 0 {
+		return 0
+	}
+	return (a.Actual - a.Estimated) / time.Duration(a.Orders)
+}
+
+// Fungsi untuk membandingkan perkiraan dan waktu siap pesanan yang dibayar dalam rentang tanggal (inklusif)
+// Rekap per item mencakup setiap pesanan yang berisi item tersebut, diurutkan dari yang paling sering terlambat
+// Pesanan tanpa perkiraan atau yang belum siap dilewati
+func buildPrepAccuracy(store *Store, from, to time.Time) (perItem []PrepAccuracy, overall PrepAccuracy) {
+	payments := map[string]Payment{}
+	for _, p := range store.Payments() {
+		payments[p.ID] = p
+	}
+	items := map[string]*PrepAccuracy{}
+	overall.Name = "Semua pesanan"
+	for _, o := range store.Orders() {
+		p, ok := payments[o.PaymentID]
+		if !ok || o.EstimatedReadyAt.IsZero() || o.ReadyAt.IsZero() {
+			continue
+		}
+		if paid := p.PaidAt.Local(); paid.Before(from) || paid.After(endOfDay(to)) {
+			continue
+		}
+		estimated, actual := o.EstimatedReadyAt.Sub(p.PaidAt), o.ReadyAt.Sub(p.PaidAt)
+		overall.add(estimated, actual)
+		seen := map[string]bool{}
+		for _, l := range o.Lines {
+			if seen[l.Item.Name] {
+				continue
+			}
+			seen[l.Item.Name] = true
+			a := items[l.Item.Name]
+			if a == nil {
+				a = &PrepAccuracy{Name: l.Item.Name}
+				items[l.Item.Name] = a
+			}
+			a.add(estimated, actual)
+		}
+	}
+	for _, a := range items {
+		perItem = append(perItem, *a)
+	}
+	slices.SortFunc(perItem, func(a, b PrepAccuracy) int {
+		if c := cmp.Compare(b.AverageDelay(), a.AverageDelay()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return perItem, overall
+}
+
+// Fungsi untuk menampilkan laporan ketepatan perkiraan waktu siap
+func printPrepAccuracy(w io.Writer, perItem []PrepAccuracy, overall PrepAccuracy, from, to time.Time) {
+	fmt.Fprintf(w, "Perkiraan vs Waktu Siap %s s.d. %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if overall.Orders == 0 {
+		fmt.Fprintln(w, "Tidak ada pesanan dengan perkiraan waktu siap.")
+		return
+	}
+	fmt.Fprintf(w, "%-20s %7s %10s %10s %10s %9s\n", "Item", "Pesanan", "Perkiraan", "Aktual", "Selisih", "Terlambat")
+	for _, a := range append(perItem, overall) {
+		n := time.Duration(a.Orders)
+		fmt.Fprintf(w, "%-20s %7d %10s %10s %10s %8d%%\n", a.Name, a.Orders,
+			(a.Estimated / n).Round(time.Second), (a.Actual / n).Round(time.Second), a.AverageDelay().Round(time.Second), a.Late*100/a.Orders)
+	}
+}
+
+// Fungsi untuk menjalankan "report prep"
+func runPrepReport(store *Store, args []string) error {
+	fs := flag.NewFlagSet("report prep", flag.ContinueOnError)
+	dates := addRangeFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, to, err := dates.parse()
+	if err != nil {
+		return err
+	}
+	perItem, overall := buildPrepAccuracy(store, from, to)
+	printPrepAccuracy(os.Stdout, perItem, overall, from, to)
+	return nil
+}
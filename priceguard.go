@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errPriceRejected = errors.New("Harga di luar batas kewajaran tidak disetujui")
+
+// Kategori batas harga untuk ongkos kirim yang diketik kasir
+const deliveryFeeCategory = "Ongkos Kirim"
+
+// Struct untuk Batas kewajaran harga per kategori, mencegah salah ketik seperti minuman Rp250.000
+type PriceGuard struct {
+	Category       string `json:"category"`        // Kategori menu atau "Ongkos Kirim"; kosong berarti batas bawaan kategori lain
+	Min            Money  `json:"min"`             // Harga terendah yang wajar
+	Max            Money  `json:"max"`             // Harga tertinggi yang wajar, 0 berarti tanpa batas atas
+	RequireManager bool   `json:"require_manager"` // Harga di luar batas butuh persetujuan admin, bukan hanya peringatan
+}
+
+// Struct untuk satu harga yang keluar dari batas kewajaran
+type priceViolation struct {
+	Message        string
+	RequireManager bool
+}
+
+// Fungsi untuk mencari batas harga kategori; kategori tanpa aturan sendiri memakai batas bawaan
+func findPriceGuard(guards []PriceGuard, category string) *PriceGuard {
+	var fallback *PriceGuard
+	for i, g := range guards {
+		if g.Category == "" {
+			fallback = &guards[i]
+		} else if strings.EqualFold(g.Category, category) {
+			return &guards[i]
+		}
+	}
+	return fallback
+}
+
+// Fungsi untuk memeriksa satu harga terhadap batas kategorinya; ok false jika harga keluar batas
+func checkPriceGuard(guards []PriceGuard, category, name string, price Money) (priceViolation, bool) {
+	g := findPriceGuard(guards, category)
+	if g == nil || (price >= g.Min && (g.Max == 0 || price <= g.Max)) {
+		return priceViolation{}, true
+	}
+	label := g.Category
+	if label == "" {
+		label = "bawaan"
+	}
+	bounds := fmt.Sprintf("Rp%.0f-Rp%.0f", g.Min, g.Max)
+	if g.Max == 0 {
+		bounds = fmt.Sprintf("minimal Rp%.0f", g.Min)
+	}
+	msg := fmt.Sprintf("Harga %s Rp%.2f di luar batas %s (%s)", name, price, label, bounds)
+	return priceViolation{Message: msg, RequireManager: g.RequireManager}, false
+}
+
+// Fungsi untuk memeriksa harga seluruh item menu terhadap batas kategorinya
+func checkMenuPrices(guards []PriceGuard, menu []MenuItem) []priceViolation {
+	var violations []priceViolation
+	for _, item := range menu {
+		if v, ok := checkPriceGuard(guards, item.Category, item.Name, item.Price); !ok {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// Fungsi untuk menampilkan harga yang keluar batas dan meminta konfirmasi admin yang sedang masuk
+// Harga yang hanya diberi peringatan tidak menahan perubahan menu
+func confirmMenuPrices(guards []PriceGuard, menu []MenuItem) ([]string, error) {
+	var messages []string
+	needsManager := false
+	for _, v := range checkMenuPrices(guards, menu) {
+		fmt.Println("PERINGATAN:", v.Message)
+		messages = append(messages, v.Message)
+		needsManager = needsManager || v.RequireManager
+	}
+	if needsManager && !strings.EqualFold(readLine("Tetap gunakan harga ini? (y/n)"), "y") {
+		return nil, errPriceRejected
+	}
+	return messages, nil
+}
+
+// Fungsi untuk memeriksa harga yang diketik kasir, mis. ongkos kirim
+// Harga di luar batas yang butuh persetujuan dikonfirmasi dengan PIN admin; mengembalikan catatan override untuk audit
+func confirmOpenPrice(restaurant *Restaurant, category, name string, price Money) (string, bool) {
+	v, ok := checkPriceGuard(restaurant.PriceGuards, category, name, price)
+	if ok {
+		return "", true
+	}
+	fmt.Println(tr("PERINGATAN:"), v.Message)
+	if !v.RequireManager {
+		return "", strings.EqualFold(readLine(tr("Tetap gunakan harga ini? (y/n)")), "y")
+	}
+	if restaurant.ApproveManager == nil {
+		return "", false
+	}
+	admin, err := restaurant.ApproveManager()
+	if err != nil {
+		fmt.Println(tr("Persetujuan admin gagal:"), err)
+		return "", false
+	}
+	return fmt.Sprintf("%s, disetujui %s", v.Message, admin.Name), true
+}
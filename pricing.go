@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// Interface untuk strategi pajak
+// Setiap wilayah/waralaba dapat memakai cara perhitungan pajak yang berbeda
+type TaxStrategy interface {
+	Name() string                          // Nama strategi di konfigurasi
+	Included() bool                        // Pajak sudah termasuk di harga menu
+	Apply(amount Money) (total, tax Money) // Menghitung total tagihan dan pajak dari harga menu
+}
+
+// Interface untuk strategi pembulatan
+type RoundingStrategy interface {
+	Name() string             // Nama strategi di konfigurasi
+	Round(amount Money) Money // Membulatkan nominal
+}
+
+// Tahap pembulatan diterapkan
+type RoundingStage string
+
+const (
+	RoundPerLine    RoundingStage = "line"    // Setiap baris dibulatkan setelah pajak
+	RoundPerOrder   RoundingStage = "order"   // Total setiap pesanan dibulatkan setelah pajak
+	RoundPerPayment RoundingStage = "payment" // Hanya total pembayaran yang dibulatkan
+)
+
+// Tanpa pajak
+type noTax struct{}
+
+func (noTax) Name() string                      { return "none" }
+func (noTax) Included() bool                    { return false }
+func (noTax) Apply(amount Money) (Money, Money) { return amount, 0 }
+
+// Pajak ditambahkan di atas harga menu, mis. harga 10.000 + PPN 11% = 11.100
+type exclusiveTax struct {
+	rate float64
+}
+
+func (exclusiveTax) Name() string   { return "exclusive" }
+func (exclusiveTax) Included() bool { return false }
+func (t exclusiveTax) Apply(amount Money) (Money, Money) {
+	tax := amount.MulRate(t.rate)
+	return amount + tax, tax
+}
+
+// Pajak sudah termasuk di harga menu, mis. harga 11.100 berisi PPN 1.100
+// Pajak dihitung mundur dari harga: DPP = harga / (1 + tarif), pajak = harga - DPP
+// Pajak dibulatkan ke sen dan DPP mengikuti, sehingga DPP + pajak selalu sama dengan harga
+type inclusiveTax struct {
+	rate float64
+}
+
+func (inclusiveTax) Name() string   { return "inclusive" }
+func (inclusiveTax) Included() bool { return true }
+func (t inclusiveTax) Apply(amount Money) (Money, Money) {
+	return amount, amount - amount.DivRate(1+t.rate)
+}
+
+// Tanpa pembulatan
+type noRounding struct{}
+
+func (noRounding) Name() string             { return "none" }
+func (noRounding) Round(amount Money) Money { return amount }
+
+// Pembulatan ke kelipatan terdekat, ke bawah, atau ke atas
+type unitRounding struct {
+	name string
+	unit Money
+	fn   func(float64) float64 // math.Round, math.Floor, atau math.Ceil
+}
+
+func (r unitRounding) Name() string             { return r.name }
+func (r unitRounding) Round(amount Money) Money { return amount.RoundTo(r.unit, r.fn) }
+
+// Struct untuk Kebijakan harga yang berlaku
+// Menggabungkan strategi pajak, strategi pembulatan, dan tahap pembulatan
+type Pricing struct {
+	Tax      TaxStrategy
+	Rounding RoundingStrategy
+	Stage    RoundingStage
+	Rules    []PriceRule // Aturan harga berjadwal, diterapkan sesuai waktu pesanan dibuat
+}
+
+// Struct untuk rincian harga satu pesanan
+type OrderPrice struct {
+	Subtotal Money // Jumlah harga menu seluruh baris
+	Tax      Money // Pajak, termasuk atau ditambahkan sesuai strategi
+	Rounding Money // Selisih pembulatan
+	Total    Money // Total yang harus dibayar untuk pesanan
+}
+
+// Fungsi untuk memeriksa konfigurasi harga
+func (c PricingConfig) validate() error {
+	switch c.Tax {
+	case "none", "exclusive", "inclusive":
+	default:
+		return fmt.Errorf("pricing.tax tidak dikenal: %s (none, exclusive, atau inclusive)", c.Tax)
+	}
+	if c.TaxRate < 0 || c.TaxRate >= 1 {
+		return fmt.Errorf("pricing.tax_rate harus antara 0 dan 1, mis. 0.11")
+	}
+	switch c.Rounding {
+	case "none", "nearest", "down", "up":
+	default:
+		return fmt.Errorf("pricing.rounding tidak dikenal: %s (none, nearest, down, atau up)", c.Rounding)
+	}
+	if c.Rounding != "none" && c.RoundTo <= 0 {
+		return fmt.Errorf("pricing.round_to harus lebih dari 0")
+	}
+	switch RoundingStage(c.Stage) {
+	case RoundPerLine, RoundPerOrder, RoundPerPayment:
+	default:
+		return fmt.Errorf("pricing.stage tidak dikenal: %s (line, order, atau payment)", c.Stage)
+	}
+	return validatePriceRules(c.Rules)
+}
+
+// Mendapatkan kebijakan harga dari konfigurasi yang sudah divalidasi
+func (c PricingConfig) Strategy() Pricing {
+	p := Pricing{Tax: noTax{}, Rounding: noRounding{}, Stage: RoundingStage(c.Stage), Rules: c.Rules}
+	switch c.Tax {
+	case "exclusive":
+		p.Tax = exclusiveTax{rate: c.TaxRate}
+	case "inclusive":
+		p.Tax = inclusiveTax{rate: c.TaxRate}
+	}
+	fns := map[string]func(float64) float64{"nearest": math.Round, "down": math.Floor, "up": math.Ceil}
+	if fn, ok := fns[c.Rounding]; ok {
+		p.Rounding = unitRounding{name: c.Rounding, unit: Money(c.RoundTo) * Rp, fn: fn}
+	}
+	return p
+}
+
+// Menghitung rincian harga pesanan dari barisnya
+// Pajak dihitung dari subtotal pesanan agar pembulatan pajak tidak menumpuk per baris,
+// kecuali pembulatan per baris yang membutuhkan total setiap baris
+func (p Pricing) PriceOrder(lines []OrderLine) OrderPrice {
+	var price OrderPrice
+	var unrounded Money
+	for _, line := range lines {
+		subtotal := line.Subtotal()
+		price.Subtotal += subtotal
+		if p.Stage == RoundPerLine {
+			total, tax := p.Tax.Apply(subtotal)
+			price.Tax += tax
+			unrounded += total
+			price.Total += p.Rounding.Round(total)
+		}
+	}
+	if p.Stage != RoundPerLine {
+		unrounded, price.Tax = p.Tax.Apply(price.Subtotal)
+		price.Total = unrounded
+	}
+	if p.Stage == RoundPerOrder {
+		price.Total = p.Rounding.Round(price.Total)
+	}
+	price.Rounding = price.Total - unrounded
+	return price
+}
+
+// Menerapkan harga berjadwal dan rincian harga ke pesanan
+func (p Pricing) Apply(order *Order) {
+	for i, line := range order.Lines {
+		// Ronde tab meja memakai harga berjadwal saat ronde itu dipesan
+		at := order.CreatedAt
+		if !line.OrderedAt.IsZero() {
+			at = line.OrderedAt
+		}
+		p.resolveLinePrice(&order.Lines[i], at)
+	}
+	price := p.PriceOrder(order.Lines)
+	order.Subtotal, order.Tax, order.Rounding, order.Total = price.Subtotal, price.Tax, price.Rounding, price.Total
+	order.TaxIncluded = p.Tax.Included()
+	order.Total += order.DeliveryFee
+}
+
+// Membulatkan total pembayaran jika pembulatan dilakukan di tahap pembayaran
+// Mengembalikan total yang ditagih dan selisih pembulatannya
+func (p Pricing) RoundPayment(amount Money) (total, rounding Money) {
+	if p.Stage != RoundPerPayment {
+		return amount, 0
+	}
+	total = p.Rounding.Round(amount)
+	return total, total - amount
+}
+
+// Total yang seharusnya tercatat di pesanan menurut rinciannya
+// Pesanan lama tanpa rincian memakai jumlah baris
+func (o Order) ExpectedTotal() Money {
+	if o.Subtotal == 0 {
+		var total Money
+		for _, l := range o.Lines {
+			total += l.Subtotal()
+		}
+		return total + o.DeliveryFee
+	}
+	total := o.Subtotal + o.Rounding + o.DeliveryFee
+	if !o.TaxIncluded {
+		total += o.Tax
+	}
+	return total
+}
+
+// Fungsi untuk menampilkan rincian subtotal, pajak, dan pembulatan sebelum total
+// Tidak menampilkan apa pun jika tidak ada pajak maupun pembulatan
+// Pajak yang sudah termasuk di harga ditampilkan sebagai DPP (dasar pengenaan pajak) dan pajak di bawah subtotal
+func writePriceBreakdown(w io.Writer, orders []Order, paymentRounding Money) {
+	var subtotal, tax, rounding, delivery Money
+	included := false
+	for _, o := range orders {
+		subtotal += o.Subtotal
+		tax += o.Tax
+		rounding += o.Rounding
+		delivery += o.DeliveryFee
+		included = included || o.TaxIncluded
+	}
+	rounding += paymentRounding
+	if tax == 0 && rounding == 0 && delivery == 0 {
+		return
+	}
+	fmt.Fprint(w, tr("Subtotal      : Rp%.2f\n", subtotal))
+	if included {
+		// Harga menu sudah termasuk pajak, komponennya dipisahkan tanpa menambah total
+		fmt.Fprint(w, tr("  DPP         : Rp%.2f\n", subtotal-tax))
+		fmt.Fprint(w, tr("  Pajak (incl): Rp%.2f\n", tax))
+	} else if tax != 0 {
+		fmt.Fprint(w, tr("Pajak         : Rp%.2f\n", tax))
+	}
+	if delivery != 0 {
+		fmt.Fprint(w, tr("Ongkos kirim  : Rp%.2f\n", delivery))
+	}
+	if rounding != 0 {
+		fmt.Fprint(w, tr("Pembulatan    : Rp%.2f\n", rounding))
+	}
+}
+
+// Keterangan harga menu untuk ditampilkan di bawah daftar menu, kosong jika tanpa pajak
+func (c PricingConfig) MenuNote() string {
+	switch {
+	case c.Tax == "none" || c.TaxRate == 0:
+		return ""
+	case c.Tax == "inclusive":
+		return tr("Harga sudah termasuk pajak %g%%.", c.TaxRate*100)
+	default:
+		return tr("Harga belum termasuk pajak %g%%.", c.TaxRate*100)
+	}
+}
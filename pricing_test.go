@@ -0,0 +1,177 @@
+package main
+
+import "testing"
+
+// Dua baris dengan subtotal Rp12.345 dan Rp17.500, sehingga pembulatan per baris dan per pesanan berbeda
+func testPricingLines() []OrderLine {
+	return []OrderLine{
+		{Item: MenuItem{Name: "Nasi Goreng", Price: 12345 * Rp}, Qty: 1},
+		{Item: MenuItem{Name: "Es Teh", Price: 8750 * Rp}, Qty: 2},
+	}
+}
+
+func TestPricingStrategy(t *testing.T) {
+	tests := []struct {
+		cfg      PricingConfig
+		tax      string
+		rounding string
+		included bool
+	}{
+		{cfg: PricingConfig{Tax: "none", Rounding: "none", Stage: "order"}, tax: "none", rounding: "none"},
+		{cfg: PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "nearest", RoundTo: 100, Stage: "order"}, tax: "exclusive", rounding: "nearest"},
+		{cfg: PricingConfig{Tax: "inclusive", TaxRate: 0.11, Rounding: "down", RoundTo: 500, Stage: "line"}, tax: "inclusive", rounding: "down", included: true},
+		{cfg: PricingConfig{Tax: "exclusive", TaxRate: 0.1, Rounding: "up", RoundTo: 1000, Stage: "payment"}, tax: "exclusive", rounding: "up"},
+	}
+	for _, tt := range tests {
+		p := tt.cfg.Strategy()
+		if p.Tax.Name() != tt.tax || p.Rounding.Name() != tt.rounding || p.Tax.Included() != tt.included || p.Stage != RoundingStage(tt.cfg.Stage) {
+			t.Errorf("Strategy(%+v) = pajak %s (termasuk %v), pembulatan %s, tahap %s", tt.cfg, p.Tax.Name(), p.Tax.Included(), p.Rounding.Name(), p.Stage)
+		}
+	}
+}
+
+func TestPricingPriceOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  PricingConfig
+		want OrderPrice
+	}{
+		{
+			name: "tanpa pajak dan pembulatan",
+			cfg:  PricingConfig{Tax: "none", Rounding: "none", Stage: "order"},
+			want: OrderPrice{Subtotal: 2984500, Total: 2984500},
+		},
+		{
+			name: "tanpa pajak, dibulatkan per pesanan",
+			cfg:  PricingConfig{Tax: "none", Rounding: "nearest", RoundTo: 100, Stage: "order"},
+			want: OrderPrice{Subtotal: 2984500, Rounding: -4500, Total: 2980000},
+		},
+		{
+			name: "pajak ditambahkan, dibulatkan per pesanan",
+			cfg:  PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "nearest", RoundTo: 100, Stage: "order"},
+			want: OrderPrice{Subtotal: 2984500, Tax: 328295, Rounding: -2795, Total: 3310000},
+		},
+		{
+			name: "pajak ditambahkan, dibulatkan ke atas per pesanan",
+			cfg:  PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "up", RoundTo: 100, Stage: "order"},
+			want: OrderPrice{Subtotal: 2984500, Tax: 328295, Rounding: 7205, Total: 3320000},
+		},
+		{
+			name: "pajak ditambahkan, dibulatkan ke atas per baris",
+			cfg:  PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "up", RoundTo: 100, Stage: "line"},
+			want: OrderPrice{Subtotal: 2984500, Tax: 328295, Rounding: 17205, Total: 3330000},
+		},
+		{
+			name: "pajak ditambahkan, dibulatkan di pembayaran",
+			cfg:  PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "nearest", RoundTo: 100, Stage: "payment"},
+			want: OrderPrice{Subtotal: 2984500, Tax: 328295, Total: 3312795},
+		},
+		{
+			name: "pajak termasuk, dibulatkan ke bawah per pesanan",
+			cfg:  PricingConfig{Tax: "inclusive", TaxRate: 0.11, Rounding: "down", RoundTo: 500, Stage: "order"},
+			want: OrderPrice{Subtotal: 2984500, Tax: 295761, Rounding: -34500, Total: 2950000},
+		},
+		{
+			name: "pajak termasuk, dibulatkan ke atas per baris",
+			cfg:  PricingConfig{Tax: "inclusive", TaxRate: 0.11, Rounding: "up", RoundTo: 500, Stage: "line"},
+			want: OrderPrice{Subtotal: 2984500, Tax: 295761, Rounding: 15500, Total: 3000000},
+		},
+		{
+			name: "pajak termasuk, dibulatkan di pembayaran",
+			cfg:  PricingConfig{Tax: "inclusive", TaxRate: 0.11, Rounding: "down", RoundTo: 500, Stage: "payment"},
+			want: OrderPrice{Subtotal: 2984500, Tax: 295761, Total: 2984500},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.Strategy().PriceOrder(testPricingLines()); got != tt.want {
+			t.Errorf("%s: PriceOrder = %+v, ingin %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPricingRoundPayment(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      PricingConfig
+		amount   Money
+		total    Money
+		rounding Money
+	}{
+		{
+			name:     "dibulatkan ke terdekat di pembayaran",
+			cfg:      PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "nearest", RoundTo: 100, Stage: "payment"},
+			amount:   3312795,
+			total:    3310000,
+			rounding: -2795,
+		},
+		{
+			name:     "tepat di tengah dibulatkan ke atas",
+			cfg:      PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "nearest", RoundTo: 100, Stage: "payment"},
+			amount:   3315000,
+			total:    3320000,
+			rounding: 5000,
+		},
+		{
+			name:     "dibulatkan ke bawah di pembayaran",
+			cfg:      PricingConfig{Tax: "inclusive", TaxRate: 0.11, Rounding: "down", RoundTo: 500, Stage: "payment"},
+			amount:   2984500,
+			total:    2950000,
+			rounding: -34500,
+		},
+		{
+			name:     "kelipatan tepat tidak bergeser",
+			cfg:      PricingConfig{Tax: "none", Rounding: "up", RoundTo: 1000, Stage: "payment"},
+			amount:   3000000,
+			total:    3000000,
+			rounding: 0,
+		},
+		{
+			name:     "pembulatan per pesanan tidak dibulatkan lagi",
+			cfg:      PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "nearest", RoundTo: 100, Stage: "order"},
+			amount:   3312795,
+			total:    3312795,
+			rounding: 0,
+		},
+		{
+			name:     "pembulatan per baris tidak dibulatkan lagi",
+			cfg:      PricingConfig{Tax: "inclusive", TaxRate: 0.11, Rounding: "up", RoundTo: 500, Stage: "line"},
+			amount:   2984500,
+			total:    2984500,
+			rounding: 0,
+		},
+	}
+	for _, tt := range tests {
+		total, rounding := tt.cfg.Strategy().RoundPayment(tt.amount)
+		if total != tt.total || rounding != tt.rounding {
+			t.Errorf("%s: RoundPayment(%d) = %d, %d; ingin %d, %d", tt.name, tt.amount, total, rounding, tt.total, tt.rounding)
+		}
+	}
+}
+
+func TestPricingApply(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  PricingConfig
+		fee  Money
+	}{
+		{name: "pajak ditambahkan", cfg: PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "nearest", RoundTo: 100, Stage: "order"}},
+		{name: "pajak termasuk", cfg: PricingConfig{Tax: "inclusive", TaxRate: 0.11, Rounding: "up", RoundTo: 500, Stage: "line"}},
+		{name: "pesanan antar", cfg: PricingConfig{Tax: "exclusive", TaxRate: 0.11, Rounding: "nearest", RoundTo: 100, Stage: "payment"}, fee: 10000 * Rp},
+	}
+	for _, tt := range tests {
+		p := tt.cfg.Strategy()
+		order := Order{Lines: testPricingLines(), DeliveryFee: tt.fee}
+		p.Apply(&order)
+		price := p.PriceOrder(order.Lines)
+		if order.Subtotal != price.Subtotal || order.Tax != price.Tax || order.Rounding != price.Rounding || order.Total != price.Total+tt.fee {
+			t.Errorf("%s: Apply = subtotal %d, pajak %d, pembulatan %d, total %d; ingin %+v + ongkos %d", tt.name, order.Subtotal, order.Tax, order.Rounding, order.Total, price, tt.fee)
+		}
+		if order.TaxIncluded != p.Tax.Included() {
+			t.Errorf("%s: TaxIncluded = %v", tt.name, order.TaxIncluded)
+		}
+		// Total tersimpan harus bisa disusun ulang dari rinciannya, seperti yang diperiksa saat memutar ulang jurnal
+		if expected := order.ExpectedTotal(); expected != order.Total {
+			t.Errorf("%s: ExpectedTotal = %d, total %d", tt.name, expected, order.Total)
+		}
+	}
+}
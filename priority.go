@@ -0,0 +1,216 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prioritas pesanan di antrean dapur
+type OrderPriority string
+
+const (
+	PriorityNormal OrderPriority = ""      // Mengantre sesuai nomor antrean
+	PriorityRider  OrderPriority = "rider" // Kurir pengantaran sudah menunggu di kasir
+	PriorityVIP    OrderPriority = "vip"   // Tamu VIP
+)
+
+var (
+	errUnknownPriority = errors.New("Prioritas tidak dikenal (rider, vip, atau normal)")
+	errOrderNotQueued  = errors.New("Pesanan tidak sedang mengantre di dapur")
+)
+
+// Fungsi untuk membaca prioritas dari input kasir atau perintah CLI
+func parsePriority(s string) (OrderPriority, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "normal":
+		return PriorityNormal, nil
+	case "rider", "kurir", "driver":
+		return PriorityRider, nil
+	case "vip":
+		return PriorityVIP, nil
+	}
+	return PriorityNormal, errUnknownPriority
+}
+
+// Nama prioritas untuk tiket dapur dan daftar antrean
+func (p OrderPriority) Label() string {
+	switch p {
+	case PriorityRider:
+		return tr("Kurir menunggu")
+	case PriorityVIP:
+		return "VIP"
+	}
+	return "-"
+}
+
+// Fungsi untuk membandingkan urutan dua pesanan di antrean dapur
+// Pesanan yang didahulukan manual berada paling depan (yang terakhir didahulukan lebih dulu),
+// lalu pesanan berprioritas, lalu sisanya sesuai nomor antrean
+func compareQueue(a, b Order) int {
+	if c := b.BumpedAt.Compare(a.BumpedAt); c != 0 {
+		return c
+	}
+	if ap, bp := a.Priority != PriorityNormal, b.Priority != PriorityNormal; ap != bp {
+		if ap {
+			return -1
+		}
+		return 1
+	}
+	if c := cmp.Compare(a.QueueNumber, b.QueueNumber); c != 0 {
+		return c
+	}
+	return a.CreatedAt.Compare(b.CreatedAt)
+}
+
+// Sisa perkiraan lama masak pesanan di antrean; pesanan yang sedang dimasak hanya menyumbang sisa waktunya
+func remainingPrepTime(o Order, fallback time.Duration, now time.Time) time.Duration {
+	remaining := orderPrepTime(o, fallback)
+	if !o.EstimatedReadyAt.IsZero() {
+		remaining = min(remaining, max(o.EstimatedReadyAt.Sub(now), 0))
+	}
+	return remaining
+}
+
+// Pesanan hari ini yang sudah dibayar tetapi belum siap, sesuai urutan antrean dapur
+// Pemanggil harus memegang s.mu
+func (s *Store) kitchenQueue(now time.Time) []Order {
+	var queue []Order
+	for _, o := range s.data.Orders {
+		if o.Status != OrderPending || o.PaymentID == "" || !o.ReadyAt.IsZero() || !sameDay(o.CreatedAt.Local(), now) {
+			continue
+		}
+		queue = append(queue, o)
+	}
+	slices.SortStableFunc(queue, compareQueue)
+	return queue
+}
+
+// Struct untuk satu baris daftar antrean dapur
+type QueueEntry struct {
+	Position int       // Urutan di antrean, mulai dari 1
+	Order    Order     // Pesanan yang mengantre
+	ETA      time.Time // Perkiraan waktu siap menurut urutan antrean saat ini
+}
+
+// Mengambil antrean dapur beserta perkiraan waktu siap terbaru
+// Perkiraan dihitung ulang dari urutan saat ini, sehingga pesanan di belakang pesanan prioritas ikut mundur
+func (s *Store) KitchenQueue(cfg KitchenConfig, now time.Time) []QueueEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fallback := cfg.DefaultPrepTime()
+	workers := time.Duration(max(cfg.Workers, 1))
+	var backlog time.Duration
+	var entries []QueueEntry
+	for i, o := range s.kitchenQueue(now) {
+		remaining := remainingPrepTime(o, fallback, now)
+		entries = append(entries, QueueEntry{Position: i + 1, Order: o, ETA: now.Add(backlog/workers + remaining).Truncate(time.Minute)})
+		backlog += remaining
+	}
+	return entries
+}
+
+// Mendahulukan pesanan ke depan antrean dapur
+// Daftar antrean dan perkiraan waktu siap mengikuti urutan baru; tiket yang sudah dicetak tidak berubah
+func (s *Store) BumpOrder(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(id)
+	if order == nil {
+		return errOrderNotFound
+	}
+	if order.Status != OrderPending || order.PaymentID == "" || !order.ReadyAt.IsZero() {
+		return errOrderNotQueued
+	}
+	if err := s.record(EventOrderBumped, eventOrderRef{OrderID: id}); err != nil {
+		return err
+	}
+	order.BumpedAt = time.Now()
+	return s.save()
+}
+
+// Fungsi untuk mencari pesanan di antrean dari ID atau nomor antrean hari ini
+func findQueueEntry(entries []QueueEntry, ref string) (QueueEntry, bool) {
+	n, err := strconv.Atoi(ref)
+	for _, e := range entries {
+		if e.Order.ID == ref || (err == nil && e.Order.QueueNumber == n) {
+			return e, true
+		}
+	}
+	return QueueEntry{}, false
+}
+
+// Fungsi untuk menampilkan antrean dapur beserta posisi dan perkiraan waktu siapnya
+func printKitchenQueue(w io.Writer, entries []QueueEntry, now time.Time) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "Antrean dapur kosong.")
+		return
+	}
+	fmt.Fprintf(w, "%-4s %-7s %-22s %-15s %-6s %s\n", "No", "Antrean", "Pesanan", "Prioritas", "Siap", "Item")
+	for _, e := range entries {
+		priority := e.Order.Priority.Label()
+		if !e.Order.BumpedAt.IsZero() {
+			priority = "didahulukan"
+		}
+		minutes := int(max(e.ETA.Sub(now), 0).Round(time.Minute) / time.Minute)
+		var items []string
+		for _, l := range e.Order.Lines {
+			items = append(items, fmt.Sprintf("%dx %s", l.Qty, l.Label()))
+		}
+		fmt.Fprintf(w, "%-4d %-7s %-22s %-15s %-6s %s (%d menit)\n", e.Position, fmt.Sprintf("%03d", e.Order.QueueNumber), e.Order.ID,
+			priority, e.ETA.Local().Format("15:04"), strings.Join(items, ", "), minutes)
+	}
+}
+
+// Fungsi untuk menjalankan "queue list"
+func runQueueList(cfg *Config, store *Store, args []string) error {
+	fs := flag.NewFlagSet("queue list", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	now := time.Now()
+	printKitchenQueue(os.Stdout, store.KitchenQueue(cfg.Kitchen, now), now)
+	return nil
+}
+
+// Fungsi untuk menjalankan "queue bump <id|nomor>"
+func runQueueBump(cfg *Config, store *Store, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Gunakan: queue bump <id pesanan|nomor antrean>")
+	}
+	now := time.Now()
+	e, ok := findQueueEntry(store.KitchenQueue(cfg.Kitchen, now), args[0])
+	if !ok {
+		return fmt.Errorf("%w: %s", errOrderNotQueued, args[0])
+	}
+	if err := store.BumpOrder(e.Order.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Pesanan %s (antrean %03d) didahulukan dari posisi %d ke posisi 1.\n", e.Order.ID, e.Order.QueueNumber, e.Position)
+	return nil
+}
+
+// Fungsi untuk menandai prioritas pesanan yang sedang diinput, mis. 'prioritas rider'
+func setOrderPriority(order *Order, value string) {
+	if strings.TrimSpace(value) == "" {
+		value = readLine(tr("Prioritas pesanan (rider, vip, atau normal):"))
+	}
+	p, err := parsePriority(value)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	order.Priority = p
+	if p == PriorityNormal {
+		fmt.Println(tr("Pesanan mengantre seperti biasa."))
+		return
+	}
+	fmt.Print(tr("Pesanan didahulukan di dapur: %s.\n", p.Label()))
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+)
+
+// Struct untuk rekap penjualan satu hari yang diperbarui setiap ada pembayaran, refund, atau pembatalan
+// Laporan harian dan item terlaris membaca rekap ini sehingga tidak perlu memindai seluruh pesanan
+type DailyProjection struct {
+	Orders         int                      `json:"orders"`             // Jumlah pesanan yang dibayar, menurut tanggal pembayaran
+	GrossSales     Money                    `json:"gross_sales"`        // Total pembayaran, menurut tanggal pembayaran
+	Tax            Money                    `json:"tax"`                // Pajak dari pesanan yang dibayar
+	Cancelled      int                      `json:"cancelled"`          // Pesanan dibatalkan, menurut tanggal pesanan
+	Refunds        int                      `json:"refunds"`            // Jumlah refund, menurut tanggal refund
+	RefundedAmount Money                    `json:"refunded_amount"`    // Total refund
+	Methods        map[PaymentMethod]Money  `json:"methods"`            // Total pembayaran per metode
+	Items          map[string]*ItemCount    `json:"items"`              // Penjualan per item setelah refund, menurut tanggal pesanan
+	Channels       map[string]*ChannelSales `json:"channels,omitempty"` // Penjualan per kanal pesanan online, menurut tanggal pembayaran
+	Tips           map[string]Money         `json:"tips,omitempty"`     // Tip per ID staf pelayan, di luar penjualan
+}
+
+// Struct untuk penjualan satu kanal pesanan online di rekap harian
+type ChannelSales struct {
+	Orders     int   `json:"orders"`
+	Sales      Money `json:"sales"`      // Total pesanan termasuk markup kanal
+	Commission Money `json:"commission"` // Komisi yang dipotong platform
+}
+
+// Struct untuk jumlah penjualan satu item di rekap harian
+type ItemCount struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Qty      int    `json:"qty"`
+	Revenue  Money  `json:"revenue"`
+}
+
+// Kunci rekap harian dari waktu lokal
+func projectionKey(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+// Mengambil rekap harian, dibuat jika belum ada; pemanggil harus memegang s.mu
+func (s *Store) projection(t time.Time) *DailyProjection {
+	if s.data.Projections == nil {
+		s.data.Projections = map[string]*DailyProjection{}
+	}
+	key := projectionKey(t)
+	p := s.data.Projections[key]
+	if p == nil {
+		p = &DailyProjection{Methods: map[PaymentMethod]Money{}, Items: map[string]*ItemCount{}}
+		s.data.Projections[key] = p
+	}
+	return p
+}
+
+// Menambahkan penjualan item ke rekap harian
+func (p *DailyProjection) addItem(item MenuItem, qty int, revenue Money) {
+	key := strings.ToLower(item.Name)
+	if p.Items[key] == nil {
+		p.Items[key] = &ItemCount{Name: item.Name, Category: item.Category}
+	}
+	p.Items[key].Qty += qty
+	p.Items[key].Revenue += revenue
+}
+
+// Mengeluarkan (sign 1) atau mengembalikan (sign -1) item yang di-refund dari penjualan item pesanan
+func (p *DailyProjection) removeRefundedItems(o *Order, r Refund, sign int) {
+	for _, rl := range r.Lines {
+		if rl.Line < len(o.Lines) {
+			p.addItem(o.Lines[rl.Line].Item, -sign*rl.Qty, rl.Amount.Times(-sign))
+		}
+	}
+}
+
+// Menambahkan tip pembayaran ke rekap harian per pelayan penerimanya
+func (p *DailyProjection) addTip(pay Payment) {
+	if pay.Tip == 0 {
+		return
+	}
+	if p.Tips == nil {
+		p.Tips = map[string]Money{}
+	}
+	p.Tips[pay.TipTo] += pay.Tip
+}
+
+// Menambahkan pesanan kanal pesanan online ke rekap harian
+func (p *DailyProjection) addChannel(o *Order) {
+	if o.Source == "" {
+		return
+	}
+	if p.Channels == nil {
+		p.Channels = map[string]*ChannelSales{}
+	}
+	if p.Channels[o.Source] == nil {
+		p.Channels[o.Source] = &ChannelSales{}
+	}
+	c := p.Channels[o.Source]
+	c.Orders++
+	c.Sales += o.Total
+	c.Commission += o.Commission
+}
+
+// Menambahkan (sign 1) atau mengeluarkan (sign -1) penjualan item satu pesanan dari rekap tanggal pesanannya
+// Hanya pesanan yang dibayar dan tidak dibatalkan yang dihitung, dikurangi item yang sudah di-refund;
+// perubahan pesanan dicatat dengan mengeluarkan keadaan lama lalu menambahkan keadaan baru.
+// Pemanggil harus memegang s.mu
+func (s *Store) projectItems(o *Order, sign int) {
+	if !s.data.ProjectionsBuilt || o.PaymentID == "" || o.Status == OrderCancelled {
+		return
+	}
+	day := s.projection(o.CreatedAt)
+	for _, l := range o.Lines {
+		day.addItem(l.Item, sign*l.Qty, l.Subtotal().Times(sign))
+	}
+	for _, r := range s.data.Refunds {
+		if r.OrderID == o.ID {
+			day.removeRefundedItems(o, r, sign)
+		}
+	}
+}
+
+// Mendaftarkan rekap penjualan sebagai subscriber kejadian penyimpanan, dipanggil saat penyimpanan dibuat
+func (s *Store) subscribeProjections() {
+	s.events.Subscribe("rekap penjualan", s.projectEvent, OrderEventPaid, OrderEventCancelled, OrderEventRefunded)
+}
+
+// Memperbarui rekap penjualan dari kejadian pesanan; dipanggil dari record saat s.mu dipegang
+// Kejadian diterima sebelum data berubah, sehingga pesanan yang dibatalkan masih berstatus pending
+// dan refund baru belum ada di daftar refund
+func (s *Store) projectEvent(e OrderEvent) {
+	if !s.data.ProjectionsBuilt {
+		return
+	}
+	switch e.Type {
+	case OrderEventPaid:
+		s.projectPayment(e.Payment)
+	case OrderEventCancelled:
+		if o := s.findOrder(e.Order.ID); o != nil {
+			s.projectItems(o, -1)
+		}
+		s.projectCancel(&e.Order)
+	case OrderEventRefunded:
+		if o := &e.Order; o.PaymentID != "" && o.Status != OrderCancelled {
+			s.projection(o.CreatedAt).removeRefundedItems(o, e.Refund, 1)
+		}
+		s.projectRefund(e.Refund)
+	}
+}
+
+// Memperbarui rekap dengan pembayaran baru; pemanggil harus memegang s.mu
+// Pesanan terkait sudah ditandai dibayar sebelum pembayaran dicatat
+func (s *Store) projectPayment(pay Payment) {
+	if !s.data.ProjectionsBuilt {
+		return
+	}
+	day := s.projection(pay.PaidAt)
+	day.Orders += len(pay.OrderIDs)
+	day.GrossSales += pay.Amount
+	day.Methods[pay.PaymentMethod()] += pay.Amount
+	day.addTip(pay)
+	for _, id := range pay.OrderIDs {
+		if o := s.findOrder(id); o != nil {
+			day.Tax += o.Tax
+			day.addChannel(o)
+			s.projectItems(o, 1)
+		}
+	}
+}
+
+// Memperbarui rekap dengan pesanan yang dibatalkan; pemanggil harus memegang s.mu
+func (s *Store) projectCancel(o *Order) {
+	if s.data.ProjectionsBuilt {
+		s.projection(o.CreatedAt).Cancelled++
+	}
+}
+
+// Memperbarui rekap dengan refund baru; pemanggil harus memegang s.mu
+func (s *Store) projectRefund(r Refund) {
+	if s.data.ProjectionsBuilt {
+		day := s.projection(r.CreatedAt)
+		day.Refunds++
+		day.RefundedAmount += r.Amount
+	}
+}
+
+// Menyusun ulang seluruh rekap dari pesanan, pembayaran, dan refund; pemanggil harus memegang s.mu
+func (s *Store) rebuildProjections() {
+	s.data.Projections = nil
+	s.data.ProjectionsBuilt = true
+	for _, p := range s.data.Payments {
+		day := s.projection(p.PaidAt)
+		day.Orders += len(p.OrderIDs)
+		day.GrossSales += p.Amount
+		day.Methods[p.PaymentMethod()] += p.Amount
+		day.addTip(p)
+		for _, id := range p.OrderIDs {
+			if o := s.findOrder(id); o != nil {
+				day.Tax += o.Tax
+				day.addChannel(o)
+			}
+		}
+	}
+	for i := range s.data.Orders {
+		o := &s.data.Orders[i]
+		if o.Status == OrderCancelled {
+			s.projectCancel(o)
+		}
+		s.projectItems(o, 1)
+	}
+	for _, r := range s.data.Refunds {
+		s.projectRefund(r)
+	}
+}
+
+// Menyusun ulang rekap penjualan, dipakai oleh penyusunan malam hari dan "report rebuild"
+func (s *Store) RebuildProjections() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rebuildProjections()
+	return s.save()
+}
+
+// Mengambil salinan rekap harian pada tanggal tertentu
+// Data lama yang belum memiliki rekap disusun sekali saat pertama dibaca
+func (s *Store) DailyProjection(date time.Time) DailyProjection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.data.ProjectionsBuilt {
+		s.rebuildProjections()
+		s.save()
+	}
+	p, ok := s.data.Projections[projectionKey(date)]
+	if !ok {
+		return DailyProjection{}
+	}
+	copied := *p
+	copied.Methods = maps.Clone(p.Methods)
+	copied.Tips = maps.Clone(p.Tips)
+	copied.Items = make(map[string]*ItemCount, len(p.Items))
+	for k, item := range p.Items {
+		c := *item
+		copied.Items[k] = &c
+	}
+	if p.Channels != nil {
+		copied.Channels = make(map[string]*ChannelSales, len(p.Channels))
+		for k, ch := range p.Channels {
+			c := *ch
+			copied.Channels[k] = &c
+		}
+	}
+	return copied
+}
+
+// Fungsi untuk menyusun ulang rekap setiap lewat tengah malam selama server berjalan
+// Rekap yang diperbarui per transaksi dicocokkan lagi dengan data mentah sekali sehari
+func runNightlyProjections(store *Store) {
+	day := projectionKey(time.Now())
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		if today := projectionKey(time.Now()); today != day {
+			day = today
+			if err := store.RebuildProjections(); err != nil {
+				fmt.Println("Gagal menyusun ulang rekap penjualan:", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Memberi nomor antrean harian ke pesanan yang belum memilikinya; pemanggil harus memegang s.mu
+// Nomor kembali ke 1 setiap hari; setelah tutup hari, nomor sudah diulang untuk tanggal berikutnya
+func (s *Store) assignQueueNumbers(orders []Order) {
+	today := time.Now().Format("2006-01-02")
+	if s.data.QueueDate < today {
+		s.data.QueueDate, s.data.QueueSeq = today, 0
+	}
+	for i := range orders {
+		if orders[i].QueueNumber == 0 {
+			s.data.QueueSeq++
+			orders[i].QueueNumber = s.data.QueueSeq
+		}
+	}
+	s.assignPickupCodes(orders)
+}
+
+// Memberi nomor antrean dan kode pengambilan ke pesanan sebelum disimpan
+// Nomor ditulis langsung ke slice orders agar pemanggil dapat mencetaknya
+func (s *Store) AssignQueueNumbers(orders []Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assignQueueNumbers(orders)
+	return s.save()
+}
+
+// Menandai pesanan sudah selesai dimasak dan siap diambil
+func (s *Store) MarkReady(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(id)
+	if order == nil {
+		return errOrderNotFound
+	}
+	if order.Status != OrderPending || !order.ReadyAt.IsZero() {
+		return nil
+	}
+	if err := s.record(EventOrderReady, eventOrderRef{OrderID: id}); err != nil {
+		return err
+	}
+	order.ReadyAt = time.Now()
+	return s.save()
+}
+
+// Fungsi untuk mencetak tiket dapur
+func writeKitchenTicket(w io.Writer, order Order) {
+	fmt.Fprintln(w, tr("---------- TIKET DAPUR ----------"))
+	fmt.Fprint(w, tr("Antrean %03d   %s\n", order.QueueNumber, order.ID))
+	writeOrderType(w, order)
+	if order.Priority != PriorityNormal {
+		fmt.Fprintln(w, tr("PRIORITAS:"), strings.ToUpper(order.Priority.Label()))
+	}
+	if len(order.Allergies) > 0 {
+		fmt.Fprintln(w, tr("ALERGI:"), strings.Join(order.Allergies, ", "))
+	}
+	writeTicketLines(w, order.Lines)
+	fmt.Fprintln(w, "---------------------------------")
+}
+
+// Fungsi untuk mencetak baris pesanan di tiket dapur beserta isi paket dan catatannya
+func writeTicketLines(w io.Writer, lines []OrderLine) {
+	for _, l := range lines {
+		if l.Item.IsCombo() {
+			writeComboComponents(w, l)
+		} else {
+			fmt.Fprintf(w, "%2dx %s\n", l.Qty, l.Label())
+		}
+		for _, n := range l.Notes {
+			fmt.Fprintln(w, "    *", n)
+		}
+		if l.FreeNote != "" {
+			fmt.Fprintln(w, "    *", l.FreeNote)
+		}
+	}
+}
+
+// Fungsi untuk mencetak potongan nomor antrean untuk pelanggan
+func writeQueueStub(w io.Writer, order Order) {
+	const width = 35
+	line := func(text string) {
+		pad := max(width-len(text), 0)
+		fmt.Fprintf(w, "|%s%s%s|\n", strings.Repeat(" ", pad/2), text, strings.Repeat(" ", pad-pad/2))
+	}
+	border := "+" + strings.Repeat("-", width) + "+"
+	fmt.Fprintln(w, border)
+	line(tr("NOMOR ANTREAN"))
+	line(fmt.Sprintf("%03d", order.QueueNumber))
+	line(order.CreatedAt.Local().Format("02/01 15:04") + "  " + order.ID)
+	if !order.EstimatedReadyAt.IsZero() {
+		line(tr("Perkiraan siap pukul %s", order.EstimatedReadyAt.Local().Format("15:04")))
+	}
+	line(tr("Ambil saat nomor Anda tampil"))
+	if order.PickupCode != "" {
+		line(tr("Cek status: kode %s", order.PickupCode))
+	}
+	fmt.Fprintln(w, border)
+}
+
+// Fungsi untuk mencetak tiket setelah pesanan dikonfirmasi
+// Potongan antrean hanya dicetak di mode food court atau untuk pesanan bawa pulang
+// Dengan stasiun dapur, setiap stasiun mendapat tiketnya sendiri alih-alih satu tiket per pesanan
+func printOrderTickets(w io.Writer, orders []Order, cfg *Config) {
+	for _, o := range orders {
+		if len(cfg.Kitchen.Stations) > 0 {
+			emitStationTickets(w, cfg.Kitchen.Stations, o)
+		} else {
+			writeKitchenTicket(w, o)
+		}
+		if o.QueueNumber > 0 && (cfg.Cashier.FoodCourt || o.Table == "") {
+			writeQueueStub(w, o)
+		}
+	}
+}
+
+// Struct untuk isi layar pengambilan pesanan
+type QueueDisplay struct {
+	Preparing []int `json:"preparing"` // Nomor antrean yang sedang dimasak
+	Ready     []int `json:"ready"`     // Nomor antrean yang siap diambil
+}
+
+// Fungsi untuk menyusun isi layar pengambilan dari pesanan hari ini yang belum disajikan
+func buildQueueDisplay(store *Store) QueueDisplay {
+	var d QueueDisplay
+	for _, o := range store.Orders() {
+		if o.Status != OrderPending || o.QueueNumber == 0 || !sameDay(o.CreatedAt.Local(), time.Now()) {
+			continue
+		}
+		if o.ReadyAt.IsZero() {
+			d.Preparing = append(d.Preparing, o.QueueNumber)
+		} else {
+			d.Ready = append(d.Ready, o.QueueNumber)
+		}
+	}
+	slices.Sort(d.Preparing)
+	slices.Sort(d.Ready)
+	return d
+}
+
+// Menampilkan layar pengambilan dalam bentuk teks
+func (d QueueDisplay) Print(w io.Writer) {
+	format := func(numbers []int) string {
+		if len(numbers) == 0 {
+			return "-"
+		}
+		parts := make([]string, len(numbers))
+		for i, n := range numbers {
+			parts[i] = fmt.Sprintf("%03d", n)
+		}
+		return strings.Join(parts, "  ")
+	}
+	fmt.Fprintln(w, "SIAP DIAMBIL   :", format(d.Ready))
+	fmt.Fprintln(w, "SEDANG DIMASAK :", format(d.Preparing))
+}
+
+// GET /display
+// Halaman layar pengambilan untuk TV; hanya berisi nomor antrean sehingga tidak memerlukan API key
+func (s *Server) handleQueueDisplay(w http.ResponseWriter, r *http.Request) {
+	d := buildQueueDisplay(s.store)
+	if r.URL.Query().Get("format") == "json" {
+		writeJSON(w, http.StatusOK, d)
+		return
+	}
+	var b strings.Builder
+	d.Print(&b)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><meta http-equiv="refresh" content="5"><title>Antrean</title></head>
+<body style="font-family:sans-serif;font-size:3em"><pre>%s</pre></body></html>`, html.EscapeString(b.String()))
+}
+
+// Fungsi untuk menjalankan "queue display"
+func runQueueDisplay(store *Store, args []string) error {
+	fs := flag.NewFlagSet("queue display", flag.ContinueOnError)
+	watch := fs.Int("watch", 0, "perbarui layar setiap sekian detik, 0 untuk sekali tampil")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for {
+		if *watch > 0 {
+			// Data dibaca ulang karena pesanan ditandai siap oleh proses kasir atau server
+			var err error
+			if store, err = store.Reopen(); err != nil {
+				return err
+			}
+			fmt.Print("\033[H\033[2J")
+		}
+		buildQueueDisplay(store).Print(os.Stdout)
+		if *watch <= 0 {
+			return nil
+		}
+		time.Sleep(time.Duration(*watch) * time.Second)
+	}
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errReceiptNotFound = errors.New("Struk tidak ditemukan")
+
+// Struct untuk Struk yang tersimpan
+// Satu struk mewakili satu pembayaran beserta pesanan yang dibayar
+type Receipt struct {
+	Payment  Payment
+	Orders   []Order
+	Customer *Customer // Pelanggan member, nil jika bukan member
+	Refunds  []Refund  // Refund yang sudah dilakukan atas pesanan di struk ini
+}
+
+// Struct untuk kriteria pencarian struk
+// Field yang kosong tidak dipakai sebagai filter
+type ReceiptQuery struct {
+	Date      time.Time     // Tanggal pembayaran
+	Around    time.Duration // Perkiraan jam pembayaran sejak tengah malam, dipakai bersama Window
+	Window    time.Duration // Selisih jam yang masih dianggap cocok
+	HasAround bool          // Filter jam dipakai atau tidak
+	Amount    Money         // Perkiraan total pembayaran
+	Tolerance Money         // Selisih total yang masih dianggap cocok
+	Item      string        // Sebagian nama item
+	Customer  string        // Sebagian nama, nomor HP, atau ID pelanggan
+}
+
+// Mengambil seluruh struk, diurutkan dari yang terbaru
+func (s *Store) Receipts() []Receipt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	receipts := make([]Receipt, 0, len(s.data.Payments))
+	for _, p := range s.data.Payments {
+		r := Receipt{Payment: p}
+		for _, id := range p.OrderIDs {
+			if order := s.findOrder(id); order != nil {
+				r.Orders = append(r.Orders, *order)
+			}
+		}
+		for _, ref := range s.data.Refunds {
+			if ref.PaymentID == p.ID {
+				r.Refunds = append(r.Refunds, ref)
+			}
+		}
+		if len(r.Orders) > 0 && r.Orders[0].CustomerID != "" {
+			for _, c := range s.data.Customers {
+				if c.ID == r.Orders[0].CustomerID {
+					r.Customer = &c
+					break
+				}
+			}
+		}
+		receipts = append(receipts, r)
+	}
+	slices.SortFunc(receipts, func(a, b Receipt) int { return b.Payment.PaidAt.Compare(a.Payment.PaidAt) })
+	return receipts
+}
+
+// Mencari struk berdasarkan ID pembayaran atau ID pesanan
+func (s *Store) Receipt(id string) (Receipt, error) {
+	for _, r := range s.Receipts() {
+		if r.Payment.ID == id || slices.ContainsFunc(r.Orders, func(o Order) bool { return o.ID == id }) {
+			return r, nil
+		}
+	}
+	return Receipt{}, errReceiptNotFound
+}
+
+// Memeriksa apakah struk cocok dengan kriteria pencarian
+func (q ReceiptQuery) Match(r Receipt) bool {
+	paid := r.Payment.PaidAt.Local()
+	if !q.Date.IsZero() && !sameDay(paid, q.Date) {
+		return false
+	}
+	if q.HasAround {
+		midnight := time.Date(paid.Year(), paid.Month(), paid.Day(), 0, 0, 0, 0, paid.Location())
+		diff := paid.Sub(midnight) - q.Around
+		if diff < -q.Window || diff > q.Window {
+			return false
+		}
+	}
+	if q.Amount > 0 && (r.Payment.Amount < q.Amount-q.Tolerance || r.Payment.Amount > q.Amount+q.Tolerance) {
+		return false
+	}
+	if q.Item != "" && !slices.ContainsFunc(r.Orders, func(o Order) bool {
+		return slices.ContainsFunc(o.Lines, func(l OrderLine) bool { return containsFold(l.Item.Name, q.Item) })
+	}) {
+		return false
+	}
+	if q.Customer != "" {
+		if r.Customer == nil {
+			return false
+		}
+		c := r.Customer
+		phone := normalizePhone(q.Customer)
+		if !containsFold(c.Name, q.Customer) && c.ID != q.Customer && (phone == "" || !strings.Contains(c.Phone, phone)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Fungsi untuk memeriksa apakah s mengandung sub tanpa membedakan huruf besar/kecil
+func containsFold(s, sub string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(strings.TrimSpace(sub)))
+}
+
+// Fungsi untuk mencari struk yang cocok dengan kriteria
+func searchReceipts(store *Store, q ReceiptQuery) []Receipt {
+	var found []Receipt
+	for _, r := range store.Receipts() {
+		if q.Match(r) {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+// Fungsi untuk membaca perkiraan nominal, mis. "78000", "78.000", "78rb", atau "78k"
+func parseApproxAmount(value string) (Money, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, suffix := range []string{"ribu", "rb", "k"} {
+		if num, ok := strings.CutSuffix(value, suffix); ok {
+			amount, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(num), ",", "."), 64)
+			if err != nil {
+				return 0, fmt.Errorf("Nominal tidak valid: %s", value)
+			}
+			return Rupiah(amount * 1000), nil
+		}
+	}
+	return parseSheetAmount(value)
+}
+
+// Ringkasan satu baris struk untuk daftar hasil pencarian
+func (r Receipt) Summary() string {
+	var items []string
+	for _, o := range r.Orders {
+		for _, l := range o.Lines {
+			items = append(items, fmt.Sprintf("%s x%d", l.Item.Name, l.Qty))
+		}
+	}
+	customer := "-"
+	if r.Customer != nil {
+		customer = r.Customer.Name
+	}
+	return fmt.Sprintf("%s  %s  Rp%10.2f  %-12s %s", r.Payment.ID, r.Payment.PaidAt.Local().Format("2006-01-02 15:04"),
+		r.Payment.Amount, customer, strings.Join(items, ", "))
+}
+
+// Mencetak ulang struk
+// Ditandai "SALINAN" agar tidak tertukar dengan struk asli
+func (r Receipt) Print(w io.Writer) {
+	r.write(w, tr("SALINAN STRUK"))
+}
+
+// Menulis isi struk dengan judul tertentu
+func (r Receipt) write(w io.Writer, title string) {
+	fmt.Fprintf(w, "========== %s ==========\n", title)
+	fmt.Fprintln(w, tr("No. pembayaran:"), r.Payment.ID)
+	fmt.Fprintln(w, tr("Waktu         :"), r.Payment.PaidAt.Local().Format("2006-01-02 15:04:05"))
+	if len(r.Orders) > 0 && r.Orders[0].Table != "" {
+		fmt.Fprintln(w, tr("Meja          :"), r.Orders[0].Table)
+	}
+	if len(r.Orders) > 0 && r.Orders[0].Type != "" {
+		fmt.Fprintln(w, tr("Jenis         :"), r.Orders[0].Type.Label())
+	}
+	if len(r.Orders) > 0 && r.Orders[0].DeliveryAddress != "" {
+		fmt.Fprintln(w, tr("Alamat        :"), r.Orders[0].DeliveryAddress)
+	}
+	if r.Customer != nil {
+		fmt.Fprint(w, tr("Pelanggan     : %s (%s)\n", r.Customer.Name, r.Customer.Phone))
+	}
+	for _, o := range r.Orders {
+		fmt.Fprint(w, tr("Pesanan %s [%s]\n", o.ID, o.Status))
+		if o.PickupCode != "" {
+			fmt.Fprintln(w, tr("Kode status   :"), o.PickupCode)
+		}
+		for _, l := range o.Lines {
+			fmt.Fprintf(w, "- %s x%d: Rp%.2f\n", l.Label(), l.Qty, l.Subtotal())
+			writePriceRuleNote(w, l)
+		}
+	}
+	writePriceBreakdown(w, r.Orders, r.Payment.Rounding)
+	if r.Payment.Deposit > 0 {
+		fmt.Fprint(w, tr("Deposit       : -Rp%.2f\n", r.Payment.Deposit))
+	}
+	if r.Payment.GiftVoucher > 0 {
+		fmt.Fprint(w, tr("Voucher %s : -Rp%.2f\n", r.Payment.VoucherCode, r.Payment.GiftVoucher))
+	}
+	fmt.Fprint(w, tr("Total         : Rp%.2f\n", r.Payment.Amount))
+	if r.Payment.Tip > 0 {
+		fmt.Fprint(w, tr("Tip           : Rp%.2f\n", r.Payment.Tip))
+	}
+	fmt.Fprint(w, tr("Dibayar       : Rp%.2f\n", r.Payment.Tendered))
+	if r.Payment.Currency != "" {
+		fmt.Fprint(w, tr("                %s %.2f x kurs Rp%.2f\n", r.Payment.Currency, r.Payment.ForeignTendered, r.Payment.FXRate))
+	}
+	fmt.Fprint(w, tr("Kembalian     : Rp%.2f\n", r.Payment.Change))
+	for _, ref := range r.Refunds {
+		fmt.Fprint(w, tr("Refund %s    : -Rp%.2f %s\n", ref.ID, ref.Amount, ref.Reason))
+	}
+	if r.Payment.WifiCode != "" {
+		fmt.Fprintln(w, tr("Wi-Fi tamu    :"), r.Payment.WifiCode)
+	}
+	fmt.Fprintln(w, "===================================")
+}
+
+// Fungsi untuk membaca flag pencarian struk
+func parseReceiptQuery(name string, args []string) (ReceiptQuery, error) {
+	var q ReceiptQuery
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	date := fs.String("date", "", "tanggal pembayaran YYYY-MM-DD, atau \"kemarin\"/\"hari-ini\"")
+	around := fs.String("time", "", "perkiraan jam pembayaran HH:MM")
+	window := fs.Int("window", 60, "selisih menit dari -time yang masih dianggap cocok")
+	amount := fs.String("amount", "", "perkiraan total, mis. 78000 atau 78rb")
+	tolerance := fs.Float64("tolerance", 1000, "selisih rupiah dari -amount yang masih dianggap cocok")
+	fs.StringVar(&q.Item, "item", "", "sebagian nama item")
+	fs.StringVar(&q.Customer, "customer", "", "nama, nomor HP, atau ID pelanggan")
+	if err := fs.Parse(args); err != nil {
+		return q, err
+	}
+
+	today := time.Now()
+	switch strings.ToLower(*date) {
+	case "":
+	case "hari-ini", "today":
+		q.Date = today
+	case "kemarin", "yesterday":
+		q.Date = today.AddDate(0, 0, -1)
+	default:
+		d, err := time.ParseInLocation("2006-01-02", *date, time.Local)
+		if err != nil {
+			return q, fmt.Errorf("Format tanggal harus YYYY-MM-DD")
+		}
+		q.Date = d
+	}
+	if *around != "" {
+		t, err := time.Parse("15:04", *around)
+		if err != nil {
+			return q, fmt.Errorf("Format jam harus HH:MM")
+		}
+		q.Around = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		q.Window = time.Duration(*window) * time.Minute
+		q.HasAround = true
+	}
+	if *amount != "" {
+		a, err := parseApproxAmount(*amount)
+		if err != nil {
+			return q, err
+		}
+		q.Amount, q.Tolerance = a, Rupiah(*tolerance)
+	}
+	return q, nil
+}
+
+// Fungsi untuk menjalankan sub-perintah "receipt"
+func runReceiptCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: receipt search|browse [filter] atau receipt show|reprint <id>")
+	}
+	switch args[0] {
+	case "search":
+		q, err := parseReceiptQuery("receipt search", args[1:])
+		if err != nil {
+			return err
+		}
+		found := searchReceipts(store, q)
+		if len(found) == 0 {
+			fmt.Println("Tidak ada struk yang cocok.")
+			return nil
+		}
+		for _, r := range found {
+			fmt.Println(r.Summary())
+		}
+		fmt.Printf("%d struk ditemukan. Cetak ulang dengan: receipt show <id>\n", len(found))
+	case "show":
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: receipt show <id pembayaran|id pesanan>")
+		}
+		r, err := store.Receipt(args[1])
+		if err != nil {
+			return err
+		}
+		r.Print(os.Stdout)
+	case "reprint":
+		fs := flag.NewFlagSet("receipt reprint", flag.ContinueOnError)
+		out := fs.String("out", "", "tulis ke file atau perangkat printer, mis. /dev/usb/lp0 (default: layar)")
+		printer := fs.Bool("printer", false, "cetak ke printer ESC/POS di konfigurasi")
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: receipt reprint <id pesanan|id pembayaran> [-out file] [-printer]")
+		}
+		if err := fs.Parse(args[2:]); err != nil {
+			return err
+		}
+		r, err := store.Receipt(args[1])
+		if err != nil {
+			return err
+		}
+		if *printer {
+			if !cfg.Printer.Enabled() {
+				return fmt.Errorf("printer.device atau printer.addr belum diatur di konfigurasi")
+			}
+			if err := printReceipt(cfg.Printer, r, tr("SALINAN STRUK")); err != nil {
+				return err
+			}
+			fmt.Println("Struk", r.Payment.ID, "dicetak ulang ke printer")
+			return nil
+		}
+		if *out == "" {
+			if cfg.Receipt.PDF() {
+				return fmt.Errorf("Struk PDF harus ditulis ke file, gunakan -out struk.pdf")
+			}
+			r.Print(os.Stdout)
+			return nil
+		}
+		f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+		if err != nil {
+			return err
+		}
+		if cfg.Receipt.PDF() {
+			err = r.renderPDF(f, cfg.Receipt, tr("SALINAN STRUK"))
+		} else {
+			r.Print(f)
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println("Struk", r.Payment.ID, "dicetak ulang ke", *out)
+	case "browse":
+		q, err := parseReceiptQuery("receipt browse", args[1:])
+		if err != nil {
+			return err
+		}
+		browseReceipts(searchReceipts(store, q))
+	default:
+		return fmt.Errorf("Sub-perintah receipt tidak dikenal: %s", args[0])
+	}
+	return nil
+}
+
+// Fungsi untuk menelusuri struk dengan tombol panah
+// Enter menampilkan struk terpilih untuk dicetak ulang, q keluar
+func browseReceipts(receipts []Receipt) {
+	if len(receipts) == 0 {
+		fmt.Println("Tidak ada struk yang cocok.")
+		return
+	}
+	var term *rawTerminal
+	var err error
+	if isTerminal(os.Stdin) {
+		term, err = enableRawMode()
+	}
+	if term == nil || err != nil {
+		for _, r := range receipts {
+			fmt.Println(r.Summary())
+		}
+		return
+	}
+	defer term.Restore()
+
+	cursor, showing := 0, false
+	for {
+		var b strings.Builder
+		b.WriteString("\x1b[H\x1b[2J")
+		if showing {
+			var sb strings.Builder
+			receipts[cursor].Print(&sb)
+			b.WriteString(strings.ReplaceAll(sb.String(), "\n", "\r\n"))
+			b.WriteString("\r\n(Enter/q kembali ke daftar)\r\n")
+		} else {
+			b.WriteString("Struk (↑/↓ pilih, Enter tampilkan, q keluar)\r\n\r\n")
+			for i, r := range receipts {
+				prefix := "  "
+				if i == cursor {
+					prefix = "> "
+				}
+				b.WriteString(prefix + r.Summary() + "\r\n")
+			}
+		}
+		fmt.Print(b.String())
+
+		switch readKey() {
+		case keyUp:
+			if !showing {
+				cursor = (cursor - 1 + len(receipts)) % len(receipts)
+			}
+		case keyDown:
+			if !showing {
+				cursor = (cursor + 1) % len(receipts)
+			}
+		case keyEnter:
+			showing = !showing
+		case keyCancel:
+			if !showing {
+				fmt.Print("\x1b[H\x1b[2J")
+				return
+			}
+			showing = false
+		}
+	}
+}
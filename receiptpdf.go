@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strings"
+)
+
+// Struct untuk Konfigurasi struk yang ditulis ke file atau dikirim lewat email
+type ReceiptConfig struct {
+	Format string `json:"format"` // Format struk: "text" (default) atau "pdf"
+	Paper  string `json:"paper"`  // Ukuran kertas PDF: "a6" (default), "80mm", atau "58mm"
+	Logo   string `json:"logo"`   // File PNG/JPEG logo di atas struk PDF, default printer.logo
+}
+
+// Memeriksa format dan ukuran kertas struk
+func (c ReceiptConfig) validate() error {
+	switch c.Format {
+	case "", "text", "pdf":
+	default:
+		return fmt.Errorf("receipt.format %q tidak dikenal (text atau pdf)", c.Format)
+	}
+	switch c.Paper {
+	case "", "a6", "80mm", "58mm":
+	default:
+		return fmt.Errorf("receipt.paper %q tidak dikenal (a6, 80mm, atau 58mm)", c.Paper)
+	}
+	return nil
+}
+
+// Struk ditulis sebagai PDF atau tidak
+func (c ReceiptConfig) PDF() bool {
+	return c.Format == "pdf"
+}
+
+// Pengaturan struk PDF untuk RenderPDF, diisi dari konfigurasi dan --receipt-format saat program dimulai
+var receiptPDF ReceiptConfig
+
+// Jumlah point PDF per milimeter
+const ptPerMM = 72 / 25.4
+
+// Lebar dan tinggi kertas PDF dalam point
+// Tinggi 0 berarti kertas thermal yang panjangnya mengikuti isi struk
+func (c ReceiptConfig) pageSize() (width, height float64) {
+	switch c.Paper {
+	case "80mm":
+		return 80 * ptPerMM, 0
+	case "58mm":
+		return 58 * ptPerMM, 0
+	}
+	return 105 * ptPerMM, 148 * ptPerMM
+}
+
+// Menulis struk sebagai PDF selebar kertas thermal atau A6, untuk dikirim lewat email atau diarsipkan
+// Ukuran kertas dan logo diambil dari konfigurasi receipt
+func (r Receipt) RenderPDF(w io.Writer) error {
+	return r.renderPDF(w, receiptPDF, tr("STRUK PEMBAYARAN"))
+}
+
+// Struct untuk satu baris teks di struk PDF
+type pdfLine struct {
+	text   string
+	bold   bool
+	size   float64
+	center bool
+}
+
+// Struct untuk logo yang disematkan ke PDF
+type pdfImage struct {
+	pixels        []byte // RGB 8 bit per kanal, sudah dikompres zlib
+	cols, rows    int    // Ukuran gambar dalam piksel
+	width, height float64
+}
+
+// Menulis struk PDF dengan judul tertentu
+// Isi struk sama dengan tampilan di layar, seperti struk ESC/POS, dengan huruf Courier bawaan PDF
+func (r Receipt) renderPDF(w io.Writer, cfg ReceiptConfig, title string) error {
+	const margin, size, titleSize = 12.0, 8.0, 12.0
+	var text bytes.Buffer
+	r.write(&text, title)
+	lines := strings.Split(strings.TrimRight(text.String(), "\n"), "\n")
+	if len(lines) >= 2 {
+		lines = lines[1 : len(lines)-1] // Garis judul dan penutup versi layar diganti judul PDF
+	}
+
+	pageWidth, pageHeight := cfg.pageSize()
+	// Lebar huruf Courier 0,6 kali ukurannya
+	cols := int((pageWidth - 2*margin) / (size * 0.6))
+	var body []pdfLine
+	for _, l := range wrapReceiptLine(escposText(title), int((pageWidth-2*margin)/(titleSize*0.6))) {
+		body = append(body, pdfLine{text: l, bold: true, size: titleSize, center: true})
+	}
+	body = append(body, pdfLine{text: strings.Repeat("-", cols), size: size})
+	totalLabel, _, _ := strings.Cut(tr("Total         : Rp%.2f\n", 0.0), "Rp")
+	for _, line := range lines {
+		bold := strings.HasPrefix(line, totalLabel)
+		for _, l := range wrapReceiptLine(escposText(line), cols) {
+			body = append(body, pdfLine{text: l, bold: bold, size: size})
+		}
+	}
+	body = append(body, pdfLine{text: strings.Repeat("-", cols), size: size})
+
+	var logo *pdfImage
+	if cfg.Logo != "" {
+		var err error
+		if logo, err = loadPDFLogo(cfg.Logo, pageWidth-2*margin, 60); err != nil {
+			return err
+		}
+	}
+	if pageHeight == 0 {
+		pageHeight = 2 * margin
+		if logo != nil {
+			pageHeight += logo.height + 6
+		}
+		for _, l := range body {
+			pageHeight += l.size * 1.25
+		}
+	}
+
+	// Baris yang tidak muat di kertas A6 dilanjutkan ke halaman berikutnya
+	var pages []*bytes.Buffer
+	page := &bytes.Buffer{}
+	pages = append(pages, page)
+	y := pageHeight - margin
+	if logo != nil {
+		y -= logo.height
+		fmt.Fprintf(page, "q %.2f 0 0 %.2f %.2f %.2f cm /Im1 Do Q\n", logo.width, logo.height, (pageWidth-logo.width)/2, y)
+		y -= 6
+	}
+	for _, l := range body {
+		lead := l.size * 1.25
+		if y-lead < margin {
+			page = &bytes.Buffer{}
+			pages = append(pages, page)
+			y = pageHeight - margin
+		}
+		y -= lead
+		x := margin
+		if l.center {
+			x = (pageWidth - float64(len(l.text))*l.size*0.6) / 2
+		}
+		font := "F1"
+		if l.bold {
+			font = "F2"
+		}
+		fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, l.size, x, y+l.size*0.2, pdfEscape(l.text))
+	}
+	return writePDF(w, pages, logo, pageWidth, pageHeight)
+}
+
+// Fungsi untuk meloloskan karakter khusus string PDF
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s)
+}
+
+// Fungsi untuk membaca logo PNG/JPEG dan memperkecilnya agar muat di lebar dan tinggi maksimal (point)
+// Piksel transparan digabung dengan latar putih karena PDF RGB tidak menyimpan transparansi
+func loadPDFLogo(path string, maxWidth, maxHeight float64) (*pdfImage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Logo struk tidak dapat dibuka: %w", err)
+	}
+	img, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("Logo struk harus berupa PNG atau JPEG: %w", err)
+	}
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW == 0 || srcH == 0 {
+		return nil, fmt.Errorf("Logo struk kosong: %s", path)
+	}
+	width := min(maxWidth, maxHeight*float64(srcW)/float64(srcH))
+	logo := &pdfImage{width: width, height: width * float64(srcH) / float64(srcW)}
+	// Resolusi cukup 4 piksel per point agar PDF tetap kecil untuk dikirim lewat email
+	logo.cols, logo.rows = min(srcW, int(width*4)), min(srcH, int(logo.height*4))
+	var raw bytes.Buffer
+	zw := zlib.NewWriter(&raw)
+	row := make([]byte, 3*logo.cols)
+	for y := range logo.rows {
+		for x := range logo.cols {
+			r, g, b, a := img.At(bounds.Min.X+x*srcW/logo.cols, bounds.Min.Y+y*srcH/logo.rows).RGBA()
+			// Warna RGBA sudah dikalikan alpha, sehingga latar putih ditambahkan sebesar sisa alphanya
+			white := 0xffff - a
+			row[3*x], row[3*x+1], row[3*x+2] = byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8)
+		}
+		zw.Write(row)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	logo.pixels = raw.Bytes()
+	return logo, nil
+}
+
+// Fungsi untuk menulis dokumen PDF dari isi setiap halaman
+// Objek: 1 katalog, 2 daftar halaman, 3-4 huruf Courier dan Courier-Bold, 5 logo jika ada, lalu halaman dan isinya
+func writePDF(w io.Writer, pages []*bytes.Buffer, logo *pdfImage, width, height float64) error {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	stream := func(dict string, data []byte) {
+		object(fmt.Sprintf("<<%s /Length %d>>\nstream\n%s\nendstream", dict, len(data), data))
+	}
+
+	first := 5
+	resources := "/Font <</F1 3 0 R /F2 4 0 R>>"
+	if logo != nil {
+		first = 6
+		resources += " /XObject <</Im1 5 0 R>>"
+	}
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", first+2*i)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<</Type /Catalog /Pages 2 0 R>>")
+	object(fmt.Sprintf("<</Type /Pages /Kids [%s] /Count %d>>", strings.Join(kids, " "), len(pages)))
+	object("<</Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding>>")
+	object("<</Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding>>")
+	if logo != nil {
+		stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+			logo.cols, logo.rows), logo.pixels)
+	}
+	for i, page := range pages {
+		object(fmt.Sprintf("<</Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources <<%s>> /Contents %d 0 R>>",
+			width, height, resources, first+2*i+1))
+		stream("", page.Bytes())
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<</Size %d /Root 1 0 R>>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
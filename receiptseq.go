@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct untuk nomor urut ID yang dilaporkan satu terminal ke server pusat
+// Dipakai untuk menemukan terminal yang mengulang nomor struk, mis. setelah dipulihkan dari cadangan lama
+type SequenceClaim struct {
+	TerminalID string         `json:"terminal_id"` // Terminal yang melapor
+	Branch     string         `json:"branch"`      // Kode cabang di ID sequence
+	Last       map[string]int `json:"last"`        // Nomor urut terakhir per prefix di file sequence terminal
+	ReportedAt time.Time      `json:"reported_at"` // Waktu laporan terakhir diterima server
+}
+
+// Struct untuk hasil pemeriksaan nomor urut terminal
+type SequenceCheck struct {
+	Issued   map[string]int `json:"issued"`             // Nomor tertinggi per prefix yang sudah tercatat di server untuk kode cabang
+	Overlaps []string       `json:"overlaps,omitempty"` // Keterangan nomor yang tumpang tindih
+}
+
+// Fungsi untuk membaca prefix dan nomor urut dari ID sequence kode cabang tertentu, mis. "ORD-JKT01-000042"
+func parseSequenceID(id, branch string) (string, int, bool) {
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 || !strings.EqualFold(parts[1], branch) {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return parts[0], n, true
+}
+
+// Mengambil nomor urut tertinggi per prefix yang sudah tercatat untuk kode cabang
+// Pesanan, draf, dan pelanggan diperiksa karena ketiganya memakai pembuat ID yang sama
+func (s *Store) IssuedSequences(branch string) map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issued := map[string]int{}
+	note := func(id string) {
+		if prefix, n, ok := parseSequenceID(id, branch); ok {
+			issued[prefix] = max(issued[prefix], n)
+		}
+	}
+	for _, o := range s.data.Orders {
+		note(o.ID)
+	}
+	for _, o := range s.data.Drafts {
+		note(o.ID)
+	}
+	for _, c := range s.data.Customers {
+		note(c.ID)
+	}
+	return issued
+}
+
+// Menyimpan laporan nomor urut terminal dan mengembalikan terminal lain yang memakai kode cabang yang sama
+func (s *Store) RecordSequenceClaim(c SequenceClaim) ([]SequenceClaim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var others []SequenceClaim
+	found := false
+	for i, existing := range s.data.Sequences {
+		switch {
+		case existing.TerminalID == c.TerminalID:
+			s.data.Sequences[i] = c
+			found = true
+		case strings.EqualFold(existing.Branch, c.Branch):
+			others = append(others, existing)
+		}
+	}
+	if !found {
+		s.data.Sequences = append(s.data.Sequences, c)
+	}
+	return others, s.save()
+}
+
+// Fungsi untuk mencocokkan nomor urut terminal dengan nomor yang sudah tercatat di server
+// Nomor terminal yang lebih rendah dari nomor tercatat berarti ID berikutnya akan mengulang struk lama
+func checkSequenceClaim(store *Store, c SequenceClaim) (SequenceCheck, error) {
+	check := SequenceCheck{Issued: store.IssuedSequences(c.Branch)}
+	for _, prefix := range slices.Sorted(maps.Keys(check.Issued)) {
+		if n := check.Issued[prefix]; c.Last[prefix] < n {
+			check.Overlaps = append(check.Overlaps, fmt.Sprintf("%s-%s: terminal %s di nomor %d, server sudah mencatat sampai %d",
+				prefix, strings.ToUpper(c.Branch), c.TerminalID, c.Last[prefix], n))
+		}
+	}
+	others, err := store.RecordSequenceClaim(c)
+	if err != nil {
+		return check, err
+	}
+	for _, o := range others {
+		check.Overlaps = append(check.Overlaps, fmt.Sprintf("Kode cabang %s juga dipakai terminal %s", strings.ToUpper(c.Branch), o.TerminalID))
+	}
+	return check, nil
+}
+
+// Fungsi untuk memberi peringatan nomor struk ganda di log server, audit, dan webhook pemantauan terminal
+// target adalah terminal yang melapor atau ID pesanan yang terulang
+func alertSequenceOverlap(cfg *Config, store *Store, target string, overlaps []string) {
+	for _, o := range overlaps {
+		fmt.Println("PERINGATAN:", o)
+	}
+	if err := store.Audit(Staff{Name: "system"}, "sequence.overlap", target, strings.Join(overlaps, "; ")); err != nil {
+		fmt.Println("Gagal mencatat audit:", err)
+	}
+	if cfg.Fleet.AlertWebhook == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]any{"event": "sequence.overlap", "target": target, "overlaps": overlaps})
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(cfg.Fleet.AlertWebhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Println("Gagal mengirim peringatan ke webhook:", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// POST /api/v1/terminal/sequence
+func (s *Server) handleTerminalSequence(w http.ResponseWriter, r *http.Request) {
+	var c SequenceClaim
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil || c.TerminalID == "" || c.Branch == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi terminal_id, branch, dan last")
+		return
+	}
+	c.ReportedAt = time.Now()
+	check, err := checkSequenceClaim(s.store, c)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(check.Overlaps) > 0 {
+		alertSequenceOverlap(s.cfg, s.store, c.TerminalID, check.Overlaps)
+	}
+	writeJSON(w, http.StatusOK, check)
+}
+
+// Mencocokkan nomor urut ID terminal dengan server pusat saat terminal mulai
+// Jika nomor terminal tertinggal (mis. file sequence ikut dipulihkan dari cadangan), nomor dilompatkan ke nomor tercatat
+// agar struk berikutnya tidak memakai nomor yang sudah dipakai
+func (b *remoteBackend) verifySequence(ids IDConfig) error {
+	g, ok := orderIDs.(*sequenceIDs)
+	if !ok {
+		return nil
+	}
+	last, err := g.Last()
+	if err != nil {
+		return err
+	}
+	var check SequenceCheck
+	claim := SequenceClaim{TerminalID: b.cfg.TerminalID, Branch: ids.Branch, Last: last}
+	if err := b.do(http.MethodPost, "/api/v1/terminal/sequence", claim, &check); err != nil {
+		return err
+	}
+	for _, o := range check.Overlaps {
+		fmt.Println(tr("PERINGATAN:"), o)
+	}
+	return g.FastForward(check.Issued)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var errTotalMismatch = errors.New("Total tagihan tidak cocok dengan rincian pesanan, pembayaran ditahan")
+
+// Struct untuk satu selisih antara nilai yang tercatat dan hasil hitung ulang
+type TotalDiscrepancy struct {
+	OrderID  string `json:"order_id,omitempty"` // Kosong untuk selisih di tingkat pembayaran
+	Field    string `json:"field"`              // subtotal, tax, rounding, total, amount, atau payment_rounding
+	Recorded Money  `json:"recorded"`           // Nilai yang terkumpul selama input pesanan
+	Expected Money  `json:"expected"`           // Nilai hasil hitung ulang dari baris pesanan
+}
+
+// Struct untuk laporan pencocokan total sebelum pembayaran diterima
+// Pesanan dan pembayaran disertakan apa adanya agar penyebab selisih bisa ditelusuri
+type ReconciliationReport struct {
+	PaymentID     string             `json:"payment_id"`
+	CheckedAt     time.Time          `json:"checked_at"`
+	Discrepancies []TotalDiscrepancy `json:"discrepancies"`
+	Orders        []Order            `json:"orders"`
+	Payment       Payment            `json:"payment"`
+}
+
+// Menandakan total cocok dengan rinciannya
+func (r ReconciliationReport) OK() bool {
+	return len(r.Discrepancies) == 0
+}
+
+// Fungsi untuk menghitung ulang total tagihan dari baris, harga berjadwal, pajak, ongkos kirim, deposit, dan voucher
+// lalu membandingkannya dengan total yang dijumlahkan sedikit demi sedikit selama input pesanan
+func reconcileTotals(pricing Pricing, orders []Order, payment Payment) ReconciliationReport {
+	report := ReconciliationReport{PaymentID: payment.ID, CheckedAt: time.Now(), Orders: orders, Payment: payment}
+	check := func(orderID, field string, recorded, expected Money) {
+		if recorded != expected {
+			report.Discrepancies = append(report.Discrepancies, TotalDiscrepancy{OrderID: orderID, Field: field, Recorded: recorded, Expected: expected})
+		}
+	}
+	var total Money
+	for _, o := range orders {
+		// Harga baris sudah diselesaikan oleh Pricing.Apply, sehingga harga berjadwal tidak dihitung dua kali
+		price := pricing.PriceOrder(o.Lines)
+		check(o.ID, "subtotal", o.Subtotal, price.Subtotal)
+		check(o.ID, "tax", o.Tax, price.Tax)
+		check(o.ID, "rounding", o.Rounding, price.Rounding)
+		check(o.ID, "total", o.Total, price.Total+o.DeliveryFee)
+		total += price.Total + o.DeliveryFee
+	}
+	amount, rounding := pricing.RoundPayment(total - payment.Deposit - payment.GiftVoucher)
+	check("", "amount", payment.Amount, amount)
+	check("", "payment_rounding", payment.Rounding, rounding)
+	return report
+}
+
+// Fungsi untuk menyimpan laporan selisih sebagai JSON di folder file data
+func dumpReconciliation(cfg *Config, report ReconciliationReport) (string, error) {
+	raw, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(filepath.Dir(cfg.DataFile), "reconcile-"+report.PaymentID+".json")
+	return path, os.WriteFile(path, raw, 0o600)
+}
+
+// Fungsi untuk memastikan total tagihan cocok dengan rincian pesanan sebelum pembayaran diterima
+// Jika tidak cocok, selisihnya ditampilkan dan disimpan, lalu pembayaran ditahan
+func verifyPaymentTotals(cfg *Config, pricing Pricing, orders []Order, payment Payment) error {
+	report := reconcileTotals(pricing, orders, payment)
+	if report.OK() {
+		return nil
+	}
+	fmt.Println(tr("PERINGATAN:"), errTotalMismatch)
+	for _, d := range report.Discrepancies {
+		target := d.OrderID
+		if target == "" {
+			target = payment.ID
+		}
+		fmt.Print(tr("  %s %s: tercatat Rp%.2f, dihitung ulang Rp%.2f\n", target, d.Field, d.Recorded, d.Expected))
+	}
+	if path, err := dumpReconciliation(cfg, report); err != nil {
+		fmt.Println(tr("Laporan selisih gagal disimpan:"), err)
+	} else {
+		fmt.Println(tr("Laporan selisih disimpan di"), path)
+	}
+	return errTotalMismatch
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Jenis baris di file rekaman sesi kasir
+const (
+	recordStart = "start" // Menu dan konfigurasi yang berlaku saat sesi dimulai
+	recordInput = "input" // Satu baris input kasir yang sudah dianonimkan
+	recordState = "state" // Hasil yang terlihat setelah input, mis. total pesanan dan kembalian
+)
+
+// Struct untuk satu baris di file rekaman sesi kasir (JSON Lines)
+type sessionRecord struct {
+	Kind   string `json:"kind"`
+	Offset int64  `json:"offset_ms"` // Milidetik sejak sesi dimulai
+
+	Prompt string `json:"prompt,omitempty"` // Prompt terakhir sebelum input
+	Text   string `json:"text,omitempty"`   // Input yang sudah dianonimkan
+	Secret bool   `json:"secret,omitempty"` // Nama staf atau PIN; isinya tidak disimpan dan dilewati saat replay
+
+	Event  string `json:"event,omitempty"`  // Jenis perubahan state, mis. "order" atau "payment"
+	Detail string `json:"detail,omitempty"` // Ringkasan state yang dibandingkan saat replay
+
+	Start *sessionStart `json:"start,omitempty"`
+}
+
+// Struct untuk lingkungan sesi yang dibutuhkan agar replay menghitung dengan aturan yang sama
+// Hanya bagian konfigurasi yang memengaruhi alur kasir yang disimpan; API key, SMTP, dan gateway tidak ikut
+type sessionStart struct {
+	At         time.Time     `json:"at"`
+	Version    string        `json:"version"`
+	Language   string        `json:"language"`
+	Menu       []MenuItem    `json:"menu"`
+	Cashier    CashierConfig `json:"cashier"`
+	Pricing    PricingConfig `json:"pricing"`
+	OrderRules []OrderRule   `json:"order_rules,omitempty"`
+	Limits     OrderLimits   `json:"order_limits"`
+}
+
+// Struct untuk perekam sesi kasir
+// Setiap baris langsung ditulis ke file agar sesi yang berhenti mendadak tetap terekam
+type sessionRecorder struct {
+	mu      sync.Mutex
+	w       *bufio.Writer
+	f       *os.File
+	started time.Time
+	secret  bool            // Input berikutnya adalah nama staf atau PIN
+	keep    bool            // Simpan perubahan state di memori, bukan ke file; dipakai saat replay
+	states  []sessionRecord // Perubahan state yang dikumpulkan saat replay untuk dibandingkan
+}
+
+// Perekam sesi yang sedang berjalan, nil jika perekaman tidak diaktifkan dengan -record
+var activeRecorder *sessionRecorder
+
+// Regex untuk data pribadi yang disamarkan dari input: alamat email dan nomor HP
+var (
+	emailPattern = regexp.MustCompile(`[^\s@]+@[^\s@]+`)
+	phonePattern = regexp.MustCompile(`\+?[0-9][0-9 \-]{7,}[0-9]`)
+)
+
+// Prompt yang inputnya selalu disamarkan karena berisi identitas pelanggan
+// Dicocokkan dengan prompt yang sudah diterjemahkan, sehingga kedua bahasa disebut
+var privatePrompts = []string{"masukkan nama", "enter name", "alamat", "address", "email"}
+
+// Fungsi untuk membuka perekam sesi ke file
+func startRecorder(path string) (*sessionRecorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("File rekaman sesi tidak dapat dibuat: %w", err)
+	}
+	return &sessionRecorder{w: bufio.NewWriter(f), f: f, started: time.Now()}, nil
+}
+
+// Menutup file rekaman
+func (r *sessionRecorder) Close() error {
+	if r == nil || r.f == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.w.Flush()
+	return r.f.Close()
+}
+
+// Menulis satu baris rekaman
+// Pemanggil harus memegang r.mu
+func (r *sessionRecorder) write(rec sessionRecord) {
+	rec.Offset = time.Since(r.started).Milliseconds()
+	if r.keep {
+		if rec.Kind == recordState {
+			r.states = append(r.states, rec)
+		}
+		return
+	}
+	raw, _ := json.Marshal(rec)
+	r.w.Write(append(raw, '\n'))
+	r.w.Flush()
+}
+
+// Mencatat menu dan konfigurasi saat sesi kasir dimulai
+func (r *sessionRecorder) Start(cfg *Config, menu []MenuItem) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cashier := cfg.Cashier
+	cashier.IntakeAddr = ""
+	r.write(sessionRecord{Kind: recordStart, Start: &sessionStart{
+		At: time.Now(), Version: version, Language: cfg.Language, Menu: menu,
+		Cashier: cashier, Pricing: cfg.Pricing, OrderRules: cfg.OrderRules,
+	}})
+}
+
+// Menandai input berikutnya sebagai nama staf atau PIN
+func (r *sessionRecorder) Secret() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.secret = true
+}
+
+// Mencatat satu baris input kasir setelah data pribadi disamarkan
+func (r *sessionRecorder) Input(prompt, line string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec := sessionRecord{Kind: recordInput, Prompt: prompt}
+	if r.secret {
+		rec.Secret = true
+		r.secret = false
+	} else {
+		rec.Text = anonymizeInput(prompt, line)
+	}
+	r.write(rec)
+}
+
+// Mencatat perubahan state yang terlihat kasir
+func (r *sessionRecorder) State(event, format string, args ...any) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.write(sessionRecord{Kind: recordState, Event: event, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Fungsi untuk menyamarkan data pribadi di input kasir
+// Input kosong tetap kosong karena Enter sering berarti "lewati" dan menentukan alur berikutnya
+func anonymizeInput(prompt, line string) string {
+	if line == "" {
+		return ""
+	}
+	lower := strings.ToLower(prompt)
+	for _, p := range privatePrompts {
+		if strings.Contains(lower, p) {
+			return "x"
+		}
+	}
+	line = emailPattern.ReplaceAllString(line, "pelanggan@example.invalid")
+	return phonePattern.ReplaceAllStringFunc(line, func(s string) string {
+		return strings.Repeat("0", len(s))
+	})
+}
+
+// Fungsi untuk meringkas baris pesanan untuk state rekaman, mis. "Es Teh (Kecil, Normal) x1, Ayam Bakar x2"
+func orderSummary(o Order) string {
+	var lines []string
+	for _, l := range o.Lines {
+		lines = append(lines, fmt.Sprintf("%s x%d", l.Label(), l.Qty))
+	}
+	return strings.Join(lines, ", ")
+}
+
+// Fungsi untuk membaca file rekaman sesi
+func readSessionRecording(path string) (sessionStart, []sessionRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return sessionStart{}, nil, err
+	}
+	defer f.Close()
+	var start *sessionStart
+	var records []sessionRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var rec sessionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return sessionStart{}, nil, fmt.Errorf("Baris %d rekaman tidak valid: %w", line, err)
+		}
+		if rec.Kind == recordStart && start == nil {
+			start = rec.Start
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return sessionStart{}, nil, err
+	}
+	if start == nil {
+		return sessionStart{}, nil, errors.New("Rekaman tidak berisi awal sesi; pastikan file dibuat dengan -record")
+	}
+	return *start, records, nil
+}
+
+// Fungsi untuk menyusun ulang input yang diputar ke kasir dari rekaman
+// Nama staf dan PIN dilewati karena replay berjalan tanpa staf terdaftar
+// Nomor HP member yang dikenali saat rekaman diganti Enter, sebab data pelanggan tidak ikut direkam
+func replayInputs(records []sessionRecord) []string {
+	var inputs []string
+	for i, rec := range records {
+		if rec.Kind != recordInput || rec.Secret {
+			continue
+		}
+		text := rec.Text
+		if i+1 < len(records) && records[i+1].Kind == recordState && records[i+1].Event == "customer.member" {
+			text = ""
+		}
+		inputs = append(inputs, text)
+	}
+	return inputs
+}
+
+// Fungsi untuk menjalankan "session replay": memutar ulang rekaman sesi kasir di data sementara
+// lalu membandingkan setiap perubahan state dengan rekaman aslinya
+func runSessionCommand(args []string) error {
+	if len(args) == 0 || args[0] != "replay" {
+		return fmt.Errorf("Gunakan: session replay <file-rekaman>")
+	}
+	fs := flag.NewFlagSet("session replay", flag.ContinueOnError)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("Gunakan: session replay <file-rekaman>")
+	}
+	start, records, err := readSessionRecording(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "tugaskedua-replay-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	cfg := &Config{DataFile: filepath.Join(dir, "data.json"), Language: start.Language, Cashier: start.Cashier, Pricing: start.Pricing, OrderRules: start.OrderRules, OrderLimits: start.Limits}
+	cfg.applyDefaults()
+	// Replay selalu memakai mode teks tanpa kunci sesi, tablet pelayan, atau email
+	cfg.Cashier.TUI, cfg.Cashier.LockAfterMinutes, cfg.Cashier.EmailReceipt = false, 0, false
+	if err := setLanguage(cfg.Language); err != nil {
+		return err
+	}
+	store, err := openStore(cfg.DataFile)
+	if err != nil {
+		return err
+	}
+	if len(start.Menu) > 0 {
+		if err := store.SetMenu(start.Menu, "menu awal sesi rekaman"); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Memutar ulang sesi %s (versi %s)\n\n", start.At.Local().Format("2006-01-02 15:04:05"), start.Version)
+	input = bufio.NewScanner(strings.NewReader(strings.Join(replayInputs(records), "\n")))
+	activeRecorder = &sessionRecorder{keep: true, started: time.Now()}
+	runErr := runCashier(newLocalBackend(cfg, store), cfg, nil)
+	replayed := activeRecorder.states
+	activeRecorder = nil
+
+	var recorded []sessionRecord
+	for _, rec := range records {
+		// Member yang dikenali hanya petunjuk untuk replayInputs, bukan state yang dibandingkan
+		if rec.Kind == recordState && rec.Event != "customer.member" {
+			recorded = append(recorded, rec)
+		}
+	}
+	fmt.Println()
+	fmt.Println("Perbandingan state rekaman dan replay:")
+	mismatch := false
+	for i := range max(len(recorded), len(replayed)) {
+		var want, got string
+		if i < len(recorded) {
+			want = recorded[i].Event + " " + recorded[i].Detail
+		}
+		if i < len(replayed) {
+			got = replayed[i].Event + " " + replayed[i].Detail
+		}
+		if want == got {
+			fmt.Printf("  = %s\n", want)
+			continue
+		}
+		mismatch = true
+		fmt.Printf("  ! rekaman: %s\n    replay : %s\n", orDash(want), orDash(got))
+	}
+	if runErr != nil && !errors.Is(runErr, errInputClosed) {
+		fmt.Println("Kasir berhenti dengan error:", runErr)
+	}
+	if !mismatch {
+		fmt.Println("Replay sama dengan rekaman.")
+		return nil
+	}
+	if !sameDay(start.At.Local(), time.Now()) || start.At.Local().Hour() != time.Now().Hour() {
+		fmt.Println("Catatan: harga berjadwal dihitung dengan jam saat replay, bukan jam rekaman.")
+	}
+	return errors.New("Replay berbeda dengan rekaman")
+}
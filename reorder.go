@@ -0,0 +1,204 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Struct untuk Konfigurasi peringatan stok dan saran pembelian bahan
+type InventoryConfig struct {
+	LowPortions int `json:"low_portions"` // Item menu yang sisa porsinya di bawah ini diperingatkan, 0 berarti hanya batas minimal bahan
+	UsageDays   int `json:"usage_days"`   // Jumlah hari pemakaian terakhir untuk rata-rata pemakaian harian, default 7
+	CoverDays   int `json:"cover_days"`   // Saran pembelian mencukupi pemakaian sekian hari, default 3
+}
+
+// Memeriksa pengaturan persediaan
+func (c InventoryConfig) validate() error {
+	if c.LowPortions < 0 || c.UsageDays < 0 || c.CoverDays < 0 {
+		return fmt.Errorf("inventory: low_portions, usage_days, dan cover_days tidak boleh negatif")
+	}
+	return nil
+}
+
+// Struct untuk peringatan stok menipis
+type StockAlert struct {
+	Name      string  `json:"name"`      // Nama bahan atau item menu
+	Item      bool    `json:"item"`      // true untuk sisa porsi item menu, false untuk stok bahan
+	Stock     float64 `json:"stock"`     // Sisa stok bahan, atau sisa porsi item
+	Unit      string  `json:"unit"`      // Satuan stok bahan, kosong untuk item
+	Threshold float64 `json:"threshold"` // Batas peringatan yang terlewati
+}
+
+// Keterangan peringatan untuk layar kasir
+func (a StockAlert) String() string {
+	if a.Item {
+		return tr("%s tinggal %d porsi (batas %d porsi)", a.Name, int(a.Stock), int(a.Threshold))
+	}
+	return tr("Bahan %s tinggal %g %s (minimal %g %s)", a.Name, a.Stock, a.Unit, a.Threshold, a.Unit)
+}
+
+// Sisa porsi item dari bahan resep yang tercatat di persediaan; pemanggil harus memegang s.mu
+// Mengembalikan false jika tidak ada bahan item yang dilacak
+func (s *Store) portionsLeft(item MenuItem) (float64, bool) {
+	portions, tracked := math.Inf(1), false
+	for _, r := range item.Recipe {
+		if ing := s.findIngredient(r.Ingredient); ing != nil && r.Qty > 0 {
+			portions, tracked = min(portions, math.Floor(ing.Stock/r.Qty)), true
+		}
+	}
+	return max(portions, 0), tracked
+}
+
+// Mengambil bahan yang stoknya di bawah batas minimal dan item yang sisa porsinya di bawah lowPortions
+func (s *Store) StockAlerts(lowPortions int) []StockAlert {
+	menu := s.Menu().Menu
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var alerts []StockAlert
+	for _, ing := range s.data.Ingredients {
+		if ing.Min > 0 && ing.Stock < ing.Min {
+			alerts = append(alerts, StockAlert{Name: ing.Name, Stock: ing.Stock, Unit: ing.Unit, Threshold: ing.Min})
+		}
+	}
+	if lowPortions <= 0 {
+		return alerts
+	}
+	for _, item := range menu {
+		if portions, ok := s.portionsLeft(item); ok && portions < float64(lowPortions) {
+			alerts = append(alerts, StockAlert{Name: item.Name, Item: true, Stock: portions, Threshold: float64(lowPortions)})
+		}
+	}
+	return alerts
+}
+
+// Fungsi untuk menampilkan peringatan stok yang menyangkut pesanan yang baru dibayar
+// Peringatan bahan dan item lain tidak diulang di setiap pembayaran; daftar lengkapnya ada di "report reorder"
+func warnLowStock(w io.Writer, backend CashierBackend, orders []Order) {
+	alerts, err := backend.StockAlerts()
+	if err != nil || len(alerts) == 0 {
+		return
+	}
+	used := map[string]bool{}
+	for _, o := range orders {
+		for _, l := range o.Lines {
+			used["item:"+strings.ToLower(l.Item.Name)] = true
+			for _, r := range l.Item.Recipe {
+				used["bahan:"+strings.ToLower(r.Ingredient)] = true
+			}
+		}
+	}
+	for _, a := range alerts {
+		key := "bahan:" + strings.ToLower(a.Name)
+		if a.Item {
+			key = "item:" + strings.ToLower(a.Name)
+		}
+		if used[key] {
+			fmt.Fprintln(w, tr("PERINGATAN STOK:"), a)
+		}
+	}
+}
+
+// Struct untuk satu baris saran pembelian bahan
+type ReorderLine struct {
+	Ingredient Ingredient
+	DailyUsage float64 // Rata-rata pemakaian per hari
+	DaysLeft   float64 // Perkiraan stok habis dalam sekian hari, +Inf jika tidak terpakai
+	Suggested  float64 // Saran jumlah pembelian, dibulatkan ke atas
+}
+
+// Fungsi untuk menyusun saran pembelian bahan dari stok dan rata-rata pemakaian
+// Pemakaian dihitung dari resep baris pesanan yang dibayar dan tidak dibatalkan selama usageDays terakhir
+// Bahan disarankan dibeli jika stoknya di bawah minimal atau tidak cukup untuk coverDays;
+// jumlahnya mengisi stok sampai par, minimal, atau pemakaian coverDays, mana yang terbesar
+func buildReorder(store *Store, usageDays, coverDays int, now time.Time) []ReorderLine {
+	since := now.AddDate(0, 0, -usageDays)
+	usage := map[string]float64{}
+	for _, o := range store.Orders() {
+		if o.PaymentID == "" || o.Status == OrderCancelled || o.CreatedAt.Before(since) || o.CreatedAt.After(now) {
+			continue
+		}
+		for _, l := range o.Lines {
+			for _, r := range l.Item.Recipe {
+				usage[strings.ToLower(r.Ingredient)] += r.Qty * float64(l.Qty)
+			}
+		}
+	}
+	var lines []ReorderLine
+	for _, ing := range store.Ingredients() {
+		daily := usage[strings.ToLower(ing.Name)] / float64(max(usageDays, 1))
+		cover := daily * float64(coverDays)
+		if ing.Stock >= ing.Min && ing.Stock >= cover {
+			continue
+		}
+		line := ReorderLine{Ingredient: ing, DailyUsage: daily, DaysLeft: math.Inf(1)}
+		if daily > 0 {
+			line.DaysLeft = max(ing.Stock, 0) / daily
+		}
+		line.Suggested = math.Ceil(max(ing.Par, ing.Min, cover) - ing.Stock)
+		if line.Suggested <= 0 {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	slices.SortFunc(lines, func(a, b ReorderLine) int {
+		if c := cmp.Compare(a.DaysLeft, b.DaysLeft); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Ingredient.Name, b.Ingredient.Name)
+	})
+	return lines
+}
+
+// Fungsi untuk menampilkan saran pembelian bahan beserta item menu yang hampir habis
+func printReorder(w io.Writer, lines []ReorderLine, items []StockAlert, usageDays, coverDays int) {
+	fmt.Fprintf(w, "Saran Pembelian Bahan (pemakaian %d hari terakhir, stok untuk %d hari)\n", usageDays, coverDays)
+	if len(lines) == 0 {
+		fmt.Fprintln(w, "Semua bahan masih cukup, tidak ada yang perlu dibeli.")
+	} else {
+		fmt.Fprintf(w, "%-16s %10s %10s %10s %8s %11s %s\n", "Bahan", "Stok", "Minimal", "Pakai/hari", "Cukup", "Saran beli", "Satuan")
+		for _, l := range lines {
+			days := "-"
+			if !math.IsInf(l.DaysLeft, 1) {
+				days = fmt.Sprintf("%.1f hr", l.DaysLeft)
+			}
+			fmt.Fprintf(w, "%-16s %10.2f %10.2f %10.2f %8s %11.0f %s\n", l.Ingredient.Name, l.Ingredient.Stock, l.Ingredient.Min,
+				l.DailyUsage, days, l.Suggested, l.Ingredient.Unit)
+		}
+	}
+	var low []StockAlert
+	for _, a := range items {
+		if a.Item {
+			low = append(low, a)
+		}
+	}
+	if len(low) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nItem menu hampir habis:")
+	for _, a := range low {
+		fmt.Fprintf(w, "- %s: sisa %d porsi\n", a.Name, int(a.Stock))
+	}
+}
+
+// Fungsi untuk menjalankan "report reorder"
+func runReorderReport(cfg *Config, store *Store, args []string) error {
+	fs := flag.NewFlagSet("report reorder", flag.ContinueOnError)
+	usageDays := fs.Int("days", cfg.Inventory.UsageDays, "jumlah hari pemakaian terakhir untuk rata-rata harian")
+	coverDays := fs.Int("cover", cfg.Inventory.CoverDays, "saran pembelian mencukupi pemakaian sekian hari")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *usageDays < 1 || *coverDays < 0 {
+		return fmt.Errorf("--days minimal 1 dan --cover tidak boleh negatif")
+	}
+	lines := buildReorder(store, *usageDays, *coverDays, time.Now())
+	printReorder(os.Stdout, lines, store.StockAlerts(cfg.Inventory.LowPortions), *usageDays, *coverDays)
+	return nil
+}
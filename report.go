@@ -0,0 +1,494 @@
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct untuk Laporan harian
+// Merangkum penjualan, pembatalan, dan refund pada satu tanggal
+type DailyReport struct {
+	Date           time.Time // Tanggal laporan
+	Orders         int       // Jumlah pesanan yang dibayar
+	GrossSales     Money     // Total penjualan sebelum refund, termasuk data historis
+	Tax            Money     // Pajak yang terkumpul dari pesanan yang dibayar
+	HistoricSales  Money     // Bagian penjualan yang berasal dari impor spreadsheet
+	Cancelled      int       // Jumlah pesanan yang dibatalkan
+	Refunds        int       // Jumlah refund
+	RefundedAmount Money     // Total dana yang dikembalikan
+	NetSales       Money     // Penjualan bersih setelah refund
+
+	Methods  map[PaymentMethod]Money  // Total pembayaran per metode, tidak termasuk data historis
+	Channels map[string]*ChannelSales // Penjualan per kanal pesanan online
+	Tips     map[string]Money         // Tip per nama pelayan, tidak termasuk penjualan
+}
+
+// Fungsi untuk memeriksa apakah dua waktu berada di tanggal yang sama
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// Fungsi untuk menyusun laporan harian dari rekap penjualan
+// Penjualan dihitung dari waktu pembayaran, refund dari waktu refund dicatat
+func buildDailyReport(store *Store, date time.Time) DailyReport {
+	day := store.DailyProjection(date)
+	report := DailyReport{
+		Date:           date,
+		Orders:         day.Orders,
+		GrossSales:     day.GrossSales,
+		Tax:            day.Tax,
+		Cancelled:      day.Cancelled,
+		Refunds:        day.Refunds,
+		RefundedAmount: day.RefundedAmount,
+		Methods:        day.Methods,
+		Channels:       day.Channels,
+		Tips:           tipsByName(day.Tips, store.Staff()),
+	}
+	for _, h := range store.History() {
+		if sameDay(h.Date, date) {
+			report.Orders += h.Transactions
+			report.GrossSales += h.Revenue
+			report.HistoricSales += h.Revenue
+		}
+	}
+	report.NetSales = report.GrossSales - report.RefundedAmount
+	return report
+}
+
+// Menampilkan laporan harian
+func (r DailyReport) Print(w io.Writer) {
+	fmt.Fprintf(w, "Laporan Harian %s\n", r.Date.Format("2006-01-02"))
+	fmt.Fprintf(w, "Pesanan dibayar   : %d\n", r.Orders)
+	fmt.Fprintf(w, "Penjualan kotor   : Rp%.2f\n", r.GrossSales)
+	if r.HistoricSales > 0 {
+		fmt.Fprintf(w, "  dari data impor : Rp%.2f\n", r.HistoricSales)
+	}
+	if r.Tax != 0 {
+		fmt.Fprintf(w, "  sebelum pajak   : Rp%.2f\n", r.GrossSales-r.Tax)
+		fmt.Fprintf(w, "  pajak           : Rp%.2f\n", r.Tax)
+	}
+	for _, method := range []PaymentMethod{MethodCash, MethodCard, MethodQRIS, MethodChannel} {
+		if amount, ok := r.Methods[method]; ok {
+			fmt.Fprintf(w, "  %-16s: Rp%.2f\n", method, amount)
+		}
+	}
+	if len(r.Channels) > 0 {
+		fmt.Fprintln(w, "Penjualan per kanal:")
+		orders, sales := r.Orders, r.GrossSales-r.HistoricSales
+		for _, name := range slices.Sorted(maps.Keys(r.Channels)) {
+			c := r.Channels[name]
+			orders, sales = orders-c.Orders, sales-c.Sales
+			fmt.Fprintf(w, "  %-16s: %d pesanan Rp%.2f, komisi Rp%.2f, bersih Rp%.2f\n", name, c.Orders, c.Sales, c.Commission, c.Sales-c.Commission)
+		}
+		fmt.Fprintf(w, "  %-16s: %d pesanan Rp%.2f\n", "kasir", orders, sales)
+	}
+	writeTips(w, r.Tips)
+	fmt.Fprintf(w, "Pesanan dibatalkan: %d\n", r.Cancelled)
+	fmt.Fprintf(w, "Refund            : %d (Rp%.2f)\n", r.Refunds, r.RefundedAmount)
+	fmt.Fprintf(w, "Penjualan bersih  : Rp%.2f\n", r.NetSales)
+}
+
+// Struct untuk rekap penyelesaian (settlement) per metode pembayaran
+type SettlementLine struct {
+	Method    PaymentMethod // Metode pembayaran
+	Payments  int           // Jumlah pembayaran
+	Collected Money         // Total dana yang diterima
+	Tips      Money         // Tip yang diterima bersama pembayaran, terpisah dari tagihan
+	Deposits  Money         // Deposit reservasi yang diterima, termasuk yang nantinya menjadi biaya no-show
+	Refunded  Money         // Total refund yang dikembalikan lewat metode ini
+	Held      Money         // Dana yang ditahan karena sengketa masih terbuka
+	Deducted  Money         // Dana yang dipotong karena sengketa kalah/diterima dan biaya chargeback
+	Expected  Money         // Dana yang seharusnya masuk rekening/laci
+}
+
+// Struct untuk Laporan rekonsiliasi settlement
+// Dipakai untuk mencocokkan laci kasir dan laporan settlement gateway pada satu tanggal
+type SettlementReport struct {
+	Date         time.Time
+	Lines        []SettlementLine
+	OpenDisputes []Dispute // Sengketa yang masih terbuka pada tanggal laporan
+}
+
+// Fungsi untuk menyusun laporan rekonsiliasi settlement
+// Sengketa dihitung pada tanggal diajukan (dana ditahan, biaya dipotong) dan tanggal diselesaikan (dana dilepas atau dipotong)
+func buildSettlementReport(store *Store, date time.Time) SettlementReport {
+	report := SettlementReport{Date: date}
+	lines := map[PaymentMethod]*SettlementLine{}
+	line := func(method PaymentMethod) *SettlementLine {
+		if lines[method] == nil {
+			lines[method] = &SettlementLine{Method: method}
+		}
+		return lines[method]
+	}
+	methods := map[string]PaymentMethod{}
+	for _, p := range store.Payments() {
+		methods[p.ID] = p.PaymentMethod()
+		if sameDay(p.PaidAt, date) {
+			l := line(p.PaymentMethod())
+			l.Payments++
+			l.Collected += p.Amount
+			l.Tips += p.Tip
+		}
+	}
+	// Deposit ditagih lewat QRIS saat reservasi dibuat, terpisah dari pembayaran tagihan
+	for _, r := range store.Reservations() {
+		if r.Deposit > 0 && !r.DepositPaidAt.IsZero() && sameDay(r.DepositPaidAt, date) {
+			line(MethodQRIS).Deposits += r.Deposit
+		}
+	}
+	for _, r := range store.Refunds() {
+		if sameDay(r.CreatedAt, date) {
+			method := r.Method
+			if method == "" {
+				method = methods[r.PaymentID]
+			}
+			line(method).Refunded += r.ProviderAmount()
+		}
+	}
+	for _, d := range store.Disputes() {
+		l := line(methods[d.PaymentID])
+		if sameDay(d.OpenedAt, date) {
+			l.Held += d.Amount
+			l.Deducted += d.Fee
+		}
+		if d.Resolved() && sameDay(d.ResolvedAt, date) {
+			l.Held -= d.Amount // Dana yang ditahan dilepas saat sengketa selesai
+			if d.Status != DisputeWon {
+				l.Deducted += d.Amount
+			}
+		}
+		if !d.Resolved() && !d.OpenedAt.After(endOfDay(date)) {
+			report.OpenDisputes = append(report.OpenDisputes, d)
+		}
+	}
+	for _, method := range []PaymentMethod{MethodCash, MethodCard, MethodQRIS} {
+		if l := lines[method]; l != nil {
+			l.Expected = l.Collected + l.Tips + l.Deposits - l.Refunded - l.Held - l.Deducted
+			report.Lines = append(report.Lines, *l)
+		}
+	}
+	return report
+}
+
+// Fungsi untuk mendapatkan akhir hari dari sebuah tanggal
+func endOfDay(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 23, 59, 59, 0, date.Location())
+}
+
+// Menampilkan laporan rekonsiliasi settlement
+func (r SettlementReport) Print(w io.Writer) {
+	fmt.Fprintf(w, "Rekonsiliasi Settlement %s\n", r.Date.Format("2006-01-02"))
+	if len(r.Lines) == 0 {
+		fmt.Fprintln(w, "Tidak ada transaksi.")
+	}
+	for _, l := range r.Lines {
+		fmt.Fprintf(w, "[%s] %d pembayaran\n", l.Method, l.Payments)
+		fmt.Fprintf(w, "  Diterima          : Rp%.2f\n", l.Collected)
+		if l.Tips > 0 {
+			fmt.Fprintf(w, "  Tip               : Rp%.2f\n", l.Tips)
+		}
+		if l.Deposits > 0 {
+			fmt.Fprintf(w, "  Deposit reservasi : Rp%.2f\n", l.Deposits)
+		}
+		fmt.Fprintf(w, "  Refund            : Rp%.2f\n", l.Refunded)
+		fmt.Fprintf(w, "  Ditahan sengketa  : Rp%.2f\n", l.Held)
+		fmt.Fprintf(w, "  Dipotong sengketa : Rp%.2f\n", l.Deducted)
+		fmt.Fprintf(w, "  Seharusnya masuk  : Rp%.2f\n", l.Expected)
+	}
+	if len(r.OpenDisputes) > 0 {
+		fmt.Fprintf(w, "Sengketa terbuka: %d\n", len(r.OpenDisputes))
+		for _, d := range r.OpenDisputes {
+			fmt.Fprintf(w, "- %s pembayaran %s Rp%.2f (%s)\n", d.ID, d.PaymentID, d.Amount, d.Reason)
+		}
+	}
+}
+
+// Struct untuk baris laporan item terlaris
+type ItemSales struct {
+	Name     string // Nama item menu
+	Category string // Kategori item menu
+	Qty      int    // Jumlah terjual setelah refund
+	Revenue  Money  // Pendapatan setelah refund
+}
+
+// Fungsi untuk menyusun laporan item terlaris dalam rentang tanggal (inklusif) dari rekap penjualan harian
+// Pesanan dibatalkan tidak dihitung dan item yang di-refund dikurangi; rekap historis ikut dijumlahkan
+func buildTopItems(store *Store, from, to time.Time, sortBy string) []ItemSales {
+	inRange := func(t time.Time) bool {
+		t = t.Local()
+		return !t.Before(from) && !t.After(endOfDay(to))
+	}
+	totals := map[string]*ItemSales{}
+	add := func(name, category string, qty int, revenue Money) {
+		key := strings.ToLower(name)
+		if totals[key] == nil {
+			totals[key] = &ItemSales{Name: name, Category: category}
+		}
+		totals[key].Qty += qty
+		totals[key].Revenue += revenue
+	}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, item := range store.DailyProjection(day).Items {
+			if item.Qty != 0 || item.Revenue != 0 {
+				add(item.Name, item.Category, item.Qty, item.Revenue)
+			}
+		}
+	}
+	for _, h := range store.History() {
+		if inRange(h.Date) {
+			add(h.Item, h.Category, h.Qty, h.Revenue)
+		}
+	}
+
+	items := make([]ItemSales, 0, len(totals))
+	for _, t := range totals {
+		items = append(items, *t)
+	}
+	slices.SortFunc(items, func(a, b ItemSales) int {
+		if sortBy == "revenue" && a.Revenue != b.Revenue {
+			return cmp.Compare(b.Revenue, a.Revenue)
+		}
+		if a.Qty != b.Qty {
+			return cmp.Compare(b.Qty, a.Qty)
+		}
+		if a.Revenue != b.Revenue {
+			return cmp.Compare(b.Revenue, a.Revenue)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return items
+}
+
+// Fungsi untuk menampilkan laporan item terlaris
+func printTopItems(w io.Writer, items []ItemSales, from, to time.Time) {
+	fmt.Fprintf(w, "Item Terlaris %s s.d. %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if len(items) == 0 {
+		fmt.Fprintln(w, "Tidak ada penjualan.")
+		return
+	}
+	for i, item := range items {
+		fmt.Fprintf(w, "%3d. %-20s %-10s %5d  Rp%.2f\n", i+1, item.Name, item.Category, item.Qty, item.Revenue)
+	}
+}
+
+// Fungsi untuk menulis laporan item terlaris dalam format CSV
+func writeTopItemsCSV(w io.Writer, items []ItemSales) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"peringkat", "item", "kategori", "jumlah", "pendapatan"})
+	for i, item := range items {
+		cw.Write([]string{strconv.Itoa(i + 1), item.Name, item.Category, strconv.Itoa(item.Qty), item.Revenue.String()})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Struct untuk baris laporan pendapatan per kategori
+type CategorySales struct {
+	Category string // Kategori item menu, "(tanpa kategori)" untuk item tanpa kategori
+	Items    int    // Jumlah item menu berbeda yang terjual
+	Qty      int    // Jumlah terjual setelah refund
+	Revenue  Money  // Pendapatan setelah refund
+}
+
+// Harga rata-rata per porsi di kategori
+func (c CategorySales) AveragePrice() Money {
+	return c.Revenue.Share(1, c.Qty)
+}
+
+// Fungsi untuk menyusun laporan pendapatan per kategori dari penjualan per item dalam rentang tanggal
+// Paket dihitung di kategori paketnya, bukan dipecah ke kategori isinya, sama seperti harga yang ditagih
+func buildCategorySales(store *Store, from, to time.Time) []CategorySales {
+	totals := map[string]*CategorySales{}
+	for _, item := range buildTopItems(store, from, to, "revenue") {
+		category := item.Category
+		if category == "" {
+			category = "(tanpa kategori)"
+		}
+		key := strings.ToLower(category)
+		if totals[key] == nil {
+			totals[key] = &CategorySales{Category: category}
+		}
+		totals[key].Items++
+		totals[key].Qty += item.Qty
+		totals[key].Revenue += item.Revenue
+	}
+	categories := make([]CategorySales, 0, len(totals))
+	for _, t := range totals {
+		categories = append(categories, *t)
+	}
+	slices.SortFunc(categories, func(a, b CategorySales) int {
+		if a.Revenue != b.Revenue {
+			return cmp.Compare(b.Revenue, a.Revenue)
+		}
+		return strings.Compare(a.Category, b.Category)
+	})
+	return categories
+}
+
+// Fungsi untuk menampilkan laporan pendapatan per kategori beserta porsinya dari total pendapatan
+func printCategorySales(w io.Writer, categories []CategorySales, from, to time.Time) {
+	fmt.Fprintf(w, "Pendapatan per Kategori %s s.d. %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if len(categories) == 0 {
+		fmt.Fprintln(w, "Tidak ada penjualan.")
+		return
+	}
+	var total Money
+	var qty int
+	for _, c := range categories {
+		total += c.Revenue
+		qty += c.Qty
+	}
+	fmt.Fprintf(w, "%-16s %6s %15s %6s %13s\n", "Kategori", "Jumlah", "Pendapatan", "Porsi", "Rata-rata")
+	for _, c := range categories {
+		share := 0.0
+		if total != 0 {
+			share = c.Revenue.Float() / total.Float() * 100
+		}
+		fmt.Fprintf(w, "%-16s %6d %15.2f %5.1f%% %13.2f\n", c.Category, c.Qty, c.Revenue, share, c.AveragePrice())
+	}
+	fmt.Fprintf(w, "%-16s %6d %15.2f %6s %13.2f\n", "Total", qty, total, "", total.Share(1, qty))
+}
+
+// Fungsi untuk menulis laporan pendapatan per kategori dalam format CSV
+func writeCategorySalesCSV(w io.Writer, categories []CategorySales) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"kategori", "item", "jumlah", "pendapatan", "rata_rata"})
+	for _, c := range categories {
+		cw.Write([]string{c.Category, strconv.Itoa(c.Items), strconv.Itoa(c.Qty), c.Revenue.String(), c.AveragePrice().String()})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Struct untuk kecepatan input pesanan satu kasir
+// Diukur dari item pertama dimasukkan sampai pembayaran, tidak termasuk waktu dapur
+type EntrySpeed struct {
+	CashierID string        // ID staf, kosong jika kasir tidak login
+	Name      string        // Nama kasir
+	Orders    int           // Jumlah pesanan yang diukur
+	Items     int           // Jumlah item pada pesanan tersebut
+	Total     time.Duration // Jumlah seluruh durasi input
+	Fastest   time.Duration // Pesanan tercepat
+	Slowest   time.Duration // Pesanan terlama
+}
+
+// Struct untuk durasi input satu pesanan
+type OrderEntryTime struct {
+	OrderID   string
+	CashierID string
+	Items     int
+	Duration  time.Duration
+	PaidAt    time.Time
+}
+
+// Rata-rata durasi input per pesanan
+func (e EntrySpeed) Average() time.Duration {
+	if e.Orders == 0 {
+		return 0
+	}
+	return e.Total / time.Duration(e.Orders)
+}
+
+// Rata-rata durasi input per item
+func (e EntrySpeed) PerItem() time.Duration {
+	if e.Items == 0 {
+		return 0
+	}
+	return e.Total / time.Duration(e.Items)
+}
+
+// Menambahkan satu pesanan ke rekap
+func (e *EntrySpeed) add(t OrderEntryTime) {
+	if e.Orders == 0 || t.Duration < e.Fastest {
+		e.Fastest = t.Duration
+	}
+	e.Slowest = max(e.Slowest, t.Duration)
+	e.Orders++
+	e.Items += t.Items
+	e.Total += t.Duration
+}
+
+// Fungsi untuk mengukur durasi input setiap pesanan yang dibayar dalam rentang tanggal (inklusif)
+// Pesanan lama tanpa waktu item pertama dilewati
+func orderEntryTimes(store *Store, from, to time.Time) []OrderEntryTime {
+	payments := map[string]Payment{}
+	for _, p := range store.Payments() {
+		payments[p.ID] = p
+	}
+	var times []OrderEntryTime
+	for _, o := range store.Orders() {
+		p, ok := payments[o.PaymentID]
+		if !ok || o.FirstItemAt.IsZero() || p.PaidAt.Before(o.FirstItemAt) {
+			continue
+		}
+		if paid := p.PaidAt.Local(); paid.Before(from) || paid.After(endOfDay(to)) {
+			continue
+		}
+		items := 0
+		for _, l := range o.Lines {
+			items += l.Qty
+		}
+		times = append(times, OrderEntryTime{OrderID: o.ID, CashierID: p.CashierID, Items: items, Duration: p.PaidAt.Sub(o.FirstItemAt), PaidAt: p.PaidAt})
+	}
+	slices.SortFunc(times, func(a, b OrderEntryTime) int { return a.PaidAt.Compare(b.PaidAt) })
+	return times
+}
+
+// Fungsi untuk merekap kecepatan input per kasir, diurutkan dari rata-rata terlama
+// Rekap terakhir adalah gabungan seluruh kasir
+func buildEntrySpeed(store *Store, times []OrderEntryTime) (perCashier []EntrySpeed, overall EntrySpeed) {
+	names := map[string]string{"": "(tanpa login)"}
+	for _, st := range store.Staff() {
+		names[st.ID] = st.Name
+	}
+	byCashier := map[string]*EntrySpeed{}
+	overall.Name = "Semua kasir"
+	for _, t := range times {
+		e := byCashier[t.CashierID]
+		if e == nil {
+			name, ok := names[t.CashierID]
+			if !ok {
+				name = t.CashierID
+			}
+			e = &EntrySpeed{CashierID: t.CashierID, Name: name}
+			byCashier[t.CashierID] = e
+		}
+		e.add(t)
+		overall.add(t)
+	}
+	for _, e := range byCashier {
+		perCashier = append(perCashier, *e)
+	}
+	slices.SortFunc(perCashier, func(a, b EntrySpeed) int {
+		if c := cmp.Compare(b.Average(), a.Average()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return perCashier, overall
+}
+
+// Fungsi untuk menampilkan laporan kecepatan input pesanan
+func printEntrySpeed(w io.Writer, perCashier []EntrySpeed, overall EntrySpeed, from, to time.Time) {
+	fmt.Fprintf(w, "Kecepatan Input Pesanan %s s.d. %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if overall.Orders == 0 {
+		fmt.Fprintln(w, "Tidak ada pesanan yang dapat diukur.")
+		return
+	}
+	fmt.Fprintf(w, "%-20s %7s %10s %10s %10s %10s\n", "Kasir", "Pesanan", "Rata-rata", "Per item", "Tercepat", "Terlama")
+	for _, e := range append(perCashier, overall) {
+		fmt.Fprintf(w, "%-20s %7d %10s %10s %10s %10s\n", e.Name, e.Orders,
+			e.Average().Round(time.Second), e.PerItem().Round(time.Second), e.Fastest.Round(time.Second), e.Slowest.Round(time.Second))
+	}
+}
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Status reservasi
+type ReservationStatus string
+
+const (
+	ReservationBooked    ReservationStatus = "booked"    // Menunggu tamu datang
+	ReservationArrived   ReservationStatus = "arrived"   // Tamu sudah datang dan duduk
+	ReservationNoShow    ReservationStatus = "no_show"   // Tamu tidak datang sampai masa tenggang habis
+	ReservationCancelled ReservationStatus = "cancelled" // Dibatalkan sebelum waktu reservasi
+)
+
+var (
+	errReservationNotFound = errors.New("Reservasi tidak ditemukan")
+	errReservationClosed   = errors.New("Reservasi sudah tidak aktif")
+	errNoDeposit           = errors.New("Tidak ada deposit reservasi untuk meja ini")
+	errReservationConflict = errors.New("Meja sudah dipesan pada slot tersebut")
+)
+
+// Lama slot reservasi bawaan, juga dipakai untuk reservasi lama yang belum mencatat lama slot
+const defaultSlotMinutes = 90
+
+// Struct untuk Reservasi meja
+// Deposit dibayar lewat tautan pembayaran gateway, dipakai memotong tagihan saat tamu datang,
+// dan menjadi biaya no-show jika tamu tidak datang sampai masa tenggang habis
+type Reservation struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`              // Nama pemesan
+	Phone     string            `json:"phone,omitempty"`   // Nomor HP pemesan
+	Guests    int               `json:"guests"`            // Jumlah tamu
+	Table     string            `json:"table,omitempty"`   // Meja yang dipesan, boleh kosong
+	At        time.Time         `json:"at"`                // Waktu kedatangan yang dipesan
+	Minutes   int               `json:"minutes,omitempty"` // Lama slot meja yang dipesan
+	Status    ReservationStatus `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+
+	Deposit       Money     `json:"deposit,omitempty"`        // Nominal deposit, 0 jika tanpa deposit
+	DepositRef    string    `json:"deposit_ref,omitempty"`    // Referensi tagihan deposit di gateway
+	DepositLink   string    `json:"deposit_link,omitempty"`   // Tautan/QR pembayaran deposit untuk dikirim ke pemesan
+	DepositPaidAt time.Time `json:"deposit_paid_at,omitzero"` // Waktu deposit diterima
+	AppliedTo     string    `json:"applied_to,omitempty"`     // Pembayaran yang dipotong deposit
+	NoShowFee     Money     `json:"no_show_fee,omitempty"`    // Deposit yang ditahan sebagai biaya no-show
+	ArrivedAt     time.Time `json:"arrived_at,omitzero"`      // Waktu tamu datang
+}
+
+// Menandakan deposit sudah dibayar dan belum dipakai atau ditahan
+func (r Reservation) DepositAvailable() bool {
+	return r.Deposit > 0 && !r.DepositPaidAt.IsZero() && r.AppliedTo == "" && r.NoShowFee == 0
+}
+
+// Waktu slot reservasi berakhir
+func (r Reservation) End() time.Time {
+	minutes := r.Minutes
+	if minutes == 0 {
+		minutes = defaultSlotMinutes
+	}
+	return r.At.Add(time.Duration(minutes) * time.Minute)
+}
+
+// Menandakan reservasi masih memakai mejanya: tamu belum datang atau sedang duduk
+func (r Reservation) Active() bool {
+	return r.Status == ReservationBooked || r.Status == ReservationArrived
+}
+
+// Memeriksa reservasi aktif lain di meja yang sama yang slotnya beririsan; pemanggil harus memegang s.mu
+func (s *Store) checkReservationSlot(r Reservation) error {
+	if r.Table == "" {
+		return nil
+	}
+	for _, other := range s.data.Reservations {
+		if other.ID != r.ID && other.Table == r.Table && other.Active() && r.At.Before(other.End()) && other.At.Before(r.End()) {
+			return fmt.Errorf("%w: meja %s dipesan %s (%s) pukul %s-%s", errReservationConflict, other.Table,
+				other.Name, other.ID, other.At.Local().Format("15:04"), other.End().Local().Format("15:04"))
+		}
+	}
+	return nil
+}
+
+// Memeriksa apakah meja reservasi masih tersedia pada slotnya
+func (s *Store) CheckReservationSlot(r Reservation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkReservationSlot(r)
+}
+
+// Mencari reservasi berdasarkan ID; pemanggil harus memegang s.mu
+func (s *Store) findReservation(id string) *Reservation {
+	for i := range s.data.Reservations {
+		if s.data.Reservations[i].ID == id {
+			return &s.data.Reservations[i]
+		}
+	}
+	return nil
+}
+
+// Menyimpan reservasi baru
+// Reservasi ditolak jika mejanya sudah dipesan reservasi aktif lain pada slot yang beririsan
+func (s *Store) AddReservation(r Reservation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkReservationSlot(r); err != nil {
+		return err
+	}
+	s.data.Reservations = append(s.data.Reservations, r)
+	return s.save()
+}
+
+// Mengambil salinan seluruh reservasi
+func (s *Store) Reservations() []Reservation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Reservation(nil), s.data.Reservations...)
+}
+
+// Mengambil reservasi hari ini yang tamunya belum datang dan slotnya belum berakhir, urut menurut waktu
+func (s *Store) UpcomingReservations(now time.Time) []Reservation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	today := now.Local().Format("2006-01-02")
+	var upcoming []Reservation
+	for _, r := range s.data.Reservations {
+		if r.Status == ReservationBooked && r.At.Local().Format("2006-01-02") == today && r.End().After(now) {
+			upcoming = append(upcoming, r)
+		}
+	}
+	slices.SortFunc(upcoming, func(a, b Reservation) int { return a.At.Compare(b.At) })
+	return upcoming
+}
+
+// Mengambil reservasi berdasarkan ID
+func (s *Store) Reservation(id string) (Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r := s.findReservation(id); r != nil {
+		return *r, nil
+	}
+	return Reservation{}, errReservationNotFound
+}
+
+// Mencatat deposit reservasi sudah diterima gateway
+func (s *Store) MarkDepositPaid(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.findReservation(id)
+	if r == nil {
+		return errReservationNotFound
+	}
+	if r.DepositPaidAt.IsZero() {
+		r.DepositPaidAt = time.Now()
+	}
+	return s.save()
+}
+
+// Mencatat tamu reservasi sudah datang lalu membuka sesi mejanya
+func (s *Store) ArriveReservation(id string) (Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.findReservation(id)
+	if r == nil {
+		return Reservation{}, errReservationNotFound
+	}
+	if r.Status != ReservationBooked {
+		return Reservation{}, errReservationClosed
+	}
+	r.Status, r.ArrivedAt = ReservationArrived, time.Now()
+	if r.Table != "" && s.openTable(r.Table) == nil {
+		s.data.Tables = append(s.data.Tables, TableSession{Table: r.Table, Guests: r.Guests, SeatedAt: r.ArrivedAt})
+	}
+	return *r, s.save()
+}
+
+// Membatalkan reservasi yang belum datang
+// Deposit yang sudah dibayar tidak dikembalikan otomatis, refund dilakukan manual lewat gateway
+func (s *Store) CancelReservation(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.findReservation(id)
+	if r == nil {
+		return errReservationNotFound
+	}
+	if r.Status != ReservationBooked {
+		return errReservationClosed
+	}
+	r.Status = ReservationCancelled
+	return s.save()
+}
+
+// Mengambil reservasi yang sudah datang di meja tertentu dengan deposit yang belum dipakai
+func (s *Store) DepositForTable(table string) (Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.data.Reservations {
+		if r.Table == table && r.Status == ReservationArrived && r.DepositAvailable() {
+			return r, nil
+		}
+	}
+	return Reservation{}, errNoDeposit
+}
+
+// Menandai deposit reservasi sudah dipakai pembayaran; pemanggil harus memegang s.mu
+func (s *Store) applyDeposit(p Payment) {
+	if p.ReservationID == "" {
+		return
+	}
+	if r := s.findReservation(p.ReservationID); r != nil {
+		r.AppliedTo = p.ID
+	}
+}
+
+// Mengubah reservasi yang tidak datang sampai masa tenggang habis menjadi no-show
+// Deposit yang sudah dibayar ditahan sebagai biaya no-show; mengembalikan reservasi yang berubah
+func (s *Store) ExpireReservations(now time.Time, grace time.Duration) ([]Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var expired []Reservation
+	for i := range s.data.Reservations {
+		r := &s.data.Reservations[i]
+		if r.Status != ReservationBooked || now.Before(r.At.Add(grace)) {
+			continue
+		}
+		r.Status = ReservationNoShow
+		if r.DepositAvailable() {
+			r.NoShowFee = r.Deposit
+		}
+		expired = append(expired, *r)
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+	return expired, s.save()
+}
+
+// Fungsi untuk memotong tagihan dengan deposit reservasi meja yang tamunya sudah datang
+// Deposit yang melebihi tagihan hanya dipakai sebesar tagihan.
+// Pembulatan tahap pembayaran dihitung ulang dari sisa tagihan agar yang ditagih tetap bulat
+func applyReservationDeposit(backend CashierBackend, pricing Pricing, payment *Payment, table string) {
+	if table == "" {
+		return
+	}
+	r, err := backend.ReservationDeposit(table)
+	if err != nil {
+		return
+	}
+	unrounded := payment.Amount - payment.Rounding
+	payment.ReservationID = r.ID
+	payment.Deposit = min(r.Deposit, unrounded)
+	payment.Amount, payment.Rounding = pricing.RoundPayment(unrounded - payment.Deposit)
+	fmt.Print(tr("Deposit reservasi %s: -Rp%.2f\n", r.Name, payment.Deposit))
+	if payment.Rounding != 0 {
+		fmt.Print(tr("Pembulatan    : Rp%.2f\n", payment.Rounding))
+	}
+	fmt.Print(tr("Sisa tagihan: Rp%.2f\n", payment.Amount))
+}
+
+// Fungsi untuk mengubah reservasi yang lewat masa tenggang menjadi no-show dan mencatatnya di audit
+func expireReservations(cfg *Config, store *Store) error {
+	expired, err := store.ExpireReservations(time.Now(), time.Duration(cfg.Reservations.GraceMinutes)*time.Minute)
+	if err != nil {
+		return err
+	}
+	for _, r := range expired {
+		detail := "tanpa deposit"
+		if r.NoShowFee > 0 {
+			detail = fmt.Sprintf("biaya no-show Rp%.2f", r.NoShowFee)
+		}
+		fmt.Printf("Reservasi %s (%s) tidak datang, %s\n", r.ID, r.Name, detail)
+		if err := store.Audit(Staff{Name: "system"}, "reservation.no_show", r.ID, detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fungsi untuk memeriksa reservasi no-show secara berkala selama server berjalan
+func runReservationExpiry(cfg *Config, store *Store) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := expireReservations(cfg, store); err != nil {
+			fmt.Println("Gagal memeriksa reservasi no-show:", err)
+		}
+	}
+}
+
+// Fungsi untuk membuat tagihan deposit di gateway dan mendapatkan tautan pembayarannya
+// Deposit ditagih lewat QRIS karena pemesan membayar dari ponselnya sendiri
+func requestDeposit(cfg *Config, r *Reservation) error {
+	gateway, err := newPaymentGateway(cfg.PaymentGateway)
+	if err != nil {
+		return err
+	}
+	if gateway == nil {
+		return errGatewayDisabled
+	}
+	result, err := gateway.Charge(ChargeRequest{PaymentID: r.ID, Method: MethodQRIS, Amount: r.Deposit})
+	if err != nil {
+		return err
+	}
+	if result.Status == ChargeFailed {
+		return errChargeFailed
+	}
+	r.DepositRef, r.DepositLink = result.Reference, result.Action
+	if result.Status == ChargePaid {
+		r.DepositPaidAt = time.Now()
+	}
+	return nil
+}
+
+// Fungsi untuk menampilkan satu reservasi
+func printReservation(r Reservation) {
+	table := "-"
+	if r.Table != "" {
+		table = r.Table
+	}
+	deposit := ""
+	switch {
+	case r.Deposit == 0:
+	case r.NoShowFee > 0:
+		deposit = fmt.Sprintf("  deposit Rp%.2f ditahan sebagai biaya no-show", r.NoShowFee)
+	case r.AppliedTo != "":
+		deposit = fmt.Sprintf("  deposit Rp%.2f dipakai di %s", r.Deposit, r.AppliedTo)
+	case r.DepositPaidAt.IsZero():
+		deposit = fmt.Sprintf("  deposit Rp%.2f belum dibayar", r.Deposit)
+	default:
+		deposit = fmt.Sprintf("  deposit Rp%.2f lunas", r.Deposit)
+	}
+	slot := r.At.Local().Format("2006-01-02 15:04") + "-" + r.End().Local().Format("15:04")
+	fmt.Printf("%s  %s  %-16s %2d orang  meja %-4s %-9s%s\n", r.ID, slot, r.Name, r.Guests, table, r.Status, deposit)
+}
+
+// Fungsi untuk menampilkan reservasi hari ini yang akan datang saat kasir dibuka
+func printUpcomingReservations(backend CashierBackend) {
+	upcoming, err := backend.UpcomingReservations()
+	if err != nil {
+		fmt.Println(tr("Reservasi hari ini tidak dapat dimuat:"), err)
+		return
+	}
+	if len(upcoming) == 0 {
+		return
+	}
+	fmt.Println(tr("Reservasi hari ini:"))
+	for _, r := range upcoming {
+		printReservation(r)
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "reservation"
+func runReservationCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: reservation add|list|ics|push|deposit <id>|arrive <id>|cancel <id>")
+	}
+	// Status no-show diperbarui sebelum setiap perintah agar daftar dan deposit selalu terkini
+	if err := expireReservations(cfg, store); err != nil {
+		return err
+	}
+	switch args[0] {
+	case "add":
+		fs := flag.NewFlagSet("reservation add", flag.ContinueOnError)
+		name := fs.String("name", "", "nama pemesan")
+		phone := fs.String("phone", "", "nomor HP pemesan")
+		guests := fs.Int("guests", 2, "jumlah tamu")
+		table := fs.String("table", "", "nomor meja")
+		at := fs.String("at", "", "waktu kedatangan YYYY-MM-DD HH:MM")
+		minutes := fs.Int("minutes", cfg.Reservations.SlotMinutes, "lama slot meja dalam menit")
+		deposit := fs.Float64("deposit", 0, "nominal deposit yang ditagih lewat tautan pembayaran")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *name == "" || *at == "" {
+			return fmt.Errorf("Gunakan: reservation add -name nama -at \"YYYY-MM-DD HH:MM\" [-phone] [-guests] [-table] [-minutes] [-deposit]")
+		}
+		if *minutes <= 0 {
+			return fmt.Errorf("Lama slot harus lebih dari 0 menit")
+		}
+		when, err := time.ParseInLocation("2006-01-02 15:04", *at, time.Local)
+		if err != nil {
+			return fmt.Errorf("Format waktu harus YYYY-MM-DD HH:MM")
+		}
+		r := Reservation{
+			ID: newID("RSV"), Name: *name, Phone: *phone, Guests: *guests, Table: *table,
+			At: when, Minutes: *minutes, Status: ReservationBooked, CreatedAt: time.Now(), Deposit: Rupiah(*deposit),
+		}
+		// Bentrok diperiksa sebelum tagihan deposit dibuat agar pemesan tidak ditagih untuk meja yang tidak tersedia
+		if err := store.CheckReservationSlot(r); err != nil {
+			return err
+		}
+		if r.Deposit > 0 {
+			if err := requestDeposit(cfg, &r); err != nil {
+				return fmt.Errorf("Gagal membuat tagihan deposit: %w", err)
+			}
+		}
+		if err := store.AddReservation(r); err != nil {
+			return err
+		}
+		fmt.Println("Reservasi dicatat:", r.ID)
+		if r.DepositLink != "" {
+			fmt.Println("Kirim tautan pembayaran deposit ke pemesan:", r.DepositLink)
+		}
+	case "list":
+		fs := flag.NewFlagSet("reservation list", flag.ContinueOnError)
+		date := fs.String("date", "", "tanggal reservasi YYYY-MM-DD, kosong untuk semua")
+		today := fs.Bool("today", false, "hanya reservasi hari ini yang tamunya belum datang")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		reservations := store.Reservations()
+		if *today {
+			reservations = store.UpcomingReservations(time.Now())
+		}
+		shown := 0
+		for _, r := range reservations {
+			if *date != "" && r.At.Local().Format("2006-01-02") != *date {
+				continue
+			}
+			printReservation(r)
+			shown++
+		}
+		if shown == 0 {
+			fmt.Println("Tidak ada reservasi.")
+		}
+	case "deposit":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: reservation deposit <id>")
+		}
+		r, err := store.Reservation(args[1])
+		if err != nil {
+			return err
+		}
+		if r.Deposit == 0 || r.DepositRef == "" {
+			return fmt.Errorf("Reservasi %s tidak memiliki deposit", r.ID)
+		}
+		if r.DepositPaidAt.IsZero() {
+			gateway, err := newPaymentGateway(cfg.PaymentGateway)
+			if err != nil {
+				return err
+			}
+			if gateway == nil {
+				return errGatewayDisabled
+			}
+			status, err := gateway.Status(r.DepositRef)
+			if err != nil {
+				return err
+			}
+			if status == ChargePaid {
+				if err := store.MarkDepositPaid(r.ID); err != nil {
+					return err
+				}
+				r, _ = store.Reservation(r.ID)
+			} else if r.DepositLink != "" {
+				fmt.Println("Tautan pembayaran deposit:", r.DepositLink)
+			}
+		}
+		printReservation(r)
+	case "arrive":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: reservation arrive <id>")
+		}
+		r, err := store.ArriveReservation(args[1])
+		if err != nil {
+			return err
+		}
+		printReservation(r)
+		if r.DepositAvailable() && r.Table != "" {
+			fmt.Printf("Deposit Rp%.2f akan dipotong dari tagihan meja %s\n", r.Deposit, r.Table)
+		}
+	case "ics", "push":
+		return runReservationCalendar(cfg, store, args[0], args[1:])
+	case "cancel":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: reservation cancel <id>")
+		}
+		if err := store.CancelReservation(args[1]); err != nil {
+			return err
+		}
+		fmt.Println("Reservasi", args[1], "dibatalkan")
+	default:
+		return fmt.Errorf("Sub-perintah reservation tidak dikenal: %s", args[0])
+	}
+	return nil
+}
+
+// Memastikan reservasi dan deposit pada pembayaran terminal sesuai dengan data di server
+func checkReservationDeposit(store *Store, p Payment) error {
+	if p.ReservationID == "" {
+		return nil
+	}
+	r, err := store.Reservation(p.ReservationID)
+	if err != nil {
+		return err
+	}
+	if !r.DepositAvailable() || p.Deposit > r.Deposit {
+		return fmt.Errorf("Deposit reservasi %s tidak dapat dipakai", r.ID)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Jenis aturan pesanan
+const (
+	RuleMaxOption = "max_option" // Pilihan varian tidak boleh melewati urutan tertentu, mis. level pedas untuk menu anak
+	RuleMaxQty    = "max_qty"    // Jumlah item dalam satu pesanan dibatasi
+	RuleRequires  = "requires"   // Item hanya boleh dipesan bersama item lain
+	RuleExcludes  = "excludes"   // Item tidak boleh dipesan bersama item lain
+)
+
+// Struct untuk Aturan pesanan yang diperiksa saat kasir memasukkan item
+// Aturan berlaku untuk item dengan nama Item, atau semua item di Category jika Item kosong
+type OrderRule struct {
+	Type     string `json:"type"`               // max_option, max_qty, requires, atau excludes
+	Item     string `json:"item,omitempty"`     // Nama item yang dibatasi
+	Category string `json:"category,omitempty"` // Kategori item yang dibatasi, dipakai jika Item kosong
+	Group    string `json:"group,omitempty"`    // Grup varian untuk max_option, mis. "Level Pedas"
+	Max      int    `json:"max,omitempty"`      // Urutan pilihan tertinggi (max_option) atau jumlah maksimal (max_qty)
+	With     string `json:"with,omitempty"`     // Nama item pasangan untuk requires dan excludes
+	Message  string `json:"message,omitempty"`  // Pesan untuk kasir, kosong untuk pesan bawaan
+}
+
+// Fungsi untuk memeriksa aturan pesanan di konfigurasi
+func validateOrderRules(rules []OrderRule) error {
+	for i, r := range rules {
+		if r.Item == "" && r.Category == "" {
+			return fmt.Errorf("order_rules[%d]: item atau category harus diisi", i)
+		}
+		switch r.Type {
+		case RuleMaxOption:
+			if r.Group == "" || r.Max < 1 {
+				return fmt.Errorf("order_rules[%d]: max_option membutuhkan group dan max", i)
+			}
+		case RuleMaxQty:
+			if r.Max < 1 {
+				return fmt.Errorf("order_rules[%d]: max_qty membutuhkan max", i)
+			}
+		case RuleRequires, RuleExcludes:
+			if r.With == "" {
+				return fmt.Errorf("order_rules[%d]: %s membutuhkan with", i, r.Type)
+			}
+		default:
+			return fmt.Errorf("order_rules[%d]: type tidak dikenal: %s (max_option, max_qty, requires, atau excludes)", i, r.Type)
+		}
+	}
+	return nil
+}
+
+// Menandakan aturan berlaku untuk item menu
+func (r OrderRule) appliesTo(item MenuItem) bool {
+	if r.Item != "" {
+		return strings.EqualFold(item.Name, r.Item)
+	}
+	return strings.EqualFold(item.Category, r.Category)
+}
+
+// Nama sasaran aturan untuk pesan bawaan
+func (r OrderRule) target() string {
+	if r.Item != "" {
+		return r.Item
+	}
+	return tr("menu %s", r.Category)
+}
+
+// Memeriksa pesanan terhadap aturan, mengembalikan pesan pelanggaran atau "" jika lolos
+// Aturan requires hanya diperiksa saat pesanan selesai (final) agar pasangannya sempat dimasukkan
+func (r OrderRule) check(order Order, final bool) string {
+	var matched []OrderLine
+	for _, l := range order.Lines {
+		if r.appliesTo(l.Item) {
+			matched = append(matched, l)
+		}
+	}
+	if len(matched) == 0 {
+		return ""
+	}
+	hasWith := slices.ContainsFunc(order.Lines, func(l OrderLine) bool { return strings.EqualFold(l.Item.Name, r.With) })
+	violated := false
+	switch r.Type {
+	case RuleMaxOption:
+		for _, l := range matched {
+			if optionPosition(l, r.Group) > r.Max {
+				violated = true
+			}
+		}
+	case RuleMaxQty:
+		qty := 0
+		for _, l := range matched {
+			qty += l.Qty
+		}
+		violated = qty > r.Max
+	case RuleRequires:
+		violated = final && !hasWith
+	case RuleExcludes:
+		violated = hasWith
+	}
+	switch {
+	case !violated:
+		return ""
+	case r.Message != "":
+		return r.Message
+	case r.Type == RuleMaxOption:
+		return tr("%s maksimal pilihan ke-%d untuk %s", r.target(), r.Max, r.Group)
+	case r.Type == RuleMaxQty:
+		return tr("%s maksimal %d per pesanan", r.target(), r.Max)
+	case r.Type == RuleRequires:
+		return tr("%s hanya bisa dipesan bersama %s", r.target(), r.With)
+	default:
+		return tr("%s tidak bisa dipesan bersama %s", r.target(), r.With)
+	}
+}
+
+// Fungsi untuk mendapatkan urutan pilihan varian yang dipilih pada baris (mulai dari 1), 0 jika grup tidak ada
+func optionPosition(line OrderLine, group string) int {
+	for _, m := range line.Modifiers {
+		if !strings.EqualFold(m.Group, group) {
+			continue
+		}
+		for _, g := range line.Item.Variants {
+			if i := slices.IndexFunc(g.Options, func(o VariantOption) bool { return o.Name == m.Name }); strings.EqualFold(g.Name, group) && i >= 0 {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// Fungsi untuk memeriksa aturan pesanan dan meminta konfirmasi kasir jika ada pelanggaran
+// Pelanggaran yang sudah pernah di-override pada pesanan ini tidak ditanyakan lagi
+// Mengembalikan pelanggaran yang di-override untuk dicatat, dan false jika kasir membatalkan
+func confirmOrderRules(rules []OrderRule, order Order, final bool) ([]string, bool) {
+	var violations []string
+	for _, r := range rules {
+		if msg := r.check(order, final); msg != "" && !slices.Contains(order.Overrides, msg) && !slices.Contains(violations, msg) {
+			violations = append(violations, msg)
+		}
+	}
+	if len(violations) == 0 {
+		return nil, true
+	}
+	for _, v := range violations {
+		fmt.Println(tr("PERINGATAN:"), v)
+	}
+	if !strings.EqualFold(readLine(tr("Tetap lanjutkan (override dicatat)? (y/n)")), "y") {
+		return nil, false
+	}
+	return violations, true
+}
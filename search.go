@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Jumlah maksimal hasil pencarian menu yang ditampilkan
+const maxSearchResults = 10
+
+// Struct untuk satu hasil pencarian menu
+type menuMatch struct {
+	Number int      // Nomor item di menu, sama dengan nomor yang diketik kasir
+	Item   MenuItem // Item menu yang cocok
+	Score  float64  // Kemiripan dengan kata kunci, makin besar makin mirip
+}
+
+// Huruf beraksen dan huruf dasarnya, mis. "é" menjadi "e"
+var diacriticFold = func() map[rune]rune {
+	folds := map[rune]rune{}
+	for base, accented := range map[rune]string{
+		'a': "àáâãäåāă", 'c': "çćč", 'e': "èéêëēėę", 'i': "ìíîïī", 'n': "ñń",
+		'o': "òóôõöøō", 'u': "ùúûüū", 'y': "ýÿ", 's': "šś", 'z': "žźż",
+	} {
+		for _, r := range accented {
+			folds[r] = base
+		}
+	}
+	return folds
+}()
+
+// Fungsi untuk menyeragamkan teks pencarian: huruf kecil, tanpa aksen, dan tanda baca menjadi spasi
+// Contoh: "Café-Latté" menjadi "cafe latte"
+func foldText(s string) string {
+	folded := strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if base, ok := diacriticFold[r]; ok {
+			return base
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+// Fungsi untuk menilai kemiripan nama item dengan kata kunci, 0 berarti tidak cocok
+// Urutan nilai: nama sama persis, awalan nama, awalan salah satu kata, bagian dari nama,
+// lalu setiap kata kunci mirip dengan salah satu kata nama (salah ketik kecil)
+func matchScore(name, query string) float64 {
+	switch {
+	case name == query:
+		return 100
+	case strings.HasPrefix(name, query):
+		return 90
+	case strings.Contains(" "+name, " "+query):
+		return 80
+	case strings.Contains(name, query):
+		return 70
+	}
+	words := strings.Fields(name)
+	total := 0.0
+	for _, q := range strings.Fields(query) {
+		best := 0.0
+		for _, w := range words {
+			if strings.HasPrefix(w, q) {
+				best = 1
+				break
+			}
+			// Kata kunci dibandingkan dengan awalan kata sepanjang kata kunci agar "ayma" tetap cocok dengan "ayam"
+			if n := len([]rune(w)); n > len([]rune(q)) {
+				w = string([]rune(w)[:len([]rune(q))])
+			}
+			length := max(len([]rune(w)), len([]rune(q)))
+			best = max(best, 1-float64(editDistance(q, w))/float64(length))
+		}
+		// Kata kunci yang terlalu jauh dari semua kata nama menggagalkan kecocokan
+		if best < 0.6 {
+			return 0
+		}
+		total += best
+	}
+	return 60 * total / float64(len(strings.Fields(query)))
+}
+
+// Fungsi untuk mencari item menu berdasarkan sebagian nama, tanpa peduli huruf besar/kecil dan aksen
+// Hasil diurutkan dari yang paling mirip; nama yang lebih pendek dan nomor menu yang lebih kecil didahulukan
+func searchMenu(menu []MenuItem, query string) []menuMatch {
+	query = foldText(query)
+	if query == "" {
+		return nil
+	}
+	var matches []menuMatch
+	for i, item := range menu {
+		if score := matchScore(foldText(item.Name), query); score > 0 {
+			matches = append(matches, menuMatch{Number: i + 1, Item: item, Score: score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b menuMatch) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return len(a.Item.Name) - len(b.Item.Name)
+	})
+	if len(matches) > maxSearchResults {
+		matches = matches[:maxSearchResults]
+	}
+	return matches
+}
+
+// Fungsi untuk menampilkan hasil pencarian menu beserta nomor item yang bisa diketik kasir
+func printMenuMatches(w io.Writer, matches []menuMatch) {
+	for _, m := range matches {
+		if m.Item.SoldOut {
+			fmt.Fprint(w, tr("%d. %s: Rp%.2f (HABIS)\n", m.Number, m.Item.Name, m.Item.Price))
+			continue
+		}
+		fmt.Fprintf(w, "%d. %s: Rp%.2f\n", m.Number, m.Item.Name, m.Item.Price)
+	}
+}
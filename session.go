@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Struct untuk Sesi kasir yang terkunci otomatis setelah tidak ada aktivitas
+// Keranjang tidak disimpan di sini; alur pesanan hanya menunggu sampai sesi dibuka kembali
+type cashierSession struct {
+	auth    staffAuthenticator
+	cashier Staff
+	timeout time.Duration
+
+	mu           sync.Mutex
+	lastActivity time.Time // Waktu input terakhir diterima
+	announced    bool      // Pesan terkunci sudah ditampilkan
+	unlocking    bool      // Sedang meminta PIN, input selama itu tidak dihitung sebagai aktivitas
+}
+
+var errSessionNotAllowed = errors.New("Hanya kasir yang sedang bertugas atau admin yang dapat membuka terminal")
+
+// Sesi kasir yang sedang berjalan, nil jika penguncian otomatis tidak dipakai
+var activeSession *cashierSession
+
+// Fungsi untuk memulai sesi kasir yang terkunci setelah timeout tanpa aktivitas
+func startSession(auth staffAuthenticator, cashier Staff, timeout time.Duration) *cashierSession {
+	s := &cashierSession{auth: auth, cashier: cashier, timeout: timeout, lastActivity: time.Now()}
+	go s.watch()
+	return s
+}
+
+// Menampilkan pemberitahuan di layar begitu sesi melewati batas waktu
+// Penguncian sebenarnya terjadi pada input berikutnya
+func (s *cashierSession) watch() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.mu.Lock()
+		if !s.unlocking && !s.announced && time.Since(s.lastActivity) > s.timeout {
+			s.announced = true
+			fmt.Print("\r\n*** Terminal terkunci karena tidak ada aktivitas. Tekan Enter lalu masukkan PIN. ***\r\n")
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Mencatat aktivitas; mengembalikan true jika sesi sudah terkunci sebelum input ini diterima
+func (s *cashierSession) activity() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.unlocking {
+		return false
+	}
+	locked := time.Since(s.lastActivity) > s.timeout
+	s.lastActivity = time.Now()
+	if locked {
+		s.unlocking = true
+	}
+	return locked
+}
+
+// Memeriksa sesi setelah input diterima
+// Jika sesi terkunci, PIN diminta dan unlocked bernilai true agar input yang diketik saat terkunci dibuang
+// Aman dipanggil pada sesi nil
+func (s *cashierSession) check() (unlocked bool, err error) {
+	if s == nil || !s.activity() {
+		return false, nil
+	}
+	return true, s.unlock()
+}
+
+// Meminta PIN kasir yang sedang bertugas atau admin sampai benar
+func (s *cashierSession) unlock() error {
+	defer func() {
+		s.mu.Lock()
+		s.unlocking, s.announced, s.lastActivity = false, false, time.Now()
+		s.mu.Unlock()
+	}()
+	fmt.Printf("Terminal terkunci. Masukkan PIN %s atau admin untuk melanjutkan.\n", s.cashier.Name)
+	for {
+		name, err := readLineErr("Nama staf:")
+		if err != nil {
+			return err
+		}
+		staff, err := s.auth.Login(name, readPIN("PIN:"))
+		switch {
+		case errors.Is(err, errInvalidPIN), errors.Is(err, errStaffLocked):
+			fmt.Println(err.Error() + ". Coba lagi.")
+			continue
+		case err != nil:
+			return err
+		case staff.ID != s.cashier.ID && staff.Role != RoleAdmin:
+			fmt.Println(errSessionNotAllowed.Error() + ".")
+			continue
+		}
+		fmt.Println("Terminal dibuka. Pesanan yang sedang berjalan tetap tersimpan.")
+		return nil
+	}
+}
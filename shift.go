@@ -0,0 +1,275 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"time"
+)
+
+var (
+	errShiftOpen    = errors.New("Masih ada shift laci yang terbuka, tutup dulu dengan \"drawer close\"")
+	errNoShiftOpen  = errors.New("Belum ada shift laci yang dibuka, buka dengan \"drawer open\"")
+	errShiftCashier = errors.New("Shift hanya dapat ditutup oleh kasir yang membukanya atau admin")
+)
+
+// Struct untuk satu shift laci kasir dari modal awal sampai uang dihitung saat tutup
+// Uang tunai yang masuk dan keluar dihitung dari pembayaran dan refund tunai selama shift berjalan
+type DrawerShift struct {
+	ID          string    `json:"id"`
+	CashierID   string    `json:"cashier_id,omitempty"` // Staf yang membuka shift
+	CashierName string    `json:"cashier_name,omitempty"`
+	OpenedAt    time.Time `json:"opened_at"`
+	Float       Money     `json:"float"` // Modal awal di laci
+
+	ClosedAt    time.Time        `json:"closed_at,omitzero"` // Kosong selama shift masih terbuka
+	ClosedBy    string           `json:"closed_by,omitempty"`
+	Payments    int              `json:"payments"`               // Jumlah pembayaran tunai
+	CashIn      Money            `json:"cash_in"`                // Uang tunai yang diterima dari pelanggan
+	ChangeGiven Money            `json:"change_given"`           // Kembalian yang diberikan
+	CashRefunds Money            `json:"cash_refunds"`           // Refund tunai yang dibayarkan dari laci
+	ForeignCash map[string]Money `json:"foreign_cash,omitempty"` // Uang asing yang diterima per mata uang, dihitung terpisah dari rupiah
+	Counted     Money            `json:"counted"`                // Uang yang dihitung kasir saat tutup
+	Note        string           `json:"note,omitempty"`
+}
+
+// Menandakan shift masih terbuka
+func (sh DrawerShift) Open() bool {
+	return sh.ClosedAt.IsZero()
+}
+
+// Uang yang seharusnya ada di laci: modal awal ditambah uang diterima, dikurangi kembalian dan refund tunai
+func (sh DrawerShift) Expected() Money {
+	return sh.Float + sh.CashIn - sh.ChangeGiven - sh.CashRefunds
+}
+
+// Selisih uang dihitung dengan uang seharusnya; positif berarti lebih, negatif berarti kurang
+func (sh DrawerShift) Difference() Money {
+	return sh.Counted - sh.Expected()
+}
+
+// Keterangan selisih untuk laporan, mis. "kurang Rp5000.00"
+func (sh DrawerShift) DifferenceLabel() string {
+	switch diff := sh.Difference(); {
+	case diff == 0:
+		return "cocok"
+	case diff > 0:
+		return fmt.Sprintf("lebih Rp%.2f", diff)
+	default:
+		return fmt.Sprintf("kurang Rp%.2f", -diff)
+	}
+}
+
+// Fungsi untuk menandai metode pembayaran yang uangnya masuk ke laci
+func isCashMethod(m PaymentMethod) bool {
+	return m == "" || m == MethodCash
+}
+
+// Menjumlahkan uang tunai yang masuk dan keluar laci sejak shift dibuka sampai waktu tertentu
+// Pemanggil harus memegang s.mu
+func (s *Store) tallyShift(sh *DrawerShift, until time.Time) {
+	sh.Payments, sh.CashIn, sh.ChangeGiven, sh.CashRefunds, sh.ForeignCash = 0, 0, 0, 0, nil
+	for _, p := range s.data.Payments {
+		if !isCashMethod(p.Method) || p.PaidAt.Before(sh.OpenedAt) || p.PaidAt.After(until) {
+			continue
+		}
+		sh.Payments++
+		// Uang asing masuk laci dalam mata uangnya, sedangkan kembaliannya tetap keluar dalam rupiah
+		if p.Currency != "" {
+			if sh.ForeignCash == nil {
+				sh.ForeignCash = map[string]Money{}
+			}
+			sh.ForeignCash[p.Currency] += p.ForeignTendered
+		} else {
+			sh.CashIn += p.Tendered
+		}
+		sh.ChangeGiven += p.Change
+	}
+	for _, r := range s.data.Refunds {
+		if !isCashMethod(r.Method) || r.CreatedAt.Before(sh.OpenedAt) || r.CreatedAt.After(until) {
+			continue
+		}
+		sh.CashRefunds += r.ProviderAmount()
+	}
+}
+
+// Mengambil shift yang sedang terbuka, nil jika tidak ada
+// Pemanggil harus memegang s.mu
+func (s *Store) openShift() *DrawerShift {
+	for i := len(s.data.Shifts) - 1; i >= 0; i-- {
+		if s.data.Shifts[i].Open() {
+			return &s.data.Shifts[i]
+		}
+	}
+	return nil
+}
+
+// Membuka shift laci dengan modal awal
+func (s *Store) OpenShift(cashier Staff, float Money) (DrawerShift, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.openShift() != nil {
+		return DrawerShift{}, errShiftOpen
+	}
+	sh := DrawerShift{ID: newID("SHF"), CashierID: cashier.ID, CashierName: cashier.Name, OpenedAt: time.Now(), Float: float}
+	s.data.Shifts = append(s.data.Shifts, sh)
+	return sh, s.save()
+}
+
+// Mengambil shift yang sedang terbuka dengan jumlah uang tunai sampai saat ini
+func (s *Store) CurrentShift() (DrawerShift, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sh := s.openShift()
+	if sh == nil {
+		return DrawerShift{}, errNoShiftOpen
+	}
+	current := *sh
+	s.tallyShift(&current, time.Now())
+	return current, nil
+}
+
+// Menutup shift yang sedang terbuka dengan uang yang dihitung kasir
+func (s *Store) CloseShift(closer Staff, counted Money, note string) (DrawerShift, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sh := s.openShift()
+	if sh == nil {
+		return DrawerShift{}, errNoShiftOpen
+	}
+	if sh.CashierID != "" && closer.ID != sh.CashierID && closer.Role != RoleAdmin {
+		return DrawerShift{}, errShiftCashier
+	}
+	sh.ClosedAt = time.Now()
+	s.tallyShift(sh, sh.ClosedAt)
+	sh.ClosedBy, sh.Counted, sh.Note = closer.Name, counted, note
+	return *sh, s.save()
+}
+
+// Mengambil salinan seluruh shift laci
+func (s *Store) Shifts() []DrawerShift {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DrawerShift(nil), s.data.Shifts...)
+}
+
+// Menampilkan laporan rekonsiliasi laci untuk satu shift
+// Shift yang masih terbuka hanya menampilkan uang seharusnya tanpa hasil hitung
+func (sh DrawerShift) Print(w io.Writer) {
+	fmt.Fprintln(w, "========== REKONSILIASI LACI ==========")
+	fmt.Fprintln(w, "Shift         :", sh.ID)
+	if sh.CashierName != "" {
+		fmt.Fprintln(w, "Kasir         :", sh.CashierName)
+	}
+	fmt.Fprintln(w, "Dibuka        :", sh.OpenedAt.Local().Format("2006-01-02 15:04:05"))
+	if !sh.Open() {
+		fmt.Fprintln(w, "Ditutup       :", sh.ClosedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintf(w, "Modal awal    : Rp%.2f\n", sh.Float)
+	fmt.Fprintf(w, "Tunai diterima: Rp%.2f (%d pembayaran)\n", sh.CashIn, sh.Payments)
+	fmt.Fprintf(w, "Kembalian     : -Rp%.2f\n", sh.ChangeGiven)
+	if sh.CashRefunds > 0 {
+		fmt.Fprintf(w, "Refund tunai  : -Rp%.2f\n", sh.CashRefunds)
+	}
+	fmt.Fprintf(w, "Seharusnya    : Rp%.2f\n", sh.Expected())
+	for _, code := range slices.Sorted(maps.Keys(sh.ForeignCash)) {
+		fmt.Fprintf(w, "Tunai %s     : %s %.2f (dihitung terpisah)\n", code, code, sh.ForeignCash[code])
+	}
+	if !sh.Open() {
+		fmt.Fprintf(w, "Dihitung      : Rp%.2f\n", sh.Counted)
+		fmt.Fprintln(w, "Selisih       :", sh.DifferenceLabel())
+		if sh.Note != "" {
+			fmt.Fprintln(w, "Catatan       :", sh.Note)
+		}
+	}
+	fmt.Fprintln(w, "=======================================")
+}
+
+// Fungsi untuk menjalankan "drawer open", "drawer status", "drawer close", dan "drawer shifts"
+func runShiftCommand(cfg *Config, store *Store, action string, args []string) error {
+	switch action {
+	case "open":
+		fs := flag.NewFlagSet("drawer open", flag.ContinueOnError)
+		float := fs.Float64("float", cfg.CashDrawer.StartingFloat.Float(), "modal awal di laci")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if *float < 0 {
+			return fmt.Errorf("Modal awal tidak boleh negatif")
+		}
+		cashier, err := login(newLocalBackend(cfg, store), "Masukkan PIN kasir.")
+		if err != nil {
+			return err
+		}
+		sh, err := store.OpenShift(cashier, Rupiah(*float))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Shift %s dibuka dengan modal awal Rp%.2f\n", sh.ID, sh.Float)
+		return store.Audit(cashier, "drawer.open", sh.ID, fmt.Sprintf("modal awal Rp%.2f", sh.Float))
+	case "status":
+		sh, err := store.CurrentShift()
+		if err != nil {
+			return err
+		}
+		sh.Print(os.Stdout)
+	case "close":
+		fs := flag.NewFlagSet("drawer close", flag.ContinueOnError)
+		countedFlag := fs.String("counted", "", "uang yang dihitung di laci, ditanyakan jika kosong")
+		note := fs.String("note", "", "catatan untuk selisih")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if _, err := store.CurrentShift(); err != nil {
+			return err
+		}
+		closer, err := login(newLocalBackend(cfg, store), "Masukkan PIN kasir.")
+		if err != nil {
+			return err
+		}
+		// Uang dihitung tanpa melihat jumlah seharusnya agar hasil hitung tidak disesuaikan
+		value := *countedFlag
+		if value == "" {
+			value = readLine("Uang di laci (dihitung):")
+		}
+		counted, err := parseSheetAmount(value)
+		if err != nil {
+			return fmt.Errorf("Jumlah uang tidak valid: %w", err)
+		}
+		sh, err := store.CloseShift(closer, counted, *note)
+		if err != nil {
+			return err
+		}
+		sh.Print(os.Stdout)
+		detail := fmt.Sprintf("seharusnya Rp%.2f, dihitung Rp%.2f, selisih Rp%.2f", sh.Expected(), sh.Counted, sh.Difference())
+		return store.Audit(closer, "drawer.close", sh.ID, detail)
+	case "shifts":
+		fs := flag.NewFlagSet("drawer shifts", flag.ContinueOnError)
+		id := fs.String("id", "", "tampilkan laporan rekonsiliasi satu shift")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		for _, sh := range store.Shifts() {
+			if *id != "" {
+				if sh.ID == *id {
+					sh.Print(os.Stdout)
+					return nil
+				}
+				continue
+			}
+			status := "terbuka"
+			if !sh.Open() {
+				status = "selisih " + sh.DifferenceLabel()
+			}
+			fmt.Printf("%s  %s  %-15s modal Rp%.2f  %s\n", sh.ID, sh.OpenedAt.Local().Format("2006-01-02 15:04"), orDash(sh.CashierName), sh.Float, status)
+		}
+		if *id != "" {
+			return fmt.Errorf("Shift %s tidak ditemukan", *id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	errOrderUnsigned     = errors.New("Pesanan ter-encode tidak memiliki tanda tangan")
+	errOrderBadSignature = errors.New("Tanda tangan pesanan tidak cocok, data mungkin telah diubah")
+	errOrderBadEncoding  = errors.New("Pesanan ter-encode tidak valid")
+)
+
+// Pemisah antara base64 pesanan dan tanda tangan; tidak termasuk alfabet base64
+const orderSignatureSep = "."
+
+// Struct untuk baris hasil decode pesanan ter-encode
+type DecodedLine struct {
+	Label     string // Nama item beserta modifier
+	UnitPrice Money  // Harga per porsi
+}
+
+// Fungsi untuk menghitung HMAC-SHA256 dari pesanan ter-encode dalam heksadesimal
+func orderSignature(encoded, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(encoded))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Fungsi untuk encode pesanan lalu menambahkan tanda tangan HMAC jika kunci diisi
+// Hasilnya berbentuk "<base64>.<hmac hex>", atau base64 saja jika tanpa kunci
+func encodeSignedOrder(order Order, key string) string {
+	encoded := encodeOrder(order)
+	if key == "" {
+		return encoded
+	}
+	return encoded + orderSignatureSep + orderSignature(encoded, key)
+}
+
+// Pola satu baris di pesanan ter-encode: "<label>:<harga>,"; label boleh berisi koma dari modifier
+var encodedLinePattern = regexp.MustCompile(`:(\d+\.\d{2}),`)
+
+// Fungsi untuk memeriksa tanda tangan lalu decode pesanan ter-encode
+// Jika kunci diisi, pesanan tanpa tanda tangan atau dengan tanda tangan yang salah ditolak
+func decodeOrder(blob, key string) ([]DecodedLine, error) {
+	blob = strings.TrimSpace(blob)
+	encoded, signature, signed := strings.Cut(blob, orderSignatureSep)
+	if key != "" {
+		if !signed {
+			return nil, errOrderUnsigned
+		}
+		if !hmac.Equal([]byte(signature), []byte(orderSignature(encoded, key))) {
+			return nil, errOrderBadSignature
+		}
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errOrderBadEncoding
+	}
+	text := string(raw)
+	var lines []DecodedLine
+	start := 0
+	for _, m := range encodedLinePattern.FindAllStringSubmatchIndex(text, -1) {
+		price, _ := ParseMoney(text[m[2]:m[3]])
+		lines = append(lines, DecodedLine{Label: text[start:m[0]], UnitPrice: price})
+		start = m[1]
+	}
+	if start != len(text) {
+		return nil, errOrderBadEncoding
+	}
+	return lines, nil
+}
+
+// Fungsi untuk menjalankan "order decode <blob>"
+func runOrderDecode(cfg *Config, blob string) error {
+	lines, err := decodeOrder(blob, cfg.Security.OrderSigningKey)
+	if err != nil {
+		return err
+	}
+	if cfg.Security.OrderSigningKey != "" {
+		fmt.Println("Tanda tangan valid.")
+	}
+	for _, l := range lines {
+		fmt.Printf("- %s: Rp%.2f\n", l.Label, l.UnitPrice)
+	}
+	return nil
+}
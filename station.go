@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Struct untuk Stasiun dapur, mis. grill, fry, atau drinks
+// Setiap stasiun menerima tiket sendiri yang hanya berisi item dari kategorinya
+type KitchenStation struct {
+	Name       string        `json:"name"`       // Nama stasiun yang dicetak di tiket
+	Categories []string      `json:"categories"` // Kategori item menu yang dimasak di stasiun ini, kosong untuk item yang tidak masuk stasiun lain
+	Printer    PrinterConfig `json:"printer"`    // Printer tiket stasiun, kosong berarti tiket hanya tampil di layar
+}
+
+// Memeriksa daftar stasiun dapur: nama wajib unik dan setiap kategori hanya milik satu stasiun
+func validateStations(stations []KitchenStation) error {
+	names := map[string]bool{}
+	categories := map[string]string{}
+	catchAll := ""
+	for _, st := range stations {
+		key := strings.ToLower(strings.TrimSpace(st.Name))
+		if key == "" {
+			return fmt.Errorf("kitchen.stations: nama stasiun wajib diisi")
+		}
+		if names[key] {
+			return fmt.Errorf("kitchen.stations: stasiun %q ditulis lebih dari sekali", st.Name)
+		}
+		names[key] = true
+		if len(st.Categories) == 0 {
+			if catchAll != "" {
+				return fmt.Errorf("kitchen.stations: hanya satu stasiun boleh tanpa kategori (%s dan %s)", catchAll, st.Name)
+			}
+			catchAll = st.Name
+		}
+		for _, c := range st.Categories {
+			c = strings.ToLower(strings.TrimSpace(c))
+			if other, ok := categories[c]; ok {
+				return fmt.Errorf("kitchen.stations: kategori %q sudah milik stasiun %s", c, other)
+			}
+			categories[c] = st.Name
+		}
+	}
+	return nil
+}
+
+// Fungsi untuk mencari stasiun yang memasak kategori item; stasiun tanpa kategori menerima sisanya
+// Mengembalikan -1 jika tidak ada stasiun yang cocok
+func stationIndex(stations []KitchenStation, category string) int {
+	fallback := -1
+	for i, st := range stations {
+		if len(st.Categories) == 0 {
+			fallback = i
+		}
+		if slices.ContainsFunc(st.Categories, func(c string) bool { return strings.EqualFold(strings.TrimSpace(c), category) }) {
+			return i
+		}
+	}
+	return fallback
+}
+
+// Struct untuk Baris pesanan yang dikirim ke satu stasiun
+type stationTicket struct {
+	Station string // Nama stasiun, kosong untuk item yang tidak masuk stasiun mana pun
+	Printer PrinterConfig
+	Lines   []OrderLine
+}
+
+// Fungsi untuk membagi baris pesanan ke stasiun dapur sesuai kategori item
+// Isi paket dibagi per kategori isinya, sehingga minuman paket tetap dibuat di stasiun minuman
+// Tiket mengikuti urutan stasiun di konfigurasi, item tanpa stasiun di tiket terakhir
+func splitStations(stations []KitchenStation, lines []OrderLine) []stationTicket {
+	tickets := make([]stationTicket, len(stations)+1)
+	for i, st := range stations {
+		tickets[i] = stationTicket{Station: st.Name, Printer: st.Printer}
+	}
+	route := func(category string) *stationTicket {
+		if i := stationIndex(stations, category); i >= 0 {
+			return &tickets[i]
+		}
+		return &tickets[len(stations)]
+	}
+	for _, l := range lines {
+		if !l.Item.IsCombo() {
+			t := route(l.Item.Category)
+			t.Lines = append(t.Lines, l)
+			continue
+		}
+		// Setiap stasiun menerima baris paket yang hanya berisi isi miliknya
+		parts := map[*stationTicket][]ComboComponent{}
+		var order []*stationTicket
+		for _, c := range l.Item.Components {
+			category := c.Category
+			if category == "" {
+				category = l.Item.Category
+			}
+			t := route(category)
+			if _, ok := parts[t]; !ok {
+				order = append(order, t)
+			}
+			parts[t] = append(parts[t], c)
+		}
+		for _, t := range order {
+			part := l
+			part.Item.Components = parts[t]
+			t.Lines = append(t.Lines, part)
+		}
+	}
+	return slices.DeleteFunc(tickets, func(t stationTicket) bool { return len(t.Lines) == 0 })
+}
+
+// Fungsi untuk mencetak tiket dapur satu stasiun berisi baris miliknya saja
+func writeStationTicket(w io.Writer, order Order, t stationTicket) {
+	fmt.Fprintln(w, tr("---------- TIKET DAPUR ----------"))
+	if t.Station != "" {
+		fmt.Fprint(w, tr("Stasiun: %s\n", strings.ToUpper(t.Station)))
+	}
+	fmt.Fprint(w, tr("Antrean %03d   %s\n", order.QueueNumber, order.ID))
+	writeOrderType(w, order)
+	if order.Priority != PriorityNormal {
+		fmt.Fprintln(w, tr("PRIORITAS:"), strings.ToUpper(order.Priority.Label()))
+	}
+	if len(order.Allergies) > 0 {
+		fmt.Fprintln(w, tr("ALERGI:"), strings.Join(order.Allergies, ", "))
+	}
+	writeTicketLines(w, t.Lines)
+	fmt.Fprintln(w, "---------------------------------")
+}
+
+// Fungsi untuk menampilkan tiket setiap stasiun dan mencetaknya ke printer stasiun jika diatur
+// Printer stasiun yang gagal tidak membatalkan pesanan, tiketnya tetap tampil di layar kasir
+func emitStationTickets(w io.Writer, stations []KitchenStation, order Order) {
+	for _, t := range splitStations(stations, order.Lines) {
+		var buf bytes.Buffer
+		writeStationTicket(&buf, order, t)
+		w.Write(buf.Bytes())
+		if !t.Printer.Enabled() {
+			continue
+		}
+		if err := printTicket(t.Printer, "tiket "+t.Station+" "+order.ID, buf.String()); err != nil {
+			fmt.Fprintln(w, tr("Tiket stasiun %s tidak dapat dicetak:", t.Station), err)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build postgres
+
+package main
+
+// Driver PostgreSQL untuk storage.driver "postgres"
+// Repositori ini tidak menyertakan go.mod, sehingga versi driver dipin oleh modul yang membuild dengan tag ini,
+// mis. go get github.com/lib/pq@v1.10.9
+import _ "github.com/lib/pq"
@@ -0,0 +1,8 @@
+//go:build sqlite
+
+package main
+
+// Driver SQLite tanpa cgo untuk storage.driver "sqlite"
+// Repositori ini tidak menyertakan go.mod, sehingga versi driver dipin oleh modul yang membuild dengan tag ini,
+// mis. go get modernc.org/sqlite@v1.29.6
+import _ "modernc.org/sqlite"
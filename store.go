@@ -0,0 +1,221 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// Struct untuk Penyimpanan data
+// Menyimpan pelanggan, pesanan, dan pembayaran ke sebuah file JSON
+type Store struct {
+	mu   sync.Mutex
+	path string
+	data storeData
+
+	webhooks []WebhookEndpoint // Tujuan webhook kejadian pesanan, hanya diisi dalam mode server
+	events   eventBus          // Subscriber kejadian pesanan yang tersimpan, mis. webhook
+
+	repo         sharedRepository  // Database bersama untuk menu dan pesanan, nil jika hanya memakai file data
+	syncedMenu   string            // JSON menu terakhir yang ditulis ke database
+	syncedOrders map[string]string // JSON terakhir setiap pesanan yang ditulis ke database
+
+	baseMenu      []MenuItem     // Menu file data utama untuk outlet yang belum memiliki menu sendiri
+	menuOverrides []MenuOverride // Perubahan menu outlet, diterapkan setiap kali menu diambil
+}
+
+// Isi file data yang disimpan ke disk
+type storeData struct {
+	Menu         []MenuItem    `json:"menu,omitempty"`          // Menu yang berlaku, kosong berarti menu bawaan
+	MenuVersions []MenuVersion `json:"menu_versions,omitempty"` // Riwayat versi menu, versi terakhir adalah menu yang berlaku
+	Customers    []Customer    `json:"customers"`               // Daftar pelanggan terdaftar
+	Orders       []Order       `json:"orders"`                  // Daftar pesanan yang sudah dibayar
+	Payments     []Payment     `json:"payments"`                // Daftar pembayaran
+	Refunds      []Refund      `json:"refunds"`                 // Daftar refund, disimpan bersama pembayaran
+
+	History []HistoricalRecord `json:"history"` // Rekap penjualan historis hasil impor
+
+	DrawerDenominations []int         `json:"drawer_denominations,omitempty"` // Pecahan yang sedang tersedia di laci
+	Shifts              []DrawerShift `json:"shifts,omitempty"`               // Shift laci kasir beserta hasil rekonsiliasinya
+
+	Terminals []TerminalStatus `json:"terminals"`           // Heartbeat terakhir setiap terminal
+	Sequences []SequenceClaim  `json:"sequences,omitempty"` // Nomor urut ID terakhir yang dilaporkan setiap terminal
+
+	MenuReleases []MenuRelease   `json:"menu_releases"` // Rilis menu yang dibuat kantor pusat
+	BranchMenu   BranchMenuState `json:"branch_menu"`   // Status rilis menu yang diterapkan di cabang ini
+
+	Disputes []Dispute `json:"disputes"` // Sengketa pembayaran dari gateway
+
+	Staff []Staff      `json:"staff"` // Staf beserta peran dan hash PIN
+	Audit []AuditEntry `json:"audit"` // Catatan audit tindakan staf
+
+	Emails []QueuedEmail `json:"emails"` // Antrean email struk
+
+	JournalSeq int64 `json:"journal_seq"` // Nomor urut kejadian terakhir di jurnal
+
+	Drafts []Order `json:"drafts"` // Pesanan belum dibayar yang disimpan saat kasir berhenti
+
+	Ingredients []Ingredient `json:"ingredients"` // Persediaan bahan dapur
+
+	KitchenNotes []KitchenNote `json:"kitchen_notes,omitempty"` // Daftar catatan dapur baku, kosong berarti daftar bawaan
+	MenuAliases  []MenuAlias   `json:"menu_aliases,omitempty"`  // Kamus alias dan salah ketik item menu
+
+	Webhooks []WebhookDelivery `json:"webhooks"` // Antrean webhook kejadian pesanan
+
+	Tables []TableSession `json:"tables"` // Sesi meja dari tamu duduk sampai pembayaran
+
+	Reservations []Reservation `json:"reservations"` // Reservasi meja beserta depositnya
+
+	Projections      map[string]*DailyProjection `json:"projections,omitempty"` // Rekap penjualan per tanggal YYYY-MM-DD
+	ProjectionsBuilt bool                        `json:"projections_built"`     // Rekap sudah disusun dari seluruh data dan diperbarui per transaksi
+
+	WifiVouchers []WifiVoucher `json:"wifi_vouchers"` // Voucher Wi-Fi tamu beserta pembayaran dan waktu pemakaiannya
+
+	GiftVouchers []GiftVoucher `json:"gift_vouchers,omitempty"` // Voucher hadiah/store credit beserta sisa saldonya
+
+	Pause StorePause `json:"pause,omitzero"` // Jeda pesanan online yang sedang berlaku
+
+	Closes []DayClose `json:"closes,omitempty"` // Riwayat tutup hari beserta langkah yang gagal
+
+	QueueDate string `json:"queue_date"` // Tanggal nomor antrean terakhir, nomor diulang setiap hari
+	QueueSeq  int    `json:"queue_seq"`  // Nomor antrean terakhir pada tanggal tersebut
+}
+
+// Fungsi untuk membuka penyimpanan dari file
+// File yang belum ada akan dibuat saat penyimpanan pertama
+func openStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	s.subscribeProjections()
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Gagal membaca file data: %w", err)
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("Format file data tidak valid: %w", err)
+	}
+	return s, nil
+}
+
+// Menulis seluruh data ke disk, lalu menu dan pesanan yang berubah ke database bersama
+// Pemanggil harus sudah memegang s.mu
+func (s *Store) save() error {
+	if err := s.writeData(); err != nil {
+		return err
+	}
+	return s.syncRepository()
+}
+
+// Ditulis ke file sementara lalu di-rename agar file tidak rusak jika proses terhenti
+// Pemanggil harus sudah memegang s.mu
+func (s *Store) writeData() error {
+	if s.path == "" {
+		return nil // Penyimpanan di memori, mis. saat replay jurnal
+	}
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("Gagal menyimpan data: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Mengambil menu yang tersimpan
+// Jika belum ada menu tersimpan, menu bawaan yang digunakan
+// Item yang bahannya tidak cukup untuk satu porsi ditandai habis
+func (s *Store) Menu() *Restaurant {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	restaurant := s.storedMenu()
+	applyMenuOverrides(restaurant, s.menuOverrides)
+	restaurant.resolveComboRecipes()
+	restaurant.resolveComboCategories()
+	s.markUnavailable(restaurant)
+	restaurant.markCombosSoldOut()
+	restaurant.KitchenNotes = s.kitchenNotes()
+	s.applyAliases(restaurant)
+	return restaurant
+}
+
+// Salinan menu tersimpan, menu utama outlet, atau menu bawaan; pemanggil harus memegang s.mu
+func (s *Store) storedMenu() *Restaurant {
+	restaurant := &Restaurant{}
+	switch {
+	case len(s.data.Menu) > 0:
+		restaurant.Menu = append([]MenuItem(nil), s.data.Menu...)
+	case len(s.baseMenu) > 0:
+		restaurant.Menu = append([]MenuItem(nil), s.baseMenu...)
+	default:
+		seedMenu(restaurant)
+	}
+	return restaurant
+}
+
+// Menyimpan menu yang berlaku sebagai versi menu baru; note mencatat asal perubahan di riwayat versi
+func (s *Store) SetMenu(menu []MenuItem, note string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replaceMenu(menu, note)
+	return s.save()
+}
+
+// Menandai item menu habis atau tersedia kembali tanpa menghapusnya dari menu
+// Menu bawaan atau menu utama outlet disalin ke file data lebih dulu agar tanda habis ikut tersimpan
+// Tanda habis tidak dicatat sebagai versi menu baru
+func (s *Store) SetItemSoldOut(name string, soldOut bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	restaurant := s.storedMenu()
+	item := restaurant.findMenuItem(name)
+	if item == nil {
+		return errItemNotFound
+	}
+	item.SoldOut = soldOut
+	s.data.Menu = restaurant.Menu
+	return s.save()
+}
+
+// Menyimpan pesanan yang sudah dibayar
+// Pesanan dengan ID yang sudah tersimpan dilewati agar checkout yang diulang tidak menggandakan pesanan dan pemakaian stok
+func (s *Store) SaveOrder(order Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findOrder(order.ID) != nil {
+		return nil
+	}
+	if order.MenuVersion == 0 {
+		order.MenuVersion = s.menuVersion()
+	}
+	if err := s.record(EventOrderSaved, order); err != nil {
+		return err
+	}
+	s.data.Orders = append(s.data.Orders, order)
+	s.consumeIngredients(order)
+	s.attachTable(order)
+	return s.save()
+}
+
+// Mengambil salinan seluruh pesanan
+func (s *Store) Orders() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Order(nil), s.data.Orders...)
+}
+
+// Fungsi untuk membuat ID unik dengan prefix, mis. "ORD-20240101-1a2b3c"
+func newID(prefix string) string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return fmt.Sprintf("%s-%s-%s", prefix, time.Now().Format("20060102"), hex.EncodeToString(b))
+}
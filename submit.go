@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Struct untuk hasil "submit --json", dipakai kios atau skrip yang membaca keluaran mesin
+type submitResult struct {
+	Payment Payment `json:"payment"`
+	Orders  []Order `json:"orders"`
+}
+
+// Fungsi untuk menjalankan "submit": mengirim satu pesanan JSON/CSV ke server pusat lalu menampilkan struknya
+// Tidak membuka file data; alamat server dan API key diambil dari bagian "client" konfigurasi atau flag
+func runSubmitCommand(cfg *Config, args []string) error {
+	fs := flag.NewFlagSet("submit", flag.ContinueOnError)
+	from := fs.String("from", "-", "file pesanan JSON/CSV, \"-\" untuk stdin")
+	format := fs.String("format", "", "format input: json atau csv (default: deteksi otomatis)")
+	server := fs.String("server", cfg.Client.ServerURL, "alamat server pusat")
+	key := fs.String("key", cfg.Client.APIKey, "API key dengan scope terminal")
+	method := fs.String("method", string(MethodCash), "metode pembayaran: cash, card, atau qris")
+	tendered := fs.Float64("tendered", 0, "uang yang diterima untuk tunai (default: sama dengan total)")
+	ref := fs.String("ref", "", "referensi transaksi dari mesin pembayaran untuk card/qris")
+	asJSON := fs.Bool("json", false, "tampilkan pembayaran dan pesanan sebagai JSON, bukan struk")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	m := PaymentMethod(strings.ToLower(*method))
+	if m != MethodCash && m != MethodCard && m != MethodQRIS {
+		return fmt.Errorf("Metode pembayaran %q tidak dikenal (cash, card, atau qris)", *method)
+	}
+
+	var raw []byte
+	var err error
+	if *from == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		raw, err = os.ReadFile(*from)
+		if *format == "" {
+			switch strings.ToLower(filepath.Ext(*from)) {
+			case ".json":
+				*format = "json"
+			case ".csv":
+				*format = "csv"
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	req, err := parseOrderRequest(raw, *format)
+	if err != nil {
+		return fmt.Errorf("Gagal membaca pesanan: %w", err)
+	}
+
+	client := cfg.Client
+	client.ServerURL, client.APIKey = *server, *key
+	backend, err := newRemoteBackend(client)
+	if err != nil {
+		return err
+	}
+	restaurant, err := backend.Menu()
+	if err != nil {
+		return err
+	}
+	restaurant.Limits = cfg.OrderLimits
+	order, err := buildOrder(restaurant, req)
+	if err != nil {
+		return err
+	}
+	pricing := cfg.Pricing.Strategy()
+	pricing.Apply(&order)
+	orders := []Order{order}
+
+	// Server menghitung ulang total dengan aturan harganya sendiri dan menolak jika berbeda
+	payment := Payment{ID: newID("PAY"), OrderIDs: []string{order.ID}, Method: m, ProviderRef: *ref}
+	payment.Amount, payment.Rounding = pricing.RoundPayment(order.Total)
+	payment.Tendered = payment.Amount
+	if m == MethodCash && *tendered > 0 {
+		if Rupiah(*tendered) < payment.Amount {
+			return fmt.Errorf("Uang diterima Rp%.2f kurang dari total Rp%.2f", *tendered, payment.Amount)
+		}
+		payment.Tendered = Rupiah(*tendered)
+	}
+	payment.Change = payment.Tendered - payment.Amount
+	payment.PaidAt = time.Now()
+	if err := verifyPaymentTotals(cfg, pricing, orders, payment); err != nil {
+		return err
+	}
+	if err := backend.Checkout(orders, payment); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(submitResult{Payment: payment, Orders: orders})
+	}
+	emitReceipt(cfg.Printer, Receipt{Payment: payment, Orders: orders}, tr("STRUK PEMBAYARAN"))
+	// Potongan antrean berisi nomor ambil dan perkiraan waktu siap untuk layar kios
+	for _, o := range orders {
+		if o.QueueNumber > 0 {
+			writeQueueStub(os.Stdout, o)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+var errNoTableTab = errors.New("Meja ini belum memiliki tab terbuka")
+
+// Mencari tab meja yang masih terbuka di antara draf pesanan
+func (s *Store) TableTab(table string) (Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range s.data.Drafts {
+		if o.Tab && !o.TabClosed && strings.EqualFold(o.Table, strings.TrimSpace(table)) {
+			return o, nil
+		}
+	}
+	return Order{}, errNoTableTab
+}
+
+// Jumlah ronde yang sudah dicatat di tab meja
+func (o Order) Rounds() int {
+	n := 0
+	for _, l := range o.Lines {
+		n = max(n, l.Round)
+	}
+	return n
+}
+
+// Fungsi untuk menambahkan pesanan satu sesi kasir ke tab meja sebagai satu ronde baru
+// Tab kosong dibuka dari pesanan pertama; mengembalikan baris ronde baru untuk tiket dapur
+func addTabRound(tab *Order, orders []Order) []OrderLine {
+	if !tab.Tab {
+		*tab = orders[0]
+		tab.Lines, tab.Tab = nil, true
+	}
+	round := tab.Rounds() + 1
+	var lines []OrderLine
+	for _, o := range orders {
+		for _, l := range o.Lines {
+			l.Round, l.OrderedAt = round, o.CreatedAt
+			lines = append(lines, l)
+		}
+		for _, a := range o.Allergies {
+			if !slices.Contains(tab.Allergies, a) {
+				tab.Allergies = append(tab.Allergies, a)
+			}
+		}
+		tab.Overrides = append(tab.Overrides, o.Overrides...)
+		if tab.CustomerID == "" {
+			tab.CustomerID = o.CustomerID
+		}
+	}
+	tab.Lines = append(tab.Lines, lines...)
+	return lines
+}
+
+// Fungsi untuk menampilkan isi tab meja per ronde beserta total sementaranya
+func writeTableTab(w io.Writer, tab Order) {
+	fmt.Fprint(w, tr("Tab meja %s (%s):\n", tab.Table, tab.ID))
+	for round := 1; round <= tab.Rounds(); round++ {
+		fmt.Fprint(w, tr("  Ronde %d:\n", round))
+		for _, l := range tab.Lines {
+			if l.Round == round {
+				fmt.Fprintf(w, "  - %s x%d: Rp%.2f\n", l.Label(), l.Qty, l.Subtotal())
+			}
+		}
+	}
+	writePriceBreakdown(w, []Order{tab}, 0)
+	fmt.Fprint(w, tr("Total tab     : Rp%.2f\n", tab.Total))
+}
+
+// Fungsi untuk mencatat pesanan sesi kasir ke tab meja lalu mencetak tiket dapur ronde tersebut
+// Mengembalikan tab yang harus dibayar jika meja meminta tagihan; false berarti tab disimpan untuk ronde berikutnya
+func takeTableTab(backend CashierBackend, pricing Pricing, table string, orders []Order) (Order, bool, error) {
+	tab, err := backend.TableTab(table)
+	if err != nil && !errors.Is(err, errNoTableTab) {
+		return Order{}, false, err
+	}
+	if len(orders) > 0 {
+		lines := addTabRound(&tab, orders)
+		// Dapur langsung memasak setiap ronde, sehingga tab tidak dikirim ulang ke dapur saat dibayar
+		writeAmendTicket(os.Stdout, tab, tr("RONDE %d", tab.Rounds()), lines)
+	}
+	if !tab.Tab {
+		return Order{}, false, errNoTableTab
+	}
+	pricing.Apply(&tab)
+	writeTableTab(os.Stdout, tab)
+	if strings.EqualFold(readLine(tr("Meja meminta tagihan? (y/n)")), "y") {
+		return tab, true, nil
+	}
+	if err := backend.SaveDrafts([]Order{tab}); err != nil {
+		return Order{}, false, err
+	}
+	fmt.Println(tr("Tab meja disimpan:"), tab.ID)
+	return tab, false, nil
+}
+
+// Fungsi untuk menjalankan "table tab <meja>" dan "table bill <meja>"
+// bill menagih seluruh ronde tab meja dengan satu pembayaran seperti "order pay"
+func runTableTab(cfg *Config, store *Store, table string, bill bool) error {
+	tab, err := store.TableTab(table)
+	if err != nil {
+		return err
+	}
+	pricing := cfg.Pricing.Strategy()
+	pricing.Apply(&tab)
+	if !bill {
+		writeTableTab(os.Stdout, tab)
+		return nil
+	}
+	backend := newLocalBackend(cfg, store)
+	cashier, err := login(backend, "Masuk sebagai kasir.")
+	if err != nil {
+		return err
+	}
+	writeTableTab(os.Stdout, tab)
+	payment, err := payOrders(cfg, backend, cashier, []Order{tab})
+	if err != nil {
+		return err
+	}
+	receipt, err := store.Receipt(payment.ID)
+	if err != nil {
+		return err
+	}
+	emitReceipt(cfg.Printer, receipt, tr("STRUK PEMBAYARAN"))
+	fmt.Println(tr("Tab meja ditutup:"), tab.ID)
+	return nil
+}
+
+// Fungsi untuk memilih pesanan yang belum dikirim ke dapur
+// Ronde tab meja sudah mendapat tiket dapur saat dipesan, sehingga tidak dicetak dan dimasak ulang saat dibayar
+func kitchenOrders(orders []Order) []Order {
+	return slices.DeleteFunc(slices.Clone(orders), func(o Order) bool { return o.Tab })
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var (
+	errTableOccupied = errors.New("Meja sedang terisi")
+	errTableFree     = errors.New("Meja sedang kosong")
+)
+
+// Struct untuk Sesi meja dari tamu duduk sampai pembayaran
+type TableSession struct {
+	Table    string    `json:"table"`              // Nomor meja
+	Guests   int       `json:"guests,omitempty"`   // Jumlah tamu, 0 jika tidak dicatat
+	SeatedAt time.Time `json:"seated_at"`          // Waktu tamu duduk, atau waktu pesanan pertama dibuat
+	ClosedAt time.Time `json:"closed_at,omitzero"` // Waktu pembayaran atau meja dikosongkan, kosong jika masih terisi
+	OrderIDs []string  `json:"order_ids,omitempty"`
+}
+
+// Lama meja terisi sampai waktu tertentu, atau sampai ditutup
+func (t TableSession) Duration(now time.Time) time.Duration {
+	if !t.ClosedAt.IsZero() {
+		now = t.ClosedAt
+	}
+	return now.Sub(t.SeatedAt)
+}
+
+// Mencari sesi meja yang masih terisi; pemanggil harus memegang s.mu
+func (s *Store) openTable(table string) *TableSession {
+	for i := range s.data.Tables {
+		if t := &s.data.Tables[i]; t.Table == table && t.ClosedAt.IsZero() {
+			return t
+		}
+	}
+	return nil
+}
+
+// Mencatat tamu duduk di meja
+func (s *Store) SeatTable(table string, guests int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.openTable(table) != nil {
+		return errTableOccupied
+	}
+	s.data.Tables = append(s.data.Tables, TableSession{Table: table, Guests: guests, SeatedAt: time.Now()})
+	return s.save()
+}
+
+// Mengosongkan meja tanpa pembayaran, mis. tamu pergi atau meja dipindah
+func (s *Store) ClearTable(table string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.openTable(table)
+	if t == nil {
+		return errTableFree
+	}
+	t.ClosedAt = time.Now()
+	return s.save()
+}
+
+// Mengambil salinan seluruh sesi meja
+func (s *Store) TableSessions() []TableSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]TableSession(nil), s.data.Tables...)
+}
+
+// Mencatat pesanan ke sesi mejanya, membuka sesi baru jika meja belum tercatat duduk; pemanggil harus memegang s.mu
+// Sesi baru dimulai dari item pertama dimasukkan agar pesanan terbuka yang dibayar belakangan tetap terukur
+func (s *Store) attachTable(order Order) {
+	if order.Table == "" {
+		return
+	}
+	t := s.openTable(order.Table)
+	if t == nil {
+		seated := order.FirstItemAt
+		if seated.IsZero() {
+			seated = order.CreatedAt
+		}
+		s.data.Tables = append(s.data.Tables, TableSession{Table: order.Table, SeatedAt: seated})
+		t = &s.data.Tables[len(s.data.Tables)-1]
+	}
+	t.OrderIDs = append(t.OrderIDs, order.ID)
+}
+
+// Menutup sesi meja dari pesanan yang dibayar; pemanggil harus memegang s.mu
+func (s *Store) closeTables(p Payment) {
+	closed := p.PaidAt
+	if closed.IsZero() {
+		closed = time.Now()
+	}
+	for _, id := range p.OrderIDs {
+		if o := s.findOrder(id); o != nil && o.Table != "" {
+			if t := s.openTable(o.Table); t != nil {
+				t.ClosedAt = closed
+			}
+		}
+	}
+}
+
+// Fungsi untuk menampilkan denah meja beserta lama setiap meja terisi
+// Meja dari konfigurasi selalu tampil; meja lain tampil jika sedang terisi
+func printFloorPlan(w io.Writer, tables []string, sessions []TableSession, now time.Time) {
+	open := map[string]TableSession{}
+	for _, t := range sessions {
+		if t.ClosedAt.IsZero() {
+			open[t.Table] = t
+		}
+	}
+	all := slices.Clone(tables)
+	for table := range open {
+		if !slices.Contains(all, table) {
+			all = append(all, table)
+		}
+	}
+	slices.SortStableFunc(all, compareTables)
+	fmt.Fprintln(w, "DENAH MEJA", now.Local().Format("15:04"))
+	if len(all) == 0 {
+		fmt.Fprintln(w, "Belum ada meja terisi.")
+		return
+	}
+	for _, table := range all {
+		t, ok := open[table]
+		if !ok {
+			fmt.Fprintf(w, "Meja %-4s  kosong\n", table)
+			continue
+		}
+		guests := ""
+		if t.Guests > 0 {
+			guests = fmt.Sprintf("  %d orang", t.Guests)
+		}
+		fmt.Fprintf(w, "Meja %-4s  %s  sejak %s  %d pesanan%s\n", table, formatTimer(t.Duration(now)), t.SeatedAt.Local().Format("15:04"), len(t.OrderIDs), guests)
+	}
+}
+
+// Mengurutkan nomor meja secara angka jika keduanya angka, selain itu secara teks
+func compareTables(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return na - nb
+	}
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Fungsi untuk menampilkan durasi sebagai "jj:mm"
+func formatTimer(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// Pembagian waktu dalam sehari untuk laporan perputaran meja
+var dayParts = []struct {
+	Name      string
+	FromHour  int // Jam mulai, termasuk
+	UntilHour int // Jam berakhir, tidak termasuk
+}{
+	{"Pagi", 0, 11},
+	{"Siang", 11, 15},
+	{"Sore", 15, 18},
+	{"Malam", 18, 24},
+}
+
+// Fungsi untuk mendapatkan nama bagian hari dari jam
+func dayPart(t time.Time) string {
+	h := t.Local().Hour()
+	for _, p := range dayParts {
+		if h >= p.FromHour && h < p.UntilHour {
+			return p.Name
+		}
+	}
+	return dayParts[len(dayParts)-1].Name
+}
+
+// Fungsi untuk mencetak laporan perputaran meja per bagian hari
+// Perputaran adalah jumlah sesi per meja per hari, dihitung dari meja di konfigurasi atau meja yang pernah terisi
+func printTurnoverReport(w io.Writer, sessions []TableSession, tables []string, from, to time.Time) {
+	fmt.Fprintf(w, "Perputaran Meja %s s.d. %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	type part struct {
+		count int
+		total time.Duration
+	}
+	parts := map[string]*part{}
+	seen := map[string]bool{}
+	for _, t := range tables {
+		seen[t] = true
+	}
+	for _, t := range sessions {
+		at := t.SeatedAt.Local()
+		if t.ClosedAt.IsZero() || at.Before(from) || at.After(endOfDay(to)) {
+			continue
+		}
+		seen[t.Table] = true
+		name := dayPart(at)
+		if parts[name] == nil {
+			parts[name] = &part{}
+		}
+		parts[name].count++
+		parts[name].total += t.Duration(t.ClosedAt)
+	}
+	if len(parts) == 0 {
+		fmt.Fprintln(w, "Tidak ada sesi meja.")
+		return
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	var count int
+	var total time.Duration
+	fmt.Fprintf(w, "%-8s %6s %10s %12s\n", "Waktu", "Sesi", "Rata-rata", "Putaran/meja")
+	for _, p := range dayParts {
+		d := parts[p.Name]
+		if d == nil {
+			continue
+		}
+		count += d.count
+		total += d.total
+		fmt.Fprintf(w, "%-8s %6d %10s %12.2f\n", p.Name, d.count, formatTimer(d.total/time.Duration(d.count)), float64(d.count)/float64(len(seen)*days))
+	}
+	fmt.Fprintf(w, "%-8s %6d %10s %12.2f\n", "Total", count, formatTimer(total/time.Duration(count)), float64(count)/float64(len(seen)*days))
+}
+
+// Fungsi untuk menjalankan "report turnover"
+func runTurnoverReport(cfg *Config, store *Store, args []string) error {
+	fs := flag.NewFlagSet("report turnover", flag.ContinueOnError)
+	dates := addRangeFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, to, err := dates.parse()
+	if err != nil {
+		return err
+	}
+	printTurnoverReport(os.Stdout, store.TableSessions(), cfg.Floor.Tables, from, to)
+	return nil
+}
+
+// Fungsi untuk menjalankan sub-perintah "table"
+func runTableCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: table floor [-watch detik]|seat <meja> [-guests n]|clear <meja>|tab <meja>|bill <meja>")
+	}
+	switch args[0] {
+	case "floor":
+		fs := flag.NewFlagSet("table floor", flag.ContinueOnError)
+		watch := fs.Int("watch", 0, "perbarui layar setiap sekian detik, 0 untuk sekali tampil")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		for {
+			if *watch > 0 {
+				// Data dibaca ulang karena meja dibuka dan ditutup oleh proses kasir atau server
+				var err error
+				if store, err = store.Reopen(); err != nil {
+					return err
+				}
+				fmt.Print("\033[H\033[2J")
+			}
+			printFloorPlan(os.Stdout, cfg.Floor.Tables, store.TableSessions(), time.Now())
+			if *watch <= 0 {
+				return nil
+			}
+			time.Sleep(time.Duration(*watch) * time.Second)
+		}
+	case "seat":
+		fs := flag.NewFlagSet("table seat", flag.ContinueOnError)
+		guests := fs.Int("guests", 0, "jumlah tamu")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("Gunakan: table seat [-guests n] <meja>")
+		}
+		if err := store.SeatTable(fs.Arg(0), *guests); err != nil {
+			return err
+		}
+		fmt.Println("Meja", fs.Arg(0), "terisi")
+	case "clear":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: table clear <meja>")
+		}
+		if err := store.ClearTable(args[1]); err != nil {
+			return err
+		}
+		fmt.Println("Meja", args[1], "dikosongkan")
+	case "tab", "bill":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: table %s <meja>", args[0])
+		}
+		return runTableTab(cfg, store, args[1], args[0] == "bill")
+	default:
+		return fmt.Errorf("Sub-perintah table tidak dikenal: %s", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Asal pesanan dari bot Telegram, dicatat di Order.Source
+const telegramSource = "telegram"
+
+// Struct untuk Konfigurasi bot Telegram untuk pemesanan jarak jauh
+// Bot hanya berjalan dalam mode server jika bot_token diisi
+type TelegramConfig struct {
+	BotToken    string `json:"bot_token"`    // Token dari @BotFather
+	APIURL      string `json:"api_url"`      // Alamat Bot API, default https://api.telegram.org
+	PollSeconds int    `json:"poll_seconds"` // Lama long polling getUpdates, juga selang pemeriksaan pembayaran
+}
+
+// Struct untuk pesan masuk dari Bot API yang dipakai bot
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		MessageID int64 `json:"message_id"`
+		From      struct {
+			FirstName string `json:"first_name"`
+		} `json:"from"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+// Struct untuk pesanan Telegram yang menunggu pembayaran QRIS di gateway
+type telegramPending struct {
+	ChatID  int64
+	Order   Order
+	Payment Payment
+}
+
+// Struct untuk bot Telegram
+// Keranjang setiap chat disimpan di memori; pesanan yang sudah dikirim disimpan sebagai pesanan terbuka
+// sehingga tetap bisa dibayar di kasir dengan "order pay" walaupun server dimulai ulang
+type telegramBot struct {
+	cfg     *Config
+	store   *Store
+	kitchen *kitchen
+	gateway PaymentGateway
+	api     string
+	http    *http.Client
+
+	mu      sync.Mutex
+	offset  int64
+	carts   map[int64]*Order
+	pending map[string]telegramPending // Per ID pembayaran
+}
+
+// Fungsi untuk membuat bot Telegram dari konfigurasi server
+func newTelegramBot(s *Server) (*telegramBot, error) {
+	gateway, err := newPaymentGateway(s.cfg.PaymentGateway)
+	if err != nil {
+		return nil, err
+	}
+	cfg := s.cfg.Telegram
+	return &telegramBot{
+		cfg: s.cfg, store: s.store, kitchen: s.kitchen, gateway: gateway,
+		api:     strings.TrimRight(cfg.APIURL, "/") + "/bot" + cfg.BotToken,
+		http:    &http.Client{Timeout: time.Duration(cfg.PollSeconds+10) * time.Second},
+		carts:   map[int64]*Order{},
+		pending: map[string]telegramPending{},
+	}, nil
+}
+
+// Fungsi untuk menjalankan bot Telegram selama server berjalan
+func runTelegramBot(s *Server) {
+	bot, err := newTelegramBot(s)
+	if err != nil {
+		fmt.Println("Bot Telegram tidak dijalankan:", err)
+		return
+	}
+	for {
+		updates, err := bot.getUpdates()
+		if err != nil {
+			fmt.Println("Gagal mengambil pesan Telegram:", err)
+			time.Sleep(5 * time.Second)
+		}
+		for _, u := range updates {
+			if u.Message != nil && u.Message.Text != "" {
+				bot.handle(u.Message.Chat.ID, u.Message.MessageID, u.Message.From.FirstName, u.Message.Text)
+			}
+		}
+		bot.checkPayments()
+	}
+}
+
+// Memanggil satu metode Bot API
+func (b *telegramBot) call(method string, body, out any) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := b.http.Post(b.api+"/"+method, "application/json", bytes.NewReader(raw))
+	if err != nil {
+		// Alamat request berisi token bot, sehingga hanya penyebabnya yang ditampilkan di log
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("Bot API tidak dapat dihubungi: %w", err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("Telegram membalas %s", resp.Status)
+	}
+	if !result.OK {
+		return fmt.Errorf("Telegram %s: %s", method, result.Description)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Result, out)
+}
+
+// Mengambil pesan baru dengan long polling; offset dimajukan agar pesan tidak diproses dua kali
+func (b *telegramBot) getUpdates() ([]telegramUpdate, error) {
+	var updates []telegramUpdate
+	body := map[string]any{"offset": b.offset, "timeout": b.cfg.Telegram.PollSeconds, "allowed_updates": []string{"message"}}
+	if err := b.call("getUpdates", body, &updates); err != nil {
+		return nil, err
+	}
+	for _, u := range updates {
+		b.offset = max(b.offset, u.UpdateID+1)
+	}
+	return updates, nil
+}
+
+// Mengirim balasan ke satu chat
+func (b *telegramBot) reply(chatID int64, text string) {
+	if err := b.call("sendMessage", map[string]any{"chat_id": chatID, "text": text}, nil); err != nil {
+		fmt.Println("Gagal membalas chat Telegram:", err)
+	}
+}
+
+// Menangani satu pesan dari pelanggan
+// Teks selain perintah dibaca seperti pesanan chat biasa, mis. "2 nasgor pedas, 1 es teh"
+func (b *telegramBot) handle(chatID, messageID int64, name, text string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return
+	}
+	command := strings.ToLower(fields[0])
+	command, _, _ = strings.Cut(command, "@") // Perintah di grup ditulis "/menu@namabot"
+	switch command {
+	case "/start", "/help":
+		b.reply(chatID, "Halo "+name+"! Kirim pesanan seperti \"2 nasi goreng pedas, 1 es teh\".\n"+
+			"/menu  lihat menu\n/keranjang  lihat pesanan\n/pesan  kirim pesanan dan bayar\n/batal  kosongkan pesanan")
+	case "/menu":
+		b.reply(chatID, telegramMenu(b.store.Menu()))
+	case "/keranjang":
+		b.reply(chatID, b.cartText(chatID))
+	case "/batal":
+		b.mu.Lock()
+		delete(b.carts, chatID)
+		b.mu.Unlock()
+		b.reply(chatID, "Pesanan dikosongkan.")
+	case "/pesan":
+		b.reply(chatID, b.place(chatID, messageID))
+	default:
+		if strings.HasPrefix(command, "/") {
+			b.reply(chatID, "Perintah tidak dikenal. Ketik /help.")
+			return
+		}
+		b.reply(chatID, b.addToCart(chatID, text))
+	}
+}
+
+// Fungsi untuk menyusun daftar menu untuk chat
+func telegramMenu(restaurant *Restaurant) string {
+	var sb strings.Builder
+	sb.WriteString("Menu:\n")
+	for _, item := range restaurant.Menu {
+		if item.SoldOut {
+			fmt.Fprintf(&sb, "- %s (habis)\n", item.Name)
+			continue
+		}
+		fmt.Fprintf(&sb, "- %s: Rp%.0f\n", item.Name, item.Price)
+	}
+	return sb.String()
+}
+
+// Menambahkan item dari teks chat ke keranjang
+func (b *telegramBot) addToCart(chatID int64, text string) string {
+	lines, unknown := parseChatOrder(b.store.Menu(), text)
+	b.mu.Lock()
+	cart := b.carts[chatID]
+	if cart == nil {
+		cart = &Order{Status: OrderPending, Type: OrderTakeaway}
+		b.carts[chatID] = cart
+	}
+	var sb strings.Builder
+	for _, l := range lines {
+		if err := b.cfg.OrderLimits.CheckLine(*cart, l.Line); err != nil {
+			fmt.Fprintf(&sb, "%s tidak ditambahkan, %v.\n", l.Line.Label(), err)
+			continue
+		}
+		if len(cart.Lines) == 0 {
+			cart.FirstItemAt = time.Now()
+		}
+		cart.Lines = append(cart.Lines, l.Line)
+		cart.Total += l.Line.Subtotal()
+		if l.Guess {
+			fmt.Fprintf(&sb, "Dibaca sebagai %s dari %q, ketik /batal jika salah.\n", l.Line.Item.Name, l.Source)
+		}
+	}
+	b.mu.Unlock()
+	for _, u := range unknown {
+		fmt.Fprintln(&sb, "Tidak dikenali:", u)
+	}
+	sb.WriteString(b.cartText(chatID))
+	return sb.String()
+}
+
+// Menyusun isi keranjang chat beserta totalnya
+func (b *telegramBot) cartText(chatID int64) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cart := b.carts[chatID]
+	if cart == nil || len(cart.Lines) == 0 {
+		return "Keranjang masih kosong. Ketik /menu untuk melihat menu."
+	}
+	order := *cart
+	order.Lines = slices.Clone(cart.Lines) // Harga berjadwal baru ditetapkan saat pesanan dikirim
+	pricing := b.cfg.Pricing.Strategy()
+	pricing.Apply(&order)
+	total, _ := pricing.RoundPayment(order.Total)
+	var sb strings.Builder
+	sb.WriteString("Pesanan Anda:\n")
+	for _, l := range order.Lines {
+		fmt.Fprintf(&sb, "- %s x%d: Rp%.0f\n", l.Label(), l.Qty, l.Subtotal())
+	}
+	fmt.Fprintf(&sb, "Total: Rp%.0f\nKetik /pesan untuk memesan.", total)
+	return sb.String()
+}
+
+// Mengirim keranjang sebagai pesanan dan membuat referensi pembayarannya
+// Tanpa gateway, pelanggan membayar di kasir dengan menyebut ID pesanan
+func (b *telegramBot) place(chatID, messageID int64) string {
+	if p := b.store.Pause(); p.Active() {
+		return "Maaf, pesanan online sedang ditutup. " + p.Banner
+	}
+	b.mu.Lock()
+	cart := b.carts[chatID]
+	delete(b.carts, chatID)
+	b.mu.Unlock()
+	if cart == nil || len(cart.Lines) == 0 {
+		return "Keranjang masih kosong."
+	}
+	order := *cart
+	order.ID, order.CreatedAt = orderIDs.NewID("ORD"), time.Now()
+	order.Source, order.SourceRef = telegramSource, strconv.FormatInt(chatID, 10)+":"+strconv.FormatInt(messageID, 10)
+	pricing := b.cfg.Pricing.Strategy()
+	pricing.Apply(&order)
+	payment := Payment{ID: newID("PAY"), OrderIDs: []string{order.ID}, Method: MethodQRIS}
+	payment.Amount, payment.Rounding = pricing.RoundPayment(order.Total)
+	if err := b.store.SaveDrafts([]Order{order}); err != nil {
+		fmt.Println("Pesanan Telegram tidak dapat disimpan:", err)
+		return "Maaf, pesanan tidak dapat disimpan. Coba lagi sebentar lagi."
+	}
+	fmt.Printf("Pesanan telegram %s masuk: %s\n", order.SourceRef, order.ID)
+
+	counter := fmt.Sprintf("Pesanan %s diterima.\nKode pembayaran: %s\nTunjukkan kode ini dan bayar Rp%.0f di kasir.", order.ID, order.ID, payment.Amount)
+	if b.gateway == nil {
+		return counter
+	}
+	result, err := b.gateway.Charge(ChargeRequest{PaymentID: payment.ID, Method: MethodQRIS, Amount: payment.Amount})
+	if err != nil || result.Status == ChargeFailed {
+		fmt.Println("Tagihan QRIS pesanan Telegram", order.ID, "gagal:", err)
+		return counter
+	}
+	payment.ProviderRef = result.Reference
+	p := telegramPending{ChatID: chatID, Order: order, Payment: payment}
+	if result.Status == ChargePaid {
+		return b.complete(p)
+	}
+	b.mu.Lock()
+	b.pending[payment.ID] = p
+	b.mu.Unlock()
+	msg := fmt.Sprintf("Pesanan %s diterima.\nReferensi pembayaran: %s\nBayar Rp%.0f lewat QRIS:", order.ID, result.Reference, payment.Amount)
+	if result.Action != "" {
+		msg += "\n" + result.Action
+	}
+	return msg
+}
+
+// Mencatat pembayaran pesanan Telegram lalu mengirimnya ke dapur, sama seperti pesanan dari kasir
+func (b *telegramBot) complete(p telegramPending) string {
+	p.Payment.PaidAt = time.Now()
+	p.Payment.Tendered = p.Payment.Amount
+	orders := []Order{p.Order}
+	if err := checkout(b.cfg, b.store, orders, p.Payment); err != nil {
+		fmt.Println("Pembayaran pesanan Telegram", p.Order.ID, "tidak dapat dicatat:", err)
+		return fmt.Sprintf("Pembayaran diterima, tetapi pesanan %s belum tercatat. Tunjukkan kode ini di kasir.", p.Order.ID)
+	}
+	if err := b.kitchen.Submit(orders[0]); err != nil {
+		fmt.Println("Pesanan", orders[0].ID, "tidak dikirim ke dapur:", err)
+	}
+	msg := fmt.Sprintf("Pembayaran Rp%.0f diterima. Nomor antrean: %03d", p.Payment.Amount, orders[0].QueueNumber)
+	if !orders[0].EstimatedReadyAt.IsZero() {
+		msg += "\nPerkiraan siap pukul " + orders[0].EstimatedReadyAt.Local().Format("15:04")
+	}
+	return msg
+}
+
+// Memeriksa tagihan QRIS yang belum dibayar
+// Tagihan yang gagal atau kedaluwarsa dilepas; pesanan terbukanya tetap bisa dibayar di kasir
+func (b *telegramBot) checkPayments() {
+	b.mu.Lock()
+	pending := make([]telegramPending, 0, len(b.pending))
+	for _, p := range b.pending {
+		pending = append(pending, p)
+	}
+	b.mu.Unlock()
+	for _, p := range pending {
+		status, err := b.gateway.Status(p.Payment.ProviderRef)
+		if err != nil || status == ChargePending {
+			continue
+		}
+		b.mu.Lock()
+		delete(b.pending, p.Payment.ID)
+		b.mu.Unlock()
+		if status == ChargePaid {
+			b.reply(p.ChatID, b.complete(p))
+			continue
+		}
+		b.reply(p.ChatID, fmt.Sprintf("Pembayaran QRIS pesanan %s gagal. Pesanan masih bisa dibayar di kasir dengan kode %s.", p.Order.ID, p.Order.ID))
+	}
+}
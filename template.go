@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math"
+	"net/mail"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template bawaan email struk, dipakai jika email.subject_template/email.body_template kosong
+const (
+	defaultEmailSubject = "Struk pembayaran {{.Receipt.Payment.ID}}"
+	defaultEmailBody    = "{{.Text}}\nTerima kasih atas kunjungan Anda.\n"
+)
+
+// Struct untuk data yang tersedia di template email struk
+type receiptTemplateData struct {
+	Receipt Receipt
+	Text    string // Struk dalam format layar, sama dengan "receipt show"
+}
+
+// Fungsi untuk membaca template dari file, atau template bawaan jika path kosong
+func loadTemplate(name, path, fallback string) (*template.Template, error) {
+	text := fallback
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Template %s tidak dapat dibaca: %w", name, err)
+		}
+		text = string(raw)
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("Template %s tidak valid: %w", name, err)
+	}
+	return t, nil
+}
+
+// Fungsi untuk menyusun judul dan isi email struk dari template
+func renderReceiptEmail(cfg SMTPConfig, receipt Receipt) (subject, body string, err error) {
+	var text strings.Builder
+	receipt.write(&text, tr("STRUK PEMBAYARAN"))
+	data := receiptTemplateData{Receipt: receipt, Text: text.String()}
+
+	subjectTmpl, err := loadTemplate("subject", cfg.SubjectTemplate, defaultEmailSubject)
+	if err != nil {
+		return "", "", err
+	}
+	bodyTmpl, err := loadTemplate("body", cfg.BodyTemplate, defaultEmailBody)
+	if err != nil {
+		return "", "", err
+	}
+	var s, b bytes.Buffer
+	if err := subjectTmpl.Execute(&s, data); err != nil {
+		return "", "", fmt.Errorf("Template judul email gagal disusun: %w", err)
+	}
+	if err := bodyTmpl.Execute(&b, data); err != nil {
+		return "", "", fmt.Errorf("Template isi email gagal disusun: %w", err)
+	}
+	// Judul email hanya satu baris
+	return strings.Join(strings.Fields(s.String()), " "), b.String(), nil
+}
+
+// Fungsi untuk menyusun struk contoh dari dua item menu pertama
+// Harga, pajak, dan pembulatan mengikuti konfigurasi agar tampilan sama dengan transaksi sungguhan
+func sampleReceipt(cfg *Config, restaurant *Restaurant) Receipt {
+	now := time.Now()
+	order := Order{ID: "ORD-CONTOH", Status: OrderPending, Table: "5", QueueNumber: 7, CreatedAt: now}
+	for i, item := range restaurant.Menu {
+		if len(order.Lines) == 2 {
+			break
+		}
+		line := OrderLine{Item: item, Qty: i + 1}
+		order.Lines = append(order.Lines, line)
+		order.Total += line.Subtotal()
+	}
+	pricing := cfg.Pricing.Strategy()
+	pricing.Apply(&order)
+	total, rounding := pricing.RoundPayment(order.Total)
+	tendered := total.RoundTo(50000*Rp, math.Ceil)
+	return Receipt{
+		Payment: Payment{
+			ID: "PAY-CONTOH", OrderIDs: []string{order.ID}, Amount: total, Tendered: tendered,
+			Change: tendered - total, Rounding: rounding, PaidAt: now,
+		},
+		Orders:   []Order{order},
+		Customer: &Customer{ID: "CUS-CONTOH", Name: "Pelanggan Contoh", Phone: "081234567890"},
+	}
+}
+
+// Template yang dapat dipratinjau
+var templateNames = []string{"receipt", "email", "ticket", "stub", "escpos"}
+
+// Fungsi untuk menjalankan "template preview" dan "template send"
+// Struk contoh dipakai kecuali -payment diisi ID pembayaran atau pesanan yang sudah ada
+func runTemplateCommand(cfg *Config, store *Store, args []string) error {
+	usage := fmt.Errorf("Gunakan: template preview <%s> [-payment id] atau template send <email|escpos> [-to alamat] [-payment id]",
+		strings.Join(templateNames, "|"))
+	if len(args) < 2 || (args[0] != "preview" && args[0] != "send") {
+		return usage
+	}
+	fs := flag.NewFlagSet("template "+args[0], flag.ContinueOnError)
+	paymentID := fs.String("payment", "", "ID pembayaran atau pesanan sungguhan, kosong untuk struk contoh")
+	to := fs.String("to", "", "alamat email tujuan uji coba")
+	if err := fs.Parse(args[2:]); err != nil {
+		return err
+	}
+	receipt := sampleReceipt(cfg, store.Menu())
+	if *paymentID != "" {
+		r, err := store.Receipt(*paymentID)
+		if err != nil {
+			return err
+		}
+		receipt = r
+	}
+
+	name := args[1]
+	if args[0] == "send" {
+		switch name {
+		case "email":
+			if cfg.Email.Host == "" {
+				return errEmailDisabled
+			}
+			addr, err := mail.ParseAddress(*to)
+			if err != nil {
+				return fmt.Errorf("Alamat email tujuan uji coba tidak valid: %q", *to)
+			}
+			subject, body, err := renderReceiptEmail(cfg.Email, receipt)
+			if err != nil {
+				return err
+			}
+			// Dikirim langsung tanpa antrean agar hasilnya langsung terlihat
+			if err := sendEmail(cfg.Email, QueuedEmail{To: addr.Address, Subject: "[UJI] " + subject, Body: body}, nil); err != nil {
+				return fmt.Errorf("Email uji coba gagal dikirim: %w", err)
+			}
+			fmt.Println("Email uji coba dikirim ke", addr.Address)
+		case "escpos":
+			if !cfg.Printer.Enabled() {
+				return fmt.Errorf("printer.device atau printer.addr belum diatur di konfigurasi")
+			}
+			if err := printReceipt(cfg.Printer, receipt, tr("STRUK PEMBAYARAN")); err != nil {
+				return err
+			}
+			fmt.Println("Struk uji coba dicetak ke printer")
+		default:
+			return usage
+		}
+		return nil
+	}
+
+	switch name {
+	case "receipt":
+		receipt.write(os.Stdout, tr("STRUK PEMBAYARAN"))
+	case "email":
+		subject, body, err := renderReceiptEmail(cfg.Email, receipt)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Subject:", subject)
+		fmt.Println()
+		fmt.Print(body)
+	case "ticket":
+		for _, o := range receipt.Orders {
+			writeKitchenTicket(os.Stdout, o)
+		}
+	case "stub":
+		for _, o := range receipt.Orders {
+			writeQueueStub(os.Stdout, o)
+		}
+	case "escpos":
+		if err := receipt.writeESCPOS(os.Stdout, cfg.Printer, tr("STRUK PEMBAYARAN"), true); err != nil {
+			return err
+		}
+	default:
+		return usage
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Jumlah yang harus dibayar pelanggan: tagihan ditambah tip
+func (p Payment) Due() Money {
+	return p.Amount + p.Tip
+}
+
+// Fungsi untuk membaca tip dari nominal, mis. "5000", atau persen dari tagihan, mis. "10%"
+// Keduanya harus berformat harga biasa, sehingga tanda, eksponen, atau "NaN" ditolak
+func parseTip(input string, bill Money) (Money, error) {
+	input = strings.TrimSpace(input)
+	if pct, ok := strings.CutSuffix(input, "%"); ok {
+		rate, err := validatePrice(strings.TrimSpace(pct))
+		if err != nil || rate > 100*Rp {
+			return 0, fmt.Errorf("Persen tip %q tidak valid", input)
+		}
+		return bill.MulRate(rate.Float() / 100), nil
+	}
+	tip, err := validatePrice(input)
+	if err != nil {
+		return 0, fmt.Errorf("Tip %q tidak valid", input)
+	}
+	return tip, nil
+}
+
+// Fungsi untuk menanyakan tip pelanggan dan pelayan penerimanya sebelum metode pembayaran
+// Tip dicatat terpisah dari tagihan sehingga tidak masuk penjualan; pelayan kosong berarti kasir yang menerima
+func promptTip(payment *Payment) {
+	if payment.Amount <= 0 {
+		return
+	}
+	for {
+		input := readLine(tr("Tip (nominal atau persen mis. 10%, Enter jika tidak ada):"))
+		if input == "" {
+			return
+		}
+		tip, err := parseTip(input, payment.Amount)
+		if err != nil {
+			fmt.Println(tr("Tip tidak valid. Coba lagi."))
+			continue
+		}
+		if tip == 0 {
+			return
+		}
+		payment.Tip = tip
+		break
+	}
+	payment.TipTo = payment.CashierID
+	if waiter := strings.TrimSpace(readLine(tr("Pelayan penerima tip (ID atau nama staf, Enter untuk kasir):"))); waiter != "" {
+		payment.TipTo = waiter
+	}
+	fmt.Print(tr("Tip Rp%.2f, total dibayar Rp%.2f\n", payment.Tip, payment.Due()))
+}
+
+// Fungsi untuk mendapatkan nama pelayan penerima tip dari ID atau nama staf yang dicatat
+func tipRecipient(staff []Staff, key string) string {
+	for _, st := range staff {
+		if st.ID == key || strings.EqualFold(st.Name, key) {
+			return st.Name
+		}
+	}
+	if key == "" {
+		return "(kasir)" // Kasir tanpa login PIN
+	}
+	return key
+}
+
+// Fungsi untuk menjumlahkan tip per nama pelayan dari rekap tip per ID staf
+func tipsByName(tips map[string]Money, staff []Staff) map[string]Money {
+	byName := map[string]Money{}
+	for key, amount := range tips {
+		byName[tipRecipient(staff, key)] += amount
+	}
+	return byName
+}
+
+// Fungsi untuk menampilkan tip per pelayan di laporan harian
+func writeTips(w io.Writer, tips map[string]Money) {
+	if len(tips) == 0 {
+		return
+	}
+	var total Money
+	for _, amount := range tips {
+		total += amount
+	}
+	fmt.Fprintf(w, "Tip (di luar penjualan): Rp%.2f\n", total)
+	for _, name := range slices.Sorted(maps.Keys(tips)) {
+		fmt.Fprintf(w, "  %-16s: Rp%.2f\n", name, tips[name])
+	}
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"bufio"
+	"cmp"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,89 +19,573 @@ import (
 // Struct untuk Menu Item
 // Mewakili item menu dengan nama dan harga
 type MenuItem struct {
-	Name  string  // Nama item menu
-	Price float64 // Harga item menu
+	Name        string   `json:"name"`                  // Nama item menu
+	Price       Money    `json:"price"`                 // Harga item menu
+	Category    string   `json:"category,omitempty"`    // Kategori item menu (mis. Makanan, Minuman)
+	SoldOut     bool     `json:"sold_out,omitempty"`    // Menandakan item sedang habis
+	Substitutes []string `json:"substitutes,omitempty"` // Nama item pengganti jika item ini habis
+
+	Variants []VariantGroup `json:"variants,omitempty"` // Pilihan varian, mis. ukuran atau level pedas
+	AddOns   []AddOn        `json:"add_ons,omitempty"`  // Tambahan berbayar, mis. extra telur
+
+	Recipe      []RecipeIngredient `json:"recipe,omitempty"`       // Bahan untuk satu porsi, item habis jika salah satu bahan tidak cukup
+	PrepMinutes int                `json:"prep_minutes,omitempty"` // Perkiraan lama masak dalam menit, kosong berarti memakai bawaan dapur
+
+	Components []ComboComponent `json:"components,omitempty"` // Isi paket, kosong jika bukan paket
+
+	Description string   `json:"description,omitempty"` // Keterangan singkat item, ditampilkan dengan -detail
+	Allergens   []string `json:"allergens,omitempty"`   // Alergen yang dikandung, mis. "Telur" atau "Kacang"
+
+	Aliases []string `json:"aliases,omitempty"` // Alias dari kamus alias, mis. "nasgor"; diisi saat menu dimuat
+
+	Code string `json:"code,omitempty"` // Kode barcode/PLU untuk scanner, hanya angka
+}
+
+// Struct untuk Baris Pesanan
+// Mewakili satu item menu yang dipesan beserta jumlahnya
+type OrderLine struct {
+	Item      MenuItem   `json:"item"`                // Item menu yang dipesan
+	Qty       int        `json:"qty"`                 // Jumlah yang dipesan
+	Modifiers []Modifier `json:"modifiers,omitempty"` // Varian dan tambahan yang dipilih
+	Notes     []string   `json:"notes,omitempty"`     // Catatan dapur baku, mis. "Tanpa Bawang"
+	FreeNote  string     `json:"free_note,omitempty"` // Catatan dapur yang tidak cocok dengan daftar baku
+
+	BasePrice Money  `json:"base_price,omitempty"` // Harga menu normal sebelum aturan harga berjadwal
+	PriceRule string `json:"price_rule,omitempty"` // Nama aturan harga yang berlaku, kosong jika harga normal
+
+	Round     int       `json:"round,omitempty"`     // Ronde pesanan pada tab meja, 0 jika bukan tab
+	OrderedAt time.Time `json:"ordered_at,omitzero"` // Waktu ronde dipesan, untuk aturan harga berjadwal pada tab meja
 }
 
 // Struct untuk Pesanan
 // Mewakili pesanan dengan daftar item dan total harga
 type Order struct {
-	MenuItems []MenuItem // Daftar item menu yang dipesan
-	Total     float64    // Total harga dari pesanan
+	ID         string      `json:"id"`                    // ID unik pesanan
+	Lines      []OrderLine `json:"lines"`                 // Daftar item menu yang dipesan
+	Total      Money       `json:"total"`                 // Total harga dari pesanan
+	CustomerID string      `json:"customer_id,omitempty"` // ID pelanggan member, kosong jika umum
+	Table      string      `json:"table,omitempty"`       // Nomor meja, kosong jika bawa pulang
+	Status     OrderStatus `json:"status"`                // Status pesanan
+	PaymentID  string      `json:"payment_id,omitempty"`  // ID pembayaran, kosong jika belum dibayar
+	CreatedAt  time.Time   `json:"created_at"`            // Waktu pesanan dibuat
+
+	FirstItemAt time.Time `json:"first_item_at,omitzero"` // Waktu item pertama dimasukkan, untuk mengukur kecepatan kasir
+
+	Subtotal    Money `json:"subtotal,omitempty"`     // Jumlah harga menu sebelum pajak dan pembulatan, kosong pada data lama
+	Tax         Money `json:"tax,omitempty"`          // Pajak pesanan
+	TaxIncluded bool  `json:"tax_included,omitempty"` // Pajak sudah termasuk di harga menu, tidak ditambahkan ke total
+	Rounding    Money `json:"rounding,omitempty"`     // Selisih pembulatan per baris atau per pesanan
+
+	QueueNumber int       `json:"queue_number,omitempty"` // Nomor antrean harian untuk pengambilan
+	ReadyAt     time.Time `json:"ready_at,omitzero"`      // Waktu dapur selesai memasak, kosong jika belum
+	PickupCode  string    `json:"pickup_code,omitempty"`  // Kode pendek untuk pelanggan mengecek status pesanan
+
+	MenuVersion int `json:"menu_version,omitempty"` // Versi menu yang berlaku saat pesanan disimpan, 0 jika menu belum pernah diubah
+
+	PrepMinutes      int       `json:"prep_minutes,omitempty"`      // Perkiraan lama masak pesanan saat dikonfirmasi
+	EstimatedReadyAt time.Time `json:"estimated_ready_at,omitzero"` // Perkiraan waktu siap dari lama masak dan antrean dapur
+
+	Overrides []string `json:"overrides,omitempty"` // Pelanggaran aturan pesanan yang tetap dilanjutkan kasir
+	Allergies []string `json:"allergies,omitempty"` // Alergi yang disebut pelanggan, dicetak di tiket dapur
+
+	ParentID string `json:"parent_id,omitempty"` // Pesanan asal jika pesanan ini tambahan setelah dikirim ke dapur
+	Revision int    `json:"revision,omitempty"`  // Jumlah revisi item setelah dikirim ke dapur, dicetak di tiket "REVISI"
+
+	Type            OrderType `json:"type,omitempty"`             // Jenis pesanan, kosong pada data lama
+	DeliveryAddress string    `json:"delivery_address,omitempty"` // Alamat pengantaran untuk pesanan antar
+	DeliveryFee     Money     `json:"delivery_fee,omitempty"`     // Ongkos kirim, ditambahkan ke total tanpa pajak
+
+	Source     string `json:"source,omitempty"`     // Kanal pemesanan online asal pesanan, kosong untuk pesanan dari kasir
+	SourceRef  string `json:"source_ref,omitempty"` // ID pesanan di platform kanal
+	Commission Money  `json:"commission,omitempty"` // Komisi platform kanal dari total pesanan
+
+	Tab bool `json:"tab,omitempty"` // Tab meja yang setiap rondenya sudah dikirim ke dapur saat dipesan
+
+	TabFlaggedAt time.Time `json:"tab_flagged_at,omitzero"` // Waktu tutup hari menemukan tab ini belum dibayar
+	TabClosed    bool      `json:"tab_closed,omitempty"`    // Tab dilepas dari mejanya saat tutup hari, tinggal dibayar dengan "order pay"
+
+	HoldLabel string `json:"hold_label,omitempty"` // Label pesanan yang ditahan kasir di tengah input, mis. nama pelanggan
+
+	Priority OrderPriority `json:"priority,omitempty"` // Prioritas di antrean dapur, mis. kurir sudah menunggu
+	BumpedAt time.Time     `json:"bumped_at,omitzero"` // Waktu pesanan didahulukan manual ke depan antrean
 }
 
 // Interface untuk manajemen menu
 // Mendefinisikan metode yang harus diimplementasikan
 type MenuManager interface {
-	AddMenuItem(name string, price float64) // Menambahkan item menu
-	PrintMenu()                             // Menampilkan daftar menu
+	AddMenuItem(name string, price Money) // Menambahkan item menu
+	PrintMenu()                           // Menampilkan daftar menu
 }
 
 // Struct Restaurant yang akan mengimplementasi interface MenuManager
 type Restaurant struct {
 	Menu []MenuItem // Daftar item menu yang tersedia
+
+	KitchenNotes []KitchenNote // Catatan dapur yang ditawarkan per baris, kosong berarti tidak ditanyakan
+	Rules        []OrderRule   // Aturan pesanan yang diperiksa saat item dimasukkan
+	Limits       OrderLimits   // Batas jumlah per item dan per pesanan
+
+	OrderTypes  bool  // Tanyakan jenis pesanan di awal input pesanan
+	DeliveryFee Money // Ongkos kirim bawaan untuk pesanan antar
+
+	AskAllergies bool // Tanyakan alergi pelanggan di awal input pesanan
+
+	LearnAlias func(alias, item string) error // Menyimpan alias dari koreksi salah ketik yang dikonfirmasi, boleh nil
+	aliasMu    sync.RWMutex                   // Melindungi alias item menu yang ditambah sesi kasir saat dibaca goroutine intake
+	Events     *eventBus                      // Menerima kejadian item.added dari input pesanan, boleh nil
+
+	Pricing     *Pricing // Aturan harga untuk ringkasan pesanan, nil berarti tanpa pajak dan pembulatan
+	SkipConfirm bool     // Pesanan langsung dikirim setelah "selesai" tanpa ringkasan
+
+	PriceGuards    []PriceGuard          // Batas kewajaran harga per kategori
+	ApproveManager func() (Staff, error) // Meminta PIN admin untuk harga di luar batas, boleh nil
+
+	IdleTimeout time.Duration // Pesanan dibatalkan atau ditahan jika prompt item tidak disentuh selama ini, 0 berarti tidak pernah
+	IdleHold    bool          // Pesanan yang ditinggalkan ditahan, bukan dibatalkan
+
+	HoldOrder func(order Order) error           // Menyimpan pesanan yang ditahan, nil berarti "tahan" tidak tersedia
+	HeldOrder func(label string) (Order, error) // Mengambil dan melepas pesanan yang ditahan dengan label tersebut
 }
 
-var wg sync.WaitGroup // WaitGroup untuk sinkronisasi goroutine
+var input = bufio.NewScanner(os.Stdin) // Scanner bersama untuk membaca input pengguna
+
+// Error yang dikembalikan saat validasi item pesanan
+var (
+	errItemNotFound = errors.New("Item tidak ditemukan di menu")
+	errItemSoldOut  = errors.New("Item sedang habis")
+
+	errInputClosed = errors.New("Input berakhir sebelum pesanan selesai")
+
+	errPaymentAttempts = errors.New("Batas percobaan pembayaran tercapai")
+)
+
+// Jumlah maksimal item pengganti yang ditawarkan
+const maxSubstitutes = 3
 
 // Implementasi interface MenuManager
 // Menambahkan item menu baru
-func (r *Restaurant) AddMenuItem(name string, price float64) {
+func (r *Restaurant) AddMenuItem(name string, price Money) {
+	// Kategori belum diketahui di sini, sehingga harga diperiksa dengan batas bawaan
+	if v, ok := checkPriceGuard(r.PriceGuards, "", name, price); !ok {
+		fmt.Println(tr("PERINGATAN:"), v.Message)
+	}
 	r.Menu = append(r.Menu, MenuItem{Name: name, Price: price})
 }
 
-// Menampilkan daftar menu
+// Menampilkan daftar menu beserta nomor yang bisa diketik kasir
 func (r *Restaurant) PrintMenu() {
-	fmt.Println("Menu:")
-	for _, item := range r.Menu {
-		fmt.Printf("%s: Rp%.2f\n", item.Name, item.Price)
+	fmt.Println(tr("Menu:"))
+	if compactMode {
+		r.printMenuCompact()
+		return
+	}
+	for i, item := range r.Menu {
+		if item.SoldOut {
+			fmt.Print(tr("%d. %s: Rp%.2f (HABIS)\n", i+1, item.Name, item.Price))
+			continue
+		}
+		fmt.Printf("%d. %s: Rp%.2f\n", i+1, item.Name, item.Price)
+		if item.IsCombo() {
+			fmt.Print(tr("   Isi: %s (hemat Rp%.2f)\n", item.ComponentsLabel(), r.ComboSavings(item)))
+		}
+		if menuDetail {
+			printMenuDetail(r, item)
+		}
+	}
+}
+
+// Mencari item menu berdasarkan nama (tidak peka huruf besar/kecil)
+// Mengembalikan pointer ke item di dalam menu agar bisa diubah langsung
+func (r *Restaurant) findMenuItem(name string) *MenuItem {
+	for i := range r.Menu {
+		if strings.EqualFold(r.Menu[i].Name, name) {
+			return &r.Menu[i]
+		}
+	}
+	return nil
+}
+
+// Mengatur kategori item menu
+func (r *Restaurant) SetCategory(name, category string) {
+	if item := r.findMenuItem(name); item != nil {
+		item.Category = category
+	}
+}
+
+// Menandai item menu sebagai habis atau tersedia kembali
+func (r *Restaurant) SetSoldOut(name string, soldOut bool) {
+	if item := r.findMenuItem(name); item != nil {
+		item.SoldOut = soldOut
+	}
+}
+
+// Mengatur daftar item pengganti untuk item menu
+func (r *Restaurant) SetSubstitutes(name string, substitutes ...string) {
+	if item := r.findMenuItem(name); item != nil {
+		item.Substitutes = substitutes
 	}
 }
 
+// Fungsi untuk mencari item pengganti dari item yang habis
+// Pengganti yang dikonfigurasi didahulukan, lalu item lain dalam kategori yang sama
+func suggestSubstitutes(restaurant *Restaurant, item MenuItem) []MenuItem {
+	var suggestions []MenuItem
+	seen := map[string]bool{strings.ToLower(item.Name): true}
+	add := func(candidate MenuItem) {
+		key := strings.ToLower(candidate.Name)
+		if candidate.SoldOut || seen[key] || len(suggestions) >= maxSubstitutes {
+			return
+		}
+		seen[key] = true
+		suggestions = append(suggestions, candidate)
+	}
+
+	for _, name := range item.Substitutes {
+		if candidate := restaurant.findMenuItem(name); candidate != nil {
+			add(*candidate)
+		}
+	}
+	if item.Category != "" {
+		for _, candidate := range restaurant.Menu {
+			if strings.EqualFold(candidate.Category, item.Category) {
+				add(candidate)
+			}
+		}
+	}
+	return suggestions
+}
+
+// Fungsi untuk menawarkan item pengganti kepada kasir
+// Kasir cukup menekan nomor pengganti, atau Enter untuk membatalkan
+func offerSubstitute(restaurant *Restaurant, item MenuItem) (*MenuItem, bool) {
+	suggestions := suggestSubstitutes(restaurant, item)
+	if len(suggestions) == 0 {
+		fmt.Print(tr("%s sedang habis dan tidak ada pengganti yang tersedia.\n", item.Name))
+		return nil, false
+	}
+
+	fmt.Print(tr("%s sedang habis. Pengganti yang tersedia:\n", item.Name))
+	for i, suggestion := range suggestions {
+		fmt.Printf("%d. %s: Rp%.2f\n", i+1, suggestion.Name, suggestion.Price)
+	}
+	choice, err := strconv.Atoi(readLine(tr("Tekan nomor pengganti (Enter untuk batal): ")))
+	if err != nil || choice < 1 || choice > len(suggestions) {
+		return nil, false
+	}
+	return &suggestions[choice-1], true
+}
+
 // Fungsi untuk menerima pesanan menggunakan goroutine dan channel
-func takeOrder(restaurant *Restaurant, ch chan<- Order) {
-	defer wg.Done() // Pastikan wg.Done dipanggil saat goroutine selesai
-	order := Order{}
+func takeOrder(restaurant *Restaurant, ch chan<- Order) error {
+	order, err := startOrder(restaurant)
+	if err != nil {
+		return err
+	}
 	var itemName string
-	var itemQty int
-	scanner := bufio.NewScanner(os.Stdin) // Scanner untuk membaca input pengguna
 
 	for {
 		// Menampilkan menu dan meminta nama item
-		fmt.Println("Masukkan nama item (ketik 'selesai' untuk menyelesaikan): ")
-		scanner.Scan()
-		itemName = strings.ToLower(scanner.Text())
+		// Terminal yang ditinggalkan kembali ke menu dengan pesanan baru agar kasir berikutnya tidak mewarisi keranjang
+		name, err := readLineWithin(tr("Masukkan nomor atau nama item, boleh diikuti jumlah mis. '1 x2' (ketik 'cari <kata>' untuk mencari menu, 'batal' untuk menghapus item terakhir, 'tahan'/'lanjut <label>' untuk menahan pesanan, 'prioritas <rider|vip>' untuk mendahulukan di dapur, 'tempel' untuk pesanan dari chat, 'selesai' untuk menyelesaikan): "), restaurant.IdleTimeout)
+		if errors.Is(err, errInputIdle) {
+			if len(order.Lines) == 0 {
+				continue
+			}
+			abandonIdleOrder(restaurant, &order)
+			if order, err = startOrder(restaurant); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		itemName = strings.ToLower(name)
 
-		if itemName == "selesai" {
+		if itemName == "selesai" || itemName == tr("selesai") {
+			overrides, ok := confirmOrderRules(restaurant.Rules, order, true)
+			if !ok {
+				continue // Kasir melengkapi pesanan terlebih dahulu
+			}
+			if !confirmOrder(restaurant, order) {
+				continue // Kasir kembali mengubah pesanan
+			}
+			order.Overrides = append(order.Overrides, overrides...)
+			// Pesanan yang dilanjutkan sudah tidak ditahan
+			order.HoldLabel = ""
 			break // Jika pengguna mengetik 'selesai', keluar dari loop
 		}
+		if itemName == "batal" || itemName == "undo" || itemName == tr("batal") {
+			undoLastLine(&order)
+			continue
+		}
+		// Label diambil dari masukan asli agar huruf besar/kecilnya tetap
+		word, label, _ := strings.Cut(strings.TrimSpace(name), " ")
+		switch strings.ToLower(word) {
+		case "tahan", "hold":
+			if holdOrder(restaurant, &order, label) {
+				// Kasir melayani pelanggan berikutnya dengan pesanan baru
+				if order, err = startOrder(restaurant); err != nil {
+					return err
+				}
+			}
+			continue
+		case "lanjut", "resume":
+			resumeHeldOrder(restaurant, &order, label)
+			continue
+		case "prioritas", "priority":
+			setOrderPriority(&order, label)
+			openCart.Update(order)
+			continue
+		}
+		if itemName == "tempel" || itemName == "paste" || itemName == tr("tempel") {
+			if err := pasteChatOrder(restaurant, &order); err != nil {
+				return err
+			}
+			continue
+		}
+		if word, query, _ := strings.Cut(itemName, " "); word == "cari" || word == "search" || word == tr("cari") {
+			searchOrderMenu(restaurant, query)
+			continue
+		}
 
 		// Validasi pesanan
-		if menuItem, ok := validateOrderItem(restaurant, itemName); ok {
-			fmt.Println("Masukkan jumlah: ")
-			fmt.Scanln(&itemQty)
-			order.MenuItems = append(order.MenuItems, *menuItem)
-			order.Total += menuItem.Price * float64(itemQty) // Menghitung total harga
+		// Kode barcode/PLU langsung menambahkan satu porsi tanpa menanyakan varian, jumlah, dan catatan
+		key, itemQty := parseItemEntry(itemName)
+		menuItem, err := restaurant.itemByCode(key)
+		scanned := !errors.Is(err, errItemNotFound)
+		if !scanned {
+			menuItem, err = lookupMenuEntry(restaurant, key)
+		}
+		if errors.Is(err, errItemNotFound) {
+			// Nama dengan salah ketik kecil ditawarkan item yang paling mirip
+			// Koreksi yang dikonfirmasi disimpan sebagai alias agar langsung dikenali berikutnya
+			if guess := closestMenuItem(restaurant, key); guess != nil &&
+				strings.EqualFold(readLine(tr("Maksud Anda %q? (y/n)", guess.Name)), "y") {
+				learnAlias(restaurant, key, guess)
+				menuItem, err = validateOrderItem(restaurant, strings.ToLower(guess.Name))
+			} else if guess == nil && searchOrderMenu(restaurant, key) {
+				// Sebagian nama seperti "ayam" menampilkan daftar item agar kasir bisa mengetik nomornya
+				continue
+			}
+		}
+		if errors.Is(err, errItemSoldOut) {
+			// Tawarkan pengganti agar pelanggan tidak langsung ditolak
+			substitute, ok := offerSubstitute(restaurant, *menuItem)
+			if !ok {
+				continue
+			}
+			menuItem, err = substitute, nil
+		}
+		if err != nil {
+			fmt.Println(tr("Item tidak valid. Coba lagi."))
+			continue
+		}
+		if !confirmAllergens(restaurant, order, *menuItem) {
+			fmt.Println(tr("Item tidak ditambahkan."))
+			continue
+		}
+
+		var modifiers []Modifier
+		if scanned {
+			modifiers = defaultModifiers(*menuItem)
+			itemQty = max(itemQty, 1)
 		} else {
-			fmt.Println("Item tidak valid. Coba lagi.")
+			modifiers = promptModifiers(*menuItem)
 		}
+		if itemQty == 0 {
+			itemQty, err = strconv.Atoi(readLine(tr("Masukkan jumlah: ")))
+			if err != nil || itemQty <= 0 {
+				fmt.Println(tr("Jumlah tidak valid. Coba lagi."))
+				continue
+			}
+		}
+		line := OrderLine{Item: *menuItem, Qty: itemQty, Modifiers: modifiers}
+		if err := restaurant.Limits.CheckLine(order, line); err != nil {
+			fmt.Println(err)
+			fmt.Println(tr("Item tidak ditambahkan."))
+			continue
+		}
+		if len(restaurant.KitchenNotes) > 0 && !scanned {
+			line.Notes, line.FreeNote = promptKitchenNotes(restaurant.KitchenNotes)
+		}
+		candidate := order
+		candidate.Lines = append(slices.Clone(order.Lines), line)
+		overrides, ok := confirmOrderRules(restaurant.Rules, candidate, false)
+		if !ok {
+			fmt.Println(tr("Item tidak ditambahkan."))
+			continue
+		}
+		order.Overrides = append(order.Overrides, overrides...)
+		if len(order.Lines) == 0 {
+			order.FirstItemAt = time.Now()
+		}
+		order.Lines = append(order.Lines, line)
+		order.Total += line.Subtotal() // Menghitung total harga
+		openCart.Update(order)
+		if scanned {
+			fmt.Print(tr("+ %s x%d: Rp%.2f. Total sementara: Rp%.2f\n", line.Label(), line.Qty, line.Subtotal(), order.Total))
+		}
+		restaurant.Events.Publish(OrderEvent{Type: OrderEventItemAdded, Order: order, Line: line})
 	}
 	// Kirim pesanan ke channel
 	ch <- order
+	return nil
+}
+
+// Fungsi untuk menghapus baris terakhir pesanan yang salah ketik dan menampilkan total yang sudah dikoreksi
+// Stok bahan baru dikurangi saat pembayaran, sehingga tidak ada stok yang perlu dikembalikan di sini
+func undoLastLine(order *Order) {
+	if len(order.Lines) == 0 {
+		fmt.Println(tr("Belum ada item untuk dihapus."))
+		return
+	}
+	line := order.Lines[len(order.Lines)-1]
+	order.Lines = order.Lines[:len(order.Lines)-1]
+	order.Total -= line.Subtotal()
+	if len(order.Lines) == 0 {
+		order.Total = 0
+		order.FirstItemAt = time.Time{}
+	}
+	openCart.Update(*order)
+	fmt.Print(tr("Dihapus: %s x%d. Total sementara: Rp%.2f\n", line.Label(), line.Qty, order.Total))
+}
+
+// Fungsi untuk menampilkan ringkasan pesanan lalu meminta konfirmasi sebelum dikirim
+// Mengembalikan false jika kasir menjawab tidak dan ingin mengubah pesanan lagi
+func confirmOrder(restaurant *Restaurant, order Order) bool {
+	if restaurant.SkipConfirm || len(order.Lines) == 0 {
+		return true
+	}
+	// Harga dihitung pada salinan agar pesanan asli tetap dihitung ulang oleh loop kasir
+	preview := order
+	preview.Lines = slices.Clone(order.Lines)
+	if restaurant.Pricing != nil {
+		restaurant.Pricing.Apply(&preview)
+	}
+	fmt.Println(tr("Ringkasan pesanan:"))
+	for _, line := range preview.Lines {
+		fmt.Printf("- %s x%d @Rp%.2f: Rp%.2f\n", line.Label(), line.Qty, line.UnitPrice(), line.Subtotal())
+	}
+	writePriceBreakdown(os.Stdout, []Order{preview}, 0)
+	fmt.Print(tr("Total         : Rp%.2f\n", preview.Total))
+	for {
+		switch strings.ToLower(readLine(tr("Kirim pesanan? (Ya/Tidak)"))) {
+		case "ya", "y", "yes":
+			return true
+		case "tidak", "t", "n", "no":
+			fmt.Println(tr("Pesanan belum dikirim, silakan ubah pesanan."))
+			return false
+		}
+		fmt.Println(tr("Jawab Ya atau Tidak."))
+	}
 }
 
 // Fungsi untuk memvalidasi item pesanan dari menu
-func validateOrderItem(restaurant *Restaurant, itemName string) (*MenuItem, bool) {
+// Item yang habis tetap dikembalikan bersama errItemSoldOut agar bisa dicarikan pengganti
+func validateOrderItem(restaurant *Restaurant, itemName string) (*MenuItem, error) {
 	for _, menuItem := range restaurant.Menu {
 		if strings.ToLower(menuItem.Name) == itemName {
-			return &menuItem, true // Item ditemukan
+			if menuItem.SoldOut {
+				return &menuItem, errItemSoldOut // Item ditemukan tetapi habis
+			}
+			return &menuItem, nil // Item ditemukan
+		}
+	}
+	return nil, errItemNotFound // Item tidak valid
+}
+
+// Fungsi untuk memisahkan jumlah dari masukan item, mis. "1 x2" atau "nasi goreng x3"
+// qty bernilai 0 jika jumlah tidak disebut
+func parseItemEntry(entry string) (key string, qty int) {
+	entry = strings.TrimSpace(entry)
+	if i := strings.LastIndex(entry, " "); i > 0 {
+		if n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(entry[i+1:]), "x")); err == nil && n > 0 {
+			return strings.TrimSpace(entry[:i]), n
+		}
+	}
+	return entry, 0
+}
+
+// Fungsi untuk mencari item berdasarkan nomor menu, nama, atau alias
+func lookupMenuEntry(restaurant *Restaurant, key string) (*MenuItem, error) {
+	if n, err := strconv.Atoi(key); err == nil {
+		if n < 1 || n > len(restaurant.Menu) {
+			return nil, errItemNotFound
+		}
+		item := restaurant.Menu[n-1]
+		if item.SoldOut {
+			return &item, errItemSoldOut
+		}
+		return &item, nil
+	}
+	item, err := validateOrderItem(restaurant, strings.ToLower(key))
+	if errors.Is(err, errItemNotFound) {
+		if aliased := restaurant.itemByAlias(key); aliased != nil {
+			return validateOrderItem(restaurant, strings.ToLower(aliased.Name))
+		}
+	}
+	return item, err
+}
+
+// Fungsi untuk menampilkan hasil pencarian menu saat menerima pesanan
+// Mengembalikan false jika tidak ada item yang cocok
+func searchOrderMenu(restaurant *Restaurant, query string) bool {
+	matches := searchMenu(restaurant.Menu, query)
+	if len(matches) == 0 {
+		fmt.Println(tr("Tidak ada item menu yang cocok dengan %q.", strings.TrimSpace(query)))
+		return false
+	}
+	fmt.Println(tr("Item yang cocok, ketik nomornya untuk memesan:"))
+	printMenuMatches(os.Stdout, matches)
+	return true
+}
+
+// Fungsi untuk mencari item menu dengan nama paling mirip
+// Hanya salah ketik kecil yang ditawarkan: paling banyak 2 huruf dan kurang dari sepertiga panjang nama
+func closestMenuItem(restaurant *Restaurant, name string) *MenuItem {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, err := strconv.Atoi(name); err == nil || name == "" {
+		return nil
+	}
+	var best *MenuItem
+	bestDistance := 0
+	for i := range restaurant.Menu {
+		d := editDistance(name, strings.ToLower(restaurant.Menu[i].Name))
+		if best == nil || d < bestDistance {
+			best, bestDistance = &restaurant.Menu[i], d
+		}
+	}
+	if best == nil || bestDistance > 2 || bestDistance*3 >= len([]rune(best.Name)) {
+		return nil
+	}
+	return best
+}
+
+// Fungsi untuk menghitung jarak Levenshtein antara dua teks
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
 		}
+		prev = cur
 	}
-	return nil, false // Item tidak valid
+	return prev[len(rb)]
 }
 
 // Fungsi untuk memvalidasi input harga
-func validatePrice(price string) (float64, error) {
+func validatePrice(price string) (Money, error) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Println("Terjadi kesalahan saat memvalidasi harga:", r)
@@ -107,93 +595,503 @@ func validatePrice(price string) (float64, error) {
 	if !matched {
 		return 0, fmt.Errorf("Format harga tidak valid") // Mengembalikan error jika format tidak valid
 	}
-	return strconv.ParseFloat(price, 64) // Mengonversi string ke float
+	return ParseMoney(price) // Mengonversi string ke sen tanpa melalui float
 }
 
 // Fungsi untuk encode pesanan ke base64
 func encodeOrder(order Order) string {
 	orderDetails := ""
-	for _, item := range order.MenuItems {
-		orderDetails += fmt.Sprintf("%s:%.2f,", item.Name, item.Price) // Menyusun detail pesanan
+	for _, line := range order.Lines {
+		orderDetails += fmt.Sprintf("%s:%.2f,", line.Label(), line.UnitPrice()) // Menyusun detail pesanan
 	}
 	encoded := base64.StdEncoding.EncodeToString([]byte(orderDetails)) // Mengonversi ke base64
 	return encoded
 }
 
-// Fungsi untuk menangani pembayaran
-func handlePayment(totalOrder float64) {
-	var priceInput string
-	var price float64
+// Struct untuk hasil pembayaran dari handlePayment
+type PaymentResult struct {
+	Method     PaymentMethod // Metode yang berhasil, atau terakhir dicoba jika gagal
+	AmountPaid Money         // Jumlah yang diterima dari pelanggan
+	Change     Money         // Kembalian tunai
+	Attempts   int           // Jumlah tagihan gateway dan input uang tunai yang dicoba
+	Err        error         // Alasan pembayaran tidak selesai, nil jika berhasil
+}
+
+// Fungsi untuk menangani pembayaran sebesar payment.Amount
+// Jika gateway tersedia, kasir memilih tunai, kartu, atau QRIS; kartu dan QRIS ditagih lewat gateway
+// Metode, referensi gateway, uang diterima, dan kembalian diisi ke payment hanya jika pembayaran berhasil
+// maxAttempts membatasi jumlah percobaan gabungan semua metode, 0 berarti mencoba terus sampai berhasil
+// currencies berisi mata uang asing yang diterima untuk pembayaran tunai, kembaliannya tetap dalam rupiah
+// Pemanggil memutuskan apa yang dilakukan jika result.Err tidak nil, mis. menyimpan pesanan sebagai draf
+func handlePayment(payment *Payment, gateway PaymentGateway, wait time.Duration, denominations []int, maxAttempts int, currencies []CurrencyConfig) PaymentResult {
+	// Tagihan yang sudah lunas dengan deposit atau voucher hadiah tidak perlu dibayar lagi
+	if payment.Due() <= 0 {
+		fmt.Println(tr("Tagihan sudah lunas, tidak ada yang perlu dibayar."))
+		payment.Tendered, payment.Change = 0, 0
+		return PaymentResult{Method: cmp.Or(payment.Method, MethodCash)}
+	}
+	var result PaymentResult
+	for gateway != nil {
+		choice, err := readLineErr(tr("Metode pembayaran: 1. Tunai, 2. Kartu, 3. QRIS (Enter untuk tunai):"))
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		method := map[string]PaymentMethod{"": MethodCash, "1": MethodCash, "2": MethodCard, "3": MethodQRIS}[choice]
+		if method == "" {
+			fmt.Println(tr("Pilihan tidak valid. Coba lagi."))
+			continue
+		}
+		if method == MethodCash {
+			break
+		}
+		result.Method = method
+		result.Attempts++
+		ref, err := chargeGateway(gateway, ChargeRequest{PaymentID: payment.ID, Method: method, Amount: payment.Due()}, wait)
+		if err != nil {
+			fmt.Print(tr("Pembayaran %s gagal: %v\n", method, err))
+			if maxAttempts > 0 && result.Attempts >= maxAttempts {
+				result.Err = fmt.Errorf("%w (%d kali): %v", errPaymentAttempts, result.Attempts, err)
+				return result
+			}
+			continue
+		}
+		fmt.Print(tr("Pembayaran %s berhasil, referensi: %s\n", method, ref))
+		payment.Method, payment.ProviderRef, payment.Tendered, payment.Change = method, ref, payment.Due(), 0
+		result.AmountPaid = payment.Tendered
+		return result
+	}
+	// Tip ikut dibayar bersama tagihan, sehingga kembalian dihitung dari tagihan ditambah tip
+	result.Method = MethodCash
+	remaining := 0
+	if maxAttempts > 0 {
+		remaining = maxAttempts - result.Attempts
+	}
+	tender, attempts, err := handleCashPayment(payment.Due(), denominations, remaining, currencies)
+	result.Attempts += attempts
+	if err != nil {
+		if errors.Is(err, errPaymentAttempts) {
+			err = fmt.Errorf("%w (%d kali)", errPaymentAttempts, result.Attempts)
+		}
+		result.Err = err
+		return result
+	}
+	payment.Tendered = tender.Amount
+	payment.Currency, payment.ForeignTendered, payment.FXRate = tender.Currency, tender.Foreign, tender.Rate
+	payment.Change = payment.Tendered - payment.Due()
+	result.AmountPaid, result.Change = payment.Tendered, payment.Change
+	return result
+}
+
+// Fungsi untuk menangani pembayaran tunai
+// Mengembalikan uang yang diterima dari pelanggan dan jumlah input yang dicoba
+// Uang asing dari currencies dikonversi ke rupiah dengan kursnya, kembalian selalu dalam rupiah
+// maxAttempts 0 berarti meminta ulang sampai jumlahnya cukup; input yang habis dikembalikan sebagai error
+func handleCashPayment(totalOrder Money, denominations []int, maxAttempts int, currencies []CurrencyConfig) (CashTender, int, error) {
+	printForeignQuotes(os.Stdout, totalOrder, currencies)
+	attempts := 0
 	for {
-		fmt.Println("Masukkan jumlah yang dibayar:")
-		fmt.Scanln(&priceInput)
+		priceInput, err := readLineErr(tr("Masukkan jumlah yang dibayar:"))
+		if err != nil {
+			return CashTender{}, attempts, err
+		}
+		attempts++
 
 		// Validasi input pembayaran
-		if validPrice, err := validatePrice(priceInput); err == nil {
-			price = validPrice
-
-			if price >= totalOrder {
-				fmt.Printf("Jumlah yang dibayar valid. Kembalian: Rp%.2f\n", price-totalOrder)
-				break
-			} else {
-				fmt.Println("Jumlah yang dibayar kurang dari total pesanan. Coba lagi.")
-			}
+		if tender, err := parseTender(priceInput, currencies); err != nil {
+			fmt.Println(tr("Input pembayaran tidak valid. Harap masukkan angka yang benar."))
+		} else if tender.Amount < totalOrder {
+			fmt.Println(tr("Jumlah yang dibayar kurang dari total pesanan. Coba lagi."))
 		} else {
-			fmt.Println("Input pembayaran tidak valid. Harap masukkan angka yang benar.")
+			if tender.Currency != "" {
+				fmt.Print(tr("Diterima %s %.2f x kurs Rp%.2f = Rp%.2f\n", tender.Currency, tender.Foreign, tender.Rate, tender.Amount))
+			}
+			fmt.Print(tr("Jumlah yang dibayar valid. Kembalian: Rp%.2f\n", tender.Amount-totalOrder))
+			printChangeBreakdown(tender.Amount-totalOrder, denominations)
+			return tender, attempts, nil
+		}
+		if maxAttempts > 0 && attempts >= maxAttempts {
+			return CashTender{}, attempts, errPaymentAttempts
 		}
 	}
 }
 
-func main() {
-	restaurant := &Restaurant{}
+// Fungsi untuk mengisi menu awal restoran
+func seedMenu(restaurant *Restaurant) {
 	// Tambah menu menggunakan pointer dan method
-	restaurant.AddMenuItem("Nasi Goreng", 25000)
-	restaurant.AddMenuItem("Mie Goreng", 22000)
-	restaurant.AddMenuItem("Ayam Bakar", 30000)
-	// Menampilkan menu
-	restaurant.PrintMenu()
+	restaurant.AddMenuItem("Nasi Goreng", 25000*Rp)
+	restaurant.AddMenuItem("Mie Goreng", 22000*Rp)
+	restaurant.AddMenuItem("Ayam Bakar", 30000*Rp)
+	for _, item := range restaurant.Menu {
+		restaurant.SetCategory(item.Name, "Makanan")
+	}
+	restaurant.AddMenuItem("Es Teh", 5000*Rp)
+	restaurant.SetCategory("Es Teh", "Minuman")
+	restaurant.SetSubstitutes("Nasi Goreng", "Mie Goreng")
+	restaurant.SetSubstitutes("Mie Goreng", "Nasi Goreng")
 
-	// Channel untuk pesanan
-	orderChannel := make(chan Order)
+	// Varian dan tambahan berbayar
+	spice := VariantGroup{Name: "Level Pedas", Options: []VariantOption{{Name: "Tidak Pedas"}, {Name: "Sedang"}, {Name: "Pedas"}}}
+	restaurant.SetVariants("Nasi Goreng", spice)
+	restaurant.SetVariants("Mie Goreng", spice)
+	restaurant.SetAddOns("Nasi Goreng", AddOn{Name: "Extra Telur", Price: 5000 * Rp})
+	restaurant.SetAddOns("Mie Goreng", AddOn{Name: "Extra Telur", Price: 5000 * Rp})
+	restaurant.SetVariants("Es Teh",
+		VariantGroup{Name: "Ukuran", Options: []VariantOption{{Name: "Kecil"}, {Name: "Besar", PriceDelta: 3000 * Rp}}},
+		VariantGroup{Name: "Es", Options: []VariantOption{{Name: "Normal"}, {Name: "Sedikit Es"}, {Name: "Tanpa Es"}}},
+	)
 
-	// Menggunakan goroutine untuk menerima pesanan
-	wg.Add(1)
-	go takeOrder(restaurant, orderChannel)
+	// Resep per porsi, stok bahan diatur dengan perintah "stock"
+	restaurant.SetRecipe("Nasi Goreng", RecipeIngredient{Ingredient: "Nasi", Qty: 200}, RecipeIngredient{Ingredient: "Telur", Qty: 1})
+	restaurant.SetRecipe("Mie Goreng", RecipeIngredient{Ingredient: "Mie", Qty: 150}, RecipeIngredient{Ingredient: "Telur", Qty: 1})
+	restaurant.SetRecipe("Ayam Bakar", RecipeIngredient{Ingredient: "Ayam", Qty: 1}, RecipeIngredient{Ingredient: "Nasi", Qty: 150})
+	restaurant.SetRecipe("Es Teh", RecipeIngredient{Ingredient: "Teh", Qty: 1})
+
+	// Keterangan menu, ditampilkan dengan -detail
+	restaurant.SetDetails("Nasi Goreng", "Nasi goreng kampung dengan telur dan kerupuk", "Telur", "Kedelai", "Udang")
+	restaurant.SetDetails("Mie Goreng", "Mie telur goreng dengan sayuran", "Gluten", "Telur", "Kedelai")
+	restaurant.SetDetails("Ayam Bakar", "Ayam bakar bumbu kecap dengan nasi putih", "Kedelai")
+	restaurant.SetDetails("Es Teh", "Teh melati manis dingin")
+
+	// Paket ditagih dengan harga paket, resepnya diambil dari isi paket
+	restaurant.AddCombo("Paket Hemat", 27000*Rp, ComboComponent{Item: "Nasi Goreng", Qty: 1}, ComboComponent{Item: "Es Teh", Qty: 1})
+
+	// Perkiraan lama masak untuk perkiraan waktu siap pesanan
+	restaurant.SetPrepTime("Nasi Goreng", 8)
+	restaurant.SetPrepTime("Mie Goreng", 8)
+	restaurant.SetPrepTime("Ayam Bakar", 15)
+	restaurant.SetPrepTime("Es Teh", 2)
+	restaurant.SetPrepTime("Paket Hemat", 8)
+}
+
+// Fungsi untuk menampilkan prompt dan membaca satu baris input
+func readLine(prompt string) string {
+	line, _ := readLineErr(prompt)
+	return line
+}
 
-	// Tunggu semua goroutine selesai sebelum menutup channel
+// Fungsi untuk membaca satu baris input, mengembalikan errInputClosed jika input sudah habis
+// Jika sesi kasir terkunci, PIN diminta terlebih dahulu lalu prompt ditampilkan ulang
+func readLineErr(prompt string) (string, error) {
+	return readLineWithin(prompt, 0)
+}
+
+// Fungsi untuk membaca satu baris input dengan batas waktu, mengembalikan errInputIdle jika tidak ada input
+// timeout 0 berarti menunggu tanpa batas seperti readLineErr
+func readLineWithin(prompt string, timeout time.Duration) (string, error) {
+	for {
+		if prompt != "" {
+			fmt.Println(prompt)
+		}
+		raw, err := scanInput(timeout)
+		if err != nil {
+			return "", err
+		}
+		line := strings.TrimSpace(raw)
+		unlocked, err := activeSession.check()
+		if err != nil {
+			return "", err
+		}
+		if !unlocked {
+			activeRecorder.Input(prompt, line)
+			return line, nil
+		}
+	}
+}
+
+// Fungsi untuk mengidentifikasi pelanggan member berdasarkan nomor HP
+// Pelanggan baru didaftarkan beserta persetujuan datanya, Enter untuk pelanggan umum
+func identifyCustomer(backend CashierBackend) string {
+	phone := readLine(tr("Nomor HP pelanggan member (Enter untuk lewati):"))
+	if phone == "" {
+		return ""
+	}
+	customer, err := backend.CustomerByPhone(phone)
+	if err == nil {
+		activeRecorder.State("customer.member", "")
+		fmt.Print(tr("Pelanggan: %s (%s)\n", customer.Name, customer.Tier))
+		return customer.ID
+	}
+	if !errors.Is(err, errCustomerNotFound) {
+		fmt.Println(tr("Gagal mencari pelanggan:"), err)
+		return ""
+	}
+
+	name := readLine(tr("Pelanggan baru. Masukkan nama:"))
+	sharing := strings.EqualFold(readLine(tr("Setuju data pembelian dibagikan ke program loyalitas? (y/n)")), "y")
+	marketing := strings.EqualFold(readLine(tr("Setuju menerima promosi? (y/n)")), "y")
+	customer, err = backend.AddCustomer(Customer{
+		Name:    name,
+		Phone:   phone,
+		Consent: Consent{DataSharing: sharing, Marketing: marketing},
+	})
+	if err != nil {
+		fmt.Println(tr("Gagal mendaftarkan pelanggan:"), err)
+		return ""
+	}
+	return customer.ID
+}
+
+// Fungsi untuk menawarkan pengiriman struk lewat email setelah pembayaran
+// Kegagalan hanya ditampilkan; pesanan sudah tersimpan dan email yang gagal dicoba lagi nanti
+func offerEmailReceipt(backend CashierBackend, paymentID string) {
+	for {
+		to := readLine(tr("Email untuk struk (Enter untuk lewati):"))
+		if to == "" {
+			return
+		}
+		err := backend.EmailReceipt(paymentID, to)
+		if err == nil {
+			fmt.Println(tr("Struk akan dikirim ke"), to)
+			return
+		}
+		fmt.Println(tr("Struk tidak dapat dikirim:"), err)
+		if errors.Is(err, errEmailDisabled) || errors.Is(err, errServerOffline) {
+			return
+		}
+	}
+}
+
+// Fungsi untuk menjalankan alur kasir interaktif
+// Saat menerima sinyal berhenti, keranjang yang belum dibayar disimpan sebagai draf dan dapur diselesaikan dulu
+// kitchen nil berarti pesanan diproses di tempat lain (server pusat)
+func runCashier(backend CashierBackend, cfg *Config, kitchen *kitchen) error {
+	opts := cfg.Cashier
+	// Kasir masuk dengan PIN jika sudah ada staf terdaftar
+	cashier, err := login(backend, "Masuk sebagai kasir.")
+	if err != nil {
+		return err
+	}
+	if cashier.Name != "" {
+		fmt.Println(tr("Kasir:"), cashier.Name)
+		if opts.LockAfterMinutes > 0 {
+			activeSession = startSession(backend, cashier, time.Duration(opts.LockAfterMinutes)*time.Minute)
+			defer func() { activeSession = nil }()
+		}
+	}
+
+	restaurant, err := backend.Menu()
+	if err != nil {
+		return err
+	}
+	activeRecorder.Start(cfg, restaurant.Menu)
+	printUpcomingReservations(backend)
+	// Menampilkan menu
+	restaurant.PrintMenu()
+	// Terminal menerima menu tanpa catatan dapur dari server, sehingga memakai daftar bawaan
+	if !opts.KitchenNotes {
+		restaurant.KitchenNotes = nil
+	} else if len(restaurant.KitchenNotes) == 0 {
+		restaurant.KitchenNotes = defaultKitchenNotes()
+	}
+	restaurant.Rules, restaurant.Limits = cfg.OrderRules, cfg.OrderLimits
+	restaurant.OrderTypes, restaurant.DeliveryFee = opts.OrderTypes, opts.DeliveryFee
+	restaurant.AskAllergies = opts.AskAllergies
+	restaurant.LearnAlias = backend.LearnAlias
+	restaurant.IdleTimeout = time.Duration(opts.IdleMinutes) * time.Minute
+	restaurant.IdleHold = opts.IdleAction == "hold"
+	restaurant.HoldOrder = func(order Order) error { return backend.SaveDrafts([]Order{order}) }
+	restaurant.HeldOrder = backend.ResumeHeldOrder
+	var broker *brokerQueue
+	if cfg.Broker.Enabled() {
+		if broker, err = startBroker(cfg.Broker); err != nil {
+			return err
+		}
+		defer drainBroker(broker)
+	}
+	events := newCashierEvents(cfg, backend, kitchen, broker)
+	restaurant.Events = events
+	restaurant.SkipConfirm = opts.SkipConfirm
+	restaurant.PriceGuards = cfg.PriceGuards
+	restaurant.ApproveManager = func() (Staff, error) {
+		admin, err := login(backend, tr("Masukkan PIN admin."))
+		if err == nil && admin.ID != "" && admin.Role != RoleAdmin {
+			err = errNotAdmin
+		}
+		return admin, err
+	}
+	pricing := cfg.Pricing.Strategy()
+	restaurant.Pricing = &pricing
+	if note := cfg.Pricing.MenuNote(); note != "" {
+		fmt.Println(note)
+	}
+
+	stop := stopSignal()
+	defer signal.Stop(stop)
 	go func() {
-		wg.Wait()
-		close(orderChannel) // Menutup channel setelah goroutine selesai
+		<-stop
+		fmt.Println(tr("\nMenghentikan kasir, pesanan baru tidak diterima..."))
+		saveOpenCart(backend)
+		kitchen.Drain()
+		drainBroker(broker)
+		fmt.Println(tr("Program selesai"))
+		os.Exit(0)
 	}()
 
-	var totalOrder float64
+	// Channel untuk pesanan, diisi oleh sesi kasir di keyboard dan sesi tambahan dari tablet pelayan
+	intake := newOrderIntake(opts.OrderBuffer)
+
+	// Menggunakan goroutine untuk menerima pesanan
+	// Mode TUI hanya dipakai jika input berasal dari terminal; perekam sesi hanya merekam mode teks
+	entry := takeOrder
+	if opts.TUI && isTerminal(os.Stdin) && activeRecorder == nil {
+		entry = takeOrderTUI
+	}
+	intake.Go(func(ch chan<- Order) error { return entry(restaurant, ch) })
+	if opts.IntakeAddr != "" {
+		srv := serveOrderIntake(opts.IntakeAddr, cfg.Server.APIKeys, restaurant, intake)
+		defer srv.Close()
+	}
+
+	var totalOrder Money
+	var orders []Order
 
 	// Mengambil pesanan dari channel
-	for order := range orderChannel {
-		fmt.Println("Pesanan Anda:")
-		for _, item := range order.MenuItems {
-			fmt.Printf("- %s\n", item.Name)
+	for order := range intake.Orders() {
+		pricing.Apply(&order)
+		fmt.Println(tr("Pesanan Anda:"))
+		for _, line := range order.Lines {
+			fmt.Printf("- %s x%d: Rp%.2f\n", line.Label(), line.Qty, line.Subtotal())
+			writePriceRuleNote(os.Stdout, line)
 		}
 		totalOrder += order.Total // Menghitung total keseluruhan pesanan
+		if len(order.Lines) > 0 {
+			orders = append(orders, order)
+			events.Publish(OrderEvent{Type: OrderEventCreated, Order: order})
+		}
+	}
+
+	if err := intake.Err(); err != nil {
+		return err
 	}
 
-	fmt.Printf("Total Pesanan: Rp%.2f\n", totalOrder)
+	totalOrder, rounding := pricing.RoundPayment(totalOrder)
+	activeRecorder.State("total", "Rp%.2f (pembulatan Rp%.2f)", totalOrder, rounding)
+	writePriceBreakdown(os.Stdout, orders, rounding)
+	fmt.Print(tr("Total Pesanan: Rp%.2f\n", totalOrder))
 
 	// Encode pesanan menggunakan base64
-	encodedOrder := encodeOrder(Order{MenuItems: restaurant.Menu})
-	fmt.Println("Pesanan (encoded base64):", encodedOrder)
+	for _, order := range orders {
+		fmt.Println(tr("Pesanan (encoded base64):"), encodeSignedOrder(order, cfg.Security.OrderSigningKey))
+	}
+
+	// Jenis pesanan sudah menentukan meja di awal input, sehingga meja tidak ditanyakan lagi
+	var table string
+	if opts.OrderTypes && len(orders) > 0 {
+		table = orders[0].Table
+	} else if opts.AskTable {
+		table = readLine(tr("Nomor meja (Enter untuk bawa pulang):"))
+	}
+	customerID := identifyCustomer(backend)
+	for i := range orders {
+		orders[i].CustomerID = customerID
+		// Pesanan dari tablet pelayan boleh sudah membawa nomor mejanya sendiri
+		if orders[i].Table == "" {
+			orders[i].Table = table
+		}
+	}
+
+	// Pesanan makan di tempat menjadi ronde baru di tab mejanya dan baru dibayar saat meja meminta tagihan
+	if opts.TableTabs && table != "" {
+		tab, billed, err := takeTableTab(backend, pricing, table, orders)
+		switch {
+		case errors.Is(err, errNoTableTab):
+			// Meja belum memiliki tab dan tidak ada pesanan baru
+		case err != nil:
+			saveOpenCart(backend)
+			kitchen.Drain()
+			return err
+		case !billed:
+			openCart.Clear()
+			kitchen.Drain()
+			fmt.Println(tr("Program selesai"))
+			return nil
+		default:
+			orders = []Order{tab}
+			totalOrder, rounding = pricing.RoundPayment(tab.Total)
+		}
+	}
+
+	// Pesanan terbuka dibayar nanti bersama pesanan lain pelanggan yang sama dengan "order pay"
+	if opts.OpenOrders && len(orders) > 0 && strings.EqualFold(readLine(tr("Bayar sekarang? (y/n, n untuk simpan sebagai pesanan terbuka)")), "n") {
+		if err := backend.SaveDrafts(orders); err != nil {
+			return err
+		}
+		openCart.Clear()
+		for _, order := range orders {
+			fmt.Println(tr("Pesanan terbuka disimpan:"), order.ID)
+		}
+		fmt.Println(tr("Program selesai"))
+		return nil
+	}
 
 	// Menangani pembayaran
-	handlePayment(totalOrder)
+	payment := Payment{
+		ID:     newID("PAY"),
+		Amount: totalOrder,
 
-	// Contoh penggunaan sync.WaitGroup untuk menunggu goroutine selesai
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		fmt.Println("Memproses pesanan di goroutine lain...")
-		time.Sleep(2 * time.Second) // Simulasi pemrosesan
-	}()
-	wg.Wait()
+		CashierID: cashier.ID,
+		Rounding:  rounding,
+	}
+	applyReservationDeposit(backend, pricing, &payment, table)
+	if cfg.Cashier.GiftVouchers {
+		applyGiftVoucher(backend, pricing, &payment)
+	}
+	if err := verifyPaymentTotals(cfg, pricing, orders, payment); err != nil {
+		saveOpenCart(backend)
+		kitchen.Drain()
+		return err
+	}
+	if opts.Tips {
+		promptTip(&payment)
+	}
+	gateway, err := newPaymentGateway(cfg.PaymentGateway)
+	if err != nil {
+		fmt.Println(tr("Pembayaran tidak selesai:"), err)
+		saveOpenCart(backend)
+		kitchen.Drain()
+		return err
+	}
+	result := handlePayment(&payment, gateway, time.Duration(cfg.PaymentGateway.WaitSeconds)*time.Second, backend.DrawerDenominations(), cfg.Cashier.PaymentAttempts, cfg.Currencies)
+	if result.Err != nil {
+		// Pesanan yang gagal dibayar disimpan sebagai draf agar bisa dibayar ulang
+		fmt.Println(tr("Pembayaran tidak selesai:"), result.Err)
+		saveOpenCart(backend)
+		kitchen.Drain()
+		return result.Err
+	}
+	payment.PaidAt = time.Now()
+	activeRecorder.State("payment", "%s Rp%.2f, dibayar Rp%.2f, kembali Rp%.2f", cmp.Or(payment.Method, MethodCash), payment.Amount, payment.Tendered, payment.Change)
+
+	// Simpan pesanan beserta pembayarannya
+	for _, order := range orders {
+		payment.OrderIDs = append(payment.OrderIDs, order.ID)
+	}
+	if len(orders) > 0 {
+		attachWifiVoucher(cfg.Wifi, backend, &payment)
+		for {
+			err := backend.Checkout(orders, payment)
+			if err == nil {
+				break
+			}
+			fmt.Println(tr("Gagal menyimpan pesanan:"), err)
+			if !strings.EqualFold(readLine(tr("Coba lagi? (y/n)")), "y") {
+				saveOpenCart(backend)
+				kitchen.Drain()
+				return fmt.Errorf("Pesanan %s belum tersimpan", strings.Join(payment.OrderIDs, ", "))
+			}
+		}
+		openCart.Clear()
+		for _, order := range orders {
+			fmt.Println(tr("ID pesanan:"), order.ID)
+		}
+		// Struk, email, dan dapur adalah subscriber order.paid, lihat newCashierEvents
+		events.Publish(OrderEvent{Type: OrderEventPaid, Payment: payment, Orders: orders})
+	}
+
+	// Kasir menunggu sampai dapur menyelesaikan semua pesanan
+	kitchen.Drain()
 
-	fmt.Println("Program selesai")
+	fmt.Println(tr("Program selesai"))
+	return nil
 }
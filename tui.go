@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Tombol yang dikenali oleh mode TUI
+type tuiKey int
+
+const (
+	keyNone tuiKey = iota
+	keyUp
+	keyDown
+	keyPlus
+	keyMinus
+	keyVariant
+	keyEnter
+	keyCancel
+)
+
+// Fungsi untuk memeriksa apakah input berasal dari terminal (TTY)
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Struct untuk mode raw terminal
+// Menyimpan pengaturan stty awal agar bisa dikembalikan setelah TUI selesai
+type rawTerminal struct {
+	saved string
+}
+
+// Fungsi untuk mengaktifkan mode raw pada terminal menggunakan stty
+func enableRawMode() (*rawTerminal, error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return &rawTerminal{saved: strings.TrimSpace(saved)}, nil
+}
+
+// Mengembalikan pengaturan terminal seperti semula
+func (t *rawTerminal) Restore() {
+	stty(t.saved)
+}
+
+// Fungsi untuk menjalankan perintah stty pada stdin
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// Fungsi untuk membaca satu tombol dari terminal dalam mode raw
+func readKey() tuiKey {
+	buf := make([]byte, 8)
+	n, err := os.Stdin.Read(buf)
+	if err != nil || n == 0 {
+		return keyCancel
+	}
+	switch {
+	case n >= 3 && buf[0] == 0x1b && buf[1] == '[':
+		switch buf[2] {
+		case 'A':
+			return keyUp
+		case 'B':
+			return keyDown
+		case 'C':
+			return keyPlus
+		case 'D':
+			return keyMinus
+		}
+	case buf[0] == '+' || buf[0] == '=':
+		return keyPlus
+	case buf[0] == '-':
+		return keyMinus
+	case buf[0] == 'v' || buf[0] == 'V':
+		return keyVariant
+	case buf[0] == '\r' || buf[0] == '\n':
+		return keyEnter
+	case buf[0] == 3 || buf[0] == 'q' || (n == 1 && buf[0] == 0x1b): // Ctrl-C, q, atau Esc
+		return keyCancel
+	}
+	return keyNone
+}
+
+// Fungsi untuk mendapatkan modifier default item (opsi pertama setiap grup varian)
+func defaultModifiers(item MenuItem) []Modifier {
+	var modifiers []Modifier
+	for _, group := range item.Variants {
+		if len(group.Options) > 0 {
+			opt := group.Options[0]
+			modifiers = append(modifiers, Modifier{Group: group.Name, Name: opt.Name, Price: opt.PriceDelta})
+		}
+	}
+	return modifiers
+}
+
+// Struct untuk state TUI pemesanan
+type orderTUI struct {
+	restaurant *Restaurant
+	order      Order
+	cursor     int    // Indeks item menu yang sedang dipilih
+	status     string // Pesan status di bagian bawah layar
+}
+
+// Fungsi untuk menerima pesanan dengan navigasi tombol panah
+// Jika terminal tidak mendukung mode raw, alur berbasis scanner yang digunakan
+func takeOrderTUI(restaurant *Restaurant, ch chan<- Order) error {
+	term, err := enableRawMode()
+	if err != nil || len(restaurant.Menu) == 0 {
+		fmt.Println("Mode TUI tidak tersedia, menggunakan mode teks.")
+		return takeOrder(restaurant, ch)
+	}
+
+	ui := &orderTUI{
+		restaurant: restaurant,
+		order:      Order{ID: orderIDs.NewID("ORD"), Status: OrderPending, CreatedAt: time.Now()},
+	}
+	if restaurant.OrderTypes {
+		// Jenis pesanan ditanyakan dalam mode teks sebelum navigasi menu dimulai
+		term.Restore()
+		if err := promptOrderType(restaurant, &ui.order); err != nil {
+			return err
+		}
+		if term, err = enableRawMode(); err != nil {
+			return err
+		}
+	}
+	for {
+		ui.render()
+		key := readKey()
+		if activeSession != nil && activeSession.activity() {
+			// Terminal terkunci: PIN diminta dalam mode teks, tombol yang ditekan dibuang
+			term.Restore()
+			fmt.Print("\x1b[H\x1b[2J")
+			if err := activeSession.unlock(); err != nil {
+				return err
+			}
+			if term, err = enableRawMode(); err != nil {
+				ui.finish(ch)
+				return nil
+			}
+			continue
+		}
+		switch key {
+		case keyUp:
+			ui.cursor = (ui.cursor - 1 + len(restaurant.Menu)) % len(restaurant.Menu)
+		case keyDown:
+			ui.cursor = (ui.cursor + 1) % len(restaurant.Menu)
+		case keyPlus:
+			ui.adjust(1)
+		case keyMinus:
+			ui.adjust(-1)
+		case keyVariant:
+			// Pilihan varian memakai prompt teks biasa, lalu kembali ke mode raw
+			term.Restore()
+			ui.addCustomLine()
+			if term, err = enableRawMode(); err != nil {
+				ui.finish(ch)
+				return nil
+			}
+		case keyEnter:
+			term.Restore()
+			ui.finish(ch)
+			return nil
+		case keyCancel:
+			term.Restore()
+			ui.order.Lines = nil
+			ui.order.Total = 0
+			fmt.Print("\r\nPesanan dibatalkan.\r\n")
+			ui.finish(ch)
+			return nil
+		}
+	}
+}
+
+// Mengubah jumlah item yang sedang dipilih dengan varian default
+// Item yang habis tidak bisa ditambah; kursor dipindahkan ke item pengganti
+func (ui *orderTUI) adjust(delta int) {
+	item := ui.restaurant.Menu[ui.cursor]
+	defaults := defaultModifiers(item)
+	index := slices.IndexFunc(ui.order.Lines, func(l OrderLine) bool {
+		return l.Item.Name == item.Name && slices.Equal(l.Modifiers, defaults)
+	})
+
+	if delta > 0 && item.SoldOut {
+		ui.status = item.Name + " sedang habis."
+		if suggestions := suggestSubstitutes(ui.restaurant, item); len(suggestions) > 0 {
+			ui.cursor = slices.IndexFunc(ui.restaurant.Menu, func(m MenuItem) bool { return m.Name == suggestions[0].Name })
+			ui.status += " Pengganti: " + suggestions[0].Name + " (tekan + untuk menambah)"
+		}
+		return
+	}
+	ui.status = ""
+	if delta > 0 {
+		if err := ui.restaurant.Limits.CheckLine(ui.order, OrderLine{Item: item, Qty: delta, Modifiers: defaults}); err != nil {
+			ui.status = err.Error()
+			return
+		}
+	}
+	switch {
+	case index < 0 && delta > 0:
+		ui.order.Lines = append(ui.order.Lines, OrderLine{Item: item, Qty: delta, Modifiers: defaults})
+	case index >= 0:
+		ui.order.Lines[index].Qty += delta
+		if ui.order.Lines[index].Qty <= 0 {
+			ui.order.Lines = slices.Delete(ui.order.Lines, index, index+1)
+		}
+	}
+	ui.recalculate()
+	// Penambahan jumlah di baris yang sudah ada diterbitkan sebagai baris baru sebanyak tambahannya
+	if delta > 0 {
+		ui.restaurant.Events.Publish(OrderEvent{Type: OrderEventItemAdded, Order: ui.order, Line: OrderLine{Item: item, Qty: delta, Modifiers: defaults}})
+	}
+}
+
+// Menambahkan baris dengan varian dan tambahan pilihan kasir
+func (ui *orderTUI) addCustomLine() {
+	item := ui.restaurant.Menu[ui.cursor]
+	fmt.Print("\x1b[H\x1b[2J")
+	if item.SoldOut {
+		ui.status = item.Name + " sedang habis."
+		return
+	}
+	modifiers := promptModifiers(item)
+	line := OrderLine{Item: item, Qty: 1, Modifiers: modifiers}
+	if err := ui.restaurant.Limits.CheckLine(ui.order, line); err != nil {
+		ui.status = err.Error()
+		return
+	}
+	ui.order.Lines = append(ui.order.Lines, line)
+	ui.status = "Ditambahkan: " + ui.order.Lines[len(ui.order.Lines)-1].Label()
+	ui.recalculate()
+	ui.restaurant.Events.Publish(OrderEvent{Type: OrderEventItemAdded, Order: ui.order, Line: line})
+}
+
+// Menghitung ulang total pesanan
+// Waktu item pertama dicatat saat baris pertama masuk keranjang
+func (ui *orderTUI) recalculate() {
+	if ui.order.FirstItemAt.IsZero() && len(ui.order.Lines) > 0 {
+		ui.order.FirstItemAt = time.Now()
+	}
+	ui.order.Total = 0
+	for _, line := range ui.order.Lines {
+		ui.order.Total += line.Subtotal()
+	}
+	openCart.Update(ui.order)
+}
+
+// Mengirim pesanan ke channel setelah TUI selesai
+func (ui *orderTUI) finish(ch chan<- Order) {
+	fmt.Print("\x1b[H\x1b[2J")
+	ch <- ui.order
+}
+
+// Menggambar ulang layar TUI
+// Mode raw tidak mengubah "\n" menjadi baris baru, sehingga setiap baris diakhiri "\r\n"
+func (ui *orderTUI) render() {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	b.WriteString("Menu (↑/↓ pilih, +/- jumlah, v varian, Enter selesai, q batal)\r\n\r\n")
+	for i, item := range ui.restaurant.Menu {
+		cursor := "  "
+		if i == ui.cursor {
+			cursor = "> "
+		}
+		qty := 0
+		for _, line := range ui.order.Lines {
+			if line.Item.Name == item.Name {
+				qty += line.Qty
+			}
+		}
+		soldOut := ""
+		if item.SoldOut {
+			soldOut = " (HABIS)"
+		}
+		fmt.Fprintf(&b, "%s%-20s Rp%10.2f  x%-3d%s\r\n", cursor, item.Name, item.Price, qty, soldOut)
+	}
+	b.WriteString("\r\nPesanan:\r\n")
+	for _, line := range ui.order.Lines {
+		fmt.Fprintf(&b, "- %s x%d: Rp%.2f\r\n", line.Label(), line.Qty, line.Subtotal())
+	}
+	fmt.Fprintf(&b, "\r\nTotal: Rp%.2f\r\n", ui.order.Total)
+	if ui.status != "" {
+		b.WriteString("\r\n" + ui.status + "\r\n")
+	}
+	fmt.Print(b.String())
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sync"
+	"time"
+)
+
+var errWatchdogTimeout = errors.New("Tidak merespons, dihentikan oleh watchdog")
+
+// Struct untuk Konfigurasi watchdog
+// Watchdog memeriksa pekerjaan yang macet (dapur, printer, gateway pembayaran) agar kasir tidak membeku tanpa pesan
+type WatchdogConfig struct {
+	Disabled       bool   `json:"disabled"`        // Matikan watchdog
+	CheckSeconds   int    `json:"check_seconds"`   // Interval pemeriksaan pekerjaan yang berjalan
+	KitchenSeconds int    `json:"kitchen_seconds"` // Batas satu pesanan di dapur sebelum dianggap macet, default 10x kitchen.prep_seconds
+	PrintSeconds   int    `json:"print_seconds"`   // Batas mencetak satu struk
+	PaymentSeconds int    `json:"payment_seconds"` // Batas satu request ke gateway, default 2x payment_gateway.timeout_seconds
+	DumpDir        string `json:"dump_dir"`        // Folder file dump goroutine, default folder kerja
+	AlertWebhook   string `json:"alert_webhook"`   // URL yang menerima peringatan pekerjaan macet, boleh kosong
+}
+
+// Jenis pekerjaan yang diawasi watchdog
+type watchKind string
+
+const (
+	watchKitchen watchKind = "dapur"
+	watchPrint   watchKind = "printer"
+	watchPayment watchKind = "gateway pembayaran"
+)
+
+// Struct untuk satu pekerjaan yang sedang diawasi
+type watchOp struct {
+	w        *watchdog
+	id       int
+	kind     watchKind
+	label    string
+	started  time.Time
+	reported bool // Peringatan hanya dikirim sekali per pekerjaan
+}
+
+// Struct untuk Watchdog
+type watchdog struct {
+	cfg WatchdogConfig
+
+	mu   sync.Mutex
+	ops  map[int]*watchOp
+	next int
+}
+
+// Watchdog yang sedang berjalan, nil jika dimatikan
+var activeWatchdog *watchdog
+
+// Fungsi untuk menjalankan watchdog di goroutine sendiri
+func startWatchdog(cfg WatchdogConfig) *watchdog {
+	if cfg.Disabled {
+		return nil
+	}
+	w := &watchdog{cfg: cfg, ops: map[int]*watchOp{}}
+	go w.monitor()
+	return w
+}
+
+// Batas waktu untuk jenis pekerjaan
+func (w *watchdog) limit(kind watchKind) time.Duration {
+	seconds := map[watchKind]int{
+		watchKitchen: w.cfg.KitchenSeconds,
+		watchPrint:   w.cfg.PrintSeconds,
+		watchPayment: w.cfg.PaymentSeconds,
+	}[kind]
+	return time.Duration(seconds) * time.Second
+}
+
+// Mulai mengawasi satu pekerjaan; End harus dipanggil setelah pekerjaan selesai
+// Watchdog nil mengembalikan op nil yang aman dipakai
+func (w *watchdog) Begin(kind watchKind, label string) *watchOp {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.next++
+	op := &watchOp{w: w, id: w.next, kind: kind, label: label, started: time.Now()}
+	w.ops[op.id] = op
+	return op
+}
+
+// Menandai pekerjaan selesai
+func (op *watchOp) End() {
+	if op == nil {
+		return
+	}
+	op.w.mu.Lock()
+	defer op.w.mu.Unlock()
+	delete(op.w.ops, op.id)
+}
+
+// Channel yang terisi saat pekerjaan melewati batas waktunya; op nil tidak pernah kedaluwarsa
+func (op *watchOp) Expired() <-chan time.Time {
+	if op == nil {
+		return nil
+	}
+	return time.After(time.Until(op.started.Add(op.w.limit(op.kind))))
+}
+
+// Melaporkan pekerjaan sebagai macet tanpa menunggu pemeriksaan berikutnya
+func (op *watchOp) Report() {
+	if op != nil {
+		op.w.report(op)
+	}
+}
+
+// Menjalankan fn dan menunggu paling lama batas waktu jenisnya
+// Jika fn macet, kasir dilepas dengan errWatchdogTimeout sementara fn tetap berjalan di goroutine sendiri dan dilaporkan
+func (w *watchdog) Guard(kind watchKind, label string, fn func() error) error {
+	if w == nil {
+		return fn()
+	}
+	op := w.Begin(kind, label)
+	done := make(chan error, 1)
+	go func() {
+		defer op.End()
+		done <- fn()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-op.Expired():
+		op.Report()
+		return fmt.Errorf("%s %s: %w", kind, label, errWatchdogTimeout)
+	}
+}
+
+// Memeriksa pekerjaan yang melewati batas waktu secara berkala
+func (w *watchdog) monitor() {
+	ticker := time.NewTicker(time.Duration(w.cfg.CheckSeconds) * time.Second)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		w.mu.Lock()
+		var stalled []*watchOp
+		for _, op := range w.ops {
+			if !op.reported && now.Sub(op.started) > w.limit(op.kind) {
+				stalled = append(stalled, op)
+			}
+		}
+		w.mu.Unlock()
+		for _, op := range stalled {
+			w.report(op)
+		}
+	}
+}
+
+// Fungsi untuk melaporkan pekerjaan macet: pesan di log, dump goroutine, dan webhook
+func (w *watchdog) report(op *watchOp) {
+	w.mu.Lock()
+	if op.reported {
+		w.mu.Unlock()
+		return
+	}
+	op.reported = true
+	w.mu.Unlock()
+
+	elapsed := time.Since(op.started).Round(time.Second)
+	message := fmt.Sprintf("%s macet: %s belum selesai setelah %s", op.kind, op.label, elapsed)
+	fmt.Println(tr("PERINGATAN:"), message)
+	dump, err := w.dumpGoroutines(op.started)
+	if err != nil {
+		fmt.Println("Dump goroutine gagal disimpan:", err)
+	} else {
+		fmt.Println("Dump goroutine disimpan di", dump)
+	}
+	if w.cfg.AlertWebhook == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]any{
+		"event": "watchdog.stalled", "kind": op.kind, "label": op.label,
+		"seconds": int(elapsed.Seconds()), "message": message, "dump": dump,
+	})
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(w.cfg.AlertWebhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Println("Gagal mengirim peringatan ke webhook:", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// Fungsi untuk menyimpan stack semua goroutine ke file agar penyebab macet bisa diperiksa
+func (w *watchdog) dumpGoroutines(at time.Time) (string, error) {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		return "", err
+	}
+	path := filepath.Join(w.cfg.DumpDir, "watchdog-"+at.Format("20060102-150405.000")+".txt")
+	return path, os.WriteFile(path, buf.Bytes(), 0o644)
+}
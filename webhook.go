@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Jenis kejadian yang dikirim ke webhook
+const (
+	WebhookOrderCreated   = "order.created"
+	WebhookOrderPaid      = "order.paid"
+	WebhookOrderCancelled = "order.cancelled"
+)
+
+var errWebhookNotFound = errors.New("Webhook tidak ditemukan di antrean")
+
+// Struct untuk Konfigurasi webhook
+// Webhook hanya dikirim dalam mode server
+type WebhookConfig struct {
+	Endpoints      []WebhookEndpoint `json:"endpoints"`       // Alamat tujuan webhook
+	MaxAttempts    int               `json:"max_attempts"`    // Batas percobaan sebelum webhook dianggap gagal permanen
+	BackoffSeconds int               `json:"backoff_seconds"` // Jeda percobaan ulang pertama, berlipat dua setiap percobaan
+	TimeoutSeconds int               `json:"timeout_seconds"` // Batas waktu satu request
+}
+
+// Struct untuk satu alamat tujuan webhook
+type WebhookEndpoint struct {
+	URL    string   `json:"url"`              // Alamat yang menerima POST JSON
+	Events []string `json:"events,omitempty"` // Kejadian yang dikirim, kosong berarti semua
+	Secret string   `json:"secret,omitempty"` // Kunci HMAC-SHA256 untuk header X-Webhook-Signature
+}
+
+// Struct untuk Webhook di antrean pengiriman
+// Disimpan bersama data agar webhook yang belum terkirim tetap dicoba lagi setelah server dinyalakan ulang
+type WebhookDelivery struct {
+	ID            string          `json:"id"`                    // ID unik webhook, dikirim juga di payload untuk deduplikasi
+	URL           string          `json:"url"`                   // Alamat tujuan
+	Secret        string          `json:"secret,omitempty"`      // Kunci HMAC endpoint saat webhook dibuat
+	Event         string          `json:"event"`                 // Jenis kejadian, mis. "order.paid"
+	Payload       json.RawMessage `json:"payload"`               // Body yang dikirim
+	Attempts      int             `json:"attempts"`              // Jumlah percobaan pengiriman
+	LastError     string          `json:"last_error,omitempty"`  // Error percobaan terakhir
+	CreatedAt     time.Time       `json:"created_at"`            // Waktu kejadian
+	NextAttemptAt time.Time       `json:"next_attempt_at"`       // Waktu percobaan berikutnya
+	DeliveredAt   time.Time       `json:"delivered_at,omitzero"` // Waktu webhook diterima dengan status 2xx
+}
+
+// Struct untuk body webhook
+type webhookPayload struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// Data webhook order.paid
+type webhookPaid struct {
+	Payment Payment `json:"payment"`
+	Orders  []Order `json:"orders"`
+}
+
+// Menandakan webhook sudah melewati batas percobaan
+func (d WebhookDelivery) GaveUp(cfg WebhookConfig) bool {
+	return d.DeliveredAt.IsZero() && d.Attempts >= cfg.MaxAttempts
+}
+
+// Mengaktifkan webhook untuk kejadian yang dicatat penyimpanan, dipanggil saat server dijalankan
+// Antrean webhook adalah subscriber kejadian pesanan penyimpanan
+func (s *Store) EnableWebhooks(endpoints []WebhookEndpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.webhooks == nil {
+		s.events.Subscribe("webhook", s.queueWebhooks, OrderEventCreated, OrderEventPaid, OrderEventCancelled)
+	}
+	s.webhooks = endpoints
+}
+
+// Memasukkan webhook untuk kejadian pesanan ke antrean; dipanggil saat s.mu dipegang
+func (s *Store) queueWebhooks(e OrderEvent) {
+	var event string
+	var body any
+	switch e.Type {
+	case OrderEventCreated:
+		event, body = WebhookOrderCreated, e.Order
+	case OrderEventPaid:
+		event, body = WebhookOrderPaid, webhookPaid{Payment: e.Payment, Orders: e.Orders}
+	case OrderEventCancelled:
+		event, body = WebhookOrderCancelled, e.Order
+	default:
+		return
+	}
+	now := time.Now()
+	for _, ep := range s.webhooks {
+		if len(ep.Events) > 0 && !slices.Contains(ep.Events, event) {
+			continue
+		}
+		id := newID("WHK")
+		raw, err := json.Marshal(webhookPayload{ID: id, Event: event, CreatedAt: now, Data: body})
+		if err != nil {
+			continue
+		}
+		s.data.Webhooks = append(s.data.Webhooks, WebhookDelivery{
+			ID: id, URL: ep.URL, Secret: ep.Secret, Event: event, Payload: raw, CreatedAt: now, NextAttemptAt: now,
+		})
+	}
+}
+
+// Mengambil webhook yang belum terkirim dan sudah waktunya dicoba lagi
+func (s *Store) DueWebhooks(cfg WebhookConfig, now time.Time) []WebhookDelivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []WebhookDelivery
+	for _, d := range s.data.Webhooks {
+		if d.DeliveredAt.IsZero() && !d.GaveUp(cfg) && !now.Before(d.NextAttemptAt) {
+			due = append(due, d)
+		}
+	}
+	return due
+}
+
+// Mengambil salinan antrean webhook
+func (s *Store) Webhooks() []WebhookDelivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]WebhookDelivery(nil), s.data.Webhooks...)
+}
+
+// Mencatat hasil percobaan pengiriman webhook
+// Percobaan yang gagal dijadwalkan ulang dengan jeda berlipat dua, mis. 5, 10, lalu 20 detik untuk backoff_seconds 5
+func (s *Store) RecordWebhookAttempt(id string, sendErr error, cfg WebhookConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.Webhooks {
+		d := &s.data.Webhooks[i]
+		if d.ID != id {
+			continue
+		}
+		d.Attempts++
+		if sendErr == nil {
+			d.DeliveredAt, d.LastError = time.Now(), ""
+		} else {
+			d.LastError = sendErr.Error()
+			backoff := time.Duration(cfg.BackoffSeconds) * time.Second << (d.Attempts - 1)
+			d.NextAttemptAt = time.Now().Add(backoff)
+		}
+		return s.save()
+	}
+	return errWebhookNotFound
+}
+
+// Menjadwalkan ulang webhook yang gagal permanen untuk segera dicoba lagi
+func (s *Store) RequeueWebhook(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.Webhooks {
+		d := &s.data.Webhooks[i]
+		if d.ID == id {
+			d.Attempts, d.NextAttemptAt = 0, time.Now()
+			return s.save()
+		}
+	}
+	return errWebhookNotFound
+}
+
+// Fungsi untuk mengirim satu webhook
+// Body ditandatangani HMAC-SHA256 jika endpoint memiliki secret; status selain 2xx dianggap gagal
+func sendWebhook(client *http.Client, d WebhookDelivery) error {
+	req, err := http.NewRequest(http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", d.Event)
+	req.Header.Set("X-Webhook-ID", d.ID)
+	if d.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(d.Secret))
+		mac.Write(d.Payload)
+		req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Fungsi untuk mengirim semua webhook yang sudah waktunya dikirim
+func deliverWebhooks(cfg WebhookConfig, store *Store, client *http.Client) (sent, failed int) {
+	for _, d := range store.DueWebhooks(cfg, time.Now()) {
+		err := sendWebhook(client, d)
+		if err := store.RecordWebhookAttempt(d.ID, err, cfg); err != nil {
+			fmt.Println("Gagal mencatat percobaan webhook:", err)
+		}
+		if err != nil {
+			failed++
+			continue
+		}
+		sent++
+	}
+	return sent, failed
+}
+
+// Fungsi untuk mengirim antrean webhook secara berkala selama server berjalan
+func runWebhookDelivery(cfg WebhookConfig, store *Store) {
+	client := &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, failed := deliverWebhooks(cfg, store, client); failed > 0 {
+			fmt.Printf("%d webhook gagal dikirim, akan dicoba lagi\n", failed)
+		}
+	}
+}
+
+// Fungsi untuk menjalankan sub-perintah "webhook"
+func runWebhookCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: webhook queue [-all]|retry <id>")
+	}
+	switch args[0] {
+	case "queue":
+		fs := flag.NewFlagSet("webhook queue", flag.ContinueOnError)
+		all := fs.Bool("all", false, "tampilkan juga webhook yang sudah terkirim")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		shown := 0
+		for _, d := range store.Webhooks() {
+			status := "menunggu"
+			switch {
+			case !d.DeliveredAt.IsZero():
+				if !*all {
+					continue
+				}
+				status = "terkirim " + d.DeliveredAt.Local().Format("2006-01-02 15:04")
+			case d.GaveUp(cfg.Webhooks):
+				status = "gagal permanen"
+			case d.Attempts > 0:
+				status = "dicoba lagi " + d.NextAttemptAt.Local().Format("15:04:05")
+			}
+			fmt.Printf("%s  %-16s %-32s %-24s percobaan %d  %s\n", d.ID, d.Event, d.URL, status, d.Attempts, d.LastError)
+			shown++
+		}
+		if shown == 0 {
+			fmt.Println("Antrean webhook kosong.")
+		}
+	case "retry":
+		if len(args) != 2 {
+			return fmt.Errorf("Gunakan: webhook retry <id>")
+		}
+		if err := store.RequeueWebhook(args[1]); err != nil {
+			return err
+		}
+		fmt.Println("Webhook", args[1], "akan dikirim ulang saat server berjalan")
+	default:
+		return fmt.Errorf("Sub-perintah webhook tidak dikenal: %s", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	errWifiDisabled  = errors.New("Voucher Wi-Fi belum diaktifkan di konfigurasi")
+	errWifiPoolEmpty = errors.New("Voucher Wi-Fi habis, impor voucher baru dengan \"wifi import\"")
+	errWifiNotFound  = errors.New("Kode voucher Wi-Fi tidak ditemukan atau belum dicetak di struk")
+)
+
+// Struct untuk Konfigurasi voucher Wi-Fi tamu
+// Voucher diambil dari pool hasil impor CSV, atau dibuat oleh API hotspot/RADIUS untuk setiap pembayaran
+type WifiConfig struct {
+	Source       string `json:"source"`        // pool (voucher hasil "wifi import") atau api, kosong berarti tidak aktif
+	APIURL       string `json:"api_url"`       // Endpoint pembuat voucher; menerima POST {"payment_id","minutes"} dan membalas {"code"}
+	APIToken     string `json:"api_token"`     // Token Bearer untuk api_url
+	Minutes      int    `json:"minutes"`       // Lama akses setiap voucher yang diminta ke API
+	LowPool      int    `json:"low_pool"`      // Peringatan dikirim saat sisa voucher pool mencapai jumlah ini
+	AlertWebhook string `json:"alert_webhook"` // URL yang menerima peringatan voucher hampir habis, boleh kosong
+}
+
+// Struct untuk satu voucher Wi-Fi
+type WifiVoucher struct {
+	Code       string    `json:"code"`                 // Kode login hotspot
+	Source     string    `json:"source"`               // pool atau api
+	AddedAt    time.Time `json:"added_at"`             // Waktu voucher diimpor atau dibuat
+	PaymentID  string    `json:"payment_id,omitempty"` // Pembayaran yang struknya memuat voucher ini
+	IssuedAt   time.Time `json:"issued_at,omitzero"`   // Waktu voucher dicetak di struk
+	RedeemedAt time.Time `json:"redeemed_at,omitzero"` // Waktu voucher dipakai login, dilaporkan portal hotspot
+}
+
+// Menambahkan voucher ke pool, kode yang sudah ada dilewati
+// Mengembalikan jumlah voucher yang benar-benar ditambahkan
+func (s *Store) AddWifiVouchers(codes []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	known := map[string]bool{}
+	for _, v := range s.data.WifiVouchers {
+		known[v.Code] = true
+	}
+	added := 0
+	for _, code := range codes {
+		if code == "" || known[code] {
+			continue
+		}
+		known[code] = true
+		s.data.WifiVouchers = append(s.data.WifiVouchers, WifiVoucher{Code: code, Source: "pool", AddedAt: time.Now()})
+		added++
+	}
+	return added, s.save()
+}
+
+// Memberikan voucher pool yang belum terpakai ke pembayaran
+// Pembayaran yang sudah mendapat voucher (mis. checkout diulang) menerima voucher yang sama; remaining adalah sisa pool
+func (s *Store) IssueWifiVoucher(paymentID string) (code string, remaining int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var free *WifiVoucher
+	for i := range s.data.WifiVouchers {
+		v := &s.data.WifiVouchers[i]
+		if v.PaymentID == paymentID {
+			return v.Code, s.freeWifiVouchers(), nil
+		}
+		if free == nil && v.PaymentID == "" && v.Source == "pool" {
+			free = v
+		}
+	}
+	if free == nil {
+		return "", 0, errWifiPoolEmpty
+	}
+	free.PaymentID, free.IssuedAt = paymentID, time.Now()
+	return free.Code, s.freeWifiVouchers(), s.save()
+}
+
+// Menghitung voucher pool yang belum diberikan; pemanggil harus memegang s.mu
+func (s *Store) freeWifiVouchers() int {
+	n := 0
+	for _, v := range s.data.WifiVouchers {
+		if v.PaymentID == "" && v.Source == "pool" {
+			n++
+		}
+	}
+	return n
+}
+
+// Mencatat voucher yang dibuat API hotspot agar pemakaiannya ikut terlacak
+func (s *Store) RecordWifiVoucher(code, paymentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.data.WifiVouchers = append(s.data.WifiVouchers, WifiVoucher{Code: code, Source: "api", AddedAt: now, PaymentID: paymentID, IssuedAt: now})
+	return s.save()
+}
+
+// Menandai voucher sudah dipakai login
+// Voucher pool yang belum dicetak di struk ditolak agar kode yang bocor terlihat di portal hotspot
+func (s *Store) RedeemWifiVoucher(code string) (WifiVoucher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.data.WifiVouchers {
+		v := &s.data.WifiVouchers[i]
+		if v.Code != code || v.PaymentID == "" {
+			continue
+		}
+		if v.RedeemedAt.IsZero() {
+			v.RedeemedAt = time.Now()
+			if err := s.save(); err != nil {
+				return WifiVoucher{}, err
+			}
+		}
+		return *v, nil
+	}
+	return WifiVoucher{}, errWifiNotFound
+}
+
+// Mengambil salinan seluruh voucher Wi-Fi
+func (s *Store) WifiVouchers() []WifiVoucher {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]WifiVoucher(nil), s.data.WifiVouchers...)
+}
+
+// Fungsi untuk meminta satu voucher ke API hotspot/RADIUS
+func requestWifiVoucher(cfg WifiConfig, paymentID string) (string, error) {
+	body, _ := json.Marshal(map[string]any{"payment_id": paymentID, "minutes": cfg.Minutes})
+	req, err := http.NewRequest(http.MethodPost, cfg.APIURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.APIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.APIToken)
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("API voucher Wi-Fi tidak dapat dihubungi: %w", err)
+	}
+	defer resp.Body.Close()
+	var out struct {
+		Code string `json:"code"`
+	}
+	if resp.StatusCode >= 300 || json.NewDecoder(resp.Body).Decode(&out) != nil || out.Code == "" {
+		return "", fmt.Errorf("API voucher Wi-Fi tidak memberikan kode (%s)", resp.Status)
+	}
+	return out.Code, nil
+}
+
+// Fungsi untuk mengambil kode Wi-Fi untuk struk pembayaran
+// Peringatan dikirim sekali saat sisa pool turun ke batas low_pool dan sekali lagi saat habis
+func issueWifiCode(cfg WifiConfig, store *Store, paymentID string) (string, error) {
+	switch cfg.Source {
+	case "":
+		return "", errWifiDisabled
+	case "api":
+		code, err := requestWifiVoucher(cfg, paymentID)
+		if err != nil {
+			return "", err
+		}
+		return code, store.RecordWifiVoucher(code, paymentID)
+	}
+	code, remaining, err := store.IssueWifiVoucher(paymentID)
+	if errors.Is(err, errWifiPoolEmpty) {
+		return "", err
+	}
+	if remaining == cfg.LowPool || remaining == 0 {
+		alertWifiPool(cfg, remaining)
+	}
+	return code, err
+}
+
+// Fungsi untuk mengirim peringatan voucher hampir habis ke log dan webhook
+func alertWifiPool(cfg WifiConfig, remaining int) {
+	message := fmt.Sprintf("Sisa voucher Wi-Fi tinggal %d", remaining)
+	fmt.Println("PERINGATAN:", message)
+	if cfg.AlertWebhook == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]any{"event": "wifi.low_pool", "remaining": remaining, "message": message})
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(cfg.AlertWebhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Println("Gagal mengirim peringatan ke webhook:", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// Fungsi untuk memberi voucher Wi-Fi ke pembayaran sebelum checkout
+// Voucher yang gagal diambil tidak menahan pembayaran, struk dicetak tanpa kode Wi-Fi
+func attachWifiVoucher(cfg WifiConfig, backend CashierBackend, payment *Payment) {
+	if cfg.Source == "" {
+		return
+	}
+	code, err := backend.WifiVoucher(payment.ID)
+	if err != nil {
+		fmt.Println(tr("Voucher Wi-Fi tidak tersedia:"), err)
+		return
+	}
+	payment.WifiCode = code
+	fmt.Println(tr("Kode Wi-Fi:"), code)
+}
+
+// Fungsi untuk membaca kode voucher dari kolom pertama file CSV
+// Baris judul "code"/"kode" dilewati
+func readWifiCSV(r io.Reader) ([]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("File voucher tidak valid: %w", err)
+	}
+	var codes []string
+	for i, rec := range records {
+		if len(rec) == 0 {
+			continue
+		}
+		code := strings.TrimSpace(rec[0])
+		if i == 0 && (strings.EqualFold(code, "code") || strings.EqualFold(code, "kode")) {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// POST /api/v1/wifi/redeem
+// Dipanggil portal hotspot atau accounting RADIUS saat kode dipakai login
+func (s *Server) handleWifiRedeem(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Code == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi code")
+		return
+	}
+	v, err := s.store.RedeemWifiVoucher(body.Code)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// POST /api/v1/terminal/wifi-vouchers
+func (s *Server) handleTerminalWifiVoucher(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		PaymentID string `json:"payment_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PaymentID == "" {
+		writeError(w, http.StatusBadRequest, "Body harus berisi payment_id")
+		return
+	}
+	code, err := s.local.WifiVoucher(body.PaymentID)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"code": code})
+}
+
+// Fungsi untuk menjalankan sub-perintah "wifi"
+func runWifiCommand(cfg *Config, store *Store, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Gunakan: wifi import <file.csv>|status|redeem <kode>")
+	}
+	switch args[0] {
+	case "import":
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: wifi import <file.csv>")
+		}
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		codes, err := readWifiCSV(f)
+		if err != nil {
+			return err
+		}
+		added, err := store.AddWifiVouchers(codes)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d voucher Wi-Fi ditambahkan, %d dilewati karena sudah ada\n", added, len(codes)-added)
+	case "status":
+		fs := flag.NewFlagSet("wifi status", flag.ContinueOnError)
+		dateFlag := fs.String("date", "", "hanya voucher yang dicetak pada tanggal YYYY-MM-DD")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		var free, issued, redeemed int
+		for _, v := range store.WifiVouchers() {
+			if v.PaymentID == "" {
+				if *dateFlag == "" {
+					free++
+				}
+				continue
+			}
+			if *dateFlag != "" && v.IssuedAt.Local().Format("2006-01-02") != *dateFlag {
+				continue
+			}
+			issued++
+			if !v.RedeemedAt.IsZero() {
+				redeemed++
+			}
+		}
+		if *dateFlag == "" {
+			fmt.Printf("Sisa voucher pool : %d", free)
+			if free <= cfg.Wifi.LowPool {
+				fmt.Print("  (HAMPIR HABIS)")
+			}
+			fmt.Println()
+		}
+		fmt.Printf("Dicetak di struk  : %d\n", issued)
+		fmt.Printf("Dipakai login     : %d", redeemed)
+		if issued > 0 {
+			fmt.Printf(" (%.0f%%)", float64(redeemed)*100/float64(issued))
+		}
+		fmt.Println()
+	case "redeem":
+		if len(args) < 2 {
+			return fmt.Errorf("Gunakan: wifi redeem <kode>")
+		}
+		v, err := store.RedeemWifiVoucher(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println("Voucher", v.Code, "dipakai pada", v.RedeemedAt.Local().Format("2006-01-02 15:04"))
+	default:
+		return fmt.Errorf("Sub-perintah wifi tidak dikenal: %s", args[0])
+	}
+	return nil
+}